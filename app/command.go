@@ -31,6 +31,7 @@ const (
 	configKeyLeaderEnable          = "consul.leader.election.enabled"
 	configKeySqlDbEnable           = "spring.datasource.enabled"
 	configKeyDisconnected          = "cli.flag.disconnected"
+	configKeyEncryptRequired       = "config.encrypt.required"
 
 	CommandRoot     = ""
 	CommandMigrate  = "migrate"
@@ -39,6 +40,10 @@ const (
 	CommandOpenApi  = "openapi"
 	CommandAsyncApi = "asyncapi"
 
+	CommandConfigSchema  = "config-schema"
+	CommandConfigEncrypt = "config-encrypt"
+	CommandConfigKeyGen  = "config-keygen"
+
 	configValueFalse = "false"
 	configValueTrue  = "true"
 )
@@ -98,6 +103,24 @@ func init() {
 	} else {
 		asyncapiprovider.CustomizeAsyncApiSpecCommand(asyncApiCommand)
 	}
+
+	if configSchemaCommand, err := AddCommand(CommandConfigSchema, "Generate configuration schema", generateConfigSchemaCommand, commandConfigSchemaInit); err != nil {
+		cli.Fatal(err)
+	} else {
+		customizeConfigSchemaCommand(configSchemaCommand)
+	}
+
+	if configEncryptCommand, err := AddCommand(CommandConfigEncrypt, "Encrypt a configuration value", encryptConfigValueCommand, commandConfigEncryptInit); err != nil {
+		cli.Fatal(err)
+	} else {
+		customizeConfigEncryptCommand(configEncryptCommand)
+	}
+
+	if configKeyGenCommand, err := AddCommand(CommandConfigKeyGen, "Generate configuration encryption keys", generateConfigKeyCommand, commandConfigKeyGenInit); err != nil {
+		cli.Fatal(err)
+	} else {
+		customizeConfigKeyGenCommand(configKeyGenCommand)
+	}
 }
 
 func AddCommand(path, brief string, command CommandObserver, init Observer) (cmd *cobra.Command, err error) {
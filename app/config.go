@@ -8,6 +8,7 @@ import (
 	"context"
 	"cto-github.cisco.com/NFV-BU/go-msx/config"
 	"cto-github.cisco.com/NFV-BU/go-msx/config/consulprovider"
+	"cto-github.cisco.com/NFV-BU/go-msx/config/kubernetesprovider"
 	"cto-github.cisco.com/NFV-BU/go-msx/config/vaultprovider"
 	"cto-github.cisco.com/NFV-BU/go-msx/consul"
 	"cto-github.cisco.com/NFV-BU/go-msx/log"
@@ -20,6 +21,7 @@ import (
 const (
 	SourceConsul           = "Consul"
 	SourceVault            = "Vault"
+	SourceKubernetes       = "Kubernetes"
 	SourceApplication      = "Application"
 	SourceProfile          = "Profile"
 	SourceCommandLine      = "CommandLine"
@@ -58,6 +60,7 @@ type Sources struct {
 	Sources           config.Provider
 	Consul            []config.Provider
 	Vault             []config.Provider
+	Kubernetes        []config.Provider
 	ProfileFiles      []config.Provider
 	Environment       config.Provider
 	CommandLine       []config.Provider
@@ -88,6 +91,7 @@ func (c Sources) Providers() []config.Provider {
 	sourcesList.Append(c.Sources)
 	sourcesList.Append(c.Consul...)
 	sourcesList.Append(c.Vault...)
+	sourcesList.Append(c.Kubernetes...)
 	sourcesList.Append(c.ProfileFiles...)
 	sourcesList.Append(c.Environment)
 	sourcesList.Append(c.CommandLine...)
@@ -101,6 +105,7 @@ var providerFactories = map[string]ProviderFactory{
 	SourceCommandLine: nil,
 	SourceConsul:      nil,
 	SourceVault:       nil,
+	SourceKubernetes:  nil,
 	SourceApplication: newApplicationProviders,
 	SourceProfile:     newProfileProvider,
 }
@@ -211,6 +216,19 @@ func registerRemoteConfigProviders(ctx context.Context) error {
 		return providers, nil
 	})
 
+	RegisterProviderFactory(SourceKubernetes, func(name string, cfg *config.Config) (providers []config.Provider, err error) {
+		providers, err = kubernetesprovider.NewProvidersFromConfig(name, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range providers {
+			providers[i] = config.NewCacheProvider(providers[i])
+		}
+
+		return providers, nil
+	})
+
 	RegisterProviderFactory(SourceVault, func(name string, cfg *config.Config) (providers []config.Provider, err error) {
 		ctx = config.ContextWithConfig(ctx, cfg)
 		providers, err = vaultprovider.NewProvidersFromConfig(name, ctx, cfg)
@@ -297,6 +315,10 @@ func loadConfig(ctx context.Context) (err error) {
 		return errors.Wrap(err, "Failed to reload with file config providers")
 	}
 
+	if err = registerConfigDecryptors(ctx, cfg); err != nil {
+		return errors.Wrap(err, "Failed to register configuration decryptors")
+	}
+
 	if sources.Consul, err = newProviders(SourceConsul, cfg); err != nil {
 		if !errors.Is(err, consul.ErrDisabled) {
 			return errors.Wrapf(err, "Failed to create providers for %q", SourceConsul)
@@ -313,11 +335,19 @@ func loadConfig(ctx context.Context) (err error) {
 		}
 	}
 
+	if sources.Kubernetes, err = newProviders(SourceKubernetes, cfg); err != nil {
+		return errors.Wrapf(err, "Failed to create providers for %q", SourceKubernetes)
+	}
+
 	cfg = config.NewConfig(sources.Providers()...)
 	if err = mustLoadConfig(ctx, cfg); err != nil {
 		return errors.Wrap(err, "Failed to reload with remote config providers")
 	}
 
+	if err = checkConfigDecrypted(cfg); err != nil {
+		return err
+	}
+
 	applicationConfig = cfg
 
 	contextInjectors.Register(func(ctx context.Context) context.Context {
@@ -0,0 +1,158 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package app
+
+import (
+	"bufio"
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
+	"cto-github.cisco.com/NFV-BU/go-msx/config/encryption"
+	transitvaultprovider "cto-github.cisco.com/NFV-BU/go-msx/transit/vaultprovider"
+	"cto-github.cisco.com/NFV-BU/go-msx/vault"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+)
+
+// registerConfigDecryptors makes the decryptors configured so far available for
+// resolving "{cipher}" values during subsequent configuration loads.
+func registerConfigDecryptors(ctx context.Context, cfg *config.Config) error {
+	encryptionConfig, err := encryption.RegisterDecryptorsFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	if !encryptionConfig.Transit.Enabled {
+		return nil
+	}
+
+	conn := vault.ConnectionFromContext(ctx)
+	if conn == nil {
+		conn, err = vault.NewConnection(config.ContextWithConfig(ctx, cfg))
+		if err != nil {
+			return errors.Wrap(err, "Failed to obtain vault connection for transit decryptor")
+		}
+	}
+
+	config.RegisterDecryptor(
+		encryption.DecryptorTransit,
+		transitvaultprovider.NewConfigCipher(ctx, conn, encryptionConfig.Transit.KeyName))
+
+	return nil
+}
+
+// checkConfigDecrypted ensures all "{cipher}" values were decrypted.
+func checkConfigDecrypted(cfg *config.Config) error {
+	encryptionConfig, err := encryption.NewConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	if keys := cfg.EncryptedKeys(); len(keys) > 0 {
+		if !encryptionConfig.Required {
+			logger.Warnf("No decryptor available for encrypted settings: %s", strings.Join(keys, ", "))
+			return nil
+		}
+
+		return errors.Errorf("No decryptor available for encrypted settings: %s", strings.Join(keys, ", "))
+	}
+
+	return nil
+}
+
+func encryptConfigValueCommand(ctx context.Context, args []string) error {
+	cfg := config.MustFromContext(ctx)
+
+	decryptor, _ := cfg.StringOr("cli.flag.decryptor", "")
+	if decryptor == "" {
+		encryptionConfig, err := encryption.NewConfig(cfg)
+		if err != nil {
+			return err
+		}
+		decryptor = encryptionConfig.Decryptor
+	}
+
+	var plaintext string
+	if len(args) > 0 {
+		plaintext = args[0]
+	} else {
+		scanner := bufio.NewScanner(os.Stdin)
+		if scanner.Scan() {
+			plaintext = scanner.Text()
+		}
+		if err := scanner.Err(); err != nil {
+			return errors.Wrap(err, "Failed to read value from standard input")
+		}
+	}
+
+	value, err := config.EncryptValue(decryptor, plaintext)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(os.Stdout, value)
+	return err
+}
+
+func customizeConfigEncryptCommand(cmd *cobra.Command) {
+	cmd.Use = CommandConfigEncrypt + " [value]"
+	cmd.Args = cobra.MaximumNArgs(1)
+	cmd.Flags().String("decryptor", "", "Specify the decryptor (key, box, transit); defaults to config.encrypt.decryptor")
+}
+
+func generateConfigKeyCommand(ctx context.Context, _ []string) error {
+	cfg := config.MustFromContext(ctx)
+	decryptor, _ := cfg.StringOr("cli.flag.decryptor", encryption.DecryptorKey)
+
+	var err error
+	switch decryptor {
+	case encryption.DecryptorKey:
+		var key string
+		if key, err = encryption.GenerateKey(); err == nil {
+			_, err = fmt.Fprintln(os.Stdout, key)
+		}
+
+	case encryption.DecryptorBox:
+		var publicKey, privateKey string
+		if publicKey, privateKey, err = encryption.GenerateBoxKeyPair(); err == nil {
+			_, err = fmt.Fprintf(os.Stdout, "public-key: %s\nprivate-key: %s\n", publicKey, privateKey)
+		}
+
+	default:
+		err = errors.Errorf("Cannot generate keys for decryptor %q", decryptor)
+	}
+
+	return err
+}
+
+func customizeConfigKeyGenCommand(cmd *cobra.Command) {
+	cmd.Args = cobra.NoArgs
+	cmd.Flags().String("decryptor", encryption.DecryptorKey, "Specify the decryptor (key, box)")
+}
+
+func commandConfigEncryptInit(_ context.Context) error {
+	// Vault remains available for the transit decryptor
+	OverrideConfig(map[string]string{
+		configKeyEncryptRequired:       configValueFalse,
+		configKeyRedisEnable:           configValueFalse,
+		configKeyKafkaEnable:           configValueFalse,
+		configKeyConsulDiscoveryEnable: configValueFalse,
+		configKeyServerEnable:          configValueFalse,
+		configKeyLeaderEnable:          configValueFalse,
+		configKeySqlDbEnable:           configValueFalse,
+		configKeyConsulEnable:          configValueFalse,
+	})
+
+	return nil
+}
+
+func commandConfigKeyGenInit(ctx context.Context) error {
+	OverrideConfig(map[string]string{
+		configKeyEncryptRequired: configValueFalse,
+	})
+	return commandVersionInit(ctx)
+}
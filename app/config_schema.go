@@ -0,0 +1,70 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package app
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+const (
+	configSchemaFormatJson     = "json"
+	configSchemaFormatMarkdown = "markdown"
+)
+
+func init() {
+	OnEvent(EventStart, PhaseAfter, checkConfigKeys)
+}
+
+// checkConfigKeys reports settings not consumed by any structure populated during startup.
+func checkConfigKeys(ctx context.Context) error {
+	return config.CheckUnknownKeys(ctx, config.MustFromContext(ctx))
+}
+
+func generateConfigSchemaCommand(ctx context.Context, _ []string) error {
+	cfg := config.MustFromContext(ctx)
+	format, _ := cfg.StringOr("cli.flag.format", configSchemaFormatJson)
+
+	var data []byte
+	var err error
+	switch format {
+	case configSchemaFormatJson:
+		data, err = config.Schemas().JsonSchema()
+	case configSchemaFormatMarkdown:
+		data = config.Schemas().Markdown()
+	default:
+		err = errors.Errorf("Unknown configuration schema format %q", format)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	outputFileName, _ := cfg.StringOr("cli.flag.output", "")
+	if outputFileName == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	if err = os.WriteFile(outputFileName, data, 0664); err != nil {
+		return errors.Wrapf(err, "Failed to save configuration schema to %q", outputFileName)
+	}
+
+	logger.WithContext(ctx).Infof("Saved configuration schema to %q", outputFileName)
+	return nil
+}
+
+func customizeConfigSchemaCommand(cmd *cobra.Command) {
+	cmd.Args = cobra.NoArgs
+	cmd.Flags().String("format", configSchemaFormatJson, "Specify the output format (json, markdown)")
+	cmd.Flags().String("output", "", "Specify the output file for the configuration schema")
+}
+
+func commandConfigSchemaInit(ctx context.Context) error {
+	return specificationInit(ctx)
+}
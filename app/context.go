@@ -17,6 +17,7 @@ import (
 	"cto-github.cisco.com/NFV-BU/go-msx/health/vaultcheck"
 	"cto-github.cisco.com/NFV-BU/go-msx/httpclient"
 	"cto-github.cisco.com/NFV-BU/go-msx/kafka"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/grpcops"
 	"cto-github.cisco.com/NFV-BU/go-msx/redis"
 	"cto-github.cisco.com/NFV-BU/go-msx/sqldb"
 	"cto-github.cisco.com/NFV-BU/go-msx/types"
@@ -37,6 +38,7 @@ func init() {
 	OnEvent(EventConfigure, PhaseAfter, configureKafkaPool)
 	OnEvent(EventConfigure, PhaseAfter, withConfig(fs.ConfigureFileSystem))
 	OnEvent(EventConfigure, PhaseAfter, configureWebService)
+	OnEvent(EventConfigure, PhaseAfter, configureGrpcServer)
 }
 
 func RegisterContextInjector(injector types.ContextInjector) {
@@ -146,3 +148,15 @@ func configureWebService(ctx context.Context) error {
 		return nil
 	})(ctx)
 }
+
+func configureGrpcServer(ctx context.Context) error {
+	return withConfig(func(cfg *config.Config) error {
+		if err := grpcops.ConfigureServer(cfg, ctx); err != nil && err != grpcops.ErrDisabled {
+			return err
+		} else if err != nil {
+			logger.Debug(err.Error())
+		}
+
+		return nil
+	})(ctx)
+}
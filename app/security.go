@@ -5,13 +5,51 @@
 package app
 
 import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/rbac"
+	"cto-github.cisco.com/NFV-BU/go-msx/security/certdetailsprovider"
 	"cto-github.cisco.com/NFV-BU/go-msx/security/certprovider"
 	"cto-github.cisco.com/NFV-BU/go-msx/security/idmdetailsprovider"
 	"cto-github.cisco.com/NFV-BU/go-msx/security/jwttokenprovider"
+	"cto-github.cisco.com/NFV-BU/go-msx/security/localissuer"
+	"cto-github.cisco.com/NFV-BU/go-msx/stream/topics/tokenrevocation"
 )
 
 func init() {
 	OnEvent(EventConfigure, PhaseAfter, jwttokenprovider.RegisterTokenProvider)
 	OnEvent(EventConfigure, PhaseAfter, idmdetailsprovider.RegisterTokenDetailsProvider)
+	OnEvent(EventConfigure, PhaseAfter, registerLocalIssuer)
 	OnEvent(EventConfigure, PhaseAfter, certprovider.RegisterCertificateProvider)
+	OnEvent(EventConfigure, PhaseAfter, registerCertificateIdentityMapper)
+	OnEvent(EventConfigure, PhaseAfter, rbac.ConfigurePolicyEngine)
+	OnEvent(EventStart, PhaseDuring, registerTokenRevocationListener)
+}
+
+func registerLocalIssuer(ctx context.Context) error {
+	err := localissuer.RegisterProviders(ctx)
+	if err == localissuer.ErrDisabled {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	OnEvent(EventStart, PhaseBefore, localissuer.RegisterController)
+	return nil
+}
+
+func registerCertificateIdentityMapper(ctx context.Context) error {
+	err := certdetailsprovider.RegisterIdentityMapper(ctx)
+	if err == certdetailsprovider.ErrDisabled {
+		return nil
+	}
+	return err
+}
+
+func registerTokenRevocationListener(ctx context.Context) error {
+	err := tokenrevocation.RegisterRevocationListener(ctx)
+	if err == tokenrevocation.ErrDisabled {
+		logger.WithContext(ctx).Info("Token revocation listener disabled")
+		return nil
+	}
+	return err
 }
@@ -8,6 +8,7 @@ import (
 	"context"
 	"cto-github.cisco.com/NFV-BU/go-msx/cache/lru"
 	"cto-github.cisco.com/NFV-BU/go-msx/config"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/grpcops"
 	redisCache "cto-github.cisco.com/NFV-BU/go-msx/redis/cache"
 	"cto-github.cisco.com/NFV-BU/go-msx/types"
 	"cto-github.cisco.com/NFV-BU/go-msx/webservice"
@@ -16,6 +17,7 @@ import (
 	"cto-github.cisco.com/NFV-BU/go-msx/webservice/apilistprovider"
 	"cto-github.cisco.com/NFV-BU/go-msx/webservice/asyncapiprovider"
 	"cto-github.cisco.com/NFV-BU/go-msx/webservice/authprovider"
+	"cto-github.cisco.com/NFV-BU/go-msx/webservice/batchprovider"
 	"cto-github.cisco.com/NFV-BU/go-msx/webservice/debugprovider"
 	"cto-github.cisco.com/NFV-BU/go-msx/webservice/envprovider"
 	"cto-github.cisco.com/NFV-BU/go-msx/webservice/healthprovider"
@@ -24,6 +26,7 @@ import (
 	"cto-github.cisco.com/NFV-BU/go-msx/webservice/loggersprovider"
 	"cto-github.cisco.com/NFV-BU/go-msx/webservice/maintenanceprovider"
 	"cto-github.cisco.com/NFV-BU/go-msx/webservice/metricsprovider"
+	"cto-github.cisco.com/NFV-BU/go-msx/webservice/operationprovider"
 	"cto-github.cisco.com/NFV-BU/go-msx/webservice/swaggerprovider"
 
 	_ "cto-github.cisco.com/NFV-BU/go-msx/ops/restops/httperrors"
@@ -46,13 +49,17 @@ func registerRegistrations(cfg *config.Config) error {
 		OnEvent(EventStart, PhaseBefore, registerSwaggerWebService)
 		OnEvent(EventStart, PhaseBefore, registerApiListWebService)
 		OnEvent(EventStart, PhaseBefore, registerAsyncApiWebService)
+		OnEvent(EventStart, PhaseBefore, registerBatchWebService)
+		OnEvent(EventStart, PhaseBefore, registerOperationWebService)
 
 		OnEvent(EventStart, PhaseAfter, webservice.Start)
+		OnEvent(EventStart, PhaseAfter, grpcops.Start)
 
 		OnEvent(EventStart, PhaseAfter, registerIdempotencyCacheRedis)
 		OnEvent(EventStart, PhaseAfter, registerIdempotencyCacheInMemory)
 		OnEvent(EventStart, PhaseAfter, idempotency.ApplyIdempotencyKeyFilter)
 
+		OnEvent(EventStop, PhaseBefore, grpcops.Stop)
 		OnEvent(EventStop, PhaseBefore, webservice.Stop)
 	}
 
@@ -125,16 +132,42 @@ func registerAsyncApiWebService(ctx context.Context) error {
 	}
 }
 
+func registerBatchWebService(ctx context.Context) error {
+	logger.WithContext(ctx).Info("Registering batch endpoint")
+	err := batchprovider.RegisterProvider(ctx)
+
+	switch err {
+	case batchprovider.ErrDisabled:
+		logger.Info("Batch endpoint disabled")
+		return nil
+	default:
+		return err
+	}
+}
+
+func registerOperationWebService(ctx context.Context) error {
+	logger.WithContext(ctx).Info("Registering operation endpoints")
+	err := operationprovider.RegisterProvider(ctx)
+
+	switch err {
+	case operationprovider.ErrDisabled:
+		logger.Info("Operation endpoints disabled")
+		return nil
+	default:
+		return err
+	}
+}
+
 func registerIdempotencyCacheRedis(ctx context.Context) error {
 	cfg := config.MustFromContext(ctx)
 
 	lru.RegisterCacheProvider(idempotency.CacheProviderInMemory, func(ctx context.Context, configRoot string) (lru.ContextCache, error) {
-		lruConfig, err := lru.NewCacheConfig(cfg, idempotency.ConfigRootIdempotencyKeyInMemory)
+		lruConfig, err := config.Bind[lru.CacheConfig](cfg, idempotency.ConfigRootIdempotencyKeyInMemory)
 		if err != nil {
 			logger.WithContext(ctx).Error(err)
 			return nil, err
 		}
-		return lru.ContextCacheAdapter{Lru: lru.NewCacheFromConfig(lruConfig)}, nil
+		return lru.ContextCacheAdapter{Lru: lru.NewCacheFromBinding(lruConfig)}, nil
 	})
 
 	return nil
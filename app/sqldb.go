@@ -9,6 +9,7 @@ import (
 	"cto-github.cisco.com/NFV-BU/go-msx/config"
 	repomigrate "cto-github.cisco.com/NFV-BU/go-msx/repository/migrate"
 	"cto-github.cisco.com/NFV-BU/go-msx/sqldb"
+	"cto-github.cisco.com/NFV-BU/go-msx/sqldb/changestream"
 	"cto-github.cisco.com/NFV-BU/go-msx/sqldb/migrate"
 )
 
@@ -32,5 +33,14 @@ func init() {
 		return nil
 	})
 
+	OnCommandsEvent(
+		[]string{CommandRoot, CommandAsyncApi},
+		EventStart, PhaseBefore,
+		changestream.Register)
+
+	OnRootEvent(EventStart, PhaseAfter, changestream.StartRelay)
+	OnRootEvent(EventStop, PhaseBefore, changestream.StopRelay)
+
 	repomigrate.RegisterMigrator(migrate.Migrate)
+	repomigrate.RegisterMigrator(changestream.Migrate)
 }
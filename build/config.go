@@ -234,6 +234,13 @@ type Generate struct {
 	Command string   `config:"default="`
 	BuiltIn []string `config:"default="`
 	VfsGen  *GenerateVfs
+	Client  *GenerateClient
+}
+
+type GenerateClient struct {
+	Spec    string
+	Service string
+	Package string `config:"default="`
 }
 
 type GenerateVfs struct {
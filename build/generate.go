@@ -8,11 +8,14 @@ import (
 	"bytes"
 	"cto-github.cisco.com/NFV-BU/go-msx/exec"
 	"cto-github.cisco.com/NFV-BU/go-msx/fs"
+	"cto-github.cisco.com/NFV-BU/go-msx/integration/restclient/generator"
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/pkg/errors"
 	"github.com/shurcooL/vfsgen"
 	"gopkg.in/pipe.v2"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
@@ -151,6 +154,8 @@ func GenerateCode(args []string) error {
 		logger.Infof("Generating path '%s'", p.Path)
 		if p.VfsGen != nil {
 			err = generateCodePathVfs(p)
+		} else if p.Client != nil {
+			err = generateCodePathClient(p)
 		} else if len(p.BuiltIn) != 0 {
 			err = generateBuiltin(p)
 		} else {
@@ -210,6 +215,36 @@ func generateCodePathVfs(p Generate) (err error) {
 	})
 }
 
+func generateCodePathClient(p Generate) (err error) {
+	loader := openapi3.NewSwaggerLoader()
+	loader.IsExternalRefsAllowed = true
+
+	var spec *openapi3.Swagger
+	if specUrl, urlErr := url.Parse(p.Client.Spec); urlErr == nil && specUrl.Scheme != "" {
+		spec, err = loader.LoadSwaggerFromURI(specUrl)
+	} else {
+		spec, err = loader.LoadSwaggerFromFile(filepath.Join(p.Path, p.Client.Spec))
+	}
+	if err != nil {
+		return errors.Wrap(err, "Failed to load OpenAPI spec")
+	}
+
+	packageName := p.Client.Package
+	if packageName == "" {
+		packageName = path.Base(p.Path)
+	}
+
+	clientGenerator, err := generator.NewGenerator(spec, generator.Options{
+		PackageName: packageName,
+		ServiceName: p.Client.Service,
+	})
+	if err != nil {
+		return err
+	}
+
+	return clientGenerator.WriteFiles(p.Path)
+}
+
 func getGenerateRootDir(p Generate) (string, error) {
 	abs, err := filepath.Abs(p.Path)
 	if err != nil {
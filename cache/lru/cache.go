@@ -27,6 +27,8 @@
 package lru
 
 import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
 	"github.com/thejerf/abtime"
 	"sync"
 	"time"
@@ -40,6 +42,12 @@ type Cache interface {
 	Clear()
 }
 
+// TtlCache is a Cache supporting per-entry TTLs.
+type TtlCache interface {
+	Cache
+	SetWithTtl(key string, value any, ttl time.Duration)
+}
+
 type HeapMapCache struct {
 	sync.RWMutex
 	ttl             time.Duration       // period before expiry
@@ -51,7 +59,8 @@ type HeapMapCache struct {
 	metrics         bool                // enable metrics collection
 	metricsPrefix   string              // prefix for metrics
 	metricsObs      metricsObserver     // the metrics for the cache
-	shutdown        bool                // stop running the ticker since the test has completed
+	shutdown        bool                // stop running the expiry ticker
+	unbind          func()              // detach from the configuration binding
 	expired         chan struct{}       // await the expiry ticker completion
 }
 
@@ -123,7 +132,10 @@ func (c *HeapMapCache) Get(key string) (value any, found bool) {
 
 // Set adds a value to the cache with the given key and current default TTL
 func (c *HeapMapCache) Set(key string, value any) {
-	c.SetWithTtl(key, value, c.ttl)
+	c.RLock()
+	ttl := c.ttl
+	c.RUnlock()
+	c.SetWithTtl(key, value, ttl)
 }
 
 // SetWithTtl adds a value to the cache with the given key and TTL
@@ -160,13 +172,21 @@ func (c *HeapMapCache) Clear() {
 
 func (c *HeapMapCache) tick() {
 	for {
-		c.timeSource.Sleep(c.expireFrequency, sleeperId)
+		c.RLock()
+		expireFrequency := c.expireFrequency
+		c.RUnlock()
+		c.timeSource.Sleep(expireFrequency, sleeperId)
 		c.expire()
 		if c.expired != nil {
 			c.expired <- struct{}{}
 		}
-		if c.shutdown {
-			close(c.expired)
+		c.RLock()
+		shutdown := c.shutdown
+		c.RUnlock()
+		if shutdown {
+			if c.expired != nil {
+				close(c.expired)
+			}
 			return
 		}
 	}
@@ -201,6 +221,32 @@ func (c *HeapMapCache) collect() int {
 	return j
 }
 
+// Reconfigure applies the expiry settings from the supplied config.  Existing entries
+// retain their expiry time.  Metrics settings cannot be changed after creation.
+func (c *HeapMapCache) Reconfigure(cfg *CacheConfig) {
+	c.Lock()
+	defer c.Unlock()
+	c.ttl = cfg.Ttl
+	c.expireFrequency = cfg.ExpireFrequency
+	c.deAgeOnAccess = cfg.DeAgeOnAccess
+	if cfg.ExpireLimit != len(c.collected) {
+		c.collected = make([]string, cfg.ExpireLimit)
+	}
+}
+
+// Close stops expiring entries and detaches the cache from its configuration binding.
+func (c *HeapMapCache) Close() {
+	c.Lock()
+	c.shutdown = true
+	unbind := c.unbind
+	c.unbind = nil
+	c.Unlock()
+
+	if unbind != nil {
+		unbind()
+	}
+}
+
 // NewCache creates a new cache with the given TTL, ExpireLimit & ExpireFrequency
 func NewCache(ttl time.Duration, expireLimit int,
 	expireFrequency time.Duration, timeSource abtime.AbstractTime) *HeapMapCache {
@@ -212,3 +258,14 @@ func NewCacheFromConfig(cfg *CacheConfig) *HeapMapCache {
 	return NewCache2(cfg.Ttl, cfg.ExpireLimit, cfg.ExpireFrequency,
 		cfg.DeAgeOnAccess, abtime.NewRealTime(), cfg.Metrics, cfg.MetricsPrefix)
 }
+
+// NewCacheFromBinding creates a new cache from the current config, and
+// reconfigures it when the bound configuration changes.  Close the cache
+// to stop receiving changes once it is no longer required.
+func NewCacheFromBinding(binding *config.Binding[CacheConfig]) *HeapMapCache {
+	c := NewCacheFromConfig(binding.Current())
+	c.unbind = binding.OnChange(func(ctx context.Context, _, current *CacheConfig) {
+		c.Reconfigure(current)
+	})
+	return c
+}
@@ -5,6 +5,7 @@
 package lru
 
 import (
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
 	"cto-github.cisco.com/NFV-BU/go-msx/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/thejerf/abtime"
@@ -202,7 +203,45 @@ func TestHeapMapCache(t *testing.T) {
 			advanceCache(cache, mockClock, 2*time.Second)
 			assert.Empty(t, cache.index)
 
-			cache.shutdown = true
+			cache.Close()
 		})
 	}
 }
+
+func TestHeapMapCache_Reconfigure(t *testing.T) {
+	mockClock := types.NewMockClock()
+	cache := NewCache2(time.Minute, 1, 500*time.Millisecond, false, mockClock, false, "")
+	cache.expired = make(chan struct{})
+
+	cache.Reconfigure(&CacheConfig{
+		Ttl:             time.Second,
+		ExpireLimit:     5,
+		ExpireFrequency: 250 * time.Millisecond,
+		DeAgeOnAccess:   true,
+	})
+
+	assert.Equal(t, time.Second, cache.ttl)
+	assert.Equal(t, 250*time.Millisecond, cache.expireFrequency)
+	assert.True(t, cache.deAgeOnAccess)
+	assert.Len(t, cache.collected, 5)
+
+	cache.Set("key1", "value1")
+	advanceCache(cache, mockClock, 2*time.Second)
+	assert.Empty(t, cache.index)
+
+	cache.Close()
+}
+
+func TestNewCacheFromBinding(t *testing.T) {
+	cfg := testCacheConfig("1s", "10", "30s", "false", "false", "", "cache")
+	binding, err := config.NewBinding[CacheConfig](cfg, "cache")
+	assert.NoError(t, err)
+
+	cache := NewCacheFromBinding(binding)
+	assert.Equal(t, time.Second, cache.ttl)
+	assert.Len(t, cache.collected, 10)
+	assert.NotNil(t, cache.unbind)
+
+	cache.Close()
+	assert.Nil(t, cache.unbind)
+}
@@ -0,0 +1,178 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package config
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// SnapshotListener receives each new snapshot created by Config.Watch.
+type SnapshotListener func(ctx context.Context, snapshot Snapshot)
+
+type bindingKey struct {
+	prefix string
+	target reflect.Type
+}
+
+type snapshotListeners struct {
+	mtx       sync.Mutex
+	listeners []SnapshotListener
+	bindings  map[bindingKey]any
+}
+
+func (l *snapshotListeners) add(listener SnapshotListener) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.listeners = append(l.listeners, listener)
+}
+
+func (l *snapshotListeners) dispatch(ctx context.Context, snapshot Snapshot) {
+	if l == nil {
+		return
+	}
+
+	l.mtx.Lock()
+	listeners := append([]SnapshotListener{}, l.listeners...)
+	l.mtx.Unlock()
+
+	for _, listener := range listeners {
+		listener(ctx, snapshot)
+	}
+}
+
+// AddSnapshotListener registers a listener to be notified of each new snapshot.
+// Listeners are executed synchronously by the watcher, and must not block.
+func (c *Config) AddSnapshotListener(listener SnapshotListener) {
+	c.listeners.add(listener)
+}
+
+// BindingCallback is executed after a Binding receives a new value.
+type BindingCallback[T any] func(ctx context.Context, previous, current *T)
+
+// Binding maintains the latest valid value of a configuration structure,
+// re-populating it whenever settings below its prefix change.
+type Binding[T any] struct {
+	prefix    string
+	current   atomic.Value
+	mtx       sync.Mutex
+	callbacks []*BindingCallback[T]
+}
+
+// Prefix returns the configuration prefix this binding populates from.
+func (b *Binding[T]) Prefix() string {
+	return b.prefix
+}
+
+// Current returns the latest valid value.  The returned value must not be modified.
+func (b *Binding[T]) Current() *T {
+	return b.current.Load().(*T)
+}
+
+// OnChange registers a callback to be executed after each accepted change.
+// The returned function removes the callback.
+func (b *Binding[T]) OnChange(callback BindingCallback[T]) (remove func()) {
+	registered := &callback
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.callbacks = append(b.callbacks, registered)
+
+	return func() {
+		b.mtx.Lock()
+		defer b.mtx.Unlock()
+		for i, c := range b.callbacks {
+			if c == registered {
+				b.callbacks = append(b.callbacks[:i:i], b.callbacks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (b *Binding[T]) populate(source PopulatorSource) (*T, error) {
+	target := new(T)
+	if err := Populate(target, b.prefix, source); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+func (b *Binding[T]) onSnapshot(ctx context.Context, snapshot Snapshot) {
+	if !snapshot.Delta.HasPrefix(b.prefix) {
+		return
+	}
+
+	next, err := b.populate(snapshot.SnapshotValues)
+	if err != nil {
+		logger.
+			WithContext(ctx).
+			WithError(err).
+			Errorf("Rejected configuration change to %q, retaining previous value", b.prefix)
+		return
+	}
+
+	b.mtx.Lock()
+	previous := b.Current()
+	b.current.Store(next)
+	callbacks := append([]*BindingCallback[T]{}, b.callbacks...)
+	b.mtx.Unlock()
+
+	logger.WithContext(ctx).Infof("Configuration binding %q updated", b.prefix)
+
+	for _, callback := range callbacks {
+		(*callback)(ctx, previous, next)
+	}
+}
+
+// NewBinding creates a new Binding populated from the latest values of the supplied config.
+// Subsequent changes are applied once they have been successfully populated and validated.
+func NewBinding[T any](cfg *Config, prefix string) (*Binding[T], error) {
+	b := &Binding[T]{
+		prefix: prefix,
+	}
+
+	initial, err := b.populate(cfg.LatestValues())
+	if err != nil {
+		return nil, err
+	}
+	b.current.Store(initial)
+
+	cfg.AddSnapshotListener(b.onSnapshot)
+	return b, nil
+}
+
+// Bind returns the shared Binding for the prefix and type, creating it if required.
+func Bind[T any](cfg *Config, prefix string) (*Binding[T], error) {
+	key := bindingKey{
+		prefix: NormalizeKey(prefix),
+		target: reflect.TypeOf((*T)(nil)).Elem(),
+	}
+
+	cfg.listeners.mtx.Lock()
+	existing, ok := cfg.listeners.bindings[key]
+	cfg.listeners.mtx.Unlock()
+	if ok {
+		return existing.(*Binding[T]), nil
+	}
+
+	b, err := NewBinding[T](cfg, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.listeners.mtx.Lock()
+	defer cfg.listeners.mtx.Unlock()
+	if existing, ok = cfg.listeners.bindings[key]; ok {
+		return existing.(*Binding[T]), nil
+	}
+	if cfg.listeners.bindings == nil {
+		cfg.listeners.bindings = make(map[bindingKey]any)
+	}
+	cfg.listeners.bindings[key] = b
+	return b, nil
+}
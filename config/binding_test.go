@@ -0,0 +1,181 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package config
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newBindingTestConfig(t *testing.T, settings map[string]string) (*Config, *InMemoryProvider) {
+	provider := NewInMemoryProvider("binding", settings)
+	cfg := NewConfig(provider)
+	err := cfg.Load(context.Background())
+	assert.NoError(t, err)
+	return cfg, provider
+}
+
+func applyBindingTestSettings(t *testing.T, cfg *Config, provider *InMemoryProvider, settings map[string]string) {
+	var entries ProviderEntries
+	for name, value := range settings {
+		entries = append(entries, NewEntry(provider, name, value))
+	}
+
+	snapshot, err := newSnapshot(entries, cfg.LatestValues())
+	assert.NoError(t, err)
+	cfg.values.latest = snapshot.SnapshotValues
+	cfg.listeners.dispatch(context.Background(), snapshot)
+}
+
+func TestSnapshotDelta_HasPrefix(t *testing.T) {
+	cfg, provider := newBindingTestConfig(t, map[string]string{
+		"alpha.bravo":   "a",
+		"alpha.charlie": "b",
+		"delta":         "c",
+	})
+
+	var entries ProviderEntries
+	for name, value := range map[string]string{"alpha.bravo": "z", "alpha.charlie": "b", "delta": "c"} {
+		entries = append(entries, NewEntry(provider, name, value))
+	}
+	snapshot, err := newSnapshot(entries, cfg.LatestValues())
+	assert.NoError(t, err)
+
+	assert.True(t, snapshot.Delta.HasPrefix("alpha"))
+	assert.True(t, snapshot.Delta.HasPrefix("alpha.bravo"))
+	assert.True(t, snapshot.Delta.HasPrefix(""))
+	assert.False(t, snapshot.Delta.HasPrefix("alpha.charlie"))
+	assert.False(t, snapshot.Delta.HasPrefix("alph"))
+	assert.False(t, snapshot.Delta.HasPrefix("delta"))
+}
+
+func TestBinding(t *testing.T) {
+	tests := []struct {
+		name        string
+		settings    map[string]string
+		wantA       time.Duration
+		wantChanged bool
+	}{
+		{
+			name:        "Changed",
+			settings:    map[string]string{"validate.a": "20m"},
+			wantA:       20 * time.Minute,
+			wantChanged: true,
+		},
+		{
+			name:     "Unrelated",
+			settings: map[string]string{"validate.a": "15m", "other": "value"},
+			wantA:    15 * time.Minute,
+		},
+		{
+			name:     "Invalid",
+			settings: map[string]string{"validate.a": "2h"},
+			wantA:    15 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, provider := newBindingTestConfig(t, map[string]string{
+				"validate.a": "15m",
+			})
+
+			binding, err := NewBinding[validateStruct](cfg, "validate")
+			assert.NoError(t, err)
+			assert.Equal(t, 15*time.Minute, binding.Current().A)
+
+			var changed bool
+			binding.OnChange(func(ctx context.Context, previous, current *validateStruct) {
+				changed = true
+				assert.Equal(t, 15*time.Minute, previous.A)
+				assert.Equal(t, tt.wantA, current.A)
+			})
+
+			applyBindingTestSettings(t, cfg, provider, tt.settings)
+
+			assert.Equal(t, tt.wantA, binding.Current().A)
+			assert.Equal(t, tt.wantChanged, changed)
+		})
+	}
+}
+
+func TestBinding_OnChange_Remove(t *testing.T) {
+	cfg, provider := newBindingTestConfig(t, map[string]string{
+		"validate.a": "15m",
+	})
+
+	binding, err := NewBinding[validateStruct](cfg, "validate")
+	assert.NoError(t, err)
+
+	var removedCalls, retainedCalls int
+	remove := binding.OnChange(func(ctx context.Context, previous, current *validateStruct) {
+		removedCalls++
+	})
+	binding.OnChange(func(ctx context.Context, previous, current *validateStruct) {
+		retainedCalls++
+	})
+
+	applyBindingTestSettings(t, cfg, provider, map[string]string{"validate.a": "20m"})
+	remove()
+	remove()
+	applyBindingTestSettings(t, cfg, provider, map[string]string{"validate.a": "25m"})
+
+	assert.Equal(t, 1, removedCalls)
+	assert.Equal(t, 2, retainedCalls)
+}
+
+func TestNewBinding_Invalid(t *testing.T) {
+	cfg, _ := newBindingTestConfig(t, map[string]string{
+		"validate.a": "2h",
+	})
+
+	binding, err := NewBinding[validateStruct](cfg, "validate")
+	assert.Error(t, err)
+	assert.Nil(t, binding)
+}
+
+func TestBind_Shared(t *testing.T) {
+	cfg, _ := newBindingTestConfig(t, map[string]string{
+		"validate.a": "15m",
+	})
+
+	first, err := Bind[validateStruct](cfg, "validate")
+	assert.NoError(t, err)
+
+	second, err := Bind[validateStruct](cfg, "Validate")
+	assert.NoError(t, err)
+	assert.Same(t, first, second)
+
+	other, err := Bind[validateStruct](cfg, "other")
+	assert.NoError(t, err)
+	assert.NotSame(t, first, other)
+}
+
+func TestBind_Concurrent(t *testing.T) {
+	cfg, _ := newBindingTestConfig(t, map[string]string{
+		"validate.a": "15m",
+	})
+
+	var wg sync.WaitGroup
+	bindings := make([]*Binding[validateStruct], 8)
+	for i := range bindings {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cfg.AddSnapshotListener(func(context.Context, Snapshot) {})
+			binding, err := Bind[validateStruct](cfg, "validate")
+			assert.NoError(t, err)
+			bindings[i] = binding
+		}(i)
+	}
+	wg.Wait()
+
+	for _, binding := range bindings {
+		assert.Same(t, bindings[0], binding)
+	}
+}
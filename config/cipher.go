@@ -0,0 +1,223 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package config
+
+import (
+	"github.com/pkg/errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	cipherPrefix          = "{cipher"
+	cipherSuffix          = "}"
+	cipherNameSeparator   = ":"
+	DefaultDecryptorAlias = ""
+)
+
+var ErrDecryptorNotRegistered = errors.New("Decryptor not registered")
+var ErrEncryptorNotSupported = errors.New("Decryptor does not support encryption")
+var ErrInvalidCipherValue = errors.New("Invalid encrypted value")
+
+// Decryptor converts the ciphertext of an encrypted value to plaintext.
+type Decryptor interface {
+	Decrypt(ciphertext string) (plaintext string, err error)
+}
+
+// Encryptor converts plaintext to ciphertext which can be passed to the matching Decryptor.
+type Encryptor interface {
+	Encrypt(plaintext string) (ciphertext string, err error)
+}
+
+type decryptorRegistry struct {
+	mtx         sync.RWMutex
+	decryptors  map[string]Decryptor
+	encryptors  map[string]Encryptor
+	defaultName string
+}
+
+func (r *decryptorRegistry) register(name string, decryptor Decryptor) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.decryptors[name] = decryptor
+}
+
+func (r *decryptorRegistry) registerEncryptor(name string, encryptor Encryptor) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.encryptors[name] = encryptor
+}
+
+func (r *decryptorRegistry) setDefault(name string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.defaultName = name
+}
+
+func (r *decryptorRegistry) decryptor(name string) (Decryptor, error) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	if name == DefaultDecryptorAlias {
+		name = r.defaultName
+	}
+
+	decryptor, ok := r.decryptors[name]
+	if !ok {
+		return nil, errors.Wrapf(ErrDecryptorNotRegistered, "Decryptor %q", name)
+	}
+	return decryptor, nil
+}
+
+func (r *decryptorRegistry) encryptor(name string) (Encryptor, error) {
+	r.mtx.RLock()
+	encryptor, ok := r.encryptors[name]
+	r.mtx.RUnlock()
+	if ok {
+		return encryptor, nil
+	}
+
+	decryptor, err := r.decryptor(name)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptor, ok = decryptor.(Encryptor)
+	if !ok {
+		return nil, errors.Wrapf(ErrEncryptorNotSupported, "Decryptor %q", name)
+	}
+	return encryptor, nil
+}
+
+func (r *decryptorRegistry) names() []string {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	var results []string
+	for name := range r.decryptors {
+		results = append(results, name)
+	}
+	sort.Strings(results)
+	return results
+}
+
+var decryptors = &decryptorRegistry{
+	decryptors: make(map[string]Decryptor),
+	encryptors: make(map[string]Encryptor),
+}
+
+// RegisterDecryptor makes the decryptor available for values of the form
+// "{cipher:<name>}<ciphertext>", replacing any previous registration.
+func RegisterDecryptor(name string, decryptor Decryptor) {
+	decryptors.register(name, decryptor)
+}
+
+// RegisterEncryptor makes an encryptor available to EncryptValue without enabling
+// decryption, for example when only a public key is available.
+func RegisterEncryptor(name string, encryptor Encryptor) {
+	decryptors.registerEncryptor(name, encryptor)
+}
+
+// SetDefaultDecryptor selects the decryptor used for values of the form "{cipher}<ciphertext>".
+func SetDefaultDecryptor(name string) {
+	decryptors.setDefault(name)
+}
+
+// Decryptors returns the names of the registered decryptors.
+func Decryptors() []string {
+	return decryptors.names()
+}
+
+// IsEncrypted returns true if the value uses the encrypted value syntax.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, cipherPrefix)
+}
+
+// ParseEncryptedValue splits an encrypted value into the decryptor name and the ciphertext.
+// The name is empty when the default decryptor is to be used.
+func ParseEncryptedValue(value string) (name, ciphertext string, err error) {
+	if !IsEncrypted(value) {
+		return "", "", ErrInvalidCipherValue
+	}
+
+	end := strings.Index(value, cipherSuffix)
+	if end < 0 {
+		return "", "", ErrInvalidCipherValue
+	}
+
+	header := value[len(cipherPrefix):end]
+	switch {
+	case header == "":
+	case strings.HasPrefix(header, cipherNameSeparator) && len(header) > 1:
+		name = header[1:]
+	default:
+		return "", "", ErrInvalidCipherValue
+	}
+
+	return name, value[end+1:], nil
+}
+
+// EncryptedValue formats the ciphertext using the encrypted value syntax.
+func EncryptedValue(name, ciphertext string) string {
+	if name == DefaultDecryptorAlias {
+		return cipherPrefix + cipherSuffix + ciphertext
+	}
+	return cipherPrefix + cipherNameSeparator + name + cipherSuffix + ciphertext
+}
+
+// EncryptValue encrypts the plaintext for the named decryptor, returning a value
+// suitable for use in a configuration file.
+func EncryptValue(name, plaintext string) (string, error) {
+	encryptor, err := decryptors.encryptor(name)
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext, err := encryptor.Encrypt(plaintext)
+	if err != nil {
+		return "", err
+	}
+
+	return EncryptedValue(name, ciphertext), nil
+}
+
+// decryptValue returns the plaintext of an encrypted value.  Values encrypted
+// using a decryptor which has not yet been registered are returned unchanged,
+// since decryptors are commonly configured from settings loaded alongside them.
+func decryptValue(value string) (result string, encrypted bool, err error) {
+	if !IsEncrypted(value) {
+		return value, false, nil
+	}
+
+	name, ciphertext, err := ParseEncryptedValue(value)
+	if err != nil {
+		return "", false, err
+	}
+
+	decryptor, err := decryptors.decryptor(name)
+	if errors.Is(err, ErrDecryptorNotRegistered) {
+		logger.Debugf("Deferring decryption: %s", err.Error())
+		return value, false, nil
+	}
+
+	plaintext, err := decryptor.Decrypt(ciphertext)
+	if err != nil {
+		return "", false, errors.Wrapf(err, "Failed to decrypt value using decryptor %q", name)
+	}
+
+	return plaintext, true, nil
+}
+
+// EncryptedKeys returns the keys of any settings which could not yet be decrypted.
+func (c *Config) EncryptedKeys() []string {
+	var results []string
+	for _, entry := range c.LatestValues().Entries() {
+		if IsEncrypted(entry.ResolvedValue.String()) {
+			results = append(results, entry.Name)
+		}
+	}
+	return results
+}
@@ -0,0 +1,218 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package config
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+// reverseCipher "encrypts" values by reversing them.
+type reverseCipher struct{}
+
+func (reverseCipher) Encrypt(plaintext string) (string, error) {
+	return reverseString(plaintext), nil
+}
+
+func (reverseCipher) Decrypt(ciphertext string) (string, error) {
+	if strings.HasPrefix(ciphertext, "!") {
+		return "", errors.New("Bad ciphertext")
+	}
+	return reverseString(ciphertext), nil
+}
+
+func reverseString(value string) string {
+	runes := []rune(value)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+// decryptOnly lacks an Encrypt method.
+type decryptOnly struct{}
+
+func (decryptOnly) Decrypt(ciphertext string) (string, error) {
+	return ciphertext, nil
+}
+
+func cipherTestEntries(values map[string]string) ProviderEntries {
+	entries, _ := NewInMemoryProvider("static", values).Load(context.Background())
+	return entries
+}
+
+func TestParseEncryptedValue(t *testing.T) {
+	tests := []struct {
+		name           string
+		value          string
+		wantName       string
+		wantCiphertext string
+		wantErr        bool
+	}{
+		{
+			name:           "Default",
+			value:          "{cipher}abc",
+			wantCiphertext: "abc",
+		},
+		{
+			name:           "Named",
+			value:          "{cipher:box}abc",
+			wantName:       "box",
+			wantCiphertext: "abc",
+		},
+		{
+			name:    "EmptyName",
+			value:   "{cipher:}abc",
+			wantErr: true,
+		},
+		{
+			name:    "Unterminated",
+			value:   "{cipher",
+			wantErr: true,
+		},
+		{
+			name:    "Other",
+			value:   "{ciphers}abc",
+			wantErr: true,
+		},
+		{
+			name:    "Plain",
+			value:   "abc",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, ciphertext, err := ParseEncryptedValue(tt.value)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrInvalidCipherValue))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantName, name)
+			assert.Equal(t, tt.wantCiphertext, ciphertext)
+		})
+	}
+}
+
+func TestEncryptValue(t *testing.T) {
+	RegisterDecryptor("test-reverse", reverseCipher{})
+	RegisterDecryptor("test-decrypt-only", decryptOnly{})
+
+	value, err := EncryptValue("test-reverse", "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "{cipher:test-reverse}terces", value)
+
+	_, err = EncryptValue("test-decrypt-only", "secret")
+	assert.True(t, errors.Is(err, ErrEncryptorNotSupported))
+
+	_, err = EncryptValue("test-missing", "secret")
+	assert.True(t, errors.Is(err, ErrDecryptorNotRegistered))
+
+	RegisterEncryptor("test-encrypt-only", reverseCipher{})
+	value, err = EncryptValue("test-encrypt-only", "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "{cipher:test-encrypt-only}terces", value)
+
+	assert.Contains(t, Decryptors(), "test-reverse")
+	assert.NotContains(t, Decryptors(), "test-encrypt-only")
+}
+
+func TestConfig_Decrypt(t *testing.T) {
+	RegisterDecryptor("test-reverse", reverseCipher{})
+
+	tests := []struct {
+		name          string
+		values        map[string]string
+		want          map[string]string
+		wantEncrypted []string
+		wantErr       bool
+	}{
+		{
+			name: "Decrypted",
+			values: map[string]string{
+				"db.password": "{cipher:test-reverse}terces",
+				"db.url":      "postgres://user:${db.password}@db",
+				"db.name":     "alpha",
+			},
+			want: map[string]string{
+				"db.password": "secret",
+				"db.url":      "postgres://user:secret@db",
+				"db.name":     "alpha",
+			},
+		},
+		{
+			name: "Deferred",
+			values: map[string]string{
+				"db.password": "{cipher:test-unregistered}terces",
+			},
+			want: map[string]string{
+				"db.password": "{cipher:test-unregistered}terces",
+			},
+			wantEncrypted: []string{"db.password"},
+		},
+		{
+			name: "Failed",
+			values: map[string]string{
+				"db.password": "{cipher:test-reverse}!terces",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid",
+			values: map[string]string{
+				"db.password": "{cipher:test-reverse",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := resolveLayers(Layers{
+				cipherTestEntries(tt.values),
+			})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			values := newSnapshotValues(entries)
+			for key, want := range tt.want {
+				entry, err := values.ResolveByName(key)
+				assert.NoError(t, err)
+				assert.Equal(t, want, entry.ResolvedValue.String())
+			}
+
+			cfg := NewInMemoryConfig(tt.values)
+			assert.Equal(t, tt.wantEncrypted, cfg.EncryptedKeys())
+		})
+	}
+}
+
+func TestResolver_Encrypted(t *testing.T) {
+	RegisterDecryptor("test-reverse", reverseCipher{})
+
+	entries, err := resolveLayers(Layers{
+		cipherTestEntries(map[string]string{
+			"alpha":   "{cipher:test-reverse}a",
+			"bravo":   "${alpha}-b",
+			"charlie": "c",
+		}),
+	})
+	assert.NoError(t, err)
+
+	values := newSnapshotValues(entries)
+	for key, want := range map[string]bool{"alpha": true, "bravo": true, "charlie": false} {
+		entry, err := values.ResolveByName(key)
+		assert.NoError(t, err)
+		assert.Equal(t, want, entry.Provenance.Encrypted, key)
+	}
+}
@@ -12,12 +12,14 @@ import (
 
 type Config struct {
 	Values
-	caches   []*Cache
-	watchers []*Watcher
-	layers   []ProviderEntries
-	changes  chan WatcherNotification
-	notify   chan Snapshot
-	values   struct {
+	caches    []*Cache
+	watchers  []*Watcher
+	layers    []ProviderEntries
+	changes   chan WatcherNotification
+	notify    chan Snapshot
+	listeners *snapshotListeners
+	history   *snapshotHistory
+	values    struct {
 		original SnapshotValues // from Load
 		latest   SnapshotValues // from Watch
 	}
@@ -35,10 +37,7 @@ func (c *Config) Load(ctx context.Context) error {
 		layers[i] = entries
 	}
 
-	merged := layers.Merge()
-
-	resolver := NewResolver(merged)
-	entries, err := resolver.Entries()
+	entries, err := resolveLayers(layers)
 	if err != nil {
 		return err
 	}
@@ -90,9 +89,8 @@ func (c *Config) Watch(ctx context.Context) {
 				continue
 			}
 			layers[layerIndex] = change.Entries
-			merged := layers.Merge()
 
-			snapshot, err := newSnapshot(merged, c.values.latest)
+			snapshot, err := newLayeredSnapshot(layers, c.values.latest)
 			if err != nil {
 				logger.
 					WithContext(ctx).
@@ -103,6 +101,8 @@ func (c *Config) Watch(ctx context.Context) {
 
 			c.layers = layers
 			c.values.latest = snapshot.SnapshotValues
+			c.history.record(snapshot)
+			c.listeners.dispatch(ctx, snapshot)
 			c.notify <- snapshot
 			logger.
 				WithContext(ctx).
@@ -181,9 +181,11 @@ func NewConfig(providers ...Provider) *Config {
 	}
 
 	cfg := &Config{
-		caches:  caches,
-		changes: make(chan WatcherNotification),
-		notify:  make(chan Snapshot),
+		caches:    caches,
+		changes:   make(chan WatcherNotification),
+		notify:    make(chan Snapshot),
+		listeners: new(snapshotListeners),
+		history:   new(snapshotHistory),
 	}
 	cfg.Values = configValues{cfg: cfg, original: true}
 	return cfg
@@ -152,6 +152,10 @@ func (p *Provider) Notify() <-chan struct{} {
 	return p.notify
 }
 
+func init() {
+	config.RegisterSchema[ProviderConfig](configRootConsulConfigProvider)
+}
+
 func NewProvidersFromConfig(name string, cfg *config.Config) ([]config.Provider, error) {
 	var providerConfig = &ProviderConfig{}
 	var err = cfg.Populate(providerConfig, configRootConsulConfigProvider)
@@ -0,0 +1,127 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package encryption
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/nacl/box"
+)
+
+const boxKeySize = 32
+
+var ErrPublicKeyRequired = errors.New("Public key required for encryption")
+var ErrPrivateKeyRequired = errors.New("Private key required for decryption")
+var ErrInvalidKeySize = errors.New("Invalid key size")
+
+// BoxCipher encrypts values using NaCl anonymous sealed boxes.  Only the public key
+// is required to encrypt values, so it can be distributed to developers while the
+// private key remains with the deployment.
+type BoxCipher struct {
+	publicKey  *[boxKeySize]byte
+	privateKey *[boxKeySize]byte
+}
+
+func (c *BoxCipher) Encrypt(plaintext string) (string, error) {
+	if c.publicKey == nil {
+		return "", ErrPublicKeyRequired
+	}
+
+	sealed, err := box.SealAnonymous(nil, []byte(plaintext), c.publicKey, rand.Reader)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to encrypt plaintext")
+	}
+
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *BoxCipher) Decrypt(ciphertext string) (string, error) {
+	if c.privateKey == nil {
+		return "", ErrPrivateKeyRequired
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to decode ciphertext")
+	}
+
+	plaintext, ok := box.OpenAnonymous(nil, sealed, c.publicKey, c.privateKey)
+	if !ok {
+		return "", errors.New("Failed to decrypt ciphertext")
+	}
+
+	return string(plaintext), nil
+}
+
+// NewBoxCipher creates a cipher from a key pair.  When only the private key is supplied,
+// the public key is derived from it.
+func NewBoxCipher(publicKey, privateKey []byte) (*BoxCipher, error) {
+	var result BoxCipher
+
+	if privateKey != nil {
+		if len(privateKey) != boxKeySize {
+			return nil, errors.Wrap(ErrInvalidKeySize, "Private key")
+		}
+		result.privateKey = new([boxKeySize]byte)
+		copy(result.privateKey[:], privateKey)
+
+		if publicKey == nil {
+			derived, err := curve25519.X25519(privateKey, curve25519.Basepoint)
+			if err != nil {
+				return nil, errors.Wrap(err, "Failed to derive public key")
+			}
+			publicKey = derived
+		}
+	}
+
+	if publicKey != nil {
+		if len(publicKey) != boxKeySize {
+			return nil, errors.Wrap(ErrInvalidKeySize, "Public key")
+		}
+		result.publicKey = new([boxKeySize]byte)
+		copy(result.publicKey[:], publicKey)
+	}
+
+	if result.publicKey == nil {
+		return nil, ErrPublicKeyRequired
+	}
+
+	return &result, nil
+}
+
+// NewBoxCipherFromFiles creates a cipher from files containing base64-encoded keys.
+// Either file name may be empty.
+func NewBoxCipherFromFiles(publicKeyFile, privateKeyFile string) (*BoxCipher, error) {
+	var publicKey, privateKey []byte
+	var err error
+
+	if publicKeyFile != "" {
+		if publicKey, err = readEncodedFile(publicKeyFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if privateKeyFile != "" {
+		if privateKey, err = readEncodedFile(privateKeyFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return NewBoxCipher(publicKey, privateKey)
+}
+
+// GenerateBoxKeyPair returns a new base64-encoded key pair.
+func GenerateBoxKeyPair() (publicKey, privateKey string, err error) {
+	public, private, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", errors.Wrap(err, "Failed to generate key pair")
+	}
+
+	return base64.StdEncoding.EncodeToString(public[:]),
+		base64.StdEncoding.EncodeToString(private[:]),
+		nil
+}
@@ -0,0 +1,94 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package encryption
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
+	"cto-github.cisco.com/NFV-BU/go-msx/log"
+	"github.com/pkg/errors"
+)
+
+const (
+	configRootEncryption = "config.encrypt"
+
+	DecryptorKey     = "key"
+	DecryptorBox     = "box"
+	DecryptorTransit = "transit"
+)
+
+var logger = log.NewLogger("msx.config.encryption")
+
+type KeyConfig struct {
+	File string `config:"optional"`
+}
+
+type BoxConfig struct {
+	PublicKeyFile  string `config:"optional"`
+	PrivateKeyFile string `config:"optional"`
+}
+
+type TransitConfig struct {
+	Enabled bool   `config:"default=false"`
+	KeyName string `config:"default=msx-config"`
+}
+
+// Config selects the decryptors used for "{cipher}" values.  When Required is set,
+// startup fails if any values cannot be decrypted.
+type Config struct {
+	Decryptor string `config:"default=key"`
+	Required  bool   `config:"default=true"`
+	Key       KeyConfig
+	Box       BoxConfig
+	Transit   TransitConfig
+}
+
+func init() {
+	config.RegisterSchema[Config](configRootEncryption)
+}
+
+func NewConfig(cfg *config.Config) (*Config, error) {
+	var encryptionConfig Config
+	if err := cfg.Populate(&encryptionConfig, configRootEncryption); err != nil {
+		return nil, err
+	}
+	return &encryptionConfig, nil
+}
+
+// RegisterDecryptorsFromConfig registers the key file and NaCl box decryptors configured
+// in cfg, and selects the default decryptor.  The transit decryptor depends on a vault
+// connection, and is registered separately.
+func RegisterDecryptorsFromConfig(cfg *config.Config) (*Config, error) {
+	encryptionConfig, err := NewConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	config.SetDefaultDecryptor(encryptionConfig.Decryptor)
+
+	if encryptionConfig.Key.File != "" {
+		keyCipher, err := NewKeyCipherFromFile(encryptionConfig.Key.File)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to create key file decryptor")
+		}
+		logger.Debugf("Registering %q decryptor from %q", DecryptorKey, encryptionConfig.Key.File)
+		config.RegisterDecryptor(DecryptorKey, keyCipher)
+	}
+
+	if encryptionConfig.Box.PublicKeyFile != "" || encryptionConfig.Box.PrivateKeyFile != "" {
+		boxCipher, err := NewBoxCipherFromFiles(encryptionConfig.Box.PublicKeyFile, encryptionConfig.Box.PrivateKeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to create NaCl box decryptor")
+		}
+		if encryptionConfig.Box.PrivateKeyFile != "" {
+			logger.Debugf("Registering %q decryptor", DecryptorBox)
+			config.RegisterDecryptor(DecryptorBox, boxCipher)
+		} else {
+			logger.Debugf("Registering %q encryptor", DecryptorBox)
+			config.RegisterEncryptor(DecryptorBox, boxCipher)
+		}
+	}
+
+	return encryptionConfig, nil
+}
@@ -0,0 +1,135 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package encryption
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
+	"cto-github.cisco.com/NFV-BU/go-msx/testhelpers/configtest"
+	"encoding/base64"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeKeyFile(t *testing.T, name, contents string) string {
+	fileName := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(fileName, []byte(contents+"\n"), 0600))
+	return fileName
+}
+
+func TestKeyCipher(t *testing.T) {
+	key, err := GenerateKey()
+	assert.NoError(t, err)
+
+	keyCipher, err := NewKeyCipherFromFile(writeKeyFile(t, "config.key", key))
+	assert.NoError(t, err)
+
+	ciphertext, err := keyCipher.Encrypt("secret")
+	assert.NoError(t, err)
+	assert.NotContains(t, ciphertext, "secret")
+
+	plaintext, err := keyCipher.Decrypt(ciphertext)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", plaintext)
+
+	otherKey, _ := GenerateKey()
+	otherKeyBytes, _ := base64.StdEncoding.DecodeString(otherKey)
+	otherCipher, err := NewKeyCipher(otherKeyBytes)
+	assert.NoError(t, err)
+	_, err = otherCipher.Decrypt(ciphertext)
+	assert.Error(t, err)
+
+	_, err = keyCipher.Decrypt("AAAA")
+	assert.True(t, errors.Is(err, ErrCiphertextTooShort))
+
+	_, err = NewKeyCipher([]byte("short"))
+	assert.Error(t, err)
+}
+
+func TestBoxCipher(t *testing.T) {
+	publicKey, privateKey, err := GenerateBoxKeyPair()
+	assert.NoError(t, err)
+
+	publicKeyFile := writeKeyFile(t, "config.pub", publicKey)
+	privateKeyFile := writeKeyFile(t, "config.key", privateKey)
+
+	encryptor, err := NewBoxCipherFromFiles(publicKeyFile, "")
+	assert.NoError(t, err)
+
+	ciphertext, err := encryptor.Encrypt("secret")
+	assert.NoError(t, err)
+
+	_, err = encryptor.Decrypt(ciphertext)
+	assert.True(t, errors.Is(err, ErrPrivateKeyRequired))
+
+	// Public key derived from the private key
+	decryptor, err := NewBoxCipherFromFiles("", privateKeyFile)
+	assert.NoError(t, err)
+
+	plaintext, err := decryptor.Decrypt(ciphertext)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", plaintext)
+
+	_, err = NewBoxCipherFromFiles("", "")
+	assert.True(t, errors.Is(err, ErrPublicKeyRequired))
+
+	_, err = NewBoxCipher([]byte("short"), nil)
+	assert.True(t, errors.Is(err, ErrInvalidKeySize))
+}
+
+func TestRegisterDecryptorsFromConfig(t *testing.T) {
+	key, _ := GenerateKey()
+	keyFile := writeKeyFile(t, "config.key", key)
+
+	publicKey, privateKey, _ := GenerateBoxKeyPair()
+	boxPrivateKeyFile := writeKeyFile(t, "box.key", privateKey)
+
+	encryptionConfig, err := RegisterDecryptorsFromConfig(configtest.NewInMemoryConfig(map[string]string{
+		"config.encrypt.key.file":             keyFile,
+		"config.encrypt.box.private-key-file": boxPrivateKeyFile,
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, DecryptorKey, encryptionConfig.Decryptor)
+	assert.False(t, encryptionConfig.Transit.Enabled)
+
+	keyValue, err := config.EncryptValue(DecryptorKey, "alpha")
+	assert.NoError(t, err)
+
+	boxCipher, _ := NewBoxCipher(mustDecode(publicKey), nil)
+	boxCiphertext, _ := boxCipher.Encrypt("bravo")
+
+	_, keyCiphertext, err := config.ParseEncryptedValue(keyValue)
+	assert.NoError(t, err)
+
+	cfg := config.NewConfig(config.NewInMemoryProvider("static", map[string]string{
+		"alpha":   "{cipher}" + keyCiphertext,
+		"bravo":   config.EncryptedValue(DecryptorBox, boxCiphertext),
+		"charlie": "${alpha}-${bravo}",
+	}))
+	assert.NoError(t, cfg.Load(context.Background()))
+
+	charlie, err := cfg.String("charlie")
+	assert.NoError(t, err)
+	assert.Equal(t, "alpha-bravo", charlie)
+	assert.Empty(t, cfg.EncryptedKeys())
+}
+
+func TestRegisterDecryptorsFromConfig_MissingKeyFile(t *testing.T) {
+	_, err := RegisterDecryptorsFromConfig(configtest.NewInMemoryConfig(map[string]string{
+		"config.encrypt.key.file": filepath.Join(t.TempDir(), "missing.key"),
+	}))
+	assert.Error(t, err)
+}
+
+func mustDecode(value string) []byte {
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
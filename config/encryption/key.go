@@ -0,0 +1,102 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"github.com/pkg/errors"
+	"io"
+	"os"
+	"strings"
+)
+
+const keySize = 32
+
+var ErrCiphertextTooShort = errors.New("Ciphertext too short")
+
+// KeyCipher encrypts values using AES-GCM with a shared symmetric key.
+type KeyCipher struct {
+	aead cipher.AEAD
+}
+
+func (c *KeyCipher) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errors.Wrap(err, "Failed to generate nonce")
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *KeyCipher) Decrypt(ciphertext string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to decode ciphertext")
+	}
+
+	nonceSize := c.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", ErrCiphertextTooShort
+	}
+
+	plaintext, err := c.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to decrypt ciphertext")
+	}
+
+	return string(plaintext), nil
+}
+
+// NewKeyCipher creates a cipher from a 16, 24 or 32 byte AES key.
+func NewKeyCipher(key []byte) (*KeyCipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create block cipher")
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create AEAD cipher")
+	}
+
+	return &KeyCipher{aead: aead}, nil
+}
+
+// NewKeyCipherFromFile creates a cipher from a file containing a base64-encoded AES key.
+func NewKeyCipherFromFile(fileName string) (*KeyCipher, error) {
+	key, err := readEncodedFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyCipher(key)
+}
+
+// GenerateKey returns a new base64-encoded 256-bit AES key.
+func GenerateKey() (string, error) {
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", errors.Wrap(err, "Failed to generate key")
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+func readEncodedFile(fileName string) ([]byte, error) {
+	contents, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read key file %q", fileName)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(contents)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to decode key file %q", fileName)
+	}
+
+	return data, nil
+}
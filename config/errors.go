@@ -16,6 +16,13 @@ var ErrCircularReference = errors.New("Circular reference detected")
 var ErrParseInvalidCloseBrace = errors.New("Invalid close brace")
 var ErrParseInvalidVariableReference = errors.New("Invalid reference in variable name")
 var ErrParseUnexpectedInput = errors.New("Unexpected input")
+var ErrParseUnterminatedFunction = errors.New("Unterminated function call")
+var ErrParseUnterminatedString = errors.New("Unterminated string")
+
+var ErrUnknownFunction = errors.New("Unknown function")
+var ErrFunctionArguments = errors.New("Wrong number of function arguments")
+var ErrInvalidFunctionArgument = errors.New("Invalid function argument")
+var ErrFileNotPermitted = errors.New("File not within a permitted directory")
 
 var ErrInvalidValue = errors.New("Failed to parse value")
 var ErrValueCannotBeSet = errors.New("Cannot set value of targets")
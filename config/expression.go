@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"github.com/pkg/errors"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 )
 
@@ -145,7 +146,7 @@ func parseExpressionAtDepth(si expressionScanner, depth int) (expr expression, s
 	for s.Current() != 0 {
 		if s.Matches("${") {
 			// Parse Variable
-			var varExpr variableExpression
+			var varExpr expression
 			varExpr, s, err = parseVariable(s, depth+1)
 			if err != nil {
 				return
@@ -175,7 +176,7 @@ func parseExpressionAtDepth(si expressionScanner, depth int) (expr expression, s
 	return
 }
 
-func parseVariable(si expressionScanner, depth int) (expr variableExpression, s expressionScanner, err error) {
+func parseVariable(si expressionScanner, depth int) (expr expression, s expressionScanner, err error) {
 	s = si
 
 	s, err = s.SkipOver("${")
@@ -189,6 +190,17 @@ func parseVariable(si expressionScanner, depth int) (expr variableExpression, s
 		return
 	}
 
+	if s.Current() == '(' {
+		// Parse Function Call
+		expr, s, err = parseFunctionCall(name, s, depth)
+		if err != nil {
+			return
+		}
+
+		s, err = s.SkipOver("}")
+		return
+	}
+
 	var defaultExpr expression
 	var requiredKey, requiredValue bool
 	switch {
@@ -249,7 +261,7 @@ func parseKeyName(si expressionScanner) (name string, s expressionScanner, err e
 	s = si
 	for s.Current() != 0 {
 		switch s.Current() {
-		case ':', '}':
+		case ':', '}', '(':
 			name = s.Value[si.Pos:s.Pos]
 			return
 		}
@@ -263,3 +275,200 @@ func parseKeyName(si expressionScanner) (name string, s expressionScanner, err e
 	name = s.Value[si.Pos:]
 	return
 }
+
+type functionExpression struct {
+	Name string
+	Args []expression
+}
+
+func (f functionExpression) Resolve(r ExpressionResolver) (string, error) {
+	fn, ok := expressionFunctions[f.Name]
+	if !ok {
+		return "", errors.Wrapf(ErrUnknownFunction, "Function %q", f.Name)
+	}
+
+	result, err := fn.Call(r, f.Args)
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to evaluate %s()", f.Name)
+	}
+
+	return result, nil
+}
+
+// expressionSensitive returns true if the expression calls a sensitive function.
+func expressionSensitive(expr expression) bool {
+	switch typed := expr.(type) {
+	case concatenateExpression:
+		for _, part := range typed.Parts {
+			if expressionSensitive(part) {
+				return true
+			}
+		}
+	case variableExpression:
+		return typed.Default != nil && expressionSensitive(typed.Default)
+	case functionExpression:
+		if expressionFunctions[typed.Name].Sensitive {
+			return true
+		}
+		for _, arg := range typed.Args {
+			if expressionSensitive(arg) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseFunctionCall parses the parenthesized argument list of a function call.
+// Arguments may be quoted strings, variables, nested function calls, or bare
+// literals, and are separated by commas.
+func parseFunctionCall(name string, si expressionScanner, depth int) (expr functionExpression, s expressionScanner, err error) {
+	s = si
+
+	fn, ok := expressionFunctions[name]
+	if !ok {
+		err = errors.Wrapf(ErrUnknownFunction, "Function %q", name)
+		return
+	}
+
+	s, err = s.SkipOver("(")
+	if err != nil {
+		return
+	}
+
+	expr = functionExpression{Name: name}
+
+	s = skipSpace(s)
+	if s.Current() == ')' {
+		s = s.Skip()
+	} else {
+		for {
+			var arg expression
+			arg, s, err = parseArgument(s, depth)
+			if err != nil {
+				return
+			}
+			expr.Args = append(expr.Args, arg)
+
+			if s.Current() == ',' {
+				s = s.Skip()
+				continue
+			}
+
+			s, err = s.SkipOver(")")
+			if err != nil {
+				return
+			}
+			break
+		}
+	}
+
+	err = fn.CheckArgs(name, len(expr.Args))
+	return
+}
+
+func parseArgument(si expressionScanner, depth int) (expr expression, s expressionScanner, err error) {
+	s = skipSpace(si)
+	result := concatenateExpression{}
+
+	for {
+		switch {
+		case s.Current() == 0:
+			err = errors.Wrap(ErrParseUnterminatedFunction, "Expected ')'")
+			return
+
+		case s.Current() == ',' || s.Current() == ')':
+			if len(result.Parts) == 0 {
+				expr = literalExpression("")
+			} else if len(result.Parts) == 1 {
+				expr = result.Parts[0]
+			} else {
+				expr = result
+			}
+			return
+
+		case s.Current() == '\'' || s.Current() == '"':
+			var litExpr literalExpression
+			litExpr, s, err = parseQuoted(s)
+			if err != nil {
+				return
+			}
+			result.Parts = append(result.Parts, litExpr)
+
+		case s.Matches("${"):
+			var varExpr expression
+			varExpr, s, err = parseVariable(s, depth+1)
+			if err != nil {
+				return
+			}
+			result.Parts = append(result.Parts, varExpr)
+
+		default:
+			start := s
+			for s.Current() != 0 && !strings.ContainsRune(`,)('"`, s.Current()) && !s.Matches("${") {
+				s.Pos++
+			}
+			text := s.Value[start.Pos:s.Pos]
+
+			if s.Current() == '(' {
+				var fnExpr functionExpression
+				fnExpr, s, err = parseFunctionCall(strings.TrimSpace(text), s, depth)
+				if err != nil {
+					return
+				}
+				result.Parts = append(result.Parts, fnExpr)
+				s = skipSpace(s)
+				continue
+			}
+
+			if s.Current() == ',' || s.Current() == ')' {
+				text = strings.TrimRightFunc(text, unicode.IsSpace)
+			}
+
+			if text != "" {
+				result.Parts = append(result.Parts, literalExpression(text))
+			}
+		}
+	}
+}
+
+// parseQuoted parses a single or double-quoted string.  A backslash escapes the
+// following character.
+func parseQuoted(si expressionScanner) (expr literalExpression, s expressionScanner, err error) {
+	s = si
+	quote := s.Current()
+	s = s.Skip()
+
+	var result strings.Builder
+	for {
+		switch s.Current() {
+		case 0:
+			err = errors.Wrapf(ErrParseUnterminatedString, "Expected %q", quote)
+			return
+
+		case '\\':
+			s = s.Skip()
+			if s.Current() == 0 {
+				continue
+			}
+
+		case quote:
+			s = s.Skip()
+			expr = literalExpression(result.String())
+			s = skipSpace(s)
+			return
+		}
+
+		r := s.Current()
+		result.WriteRune(r)
+		s.Pos += utf8.RuneLen(r)
+	}
+}
+
+func skipSpace(si expressionScanner) expressionScanner {
+	s := si
+	for s.Current() != 0 && unicode.IsSpace(s.Current()) {
+		s.Pos += utf8.RuneLen(s.Current())
+	}
+	return s
+}
@@ -0,0 +1,342 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package config
+
+import (
+	"encoding/base64"
+	"github.com/pkg/errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	configKeyProfile = "profile"
+	variadic         = -1
+)
+
+// expressionFunction implements a function callable from a placeholder, e.g. "${upper(${name})}".
+// Arguments are passed unevaluated, so functions such as default() and ifprofile() only
+// evaluate the arguments they use.
+//
+// Sensitive functions read from outside the configuration (files, environment), so their
+// results are flagged in the provenance of the resolved value, and masked when displayed.
+type expressionFunction struct {
+	MinArgs   int
+	MaxArgs   int
+	Sensitive bool
+	Call      func(r ExpressionResolver, args []expression) (string, error)
+}
+
+func (f expressionFunction) CheckArgs(name string, n int) error {
+	if n < f.MinArgs || (f.MaxArgs != variadic && n > f.MaxArgs) {
+		switch {
+		case f.MinArgs == f.MaxArgs:
+			return errors.Wrapf(ErrFunctionArguments, "%s() expects %d, found %d", name, f.MinArgs, n)
+		case f.MaxArgs == variadic:
+			return errors.Wrapf(ErrFunctionArguments, "%s() expects at least %d, found %d", name, f.MinArgs, n)
+		default:
+			return errors.Wrapf(ErrFunctionArguments, "%s() expects %d to %d, found %d", name, f.MinArgs, f.MaxArgs, n)
+		}
+	}
+	return nil
+}
+
+var expressionFunctions = map[string]expressionFunction{
+	"upper":        unaryFunction(func(value string) (string, error) { return strings.ToUpper(value), nil }),
+	"lower":        unaryFunction(func(value string) (string, error) { return strings.ToLower(value), nil }),
+	"trim":         unaryFunction(func(value string) (string, error) { return strings.TrimSpace(value), nil }),
+	"base64":       unaryFunction(base64Encode),
+	"base64decode": unaryFunction(base64Decode),
+	"file":         sensitiveFunction(unaryFunction(fileContents)),
+	"env":          {MinArgs: 1, MaxArgs: 2, Sensitive: true, Call: environmentValue},
+	"default":      {MinArgs: 1, MaxArgs: variadic, Call: firstNonEmpty},
+	"add":          binaryFunction(add),
+	"sub":          binaryFunction(subtract),
+	"mul":          binaryFunction(multiply),
+	"div":          binaryFunction(divide),
+	"ifprofile":    {MinArgs: 2, MaxArgs: 3, Call: ifProfile},
+}
+
+func unaryFunction(fn func(value string) (string, error)) expressionFunction {
+	return expressionFunction{
+		MinArgs: 1,
+		MaxArgs: 1,
+		Call: func(r ExpressionResolver, args []expression) (string, error) {
+			value, err := args[0].Resolve(r)
+			if err != nil {
+				return "", err
+			}
+			return fn(value)
+		},
+	}
+}
+
+func sensitiveFunction(fn expressionFunction) expressionFunction {
+	fn.Sensitive = true
+	return fn
+}
+
+func binaryFunction(fn func(left, right string) (string, error)) expressionFunction {
+	return expressionFunction{
+		MinArgs: 2,
+		MaxArgs: 2,
+		Call: func(r ExpressionResolver, args []expression) (string, error) {
+			values, err := resolveArgs(r, args)
+			if err != nil {
+				return "", err
+			}
+			return fn(values[0], values[1])
+		},
+	}
+}
+
+func resolveArgs(r ExpressionResolver, args []expression) ([]string, error) {
+	var values = make([]string, len(args))
+	for i, arg := range args {
+		value, err := arg.Resolve(r)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func base64Encode(value string) (string, error) {
+	return base64.StdEncoding.EncodeToString([]byte(value)), nil
+}
+
+func base64Decode(value string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", errors.Wrap(ErrInvalidFunctionArgument, err.Error())
+	}
+	return string(data), nil
+}
+
+var fileFunctionDirectoriesMtx sync.Mutex
+var fileFunctionDirectories = []string{"/run/secrets", "/var/run/secrets"}
+
+// AllowFileFunctionDirectories permits the file() placeholder function to read files
+// within the specified directories, in addition to the default secret mount points.
+func AllowFileFunctionDirectories(directories ...string) {
+	fileFunctionDirectoriesMtx.Lock()
+	defer fileFunctionDirectoriesMtx.Unlock()
+	fileFunctionDirectories = append(fileFunctionDirectories, directories...)
+}
+
+// fileFunctionPermitted returns true if the path is within one of the permitted directories.
+func fileFunctionPermitted(path string) bool {
+	fileFunctionDirectoriesMtx.Lock()
+	defer fileFunctionDirectoriesMtx.Unlock()
+
+	for _, directory := range fileFunctionDirectories {
+		directory, err := filepath.Abs(directory)
+		if err != nil {
+			continue
+		}
+
+		if resolvedDirectory, err := filepath.EvalSymlinks(directory); err == nil {
+			directory = resolvedDirectory
+		}
+
+		relative, err := filepath.Rel(directory, path)
+		if err == nil && relative != ".." && !strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fileContents returns the contents of the named file, without any trailing line break.
+// The file must be within one of the permitted directories, after following symbolic links.
+func fileContents(fileName string) (string, error) {
+	path, err := filepath.Abs(fileName)
+	if err != nil {
+		return "", err
+	}
+
+	if !fileFunctionPermitted(path) {
+		return "", errors.Wrapf(ErrFileNotPermitted, "File %q", fileName)
+	}
+
+	if path, err = filepath.EvalSymlinks(path); err != nil {
+		return "", err
+	}
+
+	if !fileFunctionPermitted(path) {
+		return "", errors.Wrapf(ErrFileNotPermitted, "File %q", fileName)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// environmentValue returns the named environment variable, or the default when it is not set.
+func environmentValue(r ExpressionResolver, args []expression) (string, error) {
+	name, err := args[0].Resolve(r)
+	if err != nil {
+		return "", err
+	}
+
+	if value, ok := os.LookupEnv(name); ok {
+		return value, nil
+	}
+
+	if len(args) > 1 {
+		return args[1].Resolve(r)
+	}
+
+	return "", nil
+}
+
+// firstNonEmpty returns the first argument with a non-empty value.
+func firstNonEmpty(r ExpressionResolver, args []expression) (string, error) {
+	for _, arg := range args {
+		value, err := arg.Resolve(r)
+		if err != nil {
+			return "", err
+		}
+		if value != "" {
+			return value, nil
+		}
+	}
+	return "", nil
+}
+
+// ifProfile returns the second argument when the named profile is active, otherwise the third.
+func ifProfile(r ExpressionResolver, args []expression) (string, error) {
+	profile, err := args[0].Resolve(r)
+	if err != nil {
+		return "", err
+	}
+
+	active := false
+	if entry, err := r.ResolveByName(configKeyProfile); err == nil {
+		for _, activeProfile := range strings.Split(entry.ResolvedValue.String(), ",") {
+			if strings.TrimSpace(activeProfile) == profile {
+				active = true
+				break
+			}
+		}
+	} else if !errors.Is(err, ErrNotFound) {
+		return "", err
+	}
+
+	switch {
+	case active:
+		return args[1].Resolve(r)
+	case len(args) > 2:
+		return args[2].Resolve(r)
+	default:
+		return "", nil
+	}
+}
+
+type arithmeticOperands struct {
+	integers  [2]int64
+	durations [2]time.Duration
+	duration  bool
+}
+
+// parseOperands interprets both values as integers, or both as durations.
+func parseOperands(left, right string) (result arithmeticOperands, err error) {
+	for i, value := range []string{left, right} {
+		if result.integers[i], err = strconv.ParseInt(strings.TrimSpace(value), 10, 64); err != nil {
+			break
+		}
+	}
+
+	if err == nil {
+		return result, nil
+	}
+
+	result.duration = true
+	for i, value := range []string{left, right} {
+		if result.durations[i], err = time.ParseDuration(strings.TrimSpace(value)); err != nil {
+			return result, errors.Wrapf(ErrInvalidFunctionArgument, "Expected integers or durations, found %q and %q", left, right)
+		}
+	}
+
+	return result, nil
+}
+
+func add(left, right string) (string, error) {
+	operands, err := parseOperands(left, right)
+	if err != nil {
+		return "", err
+	}
+
+	if operands.duration {
+		return (operands.durations[0] + operands.durations[1]).String(), nil
+	}
+	return strconv.FormatInt(operands.integers[0]+operands.integers[1], 10), nil
+}
+
+func subtract(left, right string) (string, error) {
+	operands, err := parseOperands(left, right)
+	if err != nil {
+		return "", err
+	}
+
+	if operands.duration {
+		return (operands.durations[0] - operands.durations[1]).String(), nil
+	}
+	return strconv.FormatInt(operands.integers[0]-operands.integers[1], 10), nil
+}
+
+// parseScaledOperands interprets the left value as an integer or duration, and the right value as an integer.
+func parseScaledOperands(left, right string) (operands arithmeticOperands, err error) {
+	if operands.integers[1], err = strconv.ParseInt(strings.TrimSpace(right), 10, 64); err != nil {
+		return operands, errors.Wrapf(ErrInvalidFunctionArgument, "Expected integer, found %q", right)
+	}
+
+	if operands.integers[0], err = strconv.ParseInt(strings.TrimSpace(left), 10, 64); err == nil {
+		return operands, nil
+	}
+
+	operands.duration = true
+	if operands.durations[0], err = time.ParseDuration(strings.TrimSpace(left)); err != nil {
+		return operands, errors.Wrapf(ErrInvalidFunctionArgument, "Expected integer or duration, found %q", left)
+	}
+
+	return operands, nil
+}
+
+func multiply(left, right string) (string, error) {
+	operands, err := parseScaledOperands(left, right)
+	if err != nil {
+		return "", err
+	}
+
+	if operands.duration {
+		return (operands.durations[0] * time.Duration(operands.integers[1])).String(), nil
+	}
+	return strconv.FormatInt(operands.integers[0]*operands.integers[1], 10), nil
+}
+
+func divide(left, right string) (string, error) {
+	operands, err := parseScaledOperands(left, right)
+	if err != nil {
+		return "", err
+	}
+
+	if operands.integers[1] == 0 {
+		return "", errors.Wrap(ErrInvalidFunctionArgument, "Division by zero")
+	}
+
+	if operands.duration {
+		return (operands.durations[0] / time.Duration(operands.integers[1])).String(), nil
+	}
+	return strconv.FormatInt(operands.integers[0]/operands.integers[1], 10), nil
+}
@@ -5,6 +5,11 @@
 package config
 
 import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 )
@@ -131,6 +136,177 @@ func TestParseExpression(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "Function",
+			value: "${upper(value)}",
+			wantExpr: functionExpression{
+				Name: "upper",
+				Args: []expression{literalExpression("value")},
+			},
+		},
+		{
+			name:    "FunctionNoArgs",
+			value:   "${default()}",
+			wantErr: true,
+		},
+		{
+			name:  "FunctionVariableArg",
+			value: "${upper(${spring.application.name})}",
+			wantExpr: functionExpression{
+				Name: "upper",
+				Args: []expression{variableExpression{Name: "spring.application.name"}},
+			},
+		},
+		{
+			name:  "FunctionMultipleArgs",
+			value: "${default( ${a} , 'b, c' ,\"d\")}",
+			wantExpr: functionExpression{
+				Name: "default",
+				Args: []expression{
+					variableExpression{Name: "a"},
+					literalExpression("b, c"),
+					literalExpression("d"),
+				},
+			},
+		},
+		{
+			name:  "FunctionEmptyArg",
+			value: "${default(,x)}",
+			wantExpr: functionExpression{
+				Name: "default",
+				Args: []expression{
+					literalExpression(""),
+					literalExpression("x"),
+				},
+			},
+		},
+		{
+			name:  "FunctionQuotedEscape",
+			value: `${upper('it\'s \\ ok')}`,
+			wantExpr: functionExpression{
+				Name: "upper",
+				Args: []expression{literalExpression(`it's \ ok`)},
+			},
+		},
+		{
+			name:  "FunctionConcatenatedArg",
+			value: "${upper(a-${b}-'c')}",
+			wantExpr: functionExpression{
+				Name: "upper",
+				Args: []expression{
+					concatenateExpression{
+						Parts: []expression{
+							literalExpression("a-"),
+							variableExpression{Name: "b"},
+							literalExpression("-"),
+							literalExpression("c"),
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "FunctionNested",
+			value: "${upper(lower(base64(x)))}",
+			wantExpr: functionExpression{
+				Name: "upper",
+				Args: []expression{
+					functionExpression{
+						Name: "lower",
+						Args: []expression{
+							functionExpression{
+								Name: "base64",
+								Args: []expression{literalExpression("x")},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "FunctionInVariableDefault",
+			value: "${timeout:${mul(5s, 2)}}",
+			wantExpr: variableExpression{
+				Name: "timeout",
+				Default: functionExpression{
+					Name: "mul",
+					Args: []expression{literalExpression("5s"), literalExpression("2")},
+				},
+			},
+		},
+		{
+			name:  "FunctionConcatenated",
+			value: "prefix-${lower(A)}-suffix",
+			wantExpr: concatenateExpression{
+				Parts: []expression{
+					literalExpression("prefix-"),
+					functionExpression{
+						Name: "lower",
+						Args: []expression{literalExpression("A")},
+					},
+					literalExpression("-suffix"),
+				},
+			},
+		},
+		{
+			name:  "FunctionVariadic",
+			value: "${default(a,b,c,d,e)}",
+			wantExpr: functionExpression{
+				Name: "default",
+				Args: []expression{
+					literalExpression("a"),
+					literalExpression("b"),
+					literalExpression("c"),
+					literalExpression("d"),
+					literalExpression("e"),
+				},
+			},
+		},
+		{
+			name:    "FunctionUnknown",
+			value:   "${frobnicate(x)}",
+			wantErr: true,
+		},
+		{
+			name:    "FunctionUnknownNested",
+			value:   "${upper(frobnicate(x))}",
+			wantErr: true,
+		},
+		{
+			name:    "FunctionTooFewArgs",
+			value:   "${add(1)}",
+			wantErr: true,
+		},
+		{
+			name:    "FunctionTooManyArgs",
+			value:   "${upper(a,b)}",
+			wantErr: true,
+		},
+		{
+			name:    "FunctionUnterminated",
+			value:   "${upper(a}",
+			wantErr: true,
+		},
+		{
+			name:    "FunctionUnterminatedString",
+			value:   "${upper('a)}",
+			wantErr: true,
+		},
+		{
+			name:    "FunctionMissingCloseBrace",
+			value:   "${upper(a)",
+			wantErr: true,
+		},
+		{
+			name:    "FunctionTrailingInput",
+			value:   "${upper(a) b}",
+			wantErr: true,
+		},
+		{
+			name:    "FunctionUnterminatedVariableArg",
+			value:   "${upper(${a)}",
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -145,3 +321,138 @@ func TestParseExpression(t *testing.T) {
 		})
 	}
 }
+
+func TestExpression_Functions(t *testing.T) {
+	t.Setenv("EXPRESSION_TEST_VAR", "from-env")
+
+	secretDir := t.TempDir()
+	AllowFileFunctionDirectories(secretDir)
+
+	fileName := filepath.Join(secretDir, "secret.txt")
+	assert.NoError(t, os.WriteFile(fileName, []byte("file-secret\n"), 0600))
+
+	outsideFileName := filepath.Join(t.TempDir(), "outside.txt")
+	assert.NoError(t, os.WriteFile(outsideFileName, []byte("outside-secret\n"), 0600))
+
+	linkFileName := filepath.Join(secretDir, "link.txt")
+	assert.NoError(t, os.Symlink(outsideFileName, linkFileName))
+
+	settings := map[string]string{
+		"name":        "Alpha",
+		"empty":       "",
+		"timeout":     "10s",
+		"count":       "3",
+		"profile":     "dev, test",
+		"secret.file": fileName,
+		"secret.dir":  secretDir,
+		"outside":     outsideFileName,
+		"link":        linkFileName,
+		"encoded":     "c2VjcmV0",
+		"self":        "${upper(${self})}",
+		"loop.a":      "${lower(${loop.b})}",
+		"loop.b":      "${default(${loop.a}, x)}",
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr error
+	}{
+		{name: "Upper", value: "${upper(${name})}", want: "ALPHA"},
+		{name: "Lower", value: "${lower(${name})}", want: "alpha"},
+		{name: "Trim", value: "${trim(' padded ')}", want: "padded"},
+		{name: "Base64", value: "${base64(secret)}", want: "c2VjcmV0"},
+		{name: "Base64Decode", value: "${base64decode(${encoded})}", want: "secret"},
+		{name: "Base64DecodeInvalid", value: "${base64decode('!')}", wantErr: ErrInvalidFunctionArgument},
+		{name: "Env", value: "${env(EXPRESSION_TEST_VAR)}", want: "from-env"},
+		{name: "EnvDefault", value: "${env(EXPRESSION_TEST_MISSING, fallback)}", want: "fallback"},
+		{name: "EnvMissing", value: "${env(EXPRESSION_TEST_MISSING)}", want: ""},
+		{name: "File", value: "${file(${secret.file})}", want: "file-secret"},
+		{name: "FileMissing", value: "${file(${secret.dir}/missing.txt)}", wantErr: os.ErrNotExist},
+		{name: "FileNotPermitted", value: "${file(${outside})}", wantErr: ErrFileNotPermitted},
+		{name: "FileTraversal", value: "${file(${secret.dir}/../outside.txt)}", wantErr: ErrFileNotPermitted},
+		{name: "FileSymlinkEscape", value: "${file(${link})}", wantErr: ErrFileNotPermitted},
+		{name: "DefaultFirst", value: "${default(${name}, b)}", want: "Alpha"},
+		{name: "DefaultChained", value: "${default(${empty}, ${missing}, c)}", want: "c"},
+		{name: "DefaultAllEmpty", value: "${default(${empty}, ${missing})}", want: ""},
+		{name: "DefaultLazy", value: "${default(a, ${file('/nonexistent/file')})}", want: "a"},
+		{name: "AddIntegers", value: "${add(${count}, 4)}", want: "7"},
+		{name: "AddDurations", value: "${add(${timeout}, 500ms)}", want: "10.5s"},
+		{name: "AddMixed", value: "${add(${timeout}, 5)}", wantErr: ErrInvalidFunctionArgument},
+		{name: "SubIntegers", value: "${sub(1, ${count})}", want: "-2"},
+		{name: "SubDurations", value: "${sub(1m, ${timeout})}", want: "50s"},
+		{name: "MulInteger", value: "${mul(${count}, 4)}", want: "12"},
+		{name: "MulDuration", value: "${mul(${timeout}, ${count})}", want: "30s"},
+		{name: "MulDurations", value: "${mul(1s, 1s)}", wantErr: ErrInvalidFunctionArgument},
+		{name: "DivInteger", value: "${div(7, 2)}", want: "3"},
+		{name: "DivDuration", value: "${div(${timeout}, 4)}", want: "2.5s"},
+		{name: "DivZero", value: "${div(1, 0)}", wantErr: ErrInvalidFunctionArgument},
+		{name: "DivInvalid", value: "${div(abc, 2)}", wantErr: ErrInvalidFunctionArgument},
+		{name: "IfProfileActive", value: "${ifprofile(test, yes, no)}", want: "yes"},
+		{name: "IfProfileInactive", value: "${ifprofile(prod, yes, no)}", want: "no"},
+		{name: "IfProfileInactiveNoElse", value: "${ifprofile(prod, yes)}", want: ""},
+		{name: "IfProfileLazy", value: "${ifprofile(dev, ok, ${file('/nonexistent/file')})}", want: "ok"},
+		{name: "Nested", value: "${upper(${default(${empty}, ${lower(BRAVO)})})}", want: "BRAVO"},
+		{name: "Concatenated", value: "${lower(${name})}-${mul(2, 2)}", want: "alpha-4"},
+		{name: "VariableDefault", value: "${missing:${add(1, 1)}}", want: "2"},
+		{name: "SelfReference", value: "${self}", wantErr: ErrCircularReference},
+		{name: "IndirectCycle", value: "${loop.a}", wantErr: ErrCircularReference},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := NewInMemoryProvider("static", settings).Load(context.Background())
+			assert.NoError(t, err)
+			resolver := NewResolver(entries)
+
+			expr, err := parseExpression(tt.value)
+			assert.NoError(t, err)
+
+			got, err := expr.Resolve(&resolver)
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr), "Expected %v, got %v", tt.wantErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExpression_ErrorMessages(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{
+			name:  "UnknownFunction",
+			value: "${frobnicate(x)}",
+			want:  `Function "frobnicate": Unknown function`,
+		},
+		{
+			name:  "Arity",
+			value: "${add(1)}",
+			want:  "add() expects 2, found 1: Wrong number of function arguments",
+		},
+		{
+			name:  "VariadicArity",
+			value: "${default()}",
+			want:  "default() expects at least 1, found 0: Wrong number of function arguments",
+		},
+		{
+			name:  "RangeArity",
+			value: "${env()}",
+			want:  "env() expects 1 to 2, found 0: Wrong number of function arguments",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseExpression(tt.value)
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package kubernetesprovider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// kubeletDataLink is the symlink atomically swapped by the kubelet when a mounted
+// ConfigMap or Secret is updated.
+const kubeletDataLink = "..data"
+
+// DirectoryNotifier polls a directory tree for changes.  The kubelet updates mounted
+// volumes by writing a new timestamped directory and swapping the "..data" symlink,
+// so the notifier compares the symlink target along with the size and modification
+// time of each (symlink-resolved) file.
+type DirectoryNotifier struct {
+	root     string
+	interval time.Duration
+	notify   chan struct{}
+}
+
+func (n *DirectoryNotifier) Notify() <-chan struct{} {
+	return n.notify
+}
+
+func (n *DirectoryNotifier) Run(ctx context.Context) {
+	signature, err := directorySignature(n.root)
+	if err != nil {
+		logger.WithContext(ctx).WithError(err).Warnf("Failed to scan config directory %q", n.root)
+	}
+
+	ticker := time.NewTicker(n.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			latest, err := directorySignature(n.root)
+			if err != nil {
+				// Retry on the next tick: a swap may be in progress
+				logger.WithContext(ctx).WithError(err).Warnf("Failed to scan config directory %q", n.root)
+				continue
+			}
+
+			if latest == signature {
+				continue
+			}
+
+			logger.WithContext(ctx).Infof("Change detected in config directory %q", n.root)
+			signature = latest
+
+			select {
+			case n.notify <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// directorySignature summarizes the current state of the directory tree.
+func directorySignature(root string) (string, error) {
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return "", nil
+	}
+
+	hash := sha256.New()
+
+	if target, err := os.Readlink(filepath.Join(root, kubeletDataLink)); err == nil {
+		_, _ = fmt.Fprintf(hash, "%s\n", target)
+	}
+
+	err := walkDirectory(root, "", func(relativeName, _ string, info fs.FileInfo) error {
+		_, err := fmt.Fprintf(hash, "%s:%d:%d\n", relativeName, info.Size(), info.ModTime().UnixNano())
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func NewDirectoryNotifier(root string, interval time.Duration) *DirectoryNotifier {
+	return &DirectoryNotifier{
+		root:     root,
+		interval: interval,
+		notify:   make(chan struct{}),
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package kubernetesprovider
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
+	"cto-github.cisco.com/NFV-BU/go-msx/log"
+	"fmt"
+	"github.com/pkg/errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	configRootKubernetesConfigProvider = "spring.cloud.kubernetes.config"
+)
+
+var logger = log.NewLogger("msx.config.kubernetesprovider")
+
+type ProviderConfig struct {
+	Enabled  bool          `config:"default=false"`
+	Paths    []string      `config:"default=/etc/config"`
+	Decode   bool          `config:"default=true"`
+	Watch    bool          `config:"default=true"`
+	Interval time.Duration `config:"default=5s"`
+}
+
+// Provider loads settings from a directory tree, such as a mounted ConfigMap or Secret.
+// Each file name (relative to the root) is used as the key, and the file contents as the value.
+type Provider struct {
+	name   string
+	root   string
+	decode bool
+	config.Notifier
+}
+
+func (p *Provider) Description() string {
+	return fmt.Sprintf("%s: [%s]", p.name, p.root)
+}
+
+func (p *Provider) Load(ctx context.Context) (entries config.ProviderEntries, err error) {
+	if _, err = os.Stat(p.root); errors.Is(err, fs.ErrNotExist) {
+		logger.WithContext(ctx).Warnf("Configuration directory %q not found", p.root)
+		return nil, nil
+	}
+
+	err = walkDirectory(p.root, "", func(relativeName, fileName string, _ fs.FileInfo) error {
+		if format := p.documentFormat(fileName); format != nil {
+			documentEntries, err := format(config.FileContentReader(fileName), p)
+			if err != nil {
+				return errors.Wrapf(err, "Failed to decode %q", fileName)
+			}
+
+			prefix := fileKey(path.Dir(relativeName))
+			for _, entry := range documentEntries {
+				entries = append(entries, config.NewEntry(p, config.PrefixWithName(prefix, entry.Name), entry.Value))
+			}
+			return nil
+		}
+
+		value, err := os.ReadFile(fileName)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to read %q", fileName)
+		}
+
+		entries = append(entries, config.NewEntry(p, fileKey(relativeName), string(value)))
+		return nil
+	})
+
+	return entries, err
+}
+
+// documentFormat returns the parser for files containing multiple settings, when decoding is enabled.
+func (p *Provider) documentFormat(fileName string) config.ContentFormat {
+	if !p.decode {
+		return nil
+	}
+
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".yml", ".yaml":
+		return config.ParseYaml
+	case ".properties":
+		return config.ParseProperties
+	}
+
+	return nil
+}
+
+// fileKey converts a relative file name to a configuration key.
+func fileKey(relativeName string) string {
+	if relativeName == "." {
+		return ""
+	}
+	return strings.ReplaceAll(relativeName, "/", ".")
+}
+
+// walkDirectory visits each file below root, following symlinks.  Hidden entries, including
+// the timestamped directories and "..data" symlink maintained by the kubelet, are skipped.
+func walkDirectory(root, prefix string, fn func(relativeName, fileName string, info fs.FileInfo) error) error {
+	dirEntries, err := os.ReadDir(root)
+	if err != nil {
+		return err
+	}
+
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		fileName := filepath.Join(root, name)
+		info, err := os.Stat(fileName)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to stat %q", fileName)
+		}
+
+		relativeName := path.Join(prefix, name)
+		if info.IsDir() {
+			err = walkDirectory(fileName, relativeName, fn)
+		} else {
+			err = fn(relativeName, fileName, info)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func NewProvider(name, root string, decode bool, notifier config.Notifier) *Provider {
+	if notifier == nil {
+		notifier = config.SilentNotifier{}
+	}
+
+	return &Provider{
+		name:     name,
+		root:     root,
+		decode:   decode,
+		Notifier: notifier,
+	}
+}
+
+func init() {
+	config.RegisterSchema[ProviderConfig](configRootKubernetesConfigProvider)
+}
+
+func NewProvidersFromConfig(name string, cfg *config.Config) ([]config.Provider, error) {
+	var providerConfig ProviderConfig
+	if err := cfg.Populate(&providerConfig, configRootKubernetesConfigProvider); err != nil {
+		return nil, err
+	}
+
+	if !providerConfig.Enabled {
+		logger.Info("Kubernetes configuration source disabled")
+		return nil, nil
+	}
+
+	var providers []config.Provider
+	for _, root := range providerConfig.Paths {
+		var notifier config.Notifier
+		if providerConfig.Watch {
+			notifier = NewDirectoryNotifier(root, providerConfig.Interval)
+		}
+
+		providers = append(providers, NewProvider(name, root, providerConfig.Decode, notifier))
+	}
+
+	return providers, nil
+}
@@ -0,0 +1,203 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package kubernetesprovider
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
+	"cto-github.cisco.com/NFV-BU/go-msx/testhelpers/configtest"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeVolume lays out files the same way as the kubelet atomic writer: a timestamped
+// data directory, a "..data" symlink to it, and a top-level symlink for each file.
+func writeVolume(t *testing.T, root, version string, files map[string]string) {
+	dataDir := filepath.Join(root, "..data_"+version)
+	for name, contents := range files {
+		fileName := filepath.Join(dataDir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(fileName), 0755))
+		assert.NoError(t, os.WriteFile(fileName, []byte(contents), 0644))
+	}
+
+	tmpLink := filepath.Join(root, "..data_tmp")
+	assert.NoError(t, os.Symlink(filepath.Base(dataDir), tmpLink))
+	assert.NoError(t, os.Rename(tmpLink, filepath.Join(root, kubeletDataLink)))
+
+	for name := range files {
+		top := filepath.Join(root, strings.Split(name, "/")[0])
+		if _, err := os.Lstat(top); os.IsNotExist(err) {
+			assert.NoError(t, os.Symlink(filepath.Join(kubeletDataLink, name), top))
+		}
+	}
+}
+
+func loadSettings(t *testing.T, provider *Provider) map[string]string {
+	entries, err := provider.Load(context.Background())
+	assert.NoError(t, err)
+
+	settings := make(map[string]string)
+	for _, entry := range entries {
+		settings[entry.Name] = entry.Value
+	}
+	return settings
+}
+
+func TestProvider_Load(t *testing.T) {
+	tests := []struct {
+		name   string
+		files  map[string]string
+		decode bool
+		want   map[string]string
+	}{
+		{
+			name: "Files",
+			files: map[string]string{
+				"spring.datasource.url": "postgres://db",
+				"password":              "secret",
+			},
+			want: map[string]string{
+				"spring.datasource.url": "postgres://db",
+				"password":              "secret",
+			},
+		},
+		{
+			name: "DecodeYaml",
+			files: map[string]string{
+				"application.yaml": "alpha:\n  bravo: b\n",
+			},
+			decode: true,
+			want: map[string]string{
+				"alpha.bravo": "b",
+			},
+		},
+		{
+			name: "DecodeProperties",
+			files: map[string]string{
+				"application.properties": "alpha.charlie=c\n",
+			},
+			decode: true,
+			want: map[string]string{
+				"alpha.charlie": "c",
+			},
+		},
+		{
+			name: "NoDecode",
+			files: map[string]string{
+				"application.yaml": "alpha: a",
+			},
+			want: map[string]string{
+				"application.yaml": "alpha: a",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeVolume(t, root, "1", tt.files)
+
+			provider := NewProvider("Kubernetes", root, tt.decode, nil)
+			assert.Equal(t, tt.want, loadSettings(t, provider))
+		})
+	}
+}
+
+func TestProvider_Load_Subdirectory(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "alpha"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "alpha", "bravo"), []byte("b"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "alpha", "delta.yml"), []byte("echo: e"), 0644))
+
+	provider := NewProvider("Kubernetes", root, true, nil)
+	assert.Equal(t,
+		map[string]string{
+			"alpha.bravo": "b",
+			"alpha.echo":  "e",
+		},
+		loadSettings(t, provider))
+}
+
+func TestProvider_Load_Missing(t *testing.T) {
+	provider := NewProvider("Kubernetes", filepath.Join(t.TempDir(), "missing"), true, nil)
+	entries, err := provider.Load(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestDirectorySignature_SymlinkSwap(t *testing.T) {
+	root := t.TempDir()
+	writeVolume(t, root, "1", map[string]string{"alpha": "a"})
+
+	before, err := directorySignature(root)
+	assert.NoError(t, err)
+
+	unchanged, err := directorySignature(root)
+	assert.NoError(t, err)
+	assert.Equal(t, before, unchanged)
+
+	writeVolume(t, root, "2", map[string]string{"alpha": "b"})
+
+	after, err := directorySignature(root)
+	assert.NoError(t, err)
+	assert.NotEqual(t, before, after)
+
+	provider := NewProvider("Kubernetes", root, false, nil)
+	assert.Equal(t, map[string]string{"alpha": "b"}, loadSettings(t, provider))
+}
+
+func TestDirectoryNotifier_Run(t *testing.T) {
+	root := t.TempDir()
+	writeVolume(t, root, "1", map[string]string{"alpha": "a"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	notifier := NewDirectoryNotifier(root, 10*time.Millisecond)
+	go notifier.Run(ctx)
+
+	time.Sleep(50 * time.Millisecond)
+	writeVolume(t, root, "2", map[string]string{"alpha": "b"})
+
+	select {
+	case <-notifier.Notify():
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "Change notification not received")
+	}
+}
+
+func TestNewProvidersFromConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       *config.Config
+		wantCount int
+	}{
+		{
+			name:      "Disabled",
+			cfg:       configtest.NewInMemoryConfig(nil),
+			wantCount: 0,
+		},
+		{
+			name: "Enabled",
+			cfg: configtest.NewInMemoryConfig(map[string]string{
+				"spring.cloud.kubernetes.config.enabled": "true",
+				"spring.cloud.kubernetes.config.paths":   "/etc/config,/etc/secrets",
+			}),
+			wantCount: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			providers, err := NewProvidersFromConfig("Kubernetes", tt.cfg)
+			assert.NoError(t, err)
+			assert.Len(t, providers, tt.wantCount)
+		})
+	}
+}
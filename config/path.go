@@ -103,6 +103,10 @@ type configConfig struct {
 	Path []string
 }
 
+func init() {
+	RegisterSchema[configConfig](configRootConfig)
+}
+
 func AddConfigFoldersFromPathConfig(cfg *Config) {
 	var pathConfig configConfig
 	if err := cfg.Populate(&pathConfig, configRootConfig); err != nil {
@@ -61,6 +61,8 @@ func Populate(target interface{}, prefix string, source PopulatorSource) error {
 		return errors.Wrap(err, "Failed to create populator")
 	}
 
+	schemaRegistry.Register(prefix, t)
+
 	v := reflect.ValueOf(target)
 	err = u.Populate(v, source, prefix)
 	if err != nil {
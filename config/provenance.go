@@ -0,0 +1,120 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package config
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	configRootConfigHistory = "config.history"
+)
+
+// Provenance records how a resolved entry was produced.  The winning provider
+// is identified by the entry Source.
+type Provenance struct {
+	// Overridden lists the entries for the same key from lower-precedence
+	// providers, highest precedence first.
+	Overridden ProviderEntries
+	// References lists the normalized names of the settings referenced by the value.
+	References []string
+	// Encrypted is true when the value was decrypted, or references a decrypted value.
+	Encrypted bool
+	// Sensitive is true when the value was produced by a function reading outside the
+	// configuration, such as file() or env(), or references such a value.
+	Sensitive bool
+}
+
+// overrides returns the entries hidden by higher-precedence layers, indexed by normalized name.
+func (l Layers) overrides() map[string]ProviderEntries {
+	var found = make(map[string]bool)
+	var results = make(map[string]ProviderEntries)
+	for i := len(l) - 1; i >= 0; i-- {
+		for _, entry := range l[i] {
+			if found[entry.NormalizedName] {
+				results[entry.NormalizedName] = append(results[entry.NormalizedName], entry)
+			} else {
+				found[entry.NormalizedName] = true
+			}
+		}
+	}
+	return results
+}
+
+// resolveLayers merges and resolves the layers, recording the provenance of each entry.
+func resolveLayers(layers Layers) (ResolvedEntries, error) {
+	resolver := NewResolver(layers.Merge())
+	entries, err := resolver.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := layers.overrides()
+	for i := range entries {
+		entries[i].Provenance.Overridden = overrides[entries[i].NormalizedName]
+	}
+
+	return entries, nil
+}
+
+type HistoryConfig struct {
+	Size int `config:"default=25"`
+}
+
+// SnapshotHistoryEntry records the changes applied by a single snapshot.
+type SnapshotHistoryEntry struct {
+	Timestamp time.Time
+	Delta     SnapshotDelta
+}
+
+type snapshotHistory struct {
+	mtx     sync.Mutex
+	entries []SnapshotHistoryEntry
+}
+
+func init() {
+	RegisterSchema[HistoryConfig](configRootConfigHistory)
+}
+
+func (h *snapshotHistory) record(snapshot Snapshot) {
+	if h == nil || len(snapshot.Delta) == 0 {
+		return
+	}
+
+	var historyConfig HistoryConfig
+	if err := snapshot.Populate(&historyConfig, configRootConfigHistory); err != nil {
+		logger.WithError(err).Error("Failed to load config history settings")
+		return
+	}
+
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	h.entries = append(h.entries, SnapshotHistoryEntry{
+		Timestamp: time.Now().UTC(),
+		Delta:     snapshot.Delta,
+	})
+
+	if overflow := len(h.entries) - historyConfig.Size; overflow > 0 {
+		h.entries = append([]SnapshotHistoryEntry{}, h.entries[overflow:]...)
+	}
+}
+
+func (h *snapshotHistory) list() []SnapshotHistoryEntry {
+	if h == nil {
+		return nil
+	}
+
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	return append([]SnapshotHistoryEntry{}, h.entries...)
+}
+
+// History returns the most recent changes applied by Watch, oldest first.
+// The number of entries retained is controlled by `config.history.size`.
+func (c *Config) History() []SnapshotHistoryEntry {
+	return c.history.list()
+}
@@ -0,0 +1,83 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package config
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+func TestResolveLayers_Provenance(t *testing.T) {
+	defaults := NewInMemoryProvider("defaults", nil)
+	file := NewInMemoryProvider("file", nil)
+	env := NewInMemoryProvider("env", nil)
+
+	layers := Layers{
+		{
+			NewEntry(defaults, "alpha", "a"),
+			NewEntry(defaults, "bravo", "b"),
+		},
+		{
+			NewEntry(file, "alpha", "aa"),
+		},
+		{
+			NewEntry(env, "alpha", "aaa"),
+			NewEntry(env, "charlie", "${alpha}-${bravo}-${delta:d}"),
+		},
+	}
+
+	entries, err := resolveLayers(layers)
+	assert.NoError(t, err)
+
+	values := newSnapshotValues(entries)
+
+	alpha, err := values.ResolveByName("alpha")
+	assert.NoError(t, err)
+	assert.Equal(t, env, alpha.Source)
+	assert.Len(t, alpha.Provenance.Overridden, 2)
+	assert.Equal(t, file, alpha.Provenance.Overridden[0].Source)
+	assert.Equal(t, "aa", alpha.Provenance.Overridden[0].Value)
+	assert.Equal(t, defaults, alpha.Provenance.Overridden[1].Source)
+	assert.Empty(t, alpha.Provenance.References)
+
+	bravo, err := values.ResolveByName("bravo")
+	assert.NoError(t, err)
+	assert.Empty(t, bravo.Provenance.Overridden)
+
+	charlie, err := values.ResolveByName("charlie")
+	assert.NoError(t, err)
+	assert.Equal(t, "aaa-b-d", charlie.ResolvedValue.String())
+	assert.Equal(t, []string{"alpha", "bravo", "delta"}, charlie.Provenance.References)
+}
+
+func TestSnapshotHistory_Bounded(t *testing.T) {
+	provider := NewInMemoryProvider("history", nil)
+	history := new(snapshotHistory)
+
+	previous := emptySnapshotValues
+	for i := 0; i < 5; i++ {
+		snapshot, err := newSnapshot(ProviderEntries{
+			NewEntry(provider, "config.history.size", "3"),
+			NewEntry(provider, "alpha", strconv.Itoa(i)),
+		}, previous)
+		assert.NoError(t, err)
+		history.record(snapshot)
+		previous = snapshot.SnapshotValues
+	}
+
+	// Unchanged snapshots are not recorded
+	snapshot, err := newSnapshot(ProviderEntries{
+		NewEntry(provider, "config.history.size", "3"),
+		NewEntry(provider, "alpha", "4"),
+	}, previous)
+	assert.NoError(t, err)
+	history.record(snapshot)
+
+	entries := history.list()
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "2", entries[0].Delta[0].NewEntry.ResolvedValue.String())
+	assert.Equal(t, "4", entries[2].Delta[0].NewEntry.ResolvedValue.String())
+}
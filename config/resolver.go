@@ -4,10 +4,16 @@
 
 package config
 
+import (
+	"github.com/pkg/errors"
+	"strings"
+)
+
 type Resolver struct {
-	entries  map[string]ProviderEntry
-	resolved map[string]ResolvedEntry
-	active   []ProviderEntry
+	entries    map[string]ProviderEntry
+	resolved   map[string]ResolvedEntry
+	active     []ProviderEntry
+	references map[string][]string
 }
 
 func (r *Resolver) isActive(key string) bool {
@@ -22,10 +28,15 @@ func (r *Resolver) isActive(key string) bool {
 func (r *Resolver) setActive(e ProviderEntry) error {
 	if r.isActive(e.NormalizedName) {
 		logger.Errorf("Circular reference detected:")
+		var chain []string
 		for _, entry := range r.active {
 			logger.Errorf("- %q => %q", entry.Name, entry.Value)
+			if entry.NormalizedName == e.NormalizedName || len(chain) > 0 {
+				chain = append(chain, entry.Name)
+			}
 		}
-		return ErrCircularReference
+		chain = append(chain, e.Name)
+		return errors.Wrapf(ErrCircularReference, "%s", strings.Join(chain, " -> "))
 	}
 
 	r.active = append(r.active, e)
@@ -80,9 +91,31 @@ func (r *Resolver) Resolve(entry ProviderEntry) (ResolvedEntry, error) {
 		return ResolvedEntry{}, err
 	}
 
+	value, encrypted, err := decryptValue(value)
+	if err != nil {
+		return ResolvedEntry{}, errors.Wrapf(err, "Failed to resolve %q", entry.Name)
+	}
+
+	sensitive := expressionSensitive(expr)
+
+	references := r.references[entry.NormalizedName]
+	for _, reference := range references {
+		if r.resolved[reference].Provenance.Encrypted {
+			encrypted = true
+		}
+		if r.resolved[reference].Provenance.Sensitive {
+			sensitive = true
+		}
+	}
+
 	resolved := ResolvedEntry{
 		ProviderEntry: entry,
 		ResolvedValue: Value(value),
+		Provenance: Provenance{
+			References: references,
+			Encrypted:  encrypted,
+			Sensitive:  sensitive,
+		},
 	}
 
 	r.resolved[entry.NormalizedName] = resolved
@@ -93,6 +126,12 @@ func (r *Resolver) Resolve(entry ProviderEntry) (ResolvedEntry, error) {
 func (r *Resolver) ResolveByName(name string) (ResolvedEntry, error) {
 	normalizedName := NormalizeKey(name)
 
+	// Record the reference from the entry currently being resolved
+	if len(r.active) > 0 {
+		referrer := r.active[len(r.active)-1].NormalizedName
+		r.references[referrer] = append(r.references[referrer], normalizedName)
+	}
+
 	if entry, ok := r.entries[normalizedName]; ok {
 		return r.Resolve(entry)
 	}
@@ -107,7 +146,8 @@ func NewResolver(entries ProviderEntries) Resolver {
 	}
 
 	return Resolver{
-		entries:  entryIndex,
-		resolved: make(map[string]ResolvedEntry),
+		entries:    entryIndex,
+		resolved:   make(map[string]ResolvedEntry),
+		references: make(map[string][]string),
 	}
 }
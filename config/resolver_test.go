@@ -16,6 +16,7 @@ func TestResolver(t *testing.T) {
 	type wants struct {
 		resolved map[string]string
 		err      error
+		message  string
 	}
 	tests := []struct {
 		name     string
@@ -59,6 +60,7 @@ func TestResolver(t *testing.T) {
 			want: wants{
 				resolved: nil,
 				err:      ErrCircularReference,
+				message:  `^(alpha|bravo|charlie)( -> (alpha|bravo|charlie)){3}: Circular reference detected$`,
 			},
 		},
 	}
@@ -78,6 +80,8 @@ func TestResolver(t *testing.T) {
 					t.Errorf("Expected no error, got = %v", err)
 				} else if !errors.Is(err, tt.want.err) {
 					t.Errorf("Expected error = %v, got = %v", tt.want.err, err)
+				} else if tt.want.message != "" {
+					assert.Regexp(t, tt.want.message, err.Error())
 				}
 				return
 			}
@@ -89,3 +93,24 @@ func TestResolver(t *testing.T) {
 		})
 	}
 }
+
+func TestResolver_Sensitive(t *testing.T) {
+	t.Setenv("RESOLVER_TEST_VAR", "from-env")
+
+	entries, err := resolveLayers(Layers{
+		cipherTestEntries(map[string]string{
+			"alpha":   "${env(RESOLVER_TEST_VAR)}",
+			"bravo":   "${alpha}-b",
+			"charlie": "${upper(c)}",
+			"delta":   "${missing:${env(RESOLVER_TEST_VAR)}}",
+		}),
+	})
+	assert.NoError(t, err)
+
+	values := newSnapshotValues(entries)
+	for key, want := range map[string]bool{"alpha": true, "bravo": true, "charlie": false, "delta": true} {
+		entry, err := values.ResolveByName(key)
+		assert.NoError(t, err)
+		assert.Equal(t, want, entry.Provenance.Sensitive, key)
+	}
+}
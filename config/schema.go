@@ -0,0 +1,500 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/iancoleman/strcase"
+	"github.com/pkg/errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	SchemaTypeString   = "string"
+	SchemaTypeInteger  = "integer"
+	SchemaTypeNumber   = "number"
+	SchemaTypeBoolean  = "boolean"
+	SchemaTypeDuration = "duration"
+	SchemaTypeObject   = "object"
+	SchemaTypeArray    = "array"
+	SchemaTypeMap      = "map"
+	SchemaTypeAny      = "any"
+
+	configRootConfigSchema = "config.schema"
+
+	StrictModeOff  = "off"
+	StrictModeWarn = "warn"
+	StrictModeFail = "fail"
+)
+
+var ErrUnknownKeys = errors.New("Unknown configuration keys found")
+
+// SchemaNode describes the settings consumed by a populated value.
+type SchemaNode struct {
+	Type     string  `json:"type"`
+	GoType   string  `json:"goType,omitempty"`
+	Default  *string `json:"default,omitempty"`
+	Optional bool    `json:"optional,omitempty"`
+	// Properties contains the fields of an object, keyed by setting name.
+	Properties map[string]*SchemaNode `json:"properties,omitempty"`
+	// Items describes the elements of an array, or the values of a map.
+	Items *SchemaNode `json:"items,omitempty"`
+}
+
+// recognizes returns true if the relative key is consumed by this node.
+func (n *SchemaNode) recognizes(key string) bool {
+	key = strings.TrimPrefix(key, ".")
+	if key == "" {
+		return true
+	}
+
+	switch n.Type {
+	case SchemaTypeAny:
+		return true
+
+	case SchemaTypeObject:
+		segment, rest := splitSchemaKey(key)
+		for name, property := range n.Properties {
+			if NormalizeKey(name) == segment {
+				return property.recognizes(rest)
+			}
+		}
+
+	case SchemaTypeMap:
+		_, rest := splitSchemaKey(key)
+		return n.Items.recognizes(rest)
+
+	case SchemaTypeArray:
+		if key[0] != '[' {
+			return false
+		}
+		end := strings.IndexByte(key, ']')
+		if end < 0 {
+			return false
+		}
+		return n.Items.recognizes(key[end+1:])
+	}
+
+	return false
+}
+
+// splitSchemaKey separates the first segment of a normalized key from the remainder.
+func splitSchemaKey(key string) (segment, rest string) {
+	end := strings.IndexAny(key, ".[")
+	if end < 0 {
+		return key, ""
+	}
+	return key[:end], key[end:]
+}
+
+// SchemaEntry records a structure populated from a configuration prefix.
+type SchemaEntry struct {
+	Prefix string      `json:"prefix"`
+	Schema *SchemaNode `json:"schema"`
+}
+
+type schemaRegistryKey struct {
+	prefix string
+	target reflect.Type
+}
+
+type SchemaRegistry struct {
+	mtx     sync.Mutex
+	entries map[schemaRegistryKey]SchemaEntry
+}
+
+func (r *SchemaRegistry) Register(prefix string, t reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if prefix == "" || t.Kind() != reflect.Struct {
+		return
+	}
+
+	key := schemaRegistryKey{
+		prefix: NormalizeKey(prefix),
+		target: t,
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if _, ok := r.entries[key]; ok {
+		return
+	}
+
+	r.entries[key] = SchemaEntry{
+		Prefix: prefix,
+		Schema: newSchemaNode(t, nil, map[reflect.Type]bool{}),
+	}
+}
+
+// Entries returns the registered schemas, ordered by prefix.
+func (r *SchemaRegistry) Entries() []SchemaEntry {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	var results = make([]SchemaEntry, 0, len(r.entries))
+	for _, entry := range r.entries {
+		results = append(results, entry)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Prefix == results[j].Prefix {
+			return results[i].Schema.GoType < results[j].Schema.GoType
+		}
+		return results[i].Prefix < results[j].Prefix
+	})
+
+	return results
+}
+
+// UnknownKeys returns the keys below a registered prefix which are not consumed
+// by any of the structures registered at or above that key.
+func (r *SchemaRegistry) UnknownKeys(values SnapshotValues) []string {
+	entries := r.Entries()
+
+	var results []string
+	for _, entry := range values.Entries() {
+		covered, recognized := false, false
+		for _, schemaEntry := range entries {
+			prefix := NormalizeKey(schemaEntry.Prefix)
+			if !entry.HasPrefix(prefix) {
+				continue
+			}
+
+			covered = true
+			if schemaEntry.Schema.recognizes(entry.NormalizedName[len(prefix):]) {
+				recognized = true
+				break
+			}
+		}
+
+		if covered && !recognized {
+			results = append(results, entry.Name)
+		}
+	}
+
+	sort.Strings(results)
+	return results
+}
+
+// JsonSchema renders the registered schemas as a single JSON Schema document
+// describing a nested configuration file.
+func (r *SchemaRegistry) JsonSchema() ([]byte, error) {
+	root := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{},
+	}
+
+	for _, entry := range r.Entries() {
+		if strings.ContainsAny(entry.Prefix, "[]") {
+			continue
+		}
+
+		node := entry.Schema.jsonSchema()
+		segments := strings.Split(entry.Prefix, ".")
+		for i := len(segments) - 1; i >= 0; i-- {
+			node = map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					segments[i]: node,
+				},
+			}
+		}
+
+		mergeJsonSchema(root, node)
+	}
+
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// Markdown renders the registered schemas as a markdown reference.
+func (r *SchemaRegistry) Markdown() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("# Configuration Reference\n")
+
+	for _, entry := range r.Entries() {
+		buf.WriteString(fmt.Sprintf("\n## `%s`\n\n", entry.Prefix))
+		if entry.Schema.GoType != "" {
+			buf.WriteString(fmt.Sprintf("Populates `%s`.\n\n", entry.Schema.GoType))
+		}
+		buf.WriteString("| Key | Type | Default | Optional |\n")
+		buf.WriteString("|-----|------|---------|----------|\n")
+
+		entry.Schema.eachLeaf(entry.Prefix, func(key string, node *SchemaNode) {
+			defaultValue := ""
+			if node.Default != nil {
+				defaultValue = "`" + *node.Default + "`"
+			}
+
+			optional := ""
+			if node.Optional {
+				optional = "Yes"
+			}
+
+			buf.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", key, node.Type, defaultValue, optional))
+		})
+	}
+
+	return buf.Bytes()
+}
+
+func (n *SchemaNode) eachLeaf(key string, fn func(key string, node *SchemaNode)) {
+	switch n.Type {
+	case SchemaTypeObject:
+		var names []string
+		for name := range n.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			n.Properties[name].eachLeaf(PrefixWithName(key, name), fn)
+		}
+
+	case SchemaTypeMap:
+		n.Items.eachLeaf(PrefixWithName(key, "<key>"), fn)
+
+	case SchemaTypeArray:
+		if n.Items.Type == SchemaTypeObject || n.Items.Type == SchemaTypeMap {
+			n.Items.eachLeaf(key+"[]", fn)
+		} else {
+			fn(key, n)
+		}
+
+	default:
+		fn(key, n)
+	}
+}
+
+func (n *SchemaNode) jsonSchema() map[string]any {
+	result := map[string]any{}
+
+	switch n.Type {
+	case SchemaTypeAny:
+		return result
+
+	case SchemaTypeDuration:
+		result["type"] = SchemaTypeString
+		result["format"] = SchemaTypeDuration
+
+	case SchemaTypeObject:
+		result["type"] = SchemaTypeObject
+		properties := map[string]any{}
+		for name, property := range n.Properties {
+			properties[name] = property.jsonSchema()
+		}
+		result["properties"] = properties
+
+	case SchemaTypeMap:
+		result["type"] = SchemaTypeObject
+		result["additionalProperties"] = n.Items.jsonSchema()
+
+	case SchemaTypeArray:
+		result["type"] = SchemaTypeArray
+		result["items"] = n.Items.jsonSchema()
+
+	default:
+		result["type"] = n.Type
+	}
+
+	if n.Default != nil {
+		if defaultValue, ok := n.jsonDefault(); ok {
+			result["default"] = defaultValue
+		}
+	}
+
+	return result
+}
+
+// jsonDefault converts the default value to the node type.  Defaults containing
+// references to other settings are only included for string values.
+func (n *SchemaNode) jsonDefault() (any, bool) {
+	value := *n.Default
+	switch n.Type {
+	case SchemaTypeString, SchemaTypeDuration:
+		return value, true
+	case SchemaTypeBoolean:
+		result, err := strconv.ParseBool(value)
+		return result, err == nil
+	case SchemaTypeInteger:
+		result, err := strconv.ParseInt(value, 10, 64)
+		return result, err == nil
+	case SchemaTypeNumber:
+		result, err := strconv.ParseFloat(value, 64)
+		return result, err == nil
+	case SchemaTypeArray:
+		if strings.Contains(value, "${") {
+			return nil, false
+		}
+		return strings.Split(value, ";"), true
+	}
+	return nil, false
+}
+
+func mergeJsonSchema(target, source map[string]any) {
+	targetProperties, _ := target["properties"].(map[string]any)
+	sourceProperties, _ := source["properties"].(map[string]any)
+	if targetProperties == nil || sourceProperties == nil {
+		return
+	}
+
+	for name, sourceProperty := range sourceProperties {
+		targetProperty, ok := targetProperties[name].(map[string]any)
+		if !ok {
+			targetProperties[name] = sourceProperty
+			continue
+		}
+
+		if sourcePropertyMap, ok := sourceProperty.(map[string]any); ok {
+			if _, ok = targetProperty["properties"]; !ok {
+				if _, ok = sourcePropertyMap["properties"]; ok {
+					targetProperty["properties"] = map[string]any{}
+				}
+			}
+			mergeJsonSchema(targetProperty, sourcePropertyMap)
+		}
+	}
+}
+
+func newSchemaNode(t reflect.Type, defaultValue *string, visiting map[reflect.Type]bool) *SchemaNode {
+	node := &SchemaNode{
+		Default: defaultValue,
+	}
+
+	if t.Implements(populatorType) {
+		node.Type = SchemaTypeAny
+		return node
+	}
+
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == durationType {
+		node.Type = SchemaTypeDuration
+		return node
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		node.Type = SchemaTypeInteger
+	case reflect.String:
+		node.Type = SchemaTypeString
+	case reflect.Bool:
+		node.Type = SchemaTypeBoolean
+	case reflect.Float32, reflect.Float64:
+		node.Type = SchemaTypeNumber
+	case reflect.Map:
+		node.Type = SchemaTypeMap
+		node.Items = newSchemaNode(t.Elem(), nil, visiting)
+	case reflect.Slice:
+		node.Type = SchemaTypeArray
+		node.Items = newSchemaNode(t.Elem(), nil, visiting)
+	case reflect.Struct:
+		node.GoType = t.String()
+		if visiting[t] {
+			// Recursive structure
+			node.Type = SchemaTypeAny
+			return node
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		node.Type = SchemaTypeObject
+		node.Properties = make(map[string]*SchemaNode)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			fieldOptions := parseStructTag(field.Tag.Get("config"))
+			if fieldOptions[tagOptionIgnore] != "" || !field.IsExported() {
+				continue
+			}
+
+			name := strcase.ToKebab(field.Name)
+			if fieldOptions[tagOptionKey] != "" {
+				name = fieldOptions[tagOptionKey]
+			}
+
+			var fieldDefault *string
+			if value, ok := fieldOptions[tagOptionDefault]; ok {
+				fieldDefault = &value
+			}
+
+			property := newSchemaNode(field.Type, fieldDefault, visiting)
+			property.Optional = fieldOptions[tagOptionOptional] != ""
+			node.Properties[name] = property
+		}
+	default:
+		node.Type = SchemaTypeAny
+	}
+
+	return node
+}
+
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{
+		entries: make(map[schemaRegistryKey]SchemaEntry),
+	}
+}
+
+var schemaRegistry = NewSchemaRegistry()
+
+// Schemas returns the application-wide registry of structures populated from configuration.
+func Schemas() *SchemaRegistry {
+	return schemaRegistry
+}
+
+// RegisterSchema records the structure read from the specified prefix in the application-wide
+// registry.  Packages register their configuration structures during init, so that the schema
+// export and strict mode include them even when the structure has not yet been populated.
+func RegisterSchema[T any](prefix string) {
+	schemaRegistry.Register(prefix, reflect.TypeOf((*T)(nil)).Elem())
+}
+
+type SchemaConfig struct {
+	Strict string `config:"default=off"`
+}
+
+func init() {
+	RegisterSchema[SchemaConfig](configRootConfigSchema)
+}
+
+// CheckUnknownKeys reports settings under known prefixes which are not consumed by any
+// populated structure.  Depending on `config.schema.strict`, unknown keys are ignored (off),
+// logged (warn) or cause an error (fail).
+func CheckUnknownKeys(ctx context.Context, cfg *Config) error {
+	var schemaConfig SchemaConfig
+	if err := cfg.Populate(&schemaConfig, configRootConfigSchema); err != nil {
+		return err
+	}
+
+	mode := strings.ToLower(schemaConfig.Strict)
+	if mode == StrictModeOff {
+		return nil
+	}
+
+	unknownKeys := schemaRegistry.UnknownKeys(cfg.LatestValues())
+	for _, key := range unknownKeys {
+		logger.WithContext(ctx).Warnf("Unknown configuration key %q", key)
+	}
+
+	if len(unknownKeys) > 0 && mode == StrictModeFail {
+		return errors.Wrapf(ErrUnknownKeys, "%s", strings.Join(unknownKeys, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type schemaSubStruct struct {
+	MaxAttempts int `config:"default=3"`
+}
+
+type schemaStruct struct {
+	Name     string        `config:"default=alpha"`
+	Enabled  bool          `config:"default=false"`
+	Timeout  time.Duration `config:"default=5s"`
+	Ratio    float64       `config:"optional"`
+	Hosts    []string      `config:"default=a;b"`
+	Labels   map[string]string
+	Consumer schemaSubStruct
+	Bindings map[string]schemaSubStruct
+	Renamed  string `config:"other-name,optional"`
+	Skipped  string `config:"-"`
+}
+
+func TestSchemaRegistry_Register(t *testing.T) {
+	r := NewSchemaRegistry()
+	r.Register("app.settings", reflect.TypeOf(&schemaStruct{}))
+	r.Register("app.settings", reflect.TypeOf(schemaStruct{}))
+	r.Register("app.map", reflect.TypeOf(map[string]string{}))
+	r.Register("", reflect.TypeOf(schemaStruct{}))
+
+	entries := r.Entries()
+	assert.Len(t, entries, 1)
+
+	schema := entries[0].Schema
+	assert.Equal(t, "app.settings", entries[0].Prefix)
+	assert.Equal(t, SchemaTypeObject, schema.Type)
+	assert.Equal(t, "config.schemaStruct", schema.GoType)
+	assert.NotContains(t, schema.Properties, "skipped")
+	assert.Contains(t, schema.Properties, "other-name")
+	assert.Equal(t, SchemaTypeDuration, schema.Properties["timeout"].Type)
+	assert.Equal(t, "5s", *schema.Properties["timeout"].Default)
+	assert.True(t, schema.Properties["ratio"].Optional)
+	assert.Equal(t, SchemaTypeArray, schema.Properties["hosts"].Type)
+	assert.Equal(t, SchemaTypeMap, schema.Properties["bindings"].Type)
+	assert.Equal(t, SchemaTypeInteger, schema.Properties["bindings"].Items.Properties["max-attempts"].Type)
+}
+
+func TestRegisterSchema(t *testing.T) {
+	RegisterSchema[schemaStruct]("test.register.schema")
+
+	registered := make(map[string]*SchemaNode)
+	for _, entry := range Schemas().Entries() {
+		registered[entry.Prefix] = entry.Schema
+	}
+
+	if assert.Contains(t, registered, "test.register.schema") {
+		assert.Equal(t, reflect.TypeOf(schemaStruct{}).String(), registered["test.register.schema"].GoType)
+	}
+
+	// Registered during init, without being populated
+	if assert.Contains(t, registered, configRootConfigSchema) {
+		assert.Contains(t, registered[configRootConfigSchema].Properties, "strict")
+	}
+}
+
+func TestSchemaRegistry_UnknownKeys(t *testing.T) {
+	r := NewSchemaRegistry()
+	r.Register("app.settings", reflect.TypeOf(schemaStruct{}))
+	r.Register("app.settings.extra", reflect.TypeOf(schemaSubStruct{}))
+
+	values := newSnapshotValues(ResolvedEntries{
+		{ProviderEntry: NewEntry(nil, "app.settings.name", "a")},
+		{ProviderEntry: NewEntry(nil, "app.settings.hosts[0]", "a")},
+		{ProviderEntry: NewEntry(nil, "app.settings.labels.anything", "a")},
+		{ProviderEntry: NewEntry(nil, "app.settings.consumer.max-attempts", "4")},
+		{ProviderEntry: NewEntry(nil, "app.settings.bindings.foo.max-attempts", "4")},
+		{ProviderEntry: NewEntry(nil, "app.settings.bindings.foo.max-atempts", "4")},
+		{ProviderEntry: NewEntry(nil, "app.settings.other-name", "a")},
+		{ProviderEntry: NewEntry(nil, "app.settings.skipped", "a")},
+		{ProviderEntry: NewEntry(nil, "app.settings.name.child", "a")},
+		{ProviderEntry: NewEntry(nil, "app.settings.extra.max-attempts", "a")},
+		{ProviderEntry: NewEntry(nil, "app.settingsother", "a")},
+		{ProviderEntry: NewEntry(nil, "unregistered.key", "a")},
+	})
+
+	assert.Equal(t,
+		[]string{
+			"app.settings.bindings.foo.max-atempts",
+			"app.settings.name.child",
+			"app.settings.skipped",
+		},
+		r.UnknownKeys(values))
+}
+
+func TestSchemaRegistry_JsonSchema(t *testing.T) {
+	r := NewSchemaRegistry()
+	r.Register("app.settings", reflect.TypeOf(schemaStruct{}))
+	r.Register("app.settings.extra", reflect.TypeOf(schemaSubStruct{}))
+
+	data, err := r.JsonSchema()
+	assert.NoError(t, err)
+
+	var schema map[string]any
+	assert.NoError(t, json.Unmarshal(data, &schema))
+
+	settings := schema["properties"].(map[string]any)["app"].(map[string]any)["properties"].(map[string]any)["settings"].(map[string]any)
+	properties := settings["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string", "format": "duration", "default": "5s"}, properties["timeout"])
+	assert.Equal(t, map[string]any{"type": "boolean", "default": false}, properties["enabled"])
+	assert.Equal(t, []any{"a", "b"}, properties["hosts"].(map[string]any)["default"])
+	assert.Equal(t, "object", properties["labels"].(map[string]any)["type"])
+	assert.Contains(t, properties["labels"], "additionalProperties")
+	assert.Contains(t, properties, "extra")
+}
+
+func TestSchemaRegistry_Markdown(t *testing.T) {
+	r := NewSchemaRegistry()
+	r.Register("app.settings", reflect.TypeOf(schemaSubStruct{}))
+
+	assert.Equal(t,
+		"# Configuration Reference\n"+
+			"\n## `app.settings`\n\n"+
+			"Populates `config.schemaSubStruct`.\n\n"+
+			"| Key | Type | Default | Optional |\n"+
+			"|-----|------|---------|----------|\n"+
+			"| app.settings.max-attempts | integer | `3` |  |\n",
+		string(r.Markdown()))
+}
+
+func TestCheckUnknownKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		strict  string
+		wantErr error
+	}{
+		{
+			name:   "Off",
+			strict: StrictModeOff,
+		},
+		{
+			name:   "Warn",
+			strict: StrictModeWarn,
+		},
+		{
+			name:    "Fail",
+			strict:  StrictModeFail,
+			wantErr: ErrUnknownKeys,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig(NewInMemoryProvider("static", map[string]string{
+				"config.schema.strict":                    tt.strict,
+				"check.unknown.keys.consumer.max-atempts": "4",
+			}))
+			assert.NoError(t, cfg.Load(context.Background()))
+
+			var target schemaStruct
+			assert.NoError(t, cfg.Populate(&target, "check.unknown.keys"))
+
+			err := CheckUnknownKeys(context.Background(), cfg)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ import (
 type ResolvedEntry struct {
 	ProviderEntry
 	ResolvedValue Value
+	Provenance    Provenance
 }
 
 func (s ResolvedEntry) String() string {
@@ -65,6 +66,21 @@ func (e resolvedEntryDelta) IsUnset() bool {
 
 type SnapshotDelta []resolvedEntryDelta
 
+// HasPrefix returns true if any of the changed entries are at or below the prefix.
+func (d SnapshotDelta) HasPrefix(prefix string) bool {
+	prefix = NormalizeKey(prefix)
+	if prefix == "" {
+		return len(d) > 0
+	}
+
+	for _, change := range d {
+		if change.NewEntry.HasPrefix(prefix) || change.OldEntry.HasPrefix(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 type nodeName struct {
 	NormalizedName string
 	Prefix         string
@@ -421,8 +437,11 @@ type Snapshot struct {
 }
 
 func newSnapshot(entries ProviderEntries, previous SnapshotValues) (Snapshot, error) {
-	r := NewResolver(entries)
-	resolvedEntries, err := r.Entries()
+	return newLayeredSnapshot(Layers{entries}, previous)
+}
+
+func newLayeredSnapshot(layers Layers, previous SnapshotValues) (Snapshot, error) {
+	resolvedEntries, err := resolveLayers(layers)
 	if err != nil {
 		return Snapshot{}, err
 	}
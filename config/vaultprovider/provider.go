@@ -172,6 +172,11 @@ func NewProvider(name string, cfg *ProviderConfig, contextPath string, conn vaul
 	}
 }
 
+func init() {
+	config.RegisterSchema[ProviderConfig](configRootVaultConfigProvider)
+	config.RegisterSchema[InstallerPasswordsConfig](configRootInstallerPasswords)
+}
+
 func NewProviderConfig(cfg *config.Config) (*ProviderConfig, error) {
 	var providerConfig = &ProviderConfig{}
 	var err = cfg.Populate(providerConfig, configRootVaultConfigProvider)
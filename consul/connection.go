@@ -385,6 +385,10 @@ func NewConnection(connectionConfig *ConnectionConfig) (*Connection, error) {
 	}
 }
 
+func init() {
+	config.RegisterSchema[ConnectionConfig](configRootConsulConnection)
+}
+
 func NewConnectionConfigFromConfig(cfg *config.Config) (*ConnectionConfig, error) {
 	connectionConfig := ConnectionConfig{}
 	if err := cfg.Populate(&connectionConfig, configRootConsulConnection); err != nil {
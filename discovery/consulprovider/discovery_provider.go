@@ -65,6 +65,10 @@ func convertToServiceInstance(sourceEntry *api.ServiceEntry) *discovery.ServiceI
 	}
 }
 
+func init() {
+	config.RegisterSchema[DiscoveryProviderConfig](configRootDiscoveryProvider)
+}
+
 func NewDiscoveryProviderConfigFromConfig(cfg *config.Config) (*DiscoveryProviderConfig, error) {
 	var discoveryConfig DiscoveryProviderConfig
 	if err := cfg.Populate(&discoveryConfig, configRootDiscoveryProvider); err != nil {
@@ -26,6 +26,10 @@ type FileSystemConfig struct {
 	Mode      string `config:"default=detect"`
 }
 
+func init() {
+	config.RegisterSchema[FileSystemConfig](configRootFileSystem)
+}
+
 func NewFileSystemConfig(cfg *config.Config) (*FileSystemConfig, error) {
 	var fsConfig FileSystemConfig
 	if err := cfg.Populate(&fsConfig, configRootFileSystem); err != nil {
@@ -24,6 +24,10 @@ type ClientConfig struct {
 	KeyFile     string        `config:"default="`
 }
 
+func init() {
+	config.RegisterSchema[ClientConfig](configRootHttpClient)
+}
+
 func NewClientConfig(cfg *config.Config) (*ClientConfig, error) {
 	var clientConfig ClientConfig
 	if err := cfg.Populate(&clientConfig, configRootHttpClient); err != nil {
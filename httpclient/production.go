@@ -9,12 +9,44 @@ import (
 	"crypto/tls"
 	"cto-github.cisco.com/NFV-BU/go-msx/config"
 	"net/http"
+	"sync/atomic"
 )
 
-type ProductionHttpClientFactory struct {
+type productionClientSettings struct {
 	tlsConfig    *tls.Config
 	clientConfig *ClientConfig
-	configurer   ClientConfigurer
+}
+
+func newProductionClientSettings(clientConfig *ClientConfig) (*productionClientSettings, error) {
+	tlsConfig, err := NewTlsConfig(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &productionClientSettings{
+		tlsConfig:    tlsConfig,
+		clientConfig: clientConfig,
+	}, nil
+}
+
+type ProductionHttpClientFactory struct {
+	settings   atomic.Value
+	configurer ClientConfigurer
+}
+
+func (f *ProductionHttpClientFactory) current() *productionClientSettings {
+	return f.settings.Load().(*productionClientSettings)
+}
+
+// onClientConfigChange applies updated client settings to subsequently created clients.
+func (f *ProductionHttpClientFactory) onClientConfigChange(ctx context.Context, _, current *ClientConfig) {
+	settings, err := newProductionClientSettings(current)
+	if err != nil {
+		logger.WithContext(ctx).WithError(err).Error("Failed to apply updated http client configuration")
+		return
+	}
+
+	f.settings.Store(settings)
 }
 
 func (f *ProductionHttpClientFactory) NewHttpClient() *http.Client {
@@ -23,16 +55,17 @@ func (f *ProductionHttpClientFactory) NewHttpClient() *http.Client {
 
 func (f *ProductionHttpClientFactory) NewHttpClientWithConfigurer(ctx context.Context, configurer Configurer) *http.Client {
 	contextConfigurer := ConfigurerFromContext(ctx)
+	settings := f.current()
 
 	var tlsConfig = &tls.Config{
-		InsecureSkipVerify: f.tlsConfig.InsecureSkipVerify,
-		RootCAs:            f.tlsConfig.RootCAs,
-		Certificates:       f.tlsConfig.Certificates[:],
+		InsecureSkipVerify: settings.tlsConfig.InsecureSkipVerify,
+		RootCAs:            settings.tlsConfig.RootCAs,
+		Certificates:       settings.tlsConfig.Certificates[:],
 	}
 
 	transport := &http.Transport{
 		TLSClientConfig: tlsConfig,
-		IdleConnTimeout: f.clientConfig.IdleTimeout,
+		IdleConnTimeout: settings.clientConfig.IdleTimeout,
 		Proxy:           http.ProxyFromEnvironment,
 	}
 
@@ -46,7 +79,7 @@ func (f *ProductionHttpClientFactory) NewHttpClientWithConfigurer(ctx context.Co
 
 	client := &http.Client{
 		Transport: transport,
-		Timeout:   f.clientConfig.Timeout,
+		Timeout:   settings.clientConfig.Timeout,
 	}
 
 	f.configurer.HttpClient(client)
@@ -68,21 +101,23 @@ func (f *ProductionHttpClientFactory) AddTransportConfigurationFunc(fn Transport
 	f.configurer.TransportFuncs = append(f.configurer.TransportFuncs, fn)
 }
 
+// NewProductionHttpClientFactoryFromConfig creates a new factory bound to the http client
+// configuration.  Configuration changes are applied to clients created after the change.
 func NewProductionHttpClientFactoryFromConfig(cfg *config.Config) (*ProductionHttpClientFactory, error) {
-	clientConfig, err := NewClientConfig(cfg)
+	binding, err := config.Bind[ClientConfig](cfg, configRootHttpClient)
 	if err != nil {
 		return nil, err
 	}
 
-	tlsConfig, err := NewTlsConfig(clientConfig)
+	settings, err := newProductionClientSettings(binding.Current())
 	if err != nil {
 		return nil, err
 	}
 
-	return &ProductionHttpClientFactory{
-		tlsConfig:    tlsConfig,
-		clientConfig: clientConfig,
-	}, nil
+	factory := new(ProductionHttpClientFactory)
+	factory.settings.Store(settings)
+	binding.OnChange(factory.onClientConfigChange)
+	return factory, nil
 }
 
 func NewProductionHttpClientFactory(ctx context.Context) (*ProductionHttpClientFactory, error) {
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"net/http"
 	"testing"
+	"time"
 )
 
 func TestProductionHttpClientFactory_AddClientConfigurationFunc(t *testing.T) {
@@ -33,3 +34,22 @@ func TestProductionHttpClientFactory_AddTransportConfigurationFunc(t *testing.T)
 	factory.AddTransportConfigurationFunc(transportFunc)
 	assert.Len(t, factory.configurer.TransportFuncs, 1)
 }
+
+func TestProductionHttpClientFactory_ConfigChange(t *testing.T) {
+	cfg := config.NewConfig()
+	_ = cfg.Load(context.Background())
+	factory, err := NewProductionHttpClientFactoryFromConfig(cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, factory.NewHttpClient().Timeout)
+
+	previous := factory.current().clientConfig
+	updated := *previous
+	updated.Timeout = 5 * time.Second
+	factory.onClientConfigChange(context.Background(), previous, &updated)
+	assert.Equal(t, 5*time.Second, factory.NewHttpClient().Timeout)
+
+	invalid := updated
+	invalid.CertFile = "client.pem"
+	factory.onClientConfigChange(context.Background(), &updated, &invalid)
+	assert.Equal(t, 5*time.Second, factory.NewHttpClient().Timeout)
+}
@@ -57,7 +57,7 @@ type JsonWebKey struct {
 	KeyType            string `json:"kty"`
 	RsaModulus         string `json:"n"`
 	RsaPublicExponent  string `json:"e"`
-	RsaPrivateExponent string `json:"d"`
+	RsaPrivateExponent string `json:"d,omitempty"`
 }
 
 func (j JsonWebKey) RsaPublicKey() (*rsa.PublicKey, error) {
@@ -0,0 +1,43 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package restclient
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops"
+	"github.com/pkg/errors"
+)
+
+// ResponseError reports an error response from a service endpoint.  Where the
+// status code has been mapped using restops.SetMappedStatusCodeError, the
+// ResponseError also matches the mapped error using errors.Is.
+type ResponseError struct {
+	Code   int
+	Cause  error
+	Mapped error
+}
+
+func (e *ResponseError) Error() string {
+	return e.Cause.Error()
+}
+
+func (e *ResponseError) Unwrap() error {
+	return e.Cause
+}
+
+func (e *ResponseError) Is(target error) bool {
+	return e.Mapped != nil && errors.Is(e.Mapped, target)
+}
+
+func (e *ResponseError) StatusCode() int {
+	return e.Code
+}
+
+func NewResponseError(statusCode int, cause error) *ResponseError {
+	return &ResponseError{
+		Code:   statusCode,
+		Cause:  cause,
+		Mapped: restops.MappedStatusCodeError(statusCode).OrElse(nil),
+	}
+}
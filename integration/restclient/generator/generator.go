@@ -0,0 +1,671 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package generator
+
+import (
+	"bytes"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/iancoleman/strcase"
+	"github.com/mcrawfo2/jennifer/jen"
+	"github.com/pkg/errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	pkgIntegration = "cto-github.cisco.com/NFV-BU/go-msx/integration"
+	pkgRestClient  = "cto-github.cisco.com/NFV-BU/go-msx/integration/restclient"
+	pkgHttpErrors  = "cto-github.cisco.com/NFV-BU/go-msx/ops/restops/httperrors"
+	pkgTypes       = "cto-github.cisco.com/NFV-BU/go-msx/types"
+	pkgContext     = "context"
+
+	schemaRefPrefix = "#/components/schemas/"
+
+	headerComment = "Code generated by skel generate-client-openapi. DO NOT EDIT."
+)
+
+// Options control the generated client package.
+type Options struct {
+	// PackageName is the name of the generated package.
+	PackageName string
+	// ServiceName is the discovery name of the remote service.
+	ServiceName string
+}
+
+// Generator renders a typed integration client package from an OpenAPI document.
+type Generator struct {
+	spec       *openapi3.Swagger
+	options    Options
+	operations []operation
+}
+
+// Files renders the source files of the client package, keyed by file name.
+func (g *Generator) Files() (map[string][]byte, error) {
+	if err := g.collectOperations(); err != nil {
+		return nil, err
+	}
+
+	files := map[string]*jen.File{
+		"api.go":         g.api(),
+		"context.go":     g.context(),
+		"integration.go": g.integration(),
+		"dto.go":         g.dto(),
+	}
+
+	results := make(map[string][]byte)
+	for name, f := range files {
+		var buf bytes.Buffer
+		if err := f.Render(&buf); err != nil {
+			return nil, errors.Wrapf(err, "Failed to render %q", name)
+		}
+		results[name] = buf.Bytes()
+	}
+
+	return results, nil
+}
+
+// WriteFiles renders the client package into the specified directory.
+func (g *Generator) WriteFiles(dir string) error {
+	files, err := g.Files()
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrap(err, "Failed to create directory")
+	}
+
+	for name, data := range files {
+		if err = os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			return errors.Wrapf(err, "Failed to write %q", name)
+		}
+	}
+
+	return nil
+}
+
+type parameter struct {
+	name     string
+	in       string
+	required bool
+	schema   *openapi3.SchemaRef
+}
+
+func (p parameter) FieldName() string {
+	return identifier(p.name)
+}
+
+type operation struct {
+	name         string
+	endpointName string
+	method       string
+	path         string
+	summary      string
+	parameters   []parameter
+	body         *openapi3.SchemaRef
+	bodyRequired bool
+	response     *openapi3.SchemaRef
+	envelope     bool
+}
+
+func (o operation) MethodName() string {
+	return identifier(o.name)
+}
+
+func (o operation) RequestTypeName() string {
+	return o.MethodName() + "Request"
+}
+
+func (o operation) HasRequest() bool {
+	return len(o.parameters) > 0 || o.body != nil
+}
+
+func (g *Generator) collectOperations() error {
+	g.operations = nil
+
+	var paths []string
+	for p := range g.spec.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		pathItem := g.spec.Paths[p]
+		for method, op := range pathItem.Operations() {
+			if op.OperationID == "" {
+				return errors.Errorf("Operation %s %s has no operationId", method, p)
+			}
+
+			o := operation{
+				name:         op.OperationID,
+				endpointName: strcase.ToLowerCamel(op.OperationID),
+				method:       method,
+				path:         p,
+				summary:      op.Summary,
+			}
+
+			params := make(map[string]parameter)
+			for _, ref := range append(append(openapi3.Parameters{}, pathItem.Parameters...), op.Parameters...) {
+				if ref.Value == nil || ref.Value.In == openapi3.ParameterInCookie {
+					continue
+				}
+				params[ref.Value.In+":"+ref.Value.Name] = parameter{
+					name:     ref.Value.Name,
+					in:       ref.Value.In,
+					required: ref.Value.Required || ref.Value.In == openapi3.ParameterInPath,
+					schema:   ref.Value.Schema,
+				}
+			}
+			for _, param := range params {
+				o.parameters = append(o.parameters, param)
+			}
+			sort.Slice(o.parameters, func(i, j int) bool {
+				return o.parameters[i].FieldName() < o.parameters[j].FieldName()
+			})
+
+			if op.RequestBody != nil && op.RequestBody.Value != nil {
+				if mediaType := op.RequestBody.Value.Content.Get("application/json"); mediaType != nil {
+					o.body = mediaType.Schema
+					o.bodyRequired = op.RequestBody.Value.Required
+				}
+			}
+
+			o.response = successSchema(op.Responses)
+			if payload, ok := envelopePayload(o.response); ok {
+				o.response, o.envelope = payload, true
+			}
+
+			g.operations = append(g.operations, o)
+		}
+	}
+
+	sort.SliceStable(g.operations, func(i, j int) bool {
+		return g.operations[i].MethodName() < g.operations[j].MethodName()
+	})
+
+	return nil
+}
+
+// successSchema returns the JSON schema of the first successful response.
+func successSchema(responses openapi3.Responses) *openapi3.SchemaRef {
+	var codes []string
+	for code := range responses {
+		if status, err := strconv.Atoi(code); err == nil && status >= 200 && status < 300 {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		response := responses[code].Value
+		if response == nil {
+			continue
+		}
+		if mediaType := response.Content.Get("application/json"); mediaType != nil && mediaType.Schema != nil {
+			return mediaType.Schema
+		}
+	}
+
+	return nil
+}
+
+// envelopePayload returns the payload schema of an MSX envelope response.
+func envelopePayload(schema *openapi3.SchemaRef) (*openapi3.SchemaRef, bool) {
+	if schema == nil {
+		return nil, false
+	}
+
+	if !isEnvelope(schema) {
+		return nil, false
+	}
+
+	properties := make(map[string]*openapi3.SchemaRef)
+	collectProperties(schema, properties, nil)
+	return properties["responseObject"], true
+}
+
+func collectProperties(schema *openapi3.SchemaRef, properties map[string]*openapi3.SchemaRef, required map[string]bool) {
+	if schema == nil || schema.Value == nil {
+		return
+	}
+
+	for name, property := range schema.Value.Properties {
+		if _, ok := properties[name]; !ok {
+			properties[name] = property
+		}
+	}
+
+	if required != nil {
+		for _, name := range schema.Value.Required {
+			required[name] = true
+		}
+	}
+
+	for _, subschema := range schema.Value.AllOf {
+		collectProperties(subschema, properties, required)
+	}
+}
+
+func (g *Generator) newFile() *jen.File {
+	f := jen.NewFile(g.options.PackageName)
+	f.HeaderComment(headerComment)
+	f.ImportName(pkgIntegration, "integration")
+	f.ImportName(pkgRestClient, "restclient")
+	f.ImportName(pkgTypes, "types")
+	return f
+}
+
+func (g *Generator) mockName() string {
+	return "Mock" + identifier(g.options.PackageName)
+}
+
+func (g *Generator) api() *jen.File {
+	f := g.newFile()
+	f.PackageComment("//go:generate mockery --inpackage --name=Api --structname=" + g.mockName())
+
+	var methods []jen.Code
+	for _, o := range g.operations {
+		methods = append(methods, g.methodSignature(jen.Id(o.MethodName()), o))
+	}
+
+	f.Type().Id("Api").Interface(methods...)
+	return f
+}
+
+func (g *Generator) context() *jen.File {
+	f := g.newFile()
+
+	f.Type().Id("contextKey").Int()
+	f.Const().Id("contextKeyIntegration").Id("contextKey").Op("=").Iota()
+
+	f.Func().Id("IntegrationFromContext").
+		Params(jen.Id("ctx").Qual(pkgContext, "Context")).
+		Id("Api").
+		Block(
+			jen.List(jen.Id("value"), jen.Id("_")).Op(":=").
+				Id("ctx").Dot("Value").Call(jen.Id("contextKeyIntegration")).Assert(jen.Id("Api")),
+			jen.Return(jen.Id("value")))
+
+	f.Line()
+	f.Func().Id("ContextWithIntegration").
+		Params(jen.Id("ctx").Qual(pkgContext, "Context"), jen.Id("api").Id("Api")).
+		Qual(pkgContext, "Context").
+		Block(jen.Return(jen.Qual(pkgContext, "WithValue").Call(
+			jen.Id("ctx"), jen.Id("contextKeyIntegration"), jen.Id("api"))))
+
+	return f
+}
+
+func (g *Generator) integration() *jen.File {
+	f := g.newFile()
+	f.Anon(pkgHttpErrors)
+
+	var names []jen.Code
+	for _, o := range g.operations {
+		names = append(names, jen.Id(endpointConstant(o)).Op("=").Lit(o.endpointName))
+	}
+	names = append(names, jen.Id("serviceName").Op("=").Lit(g.options.ServiceName))
+	f.Const().Defs(names...)
+
+	f.Var().Id("endpoints").Op("=").Map(jen.String()).Qual(pkgIntegration, "MsxServiceEndpoint").
+		Values(jen.DictFunc(func(d jen.Dict) {
+			for _, o := range g.operations {
+				d[jen.Id(endpointConstant(o))] = jen.Values(jen.Dict{
+					jen.Id("Method"): jen.Lit(o.method),
+					jen.Id("Path"):   jen.Lit(o.path),
+				})
+			}
+		}))
+
+	f.Type().Id("Integration").Struct(jen.Qual(pkgIntegration, "MsxServiceExecutor"))
+
+	f.Func().Id("NewIntegration").
+		Params(jen.Id("ctx").Qual(pkgContext, "Context")).
+		Params(jen.Id("Api"), jen.Error()).
+		Block(
+			jen.Id("integrationInstance").Op(":=").Id("IntegrationFromContext").Call(jen.Id("ctx")),
+			jen.If(jen.Id("integrationInstance").Op("==").Nil()).Block(
+				jen.Id("integrationInstance").Op("=").Op("&").Id("Integration").Values(jen.Dict{
+					jen.Id("MsxServiceExecutor"): jen.Qual(pkgIntegration, "NewMsxService").Call(
+						jen.Id("ctx"), jen.Id("serviceName"), jen.Id("endpoints")),
+				})),
+			jen.Return(jen.Id("integrationInstance"), jen.Nil()))
+
+	f.Line()
+	f.Func().Id("NewIntegrationWithExecutor").
+		Params(jen.Id("executor").Qual(pkgIntegration, "MsxServiceExecutor")).
+		Id("Api").
+		Block(jen.Return(jen.Op("&").Id("Integration").Values(jen.Dict{
+			jen.Id("MsxServiceExecutor"): jen.Id("executor"),
+		})))
+
+	for _, o := range g.operations {
+		g.method(f, o)
+	}
+
+	return f
+}
+
+func endpointConstant(o operation) string {
+	return "endpointName" + o.MethodName()
+}
+
+func (g *Generator) methodSignature(s *jen.Statement, o operation) *jen.Statement {
+	if o.HasRequest() {
+		s = s.Params(jen.Id("request").Id(o.RequestTypeName()))
+	} else {
+		s = s.Params()
+	}
+
+	if o.response == nil {
+		return s.Error()
+	}
+
+	return s.Params(g.resultType(o.response), jen.Error())
+}
+
+// resultType returns a pointer to named and struct types, and the plain type otherwise.
+func (g *Generator) resultType(schema *openapi3.SchemaRef) *jen.Statement {
+	if isStruct(schema) {
+		return jen.Op("*").Add(g.goType(schema))
+	}
+	return g.goType(schema)
+}
+
+func (g *Generator) method(f *jen.File, o operation) {
+	f.Line()
+	if o.summary != "" {
+		f.Comment(o.summary)
+	}
+
+	request := jen.Dict{
+		jen.Id("EndpointName"): jen.Id(endpointConstant(o)),
+	}
+
+	parameterSets := map[string]string{
+		openapi3.ParameterInPath:   "PathParameters",
+		openapi3.ParameterInQuery:  "QueryParameters",
+		openapi3.ParameterInHeader: "Headers",
+	}
+	for in, field := range parameterSets {
+		values := jen.Dict{}
+		for _, p := range o.parameters {
+			if p.in == in {
+				values[jen.Lit(p.name)] = jen.Id("request").Dot(p.FieldName())
+			}
+		}
+		if len(values) > 0 {
+			request[jen.Id(field)] = jen.Qual(pkgRestClient, "Parameters").Values(values)
+		}
+	}
+
+	if o.body != nil {
+		request[jen.Id("Body")] = jen.Id("request").Dot("Body")
+	}
+
+	if o.envelope {
+		request[jen.Id("ExpectEnvelope")] = jen.True()
+	}
+
+	var body []jen.Code
+	if o.response == nil {
+		body = append(body,
+			jen.List(jen.Id("_"), jen.Id("err")).Op(":=").Qual(pkgRestClient, "Execute").Call(
+				jen.Id("i").Dot("MsxServiceExecutor"), jen.Qual(pkgRestClient, "Request").Values(request)),
+			jen.Return(jen.Id("err")))
+	} else {
+		request[jen.Id("Payload")] = jen.Id("payload")
+
+		var result jen.Code = jen.Op("*").Id("payload")
+		if isStruct(o.response) {
+			result = jen.Id("payload")
+		}
+
+		body = append(body,
+			jen.Id("payload").Op(":=").New(g.goType(o.response)),
+			jen.List(jen.Id("_"), jen.Id("err")).Op(":=").Qual(pkgRestClient, "Execute").Call(
+				jen.Id("i").Dot("MsxServiceExecutor"), jen.Qual(pkgRestClient, "Request").Values(request)),
+			jen.If(jen.Id("err").Op("!=").Nil()).Block(
+				jen.Return(zeroValue(o.response), jen.Id("err"))),
+			jen.Return(result, jen.Nil()))
+	}
+
+	g.methodSignature(f.Func().Params(jen.Id("i").Id("Integration")).Id(o.MethodName()), o).Block(body...)
+}
+
+func zeroValue(schema *openapi3.SchemaRef) jen.Code {
+	if isStruct(schema) {
+		return jen.Nil()
+	}
+
+	switch schemaType(schema) {
+	case "string":
+		switch schema.Value.Format {
+		case "uuid":
+			return jen.Nil()
+		case "date-time":
+			return jen.Qual(pkgTypes, "Time").Values()
+		}
+		return jen.Lit("")
+	case "integer", "number":
+		return jen.Lit(0)
+	case "boolean":
+		return jen.False()
+	}
+
+	return jen.Nil()
+}
+
+func (g *Generator) dto() *jen.File {
+	f := g.newFile()
+
+	for _, o := range g.operations {
+		if !o.HasRequest() {
+			continue
+		}
+
+		var fields []jen.Code
+		for _, p := range o.parameters {
+			fields = append(fields, g.field(p.FieldName(), p.schema, p.required))
+		}
+		if o.body != nil {
+			fields = append(fields, g.field("Body", o.body, o.bodyRequired))
+		}
+
+		f.Type().Id(o.RequestTypeName()).Struct(fields...)
+		f.Line()
+	}
+
+	if g.spec.Components.Schemas == nil {
+		return f
+	}
+
+	var names []string
+	for name := range g.spec.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		schema := g.spec.Components.Schemas[name]
+		if schema.Value == nil || isEnvelope(schema) {
+			continue
+		}
+
+		if schema.Value.Description != "" {
+			f.Comment(schema.Value.Description)
+		}
+
+		statement := f.Type().Id(identifier(name))
+		if schemaType(schema) == "object" && schema.Value.AdditionalProperties == nil {
+			statement.Add(g.structType(schema))
+		} else {
+			statement.Add(g.goType(&openapi3.SchemaRef{Value: schema.Value}))
+		}
+	}
+
+	return f
+}
+
+func (g *Generator) field(name string, schema *openapi3.SchemaRef, required bool) jen.Code {
+	fieldType := g.goType(schema)
+	if !required && isPointable(schema) {
+		fieldType = jen.Op("*").Add(fieldType)
+	}
+	return jen.Id(name).Add(fieldType)
+}
+
+func (g *Generator) structType(schema *openapi3.SchemaRef) *jen.Statement {
+	properties := make(map[string]*openapi3.SchemaRef)
+	required := make(map[string]bool)
+	collectProperties(schema, properties, required)
+
+	var names []string
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var fields []jen.Code
+	for _, name := range names {
+		tag := name
+		if !required[name] {
+			tag += ",omitempty"
+		}
+		fields = append(fields, jen.Add(g.field(identifier(name), properties[name], required[name])).
+			Tag(map[string]string{"json": tag}))
+	}
+
+	return jen.Struct(fields...)
+}
+
+// goType returns the Go type for the schema.
+func (g *Generator) goType(schema *openapi3.SchemaRef) *jen.Statement {
+	if schema == nil || schema.Value == nil {
+		return jen.Interface()
+	}
+
+	if strings.HasPrefix(schema.Ref, schemaRefPrefix) {
+		return jen.Id(identifier(strings.TrimPrefix(schema.Ref, schemaRefPrefix)))
+	}
+
+	switch schemaType(schema) {
+	case "string":
+		switch schema.Value.Format {
+		case "uuid":
+			return jen.Qual(pkgTypes, "UUID")
+		case "date-time":
+			return jen.Qual(pkgTypes, "Time")
+		}
+		return jen.String()
+
+	case "integer":
+		switch schema.Value.Format {
+		case "int32":
+			return jen.Int32()
+		case "int64":
+			return jen.Int64()
+		}
+		return jen.Int()
+
+	case "number":
+		if schema.Value.Format == "float" || schema.Value.Format == "float32" {
+			return jen.Float32()
+		}
+		return jen.Float64()
+
+	case "boolean":
+		return jen.Bool()
+
+	case "array":
+		return jen.Index().Add(g.goType(schema.Value.Items))
+
+	case "object":
+		if schema.Value.AdditionalProperties != nil {
+			return jen.Map(jen.String()).Add(g.goType(schema.Value.AdditionalProperties))
+		}
+		if len(schema.Value.Properties) == 0 && len(schema.Value.AllOf) == 0 {
+			return jen.Map(jen.String()).Interface()
+		}
+		return g.structType(schema)
+	}
+
+	return jen.Interface()
+}
+
+// schemaType returns the effective type of the schema.
+func schemaType(schema *openapi3.SchemaRef) string {
+	if schema == nil || schema.Value == nil {
+		return ""
+	}
+
+	if schema.Value.Type != "" {
+		return schema.Value.Type
+	}
+
+	if len(schema.Value.Properties) > 0 || len(schema.Value.AllOf) > 0 || schema.Value.AdditionalProperties != nil {
+		return "object"
+	}
+
+	return ""
+}
+
+func isStruct(schema *openapi3.SchemaRef) bool {
+	return schemaType(schema) == "object" && schema.Value.AdditionalProperties == nil &&
+		(len(schema.Value.Properties) > 0 || len(schema.Value.AllOf) > 0)
+}
+
+func isPointable(schema *openapi3.SchemaRef) bool {
+	switch schemaType(schema) {
+	case "array", "":
+		return false
+	case "object":
+		return isStruct(schema)
+	case "string":
+		return schema.Value.Format != "uuid"
+	}
+	return true
+}
+
+func isEnvelope(schema *openapi3.SchemaRef) bool {
+	properties := make(map[string]*openapi3.SchemaRef)
+	collectProperties(schema, properties, nil)
+	return properties["success"] != nil && properties["command"] != nil && properties["httpStatus"] != nil
+}
+
+// identifier converts a name into an exported Go identifier.
+func identifier(name string) string {
+	result := strcase.ToCamel(strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name))
+
+	if result == "" || result[0] >= '0' && result[0] <= '9' {
+		result = "X" + result
+	}
+
+	return result
+}
+
+// NewGenerator creates a client generator for the OpenAPI document.
+func NewGenerator(spec *openapi3.Swagger, options Options) (*Generator, error) {
+	if options.PackageName == "" {
+		return nil, errors.New("Package name not specified")
+	}
+
+	if options.ServiceName == "" {
+		return nil, errors.New("Service name not specified")
+	}
+
+	return &Generator{
+		spec:    spec,
+		options: options,
+	}, nil
+}
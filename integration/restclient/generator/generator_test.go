@@ -0,0 +1,173 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package generator
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+const testSpec = `
+openapi: 3.0.0
+info:
+  title: Device Service
+  version: 1.0.0
+paths:
+  /api/v1/devices/{deviceId}:
+    parameters:
+      - name: deviceId
+        in: path
+        required: true
+        schema:
+          type: string
+          format: uuid
+    get:
+      operationId: getDevice
+      summary: Retrieve a device
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Device"
+    put:
+      operationId: updateDevice
+      parameters:
+        - name: force
+          in: query
+          schema:
+            type: boolean
+        - name: X-Tenant
+          in: header
+          schema:
+            type: string
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/Device"
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Envelope"
+    delete:
+      operationId: deleteDevice
+      responses:
+        "204":
+          description: No Content
+  /api/v1/devices:
+    get:
+      operationId: listDevices
+      parameters:
+        - name: tag
+          in: query
+          schema:
+            type: array
+            items:
+              type: string
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: "#/components/schemas/Device"
+components:
+  schemas:
+    Device:
+      description: Device describes a managed device.
+      type: object
+      required: [name]
+      properties:
+        id:
+          type: string
+          format: uuid
+        name:
+          type: string
+        count:
+          type: integer
+          format: int32
+        labels:
+          type: object
+          additionalProperties:
+            type: string
+        created:
+          type: string
+          format: date-time
+    Envelope:
+      allOf:
+        - type: object
+          properties:
+            command:
+              type: string
+            httpStatus:
+              type: string
+            success:
+              type: boolean
+        - type: object
+          properties:
+            responseObject:
+              $ref: "#/components/schemas/Device"
+`
+
+func TestGenerator_Files(t *testing.T) {
+	spec, err := openapi3.NewSwaggerLoader().LoadSwaggerFromData([]byte(testSpec))
+	require.NoError(t, err)
+
+	g, err := NewGenerator(spec, Options{PackageName: "devices", ServiceName: "deviceservice"})
+	require.NoError(t, err)
+
+	files, err := g.Files()
+	require.NoError(t, err)
+	require.Len(t, files, 4)
+
+	fset := token.NewFileSet()
+	for name, data := range files {
+		_, err = parser.ParseFile(fset, name, data, parser.ParseComments)
+		assert.NoError(t, err, "%s:\n%s", name, data)
+	}
+
+	api := string(files["api.go"])
+	assert.Contains(t, api, "//go:generate mockery --inpackage --name=Api --structname=MockDevices")
+	assert.Contains(t, api, "DeleteDevice(request DeleteDeviceRequest) error")
+	assert.Contains(t, api, "GetDevice(request GetDeviceRequest) (*Device, error)")
+	assert.Contains(t, api, "ListDevices(request ListDevicesRequest) ([]Device, error)")
+	assert.Contains(t, api, "UpdateDevice(request UpdateDeviceRequest) (*Device, error)")
+
+	integration := string(files["integration.go"])
+	assert.Contains(t, integration, `_ "cto-github.cisco.com/NFV-BU/go-msx/ops/restops/httperrors"`)
+	assert.Regexp(t, `serviceName\s+= "deviceservice"`, integration)
+	assert.Regexp(t, `endpointNameUpdateDevice:\s+\{\s*Method: "PUT",\s*Path:\s+"/api/v1/devices/\{deviceId\}"`, integration)
+	assert.Regexp(t, `ExpectEnvelope:\s+true`, integration)
+	assert.Contains(t, integration, `"X-Tenant": request.XTenant`)
+
+	dto := string(files["dto.go"])
+	assert.Regexp(t, `Name\s+string\s+`+"`json:\"name\"`", dto)
+	assert.Regexp(t, `Count\s+\*int32\s+`+"`json:\"count,omitempty\"`", dto)
+	assert.Regexp(t, `Labels\s+map\[string\]string`, dto)
+	assert.Regexp(t, `Created\s+\*types.Time`, dto)
+	assert.Regexp(t, `Force\s+\*bool`, dto)
+	assert.Regexp(t, `Body\s+Device`, dto)
+	assert.NotContains(t, dto, "type Envelope")
+}
+
+func TestNewGenerator(t *testing.T) {
+	_, err := NewGenerator(&openapi3.Swagger{}, Options{ServiceName: "service"})
+	assert.Error(t, err)
+
+	_, err = NewGenerator(&openapi3.Swagger{}, Options{PackageName: "service"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,164 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package restclient
+
+import (
+	"encoding"
+	"fmt"
+	"github.com/spf13/cast"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Parameters contains named request parameter values.  Nil values are omitted.
+type Parameters map[string]any
+
+// Path returns the parameters as path template values.  Values are path-escaped,
+// and slices are joined with commas.
+func (p Parameters) Path() map[string]string {
+	result := make(map[string]string)
+	for name, value := range p {
+		values, ok := formatValues(value)
+		if !ok {
+			continue
+		}
+		for i := range values {
+			values[i] = url.PathEscape(values[i])
+		}
+		result[name] = strings.Join(values, ",")
+	}
+	return result
+}
+
+// Query returns the parameters as query values.  Slices are repeated, and
+// maps are exploded into their entries.
+func (p Parameters) Query() url.Values {
+	result := make(url.Values)
+	for name, value := range p {
+		if entries, ok := formatEntries(value); ok {
+			for k, v := range entries {
+				result[k] = append(result[k], v)
+			}
+			continue
+		}
+
+		if values, ok := formatValues(value); ok {
+			result[name] = append(result[name], values...)
+		}
+	}
+	return result
+}
+
+// Header returns the parameters as header values.  Slices are joined with commas.
+func (p Parameters) Header() http.Header {
+	result := make(http.Header)
+	for name, value := range p {
+		if values, ok := formatValues(value); ok {
+			result.Set(name, strings.Join(values, ","))
+		}
+	}
+	return result
+}
+
+// indirect dereferences pointers, returning false for nil values.
+func indirect(value any) (reflect.Value, bool) {
+	v := reflect.ValueOf(value)
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	return v, v.IsValid()
+}
+
+// stringMarshaler is implemented by types such as types.UUID and types.Time.
+type stringMarshaler interface {
+	MarshalText() (string, error)
+}
+
+func formatValue(v reflect.Value) string {
+	value := v.Interface()
+	if v.CanAddr() {
+		value = v.Addr().Interface()
+	}
+
+	switch typed := value.(type) {
+	case encoding.TextMarshaler:
+		if text, err := typed.MarshalText(); err == nil {
+			return string(text)
+		}
+	case stringMarshaler:
+		if text, err := typed.MarshalText(); err == nil {
+			return text
+		}
+	case fmt.Stringer:
+		return typed.String()
+	}
+	return cast.ToString(v.Interface())
+}
+
+// isScalar returns true for values to be formatted as a single value.
+func isScalar(v reflect.Value) bool {
+	switch v.Interface().(type) {
+	case encoding.TextMarshaler, stringMarshaler, fmt.Stringer:
+		return true
+	}
+	return v.Kind() != reflect.Slice && v.Kind() != reflect.Array && v.Kind() != reflect.Map
+}
+
+func formatValues(value any) ([]string, bool) {
+	v, ok := indirect(value)
+	if !ok {
+		return nil, false
+	}
+
+	switch {
+	case isScalar(v):
+		return []string{formatValue(v)}, true
+
+	case v.Kind() == reflect.Slice, v.Kind() == reflect.Array:
+		var values []string
+		for i := 0; i < v.Len(); i++ {
+			if e, ok := indirect(v.Index(i).Interface()); ok {
+				values = append(values, formatValue(e))
+			}
+		}
+		return values, len(values) > 0
+
+	default:
+		entries, _ := formatEntries(value)
+		var keys []string
+		for k := range entries {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var values []string
+		for _, k := range keys {
+			values = append(values, k, entries[k])
+		}
+		return values, len(values) > 0
+	}
+}
+
+func formatEntries(value any) (map[string]string, bool) {
+	v, ok := indirect(value)
+	if !ok || v.Kind() != reflect.Map {
+		return nil, false
+	}
+
+	entries := make(map[string]string)
+	iter := v.MapRange()
+	for iter.Next() {
+		if e, ok := indirect(iter.Value().Interface()); ok {
+			entries[cast.ToString(iter.Key().Interface())] = formatValue(e)
+		}
+	}
+	return entries, true
+}
@@ -0,0 +1,94 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package restclient
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestParameters_Path(t *testing.T) {
+	id := types.MustParseUUID("e2f1a3a4-7f5c-4b6e-9a0a-1c2d3e4f5a6b")
+	name := "a b/c"
+
+	tests := []struct {
+		name   string
+		params Parameters
+		want   map[string]string
+	}{
+		{
+			name:   "Scalars",
+			params: Parameters{"count": 3, "enabled": true, "name": name},
+			want:   map[string]string{"count": "3", "enabled": "true", "name": "a%20b%2Fc"},
+		},
+		{
+			name:   "Pointers",
+			params: Parameters{"name": &name, "missing": (*string)(nil)},
+			want:   map[string]string{"name": "a%20b%2Fc"},
+		},
+		{
+			name:   "UUID",
+			params: Parameters{"id": id},
+			want:   map[string]string{"id": "e2f1a3a4-7f5c-4b6e-9a0a-1c2d3e4f5a6b"},
+		},
+		{
+			name:   "Slice",
+			params: Parameters{"ids": []int{1, 2}},
+			want:   map[string]string{"ids": "1,2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.params.Path())
+		})
+	}
+}
+
+func TestParameters_Query(t *testing.T) {
+	tests := []struct {
+		name   string
+		params Parameters
+		want   url.Values
+	}{
+		{
+			name:   "Scalars",
+			params: Parameters{"page": 1, "sort": "name", "missing": nil},
+			want:   url.Values{"page": {"1"}, "sort": {"name"}},
+		},
+		{
+			name:   "Slice",
+			params: Parameters{"tag": []string{"a", "b"}},
+			want:   url.Values{"tag": {"a", "b"}},
+		},
+		{
+			name:   "Map",
+			params: Parameters{"filter": map[string]string{"a": "1", "b": "2"}},
+			want:   url.Values{"a": {"1"}, "b": {"2"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.params.Query())
+		})
+	}
+}
+
+func TestParameters_Header(t *testing.T) {
+	got := Parameters{
+		"x-tenant": "abc",
+		"accept":   []string{"application/json", "text/plain"},
+		"missing":  (*int)(nil),
+	}.Header()
+
+	assert.Equal(t, http.Header{
+		"X-Tenant": {"abc"},
+		"Accept":   {"application/json,text/plain"},
+	}, got)
+}
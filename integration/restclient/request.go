@@ -0,0 +1,62 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package restclient
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/integration"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"net/http"
+)
+
+// Request describes a typed call to a named service endpoint.
+type Request struct {
+	EndpointName    string
+	PathParameters  Parameters
+	QueryParameters Parameters
+	Headers         Parameters
+	Body            any
+	Payload         any
+	ExpectEnvelope  bool
+}
+
+// EndpointRequest converts the typed request into an integration endpoint request.
+func (r Request) EndpointRequest() (*integration.MsxEndpointRequest, error) {
+	request := &integration.MsxEndpointRequest{
+		EndpointName:       r.EndpointName,
+		EndpointParameters: r.PathParameters.Path(),
+		QueryParameters:    r.QueryParameters.Query(),
+		Headers:            r.Headers.Header(),
+		Payload:            r.Payload,
+		ExpectEnvelope:     r.ExpectEnvelope,
+	}
+
+	if r.Body != nil {
+		body, err := json.Marshal(r.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to encode request body")
+		}
+		request.Body = body
+		request.Headers.Set("Content-Type", "application/json")
+	}
+
+	return request, nil
+}
+
+// Execute sends the typed request using the executor.  Error responses are
+// returned as a ResponseError.
+func Execute(executor integration.MsxServiceExecutor, request Request) (*integration.MsxResponse, error) {
+	endpointRequest, err := request.EndpointRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := executor.Execute(endpointRequest)
+	if err != nil && response != nil && response.StatusCode >= http.StatusBadRequest {
+		err = NewResponseError(response.StatusCode, err)
+	}
+
+	return response, err
+}
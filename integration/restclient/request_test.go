@@ -0,0 +1,100 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package restclient
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/integration"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops"
+	"cto-github.cisco.com/NFV-BU/go-msx/repository"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestExecute(t *testing.T) {
+	type device struct {
+		Name string `json:"name"`
+	}
+
+	restops.SetMappedStatusCodeError(http.StatusNotFound, repository.ErrNotFound)
+
+	tests := []struct {
+		name       string
+		response   *integration.MsxResponse
+		err        error
+		wantErr    error
+		wantStatus int
+	}{
+		{
+			name:     "Success",
+			response: &integration.MsxResponse{StatusCode: http.StatusOK},
+		},
+		{
+			name:       "Mapped",
+			response:   &integration.MsxResponse{StatusCode: http.StatusNotFound},
+			err:        errors.New("Device not found"),
+			wantErr:    repository.ErrNotFound,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Unmapped",
+			response:   &integration.MsxResponse{StatusCode: http.StatusBadGateway},
+			err:        errors.New("Bad gateway"),
+			wantStatus: http.StatusBadGateway,
+		},
+		{
+			name: "Transport",
+			err:  errors.New("Connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := new(integration.MockMsxServiceExecutor)
+			executor.
+				On("Execute", mock.MatchedBy(func(r *integration.MsxEndpointRequest) bool {
+					return r.EndpointName == "updateDevice" &&
+						r.EndpointParameters["deviceId"] == "abc" &&
+						r.QueryParameters.Get("force") == "true" &&
+						r.Headers.Get("Content-Type") == "application/json" &&
+						string(r.Body) == `{"name":"router"}`
+				})).
+				Return(tt.response, tt.err)
+
+			response, err := Execute(executor, Request{
+				EndpointName:    "updateDevice",
+				PathParameters:  Parameters{"deviceId": "abc"},
+				QueryParameters: Parameters{"force": true},
+				Body:            device{Name: "router"},
+				Payload:         new(device),
+			})
+
+			executor.AssertExpectations(t)
+			assert.Equal(t, tt.response, response)
+
+			if tt.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, tt.err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+
+			var statusErr restops.StatusCodeProvider
+			if tt.wantStatus != 0 {
+				require.True(t, errors.As(err, &statusErr))
+				assert.Equal(t, tt.wantStatus, statusErr.StatusCode())
+			} else {
+				assert.False(t, errors.As(err, &statusErr))
+			}
+		})
+	}
+}
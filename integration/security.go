@@ -24,6 +24,10 @@ func (c *SecurityClientSettings) Authorization() string {
 	return "Basic " + base64.StdEncoding.EncodeToString(clientCredentials)
 }
 
+func init() {
+	config.RegisterSchema[SecurityClientSettings](configRootIntegrationSecurityClient)
+}
+
 func NewSecurityClientSettings(ctx context.Context) (cfg *SecurityClientSettings, err error) {
 	cfg = &SecurityClientSettings{}
 	err = config.FromContext(ctx).LatestValues().Populate(cfg, configRootIntegrationSecurityClient)
@@ -116,6 +116,10 @@ func (c *ConnectionConfig) findSupportedVersion(version string) (*sarama.KafkaVe
 	return nil, errors.New("Unsupported version: " + version)
 }
 
+func init() {
+	config.RegisterSchema[ConnectionConfig](configRootKafka)
+}
+
 func NewConnectionConfig(cfg *config.Config) (*ConnectionConfig, error) {
 	connectionConfig := new(ConnectionConfig)
 	if err := cfg.Populate(connectionConfig, configRootKafka); err != nil {
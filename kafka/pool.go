@@ -30,6 +30,10 @@ type ConnectionPoolConfig struct {
 	PrefillParallelism int           `config:"default=0"`
 }
 
+func init() {
+	config.RegisterSchema[ConnectionPoolConfig](configRootKafkaPool)
+}
+
 func NewConnectionPoolConfig(cfg *config.Config) (*ConnectionPoolConfig, error) {
 	var poolConfig ConnectionPoolConfig
 	if err := cfg.Populate(&poolConfig, configRootKafkaPool); err != nil {
@@ -29,6 +29,10 @@ type LeaderProperties struct {
 	Disconnected    bool   `config:"default=${cli.flag.disconnected:false}"`
 }
 
+func init() {
+	config.RegisterSchema[ConsulLeaderElectionConfig](configRootConsulLeaderElection)
+}
+
 func NewConsulLeaderElectionConfigFromConfig(cfg *config.Config) (*ConsulLeaderElectionConfig, error) {
 	var leaderElectionConfig ConsulLeaderElectionConfig
 	if err := cfg.Populate(&leaderElectionConfig, configRootConsulLeaderElection); err != nil {
@@ -10,10 +10,15 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"encoding/xml"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/ghodss/yaml"
 	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
 	"io"
 	"io/ioutil"
 	"mime"
+	"reflect"
+	"sort"
 )
 
 type Encoding []string
@@ -124,6 +129,78 @@ func (m XmlMarshaler) WriteEntity(w io.Writer, value interface{}) error {
 	return xml.NewEncoder(w).Encode(value)
 }
 
+// YamlMarshaler converts entities via JSON, so that JSON struct tags are honoured.
+type YamlMarshaler struct{}
+
+func (m YamlMarshaler) ReadEntity(r io.ReadCloser, target interface{}) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, target)
+}
+
+func (m YamlMarshaler) WriteEntity(w io.Writer, value interface{}) error {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+var cborDecMode, _ = cbor.DecOptions{
+	DefaultMapType: reflect.TypeOf(map[string]interface{}{}),
+}.DecMode()
+
+type CborMarshaler struct{}
+
+func (m CborMarshaler) ReadEntity(r io.ReadCloser, target interface{}) error {
+	return cborDecMode.NewDecoder(r).Decode(target)
+}
+
+func (m CborMarshaler) WriteEntity(w io.Writer, value interface{}) error {
+	return cbor.NewEncoder(w).Encode(value)
+}
+
+// ProtobufMarshaler reads and writes entities implementing proto.Message.
+type ProtobufMarshaler struct{}
+
+func (m ProtobufMarshaler) ReadEntity(r io.ReadCloser, target interface{}) error {
+	message, ok := target.(proto.Message)
+	if !ok {
+		return errors.Errorf("Could not decode %T from protobuf", target)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	return proto.Unmarshal(data, message)
+}
+
+func (m ProtobufMarshaler) WriteEntity(w io.Writer, value interface{}) error {
+	message, ok := value.(proto.Message)
+	if !ok && value != nil && reflect.PtrTo(reflect.TypeOf(value)).Implements(ProtoMessageType) {
+		// Port field extraction dereferences pointers to messages
+		pv := reflect.New(reflect.TypeOf(value))
+		pv.Elem().Set(reflect.ValueOf(value))
+		message, ok = pv.Interface().(proto.Message)
+	}
+	if !ok {
+		return errors.Errorf("Could not encode %T to protobuf", value)
+	}
+
+	data, err := proto.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
 type BinaryMarshaler struct{}
 
 func (b BinaryMarshaler) WriteEntity(w io.Writer, value interface{}) (err error) {
@@ -169,21 +246,43 @@ func (b BinaryMarshaler) ReadEntity(r io.ReadCloser, target interface{}) (err er
 }
 
 const (
-	MarshalerJson   = "application/json"
-	MarshalerXml    = "application/xml"
-	MarshalerBinary = "application/octet-stream"
+	MarshalerJson     = "application/json"
+	MarshalerXml      = "application/xml"
+	MarshalerTextXml  = "text/xml"
+	MarshalerYaml     = "application/yaml"
+	MarshalerCbor     = "application/cbor"
+	MarshalerProtobuf = "application/x-protobuf"
+	MarshalerBinary   = "application/octet-stream"
+
+	MarshalerProblemJson = "application/problem+json"
 )
 
 var marshalers = map[string]Marshaler{
-	MarshalerJson:   JsonMarshaler{},
-	MarshalerXml:    XmlMarshaler{},
-	MarshalerBinary: BinaryMarshaler{},
+	MarshalerJson:     JsonMarshaler{},
+	MarshalerXml:      XmlMarshaler{},
+	MarshalerTextXml:  XmlMarshaler{},
+	MarshalerYaml:     YamlMarshaler{},
+	MarshalerCbor:     CborMarshaler{},
+	MarshalerProtobuf: ProtobufMarshaler{},
+	MarshalerBinary:   BinaryMarshaler{},
+
+	MarshalerProblemJson: JsonMarshaler{},
 }
 
 func RegisterMarshaler(name string, m Marshaler) {
 	marshalers[name] = m
 }
 
+// Marshalers returns the sorted list of registered marshaler media types.
+func Marshalers() []string {
+	var results []string
+	for name := range marshalers {
+		results = append(results, name)
+	}
+	sort.Strings(results)
+	return results
+}
+
 func NewMarshaler(marshaler string) (Marshaler, error) {
 	m, ok := marshalers[marshaler]
 
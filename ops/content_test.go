@@ -10,7 +10,10 @@ import (
 	"cto-github.cisco.com/NFV-BU/go-msx/types"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 	"io"
+	"reflect"
 	"testing"
 )
 
@@ -281,3 +284,97 @@ func TestEncoding_Reader(t *testing.T) {
 		})
 	}
 }
+
+func TestMarshalers_RoundTrip(t *testing.T) {
+	type entity struct {
+		Name  string `json:"name" xml:"name"`
+		Count int    `json:"count" xml:"count"`
+	}
+
+	tests := []struct {
+		name   string
+		mime   string
+		value  interface{}
+		target interface{}
+	}{
+		{
+			name:   "Json",
+			mime:   MarshalerJson,
+			value:  entity{Name: "alpha", Count: 2},
+			target: new(entity),
+		},
+		{
+			name:   "Xml",
+			mime:   MarshalerXml,
+			value:  entity{Name: "alpha", Count: 2},
+			target: new(entity),
+		},
+		{
+			name:   "TextXml",
+			mime:   MarshalerTextXml,
+			value:  entity{Name: "alpha", Count: 2},
+			target: new(entity),
+		},
+		{
+			name:   "Yaml",
+			mime:   MarshalerYaml,
+			value:  entity{Name: "alpha", Count: 2},
+			target: new(entity),
+		},
+		{
+			name:   "Cbor",
+			mime:   MarshalerCbor,
+			value:  entity{Name: "alpha", Count: 2},
+			target: new(entity),
+		},
+		{
+			name:   "Protobuf",
+			mime:   MarshalerProtobuf,
+			value:  wrapperspb.String("alpha"),
+			target: new(wrapperspb.StringValue),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMarshaler(tt.mime)
+			assert.NoError(t, err)
+
+			buffer := new(bytes.Buffer)
+			err = m.WriteEntity(buffer, tt.value)
+			assert.NoError(t, err)
+
+			err = m.ReadEntity(io.NopCloser(buffer), tt.target)
+			assert.NoError(t, err)
+
+			if message, ok := tt.target.(proto.Message); ok {
+				assert.True(t, proto.Equal(tt.value.(proto.Message), message))
+			} else {
+				assert.Equal(t, tt.value, *tt.target.(*entity))
+			}
+		})
+	}
+}
+
+func TestProtobufMarshaler_NotMessage(t *testing.T) {
+	m := ProtobufMarshaler{}
+	err := m.WriteEntity(new(bytes.Buffer), types.Pojo{})
+	assert.Error(t, err)
+
+	err = m.ReadEntity(io.NopCloser(new(bytes.Buffer)), new(types.Pojo))
+	assert.Error(t, err)
+}
+
+func TestProtobufMarshaler_MessageValue(t *testing.T) {
+	m := ProtobufMarshaler{}
+	value := reflect.ValueOf(wrapperspb.String("alpha")).Elem().Interface()
+
+	buffer := new(bytes.Buffer)
+	err := m.WriteEntity(buffer, value)
+	assert.NoError(t, err)
+
+	target := new(wrapperspb.StringValue)
+	err = m.ReadEntity(io.NopCloser(buffer), target)
+	assert.NoError(t, err)
+	assert.Equal(t, "alpha", target.GetValue())
+}
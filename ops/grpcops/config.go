@@ -0,0 +1,65 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package grpcops
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/certificate"
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
+	"github.com/bmatcuk/doublestar"
+	"strconv"
+)
+
+const configRootGrpcServer = "server.grpc"
+
+// AuthenticationConfig lists the full method names (e.g. "/grpc.health.v1.Health/Check")
+// which may be called without credentials.  All other methods require an authenticated client.
+type AuthenticationConfig struct {
+	Whitelist []string `config:"default=/grpc.health.v1.Health/*"`
+}
+
+// RequiresAuthentication returns true if the method is not whitelisted.
+func (c AuthenticationConfig) RequiresAuthentication(fullMethod string) (bool, error) {
+	for _, rule := range c.Whitelist {
+		if matches, err := doublestar.Match(rule, fullMethod); err != nil {
+			return true, err
+		} else if matches {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+type ServerConfig struct {
+	Enabled        bool   `config:"default=false"`
+	Host           string `config:"default=${network.outbound.address:0.0.0.0}"`
+	Port           int    `config:"default=9090"`
+	Package        string `config:"default=msx.${info.app.name:api}"`
+	Reflection     bool   `config:"default=true"`
+	Tls            certificate.TLSConfig
+	Authentication AuthenticationConfig
+	Disconnected   bool `config:"default=${cli.flag.disconnected:false}"`
+}
+
+func (c ServerConfig) Address() string {
+	return c.Host + ":" + strconv.Itoa(c.Port)
+}
+
+func (c ServerConfig) GenericAddress() string {
+	return "0.0.0.0:" + strconv.Itoa(c.Port)
+}
+
+func init() {
+	config.RegisterSchema[ServerConfig](configRootGrpcServer)
+}
+
+func NewServerConfig(cfg *config.Config) (*ServerConfig, error) {
+	var serverConfig ServerConfig
+	if err := cfg.Populate(&serverConfig, configRootGrpcServer); err != nil {
+		return nil, err
+	}
+
+	return &serverConfig, nil
+}
@@ -0,0 +1,451 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package grpcops
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/ops"
+	"encoding"
+	"encoding/json"
+	"github.com/iancoleman/strcase"
+	"github.com/swaggest/jsonschema-go"
+	"github.com/swaggest/refl"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	// Register google.protobuf.Value for dynamic fields
+	_ "google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	protoFileStruct   = "google/protobuf/struct.proto"
+	protoMessageValue = ".google.protobuf.Value"
+)
+
+var (
+	jsonMarshalerInstance     json.Marshaler
+	jsonMarshalerType         = reflect.TypeOf(&jsonMarshalerInstance).Elem()
+	encodingTextMarshalerInst encoding.TextMarshaler
+	encodingTextMarshalerType = reflect.TypeOf(&encodingTextMarshalerInst).Elem()
+)
+
+// fieldSpec describes the protobuf type of a reflected go type.
+type fieldSpec struct {
+	kind     descriptorpb.FieldDescriptorProto_Type
+	typeName string
+	repeated bool
+	optional bool
+	mapKey   *fieldSpec
+	mapValue *fieldSpec
+}
+
+func (s fieldSpec) isScalar() bool {
+	return !s.repeated && s.mapKey == nil && s.kind != descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+}
+
+func (s fieldSpec) isCollection() bool {
+	return s.repeated || s.mapKey != nil
+}
+
+var scalarKinds = map[reflect.Kind]descriptorpb.FieldDescriptorProto_Type{
+	reflect.Bool:    descriptorpb.FieldDescriptorProto_TYPE_BOOL,
+	reflect.Int:     descriptorpb.FieldDescriptorProto_TYPE_INT64,
+	reflect.Int8:    descriptorpb.FieldDescriptorProto_TYPE_INT32,
+	reflect.Int16:   descriptorpb.FieldDescriptorProto_TYPE_INT32,
+	reflect.Int32:   descriptorpb.FieldDescriptorProto_TYPE_INT32,
+	reflect.Int64:   descriptorpb.FieldDescriptorProto_TYPE_INT64,
+	reflect.Uint:    descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+	reflect.Uint8:   descriptorpb.FieldDescriptorProto_TYPE_UINT32,
+	reflect.Uint16:  descriptorpb.FieldDescriptorProto_TYPE_UINT32,
+	reflect.Uint32:  descriptorpb.FieldDescriptorProto_TYPE_UINT32,
+	reflect.Uint64:  descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+	reflect.Float32: descriptorpb.FieldDescriptorProto_TYPE_FLOAT,
+	reflect.Float64: descriptorpb.FieldDescriptorProto_TYPE_DOUBLE,
+	reflect.String:  descriptorpb.FieldDescriptorProto_TYPE_STRING,
+}
+
+var schemaTypeKinds = map[jsonschema.SimpleType]descriptorpb.FieldDescriptorProto_Type{
+	jsonschema.String:  descriptorpb.FieldDescriptorProto_TYPE_STRING,
+	jsonschema.Integer: descriptorpb.FieldDescriptorProto_TYPE_INT64,
+	jsonschema.Number:  descriptorpb.FieldDescriptorProto_TYPE_DOUBLE,
+	jsonschema.Boolean: descriptorpb.FieldDescriptorProto_TYPE_BOOL,
+}
+
+// DescriptorBuilder reflects protobuf message definitions from go types,
+// following their JSON serialization.
+type DescriptorBuilder struct {
+	file     *descriptorpb.FileDescriptorProto
+	names    map[string]struct{}
+	messages map[reflect.Type]string
+}
+
+// File returns the protobuf file definition containing the reflected messages.
+func (b *DescriptorBuilder) File() *descriptorpb.FileDescriptorProto {
+	return b.file
+}
+
+func (b *DescriptorBuilder) fullName(name string) string {
+	return "." + b.file.GetPackage() + "." + name
+}
+
+func (b *DescriptorBuilder) uniqueName(name string) string {
+	name = identifier(strcase.ToCamel(name))
+	result := name
+	for i := 2; ; i++ {
+		if _, ok := b.names[result]; !ok {
+			break
+		}
+		result = name + strconv.Itoa(i)
+	}
+	b.names[result] = struct{}{}
+	return result
+}
+
+func (b *DescriptorBuilder) useValue() fieldSpec {
+	found := false
+	for _, dep := range b.file.Dependency {
+		if dep == protoFileStruct {
+			found = true
+			break
+		}
+	}
+	if !found {
+		b.file.Dependency = append(b.file.Dependency, protoFileStruct)
+	}
+
+	return fieldSpec{
+		kind:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+		typeName: protoMessageValue,
+	}
+}
+
+// AddMessage declares a new empty top-level message.
+func (b *DescriptorBuilder) AddMessage(name string) *descriptorpb.DescriptorProto {
+	message := &descriptorpb.DescriptorProto{
+		Name: proto.String(b.uniqueName(name)),
+	}
+	b.file.MessageType = append(b.file.MessageType, message)
+	return message
+}
+
+// AddField appends a field of the specified go type to the message.
+func (b *DescriptorBuilder) AddField(message *descriptorpb.DescriptorProto, jsonName string, t reflect.Type) error {
+	spec, err := b.FieldSpec(t, message.GetName()+strcase.ToCamel(jsonName))
+	if err != nil {
+		return err
+	}
+
+	b.addFieldSpec(message, jsonName, spec)
+	return nil
+}
+
+func (b *DescriptorBuilder) addFieldSpec(message *descriptorpb.DescriptorProto, jsonName string, spec fieldSpec) {
+	name := b.fieldName(message, jsonName)
+	number := int32(len(message.Field) + 1)
+
+	field := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		JsonName: proto.String(jsonName),
+		Number:   proto.Int32(number),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:     spec.kind.Enum(),
+	}
+
+	switch {
+	case spec.mapKey != nil:
+		entryName := strcase.ToCamel(name) + "Entry"
+		message.NestedType = append(message.NestedType, &descriptorpb.DescriptorProto{
+			Name: proto.String(entryName),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				b.entryField("key", 1, *spec.mapKey),
+				b.entryField("value", 2, *spec.mapValue),
+			},
+			Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+		})
+		field.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+		field.TypeName = proto.String(b.fullName(message.GetName() + "." + entryName))
+		field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+
+	case spec.repeated:
+		field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	}
+
+	if spec.typeName != "" && spec.mapKey == nil {
+		field.TypeName = proto.String(spec.typeName)
+	}
+
+	if spec.optional && spec.isScalar() {
+		// Track presence using a synthetic oneof
+		field.Proto3Optional = proto.Bool(true)
+		field.OneofIndex = proto.Int32(int32(len(message.OneofDecl)))
+		message.OneofDecl = append(message.OneofDecl, &descriptorpb.OneofDescriptorProto{
+			Name: proto.String("_" + name),
+		})
+	}
+
+	message.Field = append(message.Field, field)
+}
+
+func (b *DescriptorBuilder) entryField(name string, number int32, spec fieldSpec) *descriptorpb.FieldDescriptorProto {
+	field := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		JsonName: proto.String(name),
+		Number:   proto.Int32(number),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:     spec.kind.Enum(),
+	}
+	if spec.typeName != "" {
+		field.TypeName = proto.String(spec.typeName)
+	}
+	return field
+}
+
+func (b *DescriptorBuilder) fieldName(message *descriptorpb.DescriptorProto, jsonName string) string {
+	name := identifier(strcase.ToSnake(jsonName))
+	result := name
+	for i := 2; ; i++ {
+		found := false
+		for _, field := range message.Field {
+			if field.GetName() == result {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return result
+		}
+		result = name + "_" + strconv.Itoa(i)
+	}
+}
+
+// FieldSpec calculates the protobuf type of the specified go type.  Named
+// messages are declared for any struct types encountered.
+func (b *DescriptorBuilder) FieldSpec(t reflect.Type, nameHint string) (spec fieldSpec, err error) {
+	optional := false
+	if t.Kind() == reflect.Ptr {
+		t = refl.DeepIndirect(t)
+		optional = true
+	}
+
+	defer func() {
+		spec.optional = optional
+	}()
+
+	if isCustomJson(t) {
+		return b.customFieldSpec(t), nil
+	}
+
+	if kind, ok := scalarKinds[t.Kind()]; ok {
+		return fieldSpec{kind: kind}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return b.useValue(), nil
+
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return fieldSpec{kind: descriptorpb.FieldDescriptorProto_TYPE_BYTES}, nil
+		}
+
+		var elem fieldSpec
+		if elem, err = b.FieldSpec(t.Elem(), nameHint+"Item"); err != nil {
+			return
+		}
+		if elem.isCollection() {
+			// Nested collections are not supported by protobuf
+			return b.useValue(), nil
+		}
+		elem.repeated = true
+		return elem, nil
+
+	case reflect.Map:
+		key, ok := scalarKinds[t.Key().Kind()]
+		if !ok || isCustomJson(t.Key()) || key == descriptorpb.FieldDescriptorProto_TYPE_FLOAT ||
+			key == descriptorpb.FieldDescriptorProto_TYPE_DOUBLE {
+			return b.useValue(), nil
+		}
+
+		var value fieldSpec
+		if value, err = b.FieldSpec(t.Elem(), nameHint+"Value"); err != nil {
+			return
+		}
+		if value.isCollection() {
+			return b.useValue(), nil
+		}
+
+		return fieldSpec{
+			kind:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+			mapKey:   &fieldSpec{kind: key},
+			mapValue: &value,
+		}, nil
+
+	case reflect.Struct:
+		var name string
+		if name, err = b.Message(t, nameHint); err != nil {
+			return
+		}
+		return fieldSpec{
+			kind:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+			typeName: name,
+		}, nil
+
+	default:
+		return b.useValue(), nil
+	}
+}
+
+// customFieldSpec uses the JSON schema of a type with custom serialization
+// to select a scalar type, falling back to a dynamic value.
+func (b *DescriptorBuilder) customFieldSpec(t reflect.Type) fieldSpec {
+	if t.Implements(ops.TextMarshalerType) || t.Implements(encodingTextMarshalerType) {
+		return fieldSpec{kind: descriptorpb.FieldDescriptorProto_TYPE_STRING}
+	}
+
+	reflector := jsonschema.Reflector{}
+	schema, err := reflector.Reflect(reflect.Zero(t).Interface())
+	if err == nil && schema.Type != nil {
+		var simpleTypes []jsonschema.SimpleType
+		if schema.Type.SimpleTypes != nil {
+			simpleTypes = append(simpleTypes, *schema.Type.SimpleTypes)
+		}
+		for _, simpleType := range schema.Type.SliceOfSimpleTypeValues {
+			if simpleType != jsonschema.Null {
+				simpleTypes = append(simpleTypes, simpleType)
+			}
+		}
+		if len(simpleTypes) == 1 {
+			if kind, ok := schemaTypeKinds[simpleTypes[0]]; ok {
+				return fieldSpec{kind: kind}
+			}
+		}
+	}
+
+	return b.useValue()
+}
+
+// Message declares a message for the struct type and returns its fully-qualified name.
+func (b *DescriptorBuilder) Message(t reflect.Type, nameHint string) (string, error) {
+	if name, ok := b.messages[t]; ok {
+		return name, nil
+	}
+
+	name := messageName(t)
+	if name == "" {
+		name = nameHint
+	}
+
+	message := b.AddMessage(name)
+	fullName := b.fullName(message.GetName())
+	b.messages[t] = fullName
+
+	if err := b.addStructFields(message, t); err != nil {
+		return "", err
+	}
+
+	return fullName, nil
+}
+
+func (b *DescriptorBuilder) addStructFields(message *descriptorpb.DescriptorProto, t reflect.Type) error {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+
+		jsonName, embedded, skip := jsonFieldName(sf)
+		if skip {
+			continue
+		}
+
+		if embedded {
+			if err := b.addStructFields(message, refl.DeepIndirect(sf.Type)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := b.AddField(message, jsonName, sf.Type); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// jsonFieldName returns the JSON property name of the struct field, following encoding/json.
+func jsonFieldName(sf reflect.StructField) (name string, embedded bool, skip bool) {
+	tag, hasTag := sf.Tag.Lookup("json")
+	if tag == "-" {
+		return "", false, true
+	}
+
+	name = strings.Split(tag, ",")[0]
+
+	if sf.Anonymous && name == "" {
+		t := refl.DeepIndirect(sf.Type)
+		if t.Kind() == reflect.Struct {
+			return "", true, false
+		}
+	}
+
+	if !sf.IsExported() {
+		return "", false, true
+	}
+
+	if !hasTag || name == "" {
+		name = sf.Name
+	}
+
+	return name, false, false
+}
+
+func isCustomJson(t reflect.Type) bool {
+	for _, i := range []reflect.Type{jsonMarshalerType, encodingTextMarshalerType, ops.TextMarshalerType} {
+		if t.Implements(i) || reflect.PtrTo(t).Implements(i) {
+			return true
+		}
+	}
+	return false
+}
+
+var regexpTypeArgument = regexp.MustCompile(`[^\[\],]*[./]`)
+var regexpNonIdentifier = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// messageName generates a message name from a named go type, including any type arguments.
+func messageName(t reflect.Type) string {
+	name := t.Name()
+	if name == "" {
+		return ""
+	}
+
+	name = regexpTypeArgument.ReplaceAllString(name, "")
+	var parts []string
+	for _, part := range regexpNonIdentifier.Split(name, -1) {
+		parts = append(parts, strcase.ToCamel(part))
+	}
+	return strings.Join(parts, "")
+}
+
+// identifier converts a string into a valid protobuf identifier.
+func identifier(s string) string {
+	s = regexpNonIdentifier.ReplaceAllString(s, "_")
+	if s == "" || (s[0] >= '0' && s[0] <= '9') {
+		s = "_" + s
+	}
+	return s
+}
+
+func NewDescriptorBuilder(pkg, path string) *DescriptorBuilder {
+	return &DescriptorBuilder{
+		file: &descriptorpb.FileDescriptorProto{
+			Name:    proto.String(path),
+			Package: proto.String(pkg),
+			Syntax:  proto.String("proto3"),
+		},
+		names:    make(map[string]struct{}),
+		messages: make(map[reflect.Type]string),
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package grpcops
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testPage[I any] struct {
+	Items []I `json:"items"`
+}
+
+type testEmbedded struct {
+	Created time.Time `json:"created"`
+}
+
+type testRecord struct {
+	testEmbedded
+	Id       types.UUID        `json:"id"`
+	Name     string            `json:"name"`
+	Size     *int              `json:"size,omitempty"`
+	Labels   map[string]string `json:"labels"`
+	Matrix   [][]int           `json:"matrix"`
+	Data     []byte            `json:"data"`
+	Extra    any               `json:"extra"`
+	Ignored  string            `json:"-"`
+	Children []testRecordChild `json:"children"`
+}
+
+type testRecordChild struct {
+	Value float64 `json:"value"`
+}
+
+func TestDescriptorBuilder_FieldSpec(t *testing.T) {
+	tests := []struct {
+		name     string
+		t        reflect.Type
+		wantKind descriptorpb.FieldDescriptorProto_Type
+		wantName string
+		repeated bool
+		optional bool
+		isMap    bool
+	}{
+		{name: "String", t: reflect.TypeOf(""), wantKind: descriptorpb.FieldDescriptorProto_TYPE_STRING},
+		{name: "Int", t: reflect.TypeOf(0), wantKind: descriptorpb.FieldDescriptorProto_TYPE_INT64},
+		{name: "Bool", t: reflect.TypeOf(true), wantKind: descriptorpb.FieldDescriptorProto_TYPE_BOOL},
+		{name: "Pointer", t: reflect.TypeOf(new(float64)), wantKind: descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, optional: true},
+		{name: "Bytes", t: reflect.TypeOf([]byte{}), wantKind: descriptorpb.FieldDescriptorProto_TYPE_BYTES},
+		{name: "Slice", t: reflect.TypeOf([]string{}), wantKind: descriptorpb.FieldDescriptorProto_TYPE_STRING, repeated: true},
+		{name: "NestedSlice", t: reflect.TypeOf([][]string{}), wantKind: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, wantName: protoMessageValue},
+		{name: "Map", t: reflect.TypeOf(map[string]int{}), wantKind: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, isMap: true},
+		{name: "Interface", t: reflect.TypeOf(new(any)).Elem(), wantKind: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, wantName: protoMessageValue},
+		{name: "UUID", t: reflect.TypeOf(types.UUID{}), wantKind: descriptorpb.FieldDescriptorProto_TYPE_STRING},
+		{name: "Time", t: reflect.TypeOf(time.Time{}), wantKind: descriptorpb.FieldDescriptorProto_TYPE_STRING},
+		{name: "Struct", t: reflect.TypeOf(testRecordChild{}), wantKind: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, wantName: ".msx.test.TestRecordChild"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewDescriptorBuilder("msx.test", "msx/test/api.proto")
+			spec, err := b.FieldSpec(tt.t, "Hint")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantKind, spec.kind)
+			assert.Equal(t, tt.wantName, spec.typeName)
+			assert.Equal(t, tt.repeated, spec.repeated)
+			assert.Equal(t, tt.optional, spec.optional)
+			assert.Equal(t, tt.isMap, spec.mapKey != nil)
+		})
+	}
+}
+
+func TestDescriptorBuilder_Message(t *testing.T) {
+	b := NewDescriptorBuilder("msx.test", "msx/test/api.proto")
+	name, err := b.Message(reflect.TypeOf(testRecord{}), "Hint")
+	require.NoError(t, err)
+	assert.Equal(t, ".msx.test.TestRecord", name)
+
+	file, err := protodesc.NewFile(b.File(), protoregistry.GlobalFiles)
+	require.NoError(t, err)
+
+	md := file.Messages().ByName("TestRecord")
+	require.NotNil(t, md)
+
+	var jsonNames []string
+	for i := 0; i < md.Fields().Len(); i++ {
+		jsonNames = append(jsonNames, md.Fields().Get(i).JSONName())
+	}
+	assert.Equal(t,
+		[]string{"created", "id", "name", "size", "labels", "matrix", "data", "extra", "children"},
+		jsonNames)
+
+	assert.True(t, md.Fields().ByJSONName("size").HasPresence())
+	assert.True(t, md.Fields().ByJSONName("labels").IsMap())
+	assert.True(t, md.Fields().ByJSONName("children").IsList())
+	assert.NotNil(t, file.Messages().ByName("TestRecordChild"))
+}
+
+func Test_messageName(t *testing.T) {
+	tests := []struct {
+		name string
+		t    reflect.Type
+		want string
+	}{
+		{name: "Named", t: reflect.TypeOf(testRecord{}), want: "TestRecord"},
+		{name: "Generic", t: reflect.TypeOf(testPage[testRecord]{}), want: "TestPageTestRecord"},
+		{name: "Anonymous", t: reflect.TypeOf(struct{}{}), want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, messageName(tt.t))
+		})
+	}
+}
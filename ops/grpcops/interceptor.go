@@ -0,0 +1,267 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package grpcops
+
+import (
+	"bytes"
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops"
+	"cto-github.cisco.com/NFV-BU/go-msx/rbac"
+	"cto-github.cisco.com/NFV-BU/go-msx/security"
+	"cto-github.cisco.com/NFV-BU/go-msx/security/httprequest"
+	"cto-github.cisco.com/NFV-BU/go-msx/trace"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"cto-github.cisco.com/NFV-BU/go-msx/webservice/authprovider"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+)
+
+// ChainUnaryInterceptors composes the interceptors around the handler, with the
+// first interceptor outermost.
+func ChainUnaryInterceptors(interceptors []grpc.UnaryServerInterceptor, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) grpc.UnaryHandler {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(ctx context.Context, req any) (any, error) {
+			return interceptor(ctx, req, info, next)
+		}
+	}
+	return handler
+}
+
+// serverContext combines the request context with the values of the application context.
+type serverContext struct {
+	context.Context
+	values context.Context
+}
+
+func (c serverContext) Value(key any) any {
+	if v := c.Context.Value(key); v != nil {
+		return v
+	}
+	return c.values.Value(key)
+}
+
+// ContextInterceptor supplies the values of the application context, and the
+// supplied injectors, to each request.
+func ContextInterceptor(values context.Context, injectors *types.ContextInjectors) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx = serverContext{Context: ctx, values: values}
+		if injectors != nil {
+			ctx = injectors.Inject(ctx)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// ContextInjectorsInterceptor executes the injectors against the request context.
+func ContextInjectorsInterceptor(injectors types.ContextInjectors) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(injectors.Inject(ctx), req)
+	}
+}
+
+// RecoveryInterceptor converts panics into Internal errors.
+func RecoveryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.WithContext(ctx).Errorf("Recovered from panic in %q: %v", info.FullMethod, r)
+			resp, err = nil, status.Errorf(codes.Internal, "Exception: %v", r)
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// TracingInterceptor executes each request within a trace operation.
+func TracingInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	err = trace.NewOperation(info.FullMethod, func(ctx context.Context) error {
+		var handlerErr error
+		resp, handlerErr = handler(ctx, req)
+		return handlerErr
+	}).Run(ctx)
+	return
+}
+
+// AuthenticationInterceptor creates the security.UserContext from the bearer token
+// in the request metadata.  Methods not whitelisted by the configuration require a
+// client token holding the read and write scopes, which has not expired or been revoked.
+func AuthenticationInterceptor(cfg AuthenticationConfig) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		required, err := cfg.RequiresAuthentication(info.FullMethod)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		httpRequest := httpRequestFromContext(ctx, info.FullMethod)
+
+		token, err := httprequest.ExtractToken(httpRequest)
+		if err == httprequest.ErrNotFound {
+			if required {
+				return nil, status.Error(codes.Unauthenticated, "Authentication required")
+			}
+			return handler(ctx, req)
+		} else if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		userContext, err := security.NewUserContextFromToken(ctx, token)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		if err = security.CheckTokenRevoked(userContext); err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		ctx = security.ContextWithUserContext(ctx, userContext)
+
+		if required {
+			if err = authprovider.AuthenticateUser(ctx); err != nil {
+				return nil, StatusFromError(err, nil).Err()
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// PermissionsInterceptor requires the user to hold any of the specified permissions.
+func PermissionsInterceptor(anyOf ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		// Temporarily allow system user
+		var userContext = security.UserContextFromContext(ctx)
+		if userContext.UserName != "system" {
+			if err := rbac.HasPermission(ctx, anyOf); err != nil {
+				logger.WithContext(ctx).WithError(err).WithField("perms", anyOf).Error("Permission denied")
+				return nil, status.Error(codes.PermissionDenied, err.Error())
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// ErrorConverterInterceptor converts handler errors into gRPC status errors, using
+// the supplied ErrorConverter for errors without an HTTP status code.
+func ErrorConverterInterceptor(converter restops.ErrorConverter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			s := StatusFromError(err, converter)
+			logger.WithContext(ctx).WithError(err).Errorf("Request %q failed with status %s", info.FullMethod, s.Code())
+			return nil, s.Err()
+		}
+		return resp, nil
+	}
+}
+
+// MiddlewareInterceptor executes the endpoint HTTP middleware against an HTTP
+// request derived from the gRPC request metadata.  Middleware responding
+// without calling the next handler fail the request with the equivalent status.
+func MiddlewareInterceptor(m restops.Middlewares) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		called := false
+		final := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			resp, err = handler(r.Context(), req)
+		})
+
+		recorder := &responseRecorder{header: make(http.Header)}
+		m.Compose(final).ServeHTTP(recorder, httpRequestFromContext(ctx, info.FullMethod))
+
+		if header := metadataFromHeader(recorder.header); len(header) > 0 {
+			if headerErr := grpc.SetHeader(ctx, header); headerErr != nil {
+				logger.WithContext(ctx).WithError(headerErr).Warn("Failed to set response header metadata")
+			}
+		}
+
+		if !called {
+			code := recorder.code
+			if code == 0 {
+				code = http.StatusOK
+			}
+
+			message := strings.TrimSpace(recorder.body.String())
+			if message == "" {
+				message = http.StatusText(code)
+			}
+
+			return nil, status.Error(CodeFromHttpStatus(code), message)
+		}
+
+		return
+	}
+}
+
+// httpRequestFromContext creates an HTTP request from the gRPC request metadata.
+func httpRequestFromContext(ctx context.Context, fullMethod string) *http.Request {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	request := &http.Request{
+		Method:     http.MethodPost,
+		URL:        &url.URL{Path: fullMethod},
+		Proto:      "HTTP/2.0",
+		ProtoMajor: 2,
+		Header:     make(http.Header),
+		Body:       http.NoBody,
+		RequestURI: fullMethod,
+	}
+
+	for key, values := range md {
+		if strings.HasPrefix(key, ":") || strings.HasSuffix(key, "-bin") {
+			continue
+		}
+		request.Header[textproto.CanonicalMIMEHeaderKey(key)] = values
+	}
+
+	if authority := md.Get(":authority"); len(authority) > 0 {
+		request.Host = authority[0]
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		request.RemoteAddr = p.Addr.String()
+	}
+
+	return request.WithContext(ctx)
+}
+
+func metadataFromHeader(header http.Header) metadata.MD {
+	md := metadata.MD{}
+	for key, values := range header {
+		md.Append(key, values...)
+	}
+	return md
+}
+
+// responseRecorder captures the response written by HTTP middleware.
+type responseRecorder struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	if r.code == 0 {
+		r.code = http.StatusOK
+	}
+	return r.body.Write(data)
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	if r.code == 0 {
+		r.code = statusCode
+	}
+}
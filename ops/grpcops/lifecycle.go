@@ -0,0 +1,51 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package grpcops
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops"
+)
+
+var server *Server
+
+// Start registers the restops endpoints and initiates the server listening
+func Start(ctx context.Context) error {
+	if server == nil {
+		return nil
+	}
+	server.RegisterEndpoints(restops.RegisteredEndpoints())
+	return server.Serve(ctx)
+}
+
+// Stop terminates the listening gRPC server
+func Stop(ctx context.Context) error {
+	if server == nil {
+		return nil
+	}
+	return server.StopServing(ctx)
+}
+
+// NewServerFromConfig creates a new Server from the supplied configuration
+func NewServerFromConfig(cfg *config.Config, ctx context.Context) (*Server, error) {
+	serverConfig, err := NewServerConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if !serverConfig.Enabled {
+		return nil, ErrDisabled
+	}
+
+	return NewServer(serverConfig, ctx), nil
+}
+
+// ConfigureServer creates a new Server from the supplied configuration and
+// stores it in the `server` global
+func ConfigureServer(cfg *config.Config, ctx context.Context) (err error) {
+	server, err = NewServerFromConfig(cfg, ctx)
+	return
+}
@@ -0,0 +1,563 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package grpcops
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"cto-github.cisco.com/NFV-BU/go-msx/validate"
+	"cto-github.cisco.com/NFV-BU/go-msx/webservice"
+	"encoding/json"
+	"github.com/emicklei/go-restful"
+	"github.com/iancoleman/strcase"
+	"github.com/pkg/errors"
+	"github.com/swaggest/refl"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"io"
+	"net/http"
+	"reflect"
+)
+
+const (
+	fieldNameBody      = "body"
+	defaultServiceName = "Api"
+)
+
+// Method exposes a restops Endpoint as a unary gRPC method.  The request message
+// contains the path, query and form parameters of the endpoint, along with the
+// request body in the "body" field.  Headers and cookies are read from the
+// request metadata.  The response message contains the response body in the
+// "body" field, and response headers are returned as header metadata.
+type Method struct {
+	Package      string
+	Service      string
+	Name         string
+	Endpoint     *restops.Endpoint
+	Input        protoreflect.MessageDescriptor
+	Output       protoreflect.MessageDescriptor
+	Interceptors []grpc.UnaryServerInterceptor
+
+	parameters          []methodParameter
+	inputName           string
+	outputName          string
+	requestBodyMessage  bool
+	responseBodyMessage bool
+}
+
+func (m *Method) FullMethod() string {
+	return "/" + m.Package + "." + m.Service + "/" + m.Name
+}
+
+// Describe declares the request and response messages of the method.
+func (m *Method) Describe(b *DescriptorBuilder) (err error) {
+	if m.inputName, err = m.describeRequest(b); err != nil {
+		return errors.Wrap(err, "Failed to describe request message")
+	}
+
+	if m.outputName, err = m.describeResponse(b); err != nil {
+		return errors.Wrap(err, "Failed to describe response message")
+	}
+
+	return nil
+}
+
+func (m *Method) describeRequest(b *DescriptorBuilder) (string, error) {
+	message := b.AddMessage(m.Name + "Request")
+	request := m.Endpoint.Request
+
+	m.parameters = nil
+	if request.Port != nil {
+		for _, pf := range request.Port.Fields {
+			switch pf.Group {
+			case restops.FieldGroupHttpPath, restops.FieldGroupHttpQuery, restops.FieldGroupHttpForm:
+			default:
+				continue
+			}
+
+			spec, err := m.parameterFieldSpec(b, pf)
+			if err != nil {
+				return "", err
+			}
+
+			explode, _ := pf.BoolOption("explode")
+			parameter := methodParameter{
+				Group:    pf.Group,
+				Peer:     pf.Peer,
+				JsonName: pf.Peer,
+				Style:    pf.Options["style"],
+				Explode:  explode,
+			}
+			m.parameters = append(m.parameters, parameter)
+			b.addFieldSpec(message, parameter.JsonName, spec)
+		}
+	}
+
+	if request.HasBody() && len(request.Body.FormFields) == 0 && request.Body.Payload.IsPresent() {
+		bodyType := reflect.TypeOf(request.Body.Payload.Value())
+		m.requestBodyMessage = restops.IsCodecMediaType(request.Body.Mime) && !isByteSlice(bodyType)
+		if err := m.addBodyField(b, message, bodyType, m.requestBodyMessage); err != nil {
+			return "", err
+		}
+	}
+
+	return b.fullName(message.GetName()), nil
+}
+
+func (m *Method) parameterFieldSpec(b *DescriptorBuilder, pf *ops.PortField) (spec fieldSpec, err error) {
+	switch pf.Type.Shape {
+	case ops.FieldShapePrimitive:
+		if spec, err = b.FieldSpec(pf.Type.Type, ""); err != nil {
+			return
+		}
+		if !spec.isScalar() {
+			spec = fieldSpec{kind: descriptorpb.FieldDescriptorProto_TYPE_STRING}
+		}
+		// Retain the presence of explicit zero values
+		spec.optional = true
+
+	case ops.FieldShapeArray:
+		spec = fieldSpec{kind: descriptorpb.FieldDescriptorProto_TYPE_STRING}
+		if pf.Type.Items != nil {
+			var items fieldSpec
+			if items, err = b.FieldSpec(pf.Type.Items.Type, ""); err != nil {
+				return
+			}
+			if items.isScalar() {
+				spec = items
+			}
+		}
+		spec.optional = false
+		spec.repeated = true
+
+	case ops.FieldShapeObject:
+		spec = fieldSpec{
+			kind:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+			mapKey:   &fieldSpec{kind: descriptorpb.FieldDescriptorProto_TYPE_STRING},
+			mapValue: &fieldSpec{kind: descriptorpb.FieldDescriptorProto_TYPE_STRING},
+		}
+
+	default:
+		err = errors.Wrapf(ErrUnsupportedEndpoint, "Input field %q has unsupported shape %q", pf.Name, pf.Type.Shape)
+	}
+
+	return
+}
+
+func (m *Method) describeResponse(b *DescriptorBuilder) (string, error) {
+	message := b.AddMessage(m.Name + "Response")
+	response := m.Endpoint.Response
+
+	switch {
+	case response.Envelope || response.Success.Paging.IsPresent():
+		m.responseBodyMessage = true
+		b.addFieldSpec(message, fieldNameBody, b.useValue())
+
+	case response.Success.Payload.IsPresent():
+		bodyType := reflect.TypeOf(response.Success.Payload.Value())
+		m.responseBodyMessage = restops.IsCodecMediaType(response.Success.Mime) && !isByteSlice(bodyType)
+		if err := m.addBodyField(b, message, bodyType, m.responseBodyMessage); err != nil {
+			return "", err
+		}
+	}
+
+	return b.fullName(message.GetName()), nil
+}
+
+func (m *Method) addBodyField(b *DescriptorBuilder, message *descriptorpb.DescriptorProto, bodyType reflect.Type, structured bool) error {
+	if !structured {
+		b.addFieldSpec(message, fieldNameBody, fieldSpec{kind: descriptorpb.FieldDescriptorProto_TYPE_BYTES})
+		return nil
+	}
+
+	spec, err := b.FieldSpec(bodyType, m.Name+"Body")
+	if err != nil {
+		return err
+	}
+	spec.optional = false
+
+	b.addFieldSpec(message, fieldNameBody, spec)
+	return nil
+}
+
+// Resolve locates the request and response message descriptors in the built file.
+func (m *Method) Resolve(file protoreflect.FileDescriptor) error {
+	input, err := findMessage(file, m.inputName)
+	if err != nil {
+		return err
+	}
+
+	output, err := findMessage(file, m.outputName)
+	if err != nil {
+		return err
+	}
+
+	m.Input, m.Output = input, output
+	return nil
+}
+
+func findMessage(file protoreflect.FileDescriptor, fullName string) (protoreflect.MessageDescriptor, error) {
+	name := protoreflect.FullName(fullName[1:])
+	message := file.Messages().ByName(name.Name())
+	if message == nil || message.FullName() != name {
+		return nil, errors.Errorf("Message %q not found", fullName)
+	}
+	return message, nil
+}
+
+// MethodDescriptor returns the protobuf description of the method.
+func (m *Method) MethodDescriptor() *descriptorpb.MethodDescriptorProto {
+	return &descriptorpb.MethodDescriptorProto{
+		Name:       proto.String(m.Name),
+		InputType:  proto.String(m.inputName),
+		OutputType: proto.String(m.outputName),
+	}
+}
+
+// MethodDesc returns the grpc registration for the method.
+func (m *Method) MethodDesc() grpc.MethodDesc {
+	fullMethod := m.FullMethod()
+
+	return grpc.MethodDesc{
+		MethodName: m.Name,
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			request := dynamicpb.NewMessage(m.Input)
+			if err := dec(request); err != nil {
+				return nil, err
+			}
+
+			info := &grpc.UnaryServerInfo{
+				Server:     srv,
+				FullMethod: fullMethod,
+			}
+
+			handler := ChainUnaryInterceptors(m.Interceptors, info, m.Handle)
+			if interceptor == nil {
+				return handler(ctx, request)
+			}
+			return interceptor(ctx, request, info, handler)
+		},
+	}
+}
+
+// Handle executes the endpoint using the supplied request message.
+func (m *Method) Handle(ctx context.Context, req any) (any, error) {
+	request, ok := req.(proto.Message)
+	if !ok {
+		return nil, errors.Errorf("Unexpected request type %T", req)
+	}
+
+	values, err := messageToValue(request.ProtoReflect())
+	if err != nil {
+		return nil, webservice.NewBadRequestError(err)
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	source := NewMessageRequestDataSource(m, values.(map[string]any), md)
+
+	e := m.Endpoint
+	decoder := restops.NewRequestDecoder(source)
+	if e.Request.HasBody() {
+		decoder = restops.NewEndpointRequestDecoder(source, e.Request.Consumes())
+	}
+
+	// Validate request according to the endpoint port schemas
+	validator := restops.NewRequestValidator(e.Request.Port, decoder)
+	if err = validator.ValidateRequest(); err != nil {
+		return nil, requestError(err)
+	}
+
+	// Populate inputs
+	var inputs interface{}
+	populator := ops.NewInputsPopulator(e.Request.Port, decoder)
+	if inputs, err = populator.PopulateInputs(); err != nil {
+		return nil, requestError(err)
+	}
+
+	// Custom validation for args
+	if e.Request.Validator != nil && inputs != nil {
+		if err = e.Request.Validator(inputs); err != nil {
+			return nil, webservice.NewBadRequestError(err)
+		}
+	}
+
+	// Call the handler
+	encoder := NewMessageResponseEncoder()
+	handlerContext := &MethodHandlerContext{
+		inputType:  e.Inputs.OrElse(nil),
+		outputType: e.Outputs.OrElse(nil),
+		inputs:     inputs,
+		decoder:    decoder,
+		encoder:    encoder,
+	}
+
+	ctx = types.ContextWithHandlerContext(ctx, handlerContext)
+
+	// Evaluate preconditions from the request metadata before applying unsafe requests
+	if ctx, err = restops.CheckPreconditions(ctx, e, httpRequestFromContext(ctx, m.FullMethod()), inputs); err != nil {
+		return nil, err
+	}
+
+	if err = e.Handler.Call(ctx); err != nil {
+		return nil, err
+	}
+
+	// Auto-validation for validatable Port Struct
+	outputs := handlerContext.outputs
+	if outputs != nil {
+		if err = validate.ValidateValue(reflect.ValueOf(outputs)); err != nil {
+			errs := &ops.ValidationFailure{
+				Path:     "response",
+				Children: make(map[string]*ops.ValidationFailure),
+			}
+			return nil, restops.NewStatusCodeError(errs.Apply(err), http.StatusInternalServerError)
+		}
+	}
+
+	// Populate the response from the outputs
+	outputsPopulator := restops.OutputsPopulator{
+		Endpoint: e,
+		Outputs:  &outputs,
+		Observer: restops.CompositeResponseObserver{
+			restops.LoggingResponseObserver{Context: ctx},
+			restops.TracingResponseObserver{Context: ctx},
+		},
+		Encoder: encoder,
+		Describer: MethodRequestDescriber{
+			FullMethod: m.FullMethod(),
+			Source:     source,
+		},
+	}
+
+	if err = outputsPopulator.PopulateOutputs(); err != nil {
+		return nil, restops.NewStatusCodeError(err, http.StatusInternalServerError)
+	}
+
+	if len(encoder.Header) > 0 {
+		if err = grpc.SetHeader(ctx, encoder.Header); err != nil {
+			logger.WithContext(ctx).WithError(err).Warn("Failed to set response header metadata")
+		}
+	}
+
+	return m.response(encoder.Body)
+}
+
+func (m *Method) response(body any) (proto.Message, error) {
+	response := dynamicpb.NewMessage(m.Output)
+	if body == nil {
+		return response, nil
+	}
+
+	fd := m.Output.Fields().ByName(fieldNameBody)
+	if fd == nil {
+		return response, nil
+	}
+
+	if !m.responseBodyMessage {
+		data, err := bodyBytes(body)
+		if err != nil {
+			return nil, err
+		}
+		response.Set(fd, protoreflect.ValueOfBytes(data))
+		return response, nil
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to marshal response body")
+	}
+
+	var value any
+	if err = json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+
+	if value == nil {
+		return response, nil
+	}
+
+	if err = valueToField(value, fd, response); err != nil {
+		return nil, errors.Wrap(err, "Failed to convert response body")
+	}
+
+	return response, nil
+}
+
+func bodyBytes(body any) ([]byte, error) {
+	switch typedBody := body.(type) {
+	case []byte:
+		return typedBody, nil
+	case string:
+		return []byte(typedBody), nil
+	case types.TextMarshaler:
+		text, err := typedBody.MarshalText()
+		return []byte(text), err
+	case io.Reader:
+		return io.ReadAll(typedBody)
+	default:
+		return json.Marshal(body)
+	}
+}
+
+func isByteSlice(t reflect.Type) bool {
+	t = refl.DeepIndirect(t)
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+// requestError retains the status of errors such as unsupported media types,
+// and otherwise reports a bad request.
+func requestError(err error) error {
+	var statusCodeError restops.StatusCodeError
+	if errors.As(err, &statusCodeError) {
+		return statusCodeError
+	}
+	return webservice.NewBadRequestError(err)
+}
+
+var unsupportedArgumentTypes = types.NewTypeSet(
+	restfulRequestPointerType,
+	restfulResponsePointerType,
+	httpRequestPointerType,
+	httpResponseWriterType,
+)
+
+// NewMethod creates a Method for the endpoint.  Endpoints which handle HTTP
+// directly, produce streamed responses, or execute asynchronously are not supported.
+func NewMethod(e *restops.Endpoint) (*Method, error) {
+	if e.Unmanaged || e.Handler == nil || !e.Func.IsPresent() {
+		return nil, errors.Wrap(ErrUnsupportedEndpoint, "Endpoint is not managed by restops")
+	}
+
+	funcType := reflect.TypeOf(e.Func.Value())
+	for i := 0; i < funcType.NumIn(); i++ {
+		if _, ok := unsupportedArgumentTypes[funcType.In(i)]; ok {
+			return nil, errors.Wrapf(ErrUnsupportedEndpoint, "Handler argument type %v", funcType.In(i))
+		}
+	}
+
+	if e.Response.Success.Stream {
+		return nil, errors.Wrap(ErrUnsupportedEndpoint, "Streamed responses are not supported")
+	}
+
+	if e.Async != nil {
+		return nil, errors.Wrap(ErrUnsupportedEndpoint, "Asynchronous endpoints are not supported")
+	}
+
+	if e.OperationID == "" {
+		return nil, errors.Wrap(ErrUnsupportedEndpoint, "Operation id not specified")
+	}
+
+	service := defaultServiceName
+	if len(e.Tags) > 0 {
+		service = identifier(strcase.ToCamel(e.Tags[0]))
+	}
+
+	var interceptors []grpc.UnaryServerInterceptor
+	interceptors = append(interceptors,
+		ErrorConverterInterceptor(e.ErrorConverter),
+		ContextInjectorsInterceptor(e.Injectors))
+	if len(e.Permissions) > 0 {
+		interceptors = append(interceptors, PermissionsInterceptor(e.Permissions...))
+	}
+	if len(e.Middleware) > 0 {
+		interceptors = append(interceptors, MiddlewareInterceptor(e.Middleware))
+	}
+
+	return &Method{
+		Service:      service,
+		Name:         identifier(strcase.ToCamel(e.OperationID)),
+		Endpoint:     e,
+		Interceptors: interceptors,
+	}, nil
+}
+
+// MethodRequestDescriber describes the gRPC request for response envelopes.
+type MethodRequestDescriber struct {
+	FullMethod string
+	Source     *MessageRequestDataSource
+}
+
+func (d MethodRequestDescriber) Path() string {
+	return d.FullMethod
+}
+
+func (d MethodRequestDescriber) Parameters() map[string]interface{} {
+	result := make(map[string]interface{})
+	for k, v := range d.Source.PathParameters() {
+		result[k] = v
+	}
+	return result
+}
+
+var contextContextInstance context.Context
+var contextContextType = reflect.TypeOf(&contextContextInstance).Elem()
+
+var restfulRequestPointerInstance *restful.Request
+var restfulRequestPointerType = reflect.TypeOf(&restfulRequestPointerInstance).Elem()
+
+var restfulResponsePointerInstance *restful.Response
+var restfulResponsePointerType = reflect.TypeOf(&restfulResponsePointerInstance).Elem()
+
+var httpRequestPointerInstance *http.Request
+var httpRequestPointerType = reflect.TypeOf(&httpRequestPointerInstance).Elem()
+
+var httpResponseWriterInstance http.ResponseWriter
+var httpResponseWriterType = reflect.TypeOf(&httpResponseWriterInstance).Elem()
+
+var inputDecoderInstance ops.InputDecoder
+var inputDecoderType = reflect.TypeOf(&inputDecoderInstance).Elem()
+
+var responseEncoderInstance restops.ResponseEncoder
+var responseEncoderType = reflect.TypeOf(&responseEncoderInstance).Elem()
+
+var errorInstance error
+var errorType = reflect.TypeOf(&errorInstance).Elem()
+
+// MethodHandlerContext holds the data to be injected into the endpoint's handler function
+type MethodHandlerContext struct {
+	inputType  reflect.Type
+	outputType reflect.Type
+	inputs     interface{}
+	outputs    interface{}
+	decoder    ops.InputDecoder
+	encoder    restops.ResponseEncoder
+}
+
+func (m *MethodHandlerContext) GenerateArgument(ctx context.Context, t types.HandlerValueType) (result reflect.Value, err error) {
+	switch t.ValueType {
+	case contextContextType:
+		result = reflect.ValueOf(ctx)
+	case inputDecoderType:
+		result = reflect.ValueOf(m.decoder)
+	case responseEncoderType:
+		result = reflect.ValueOf(m.encoder)
+	case m.inputType:
+		result = reflect.ValueOf(m.inputs)
+	default:
+		err = errors.Wrapf(types.ErrUnknownValueType, "%v", t)
+	}
+	return
+}
+
+func (m *MethodHandlerContext) HandleResult(t types.HandlerValueType, v reflect.Value) (err error) {
+	switch t.ValueType {
+	case errorType:
+		erri := v.Interface()
+		if erri != nil {
+			err = erri.(error)
+		}
+	case m.outputType:
+		m.outputs = v.Interface()
+	default:
+		err = errors.Wrapf(types.ErrUnknownValueType, "%v", t)
+	}
+	return
+}
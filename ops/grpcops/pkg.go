@@ -0,0 +1,18 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+// Package grpcops exposes restops endpoints as gRPC methods.
+package grpcops
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/log"
+	"github.com/pkg/errors"
+)
+
+var logger = log.NewPackageLogger()
+
+var (
+	ErrDisabled            = errors.New("gRPC server disabled")
+	ErrUnsupportedEndpoint = errors.New("Endpoint cannot be exposed over gRPC")
+)
@@ -0,0 +1,179 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package grpcops
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/ops"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops"
+	"encoding/json"
+	"github.com/spf13/cast"
+	"google.golang.org/grpc/metadata"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// MessageRequestDataSource presents a gRPC request message and its metadata as
+// the HTTP request expected by the endpoint.
+type MessageRequestDataSource struct {
+	method   *Method
+	values   map[string]any
+	metadata metadata.MD
+}
+
+func (s *MessageRequestDataSource) Cookies() []*http.Cookie {
+	request := http.Request{Header: s.Headers()}
+	return request.Cookies()
+}
+
+func (s *MessageRequestDataSource) Headers() http.Header {
+	headers := make(http.Header)
+	for key, values := range s.metadata {
+		if strings.HasPrefix(key, ":") || strings.HasSuffix(key, "-bin") {
+			continue
+		}
+		headers[textproto.CanonicalMIMEHeaderKey(key)] = values
+	}
+	return headers
+}
+
+func (s *MessageRequestDataSource) Form() (url.Values, *multipart.Form, error) {
+	return s.parameterValues(restops.FieldGroupHttpForm), nil, nil
+}
+
+func (s *MessageRequestDataSource) Query() url.Values {
+	return s.parameterValues(restops.FieldGroupHttpQuery)
+}
+
+func (s *MessageRequestDataSource) PathParameters() map[string]string {
+	results := make(map[string]string)
+	for key, values := range s.parameterValues(restops.FieldGroupHttpPath) {
+		results[key] = values[0]
+	}
+	return results
+}
+
+func (s *MessageRequestDataSource) Body() ([]byte, error) {
+	body, ok := s.values[fieldNameBody]
+	if !ok {
+		return nil, nil
+	}
+
+	if data, ok := body.([]byte); ok && !s.method.requestBodyMessage {
+		return data, nil
+	}
+
+	return json.Marshal(body)
+}
+
+func (s *MessageRequestDataSource) BodyContentOptions(defaultContentType string, defaultEncoding string) ops.ContentOptions {
+	contentType := defaultContentType
+	if s.method.requestBodyMessage {
+		contentType = restops.MediaTypeJson
+	}
+	return ops.NewContentOptions(contentType)
+}
+
+func (s *MessageRequestDataSource) parameterValues(group string) url.Values {
+	results := make(url.Values)
+	for _, parameter := range s.method.parameters {
+		if parameter.Group != group {
+			continue
+		}
+
+		value, ok := s.values[parameter.JsonName]
+		if !ok {
+			continue
+		}
+
+		switch typedValue := value.(type) {
+		case []any:
+			parameter.encodeArray(results, typedValue)
+		case map[string]any:
+			parameter.encodeObject(results, typedValue)
+		default:
+			results.Add(parameter.Peer, cast.ToString(value))
+		}
+	}
+	return results
+}
+
+// methodParameter maps a non-body input port field onto a request message field.
+type methodParameter struct {
+	Group    string
+	Peer     string
+	JsonName string
+	Style    string
+	Explode  bool
+}
+
+func (p methodParameter) encodeArray(values url.Values, items []any) {
+	var parts []string
+	for _, item := range items {
+		parts = append(parts, cast.ToString(item))
+	}
+
+	switch {
+	case p.Group == restops.FieldGroupHttpPath:
+		values.Set(p.Peer, strings.Join(parts, ","))
+	case p.Explode:
+		values[p.Peer] = parts
+	case p.Style == restops.FieldStyleSpaceDelimited:
+		values.Set(p.Peer, strings.Join(parts, " "))
+	case p.Style == restops.FieldStylePipeDelimited:
+		values.Set(p.Peer, strings.Join(parts, "|"))
+	default:
+		values.Set(p.Peer, strings.Join(parts, ","))
+	}
+}
+
+func (p methodParameter) encodeObject(values url.Values, object map[string]any) {
+	var keys []string
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	switch {
+	case p.Group == restops.FieldGroupHttpForm:
+		data, _ := json.Marshal(object)
+		values.Set(p.Peer, string(data))
+
+	case p.Style == restops.FieldStyleDeepObject:
+		for _, key := range keys {
+			values.Set(p.Peer+"["+key+"]", cast.ToString(object[key]))
+		}
+
+	case p.Group == restops.FieldGroupHttpQuery && p.Explode:
+		for _, key := range keys {
+			values.Set(key, cast.ToString(object[key]))
+		}
+
+	case p.Explode:
+		var parts []string
+		for _, key := range keys {
+			parts = append(parts, key+"="+cast.ToString(object[key]))
+		}
+		values.Set(p.Peer, strings.Join(parts, ","))
+
+	default:
+		var parts []string
+		for _, key := range keys {
+			parts = append(parts, key, cast.ToString(object[key]))
+		}
+		values.Set(p.Peer, strings.Join(parts, ","))
+	}
+}
+
+func NewMessageRequestDataSource(method *Method, values map[string]any, md metadata.MD) *MessageRequestDataSource {
+	return &MessageRequestDataSource{
+		method:   method,
+		values:   values,
+		metadata: md,
+	}
+}
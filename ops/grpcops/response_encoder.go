@@ -0,0 +1,96 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package grpcops
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"github.com/spf13/cast"
+	"google.golang.org/grpc/metadata"
+	"sort"
+	"strings"
+)
+
+// MessageResponseEncoder collects the response populated from endpoint outputs,
+// to be returned as a gRPC response message and header metadata.
+type MessageResponseEncoder struct {
+	Header metadata.MD
+	Code   int
+	Mime   string
+	Body   any
+}
+
+func (e *MessageResponseEncoder) EncodeHeaderPrimitive(name string, value types.Optional[string], style string, explode bool) (err error) {
+	if value.IsPresent() {
+		e.Header.Append(name, value.Value())
+	}
+	return nil
+}
+
+func (e *MessageResponseEncoder) EncodeHeaderArray(name string, values []string, style string, explode bool) (err error) {
+	if len(values) > 0 {
+		e.Header.Append(name, strings.Join(values, ","))
+	}
+	return nil
+}
+
+func (e *MessageResponseEncoder) EncodeHeaderObject(name string, value types.Pojo, style string, explode bool) (err error) {
+	if len(value) == 0 {
+		return nil
+	}
+
+	var keys []string
+	for k := range value {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, k := range keys {
+		v := cast.ToString(value[k])
+		if explode {
+			parts = append(parts, k+"="+v)
+		} else {
+			parts = append(parts, k, v)
+		}
+	}
+
+	e.Header.Append(name, strings.Join(parts, ","))
+	return nil
+}
+
+// NegotiateMime selects the default media type, since the response message
+// defines the body encoding.
+func (e *MessageResponseEncoder) NegotiateMime(offers []string) (mime string, err error) {
+	if len(offers) == 0 {
+		return "", nil
+	}
+	return offers[0], nil
+}
+
+func (e *MessageResponseEncoder) EncodeMime(mime string) (err error) {
+	e.Mime = mime
+	return nil
+}
+
+func (e *MessageResponseEncoder) EncodeCode(code int) (err error) {
+	e.Code = code
+	return nil
+}
+
+func (e *MessageResponseEncoder) EncodeBody(body interface{}) error {
+	if _, ok := body.(restops.Streamer); ok {
+		return restops.ErrStreamingNotSupported
+	}
+
+	e.Body = body
+	return nil
+}
+
+func NewMessageResponseEncoder() *MessageResponseEncoder {
+	return &MessageResponseEncoder{
+		Header: metadata.MD{},
+	}
+}
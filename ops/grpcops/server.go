@@ -0,0 +1,277 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package grpcops
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/background"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops"
+	"cto-github.cisco.com/NFV-BU/go-msx/trace"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	v1alphagrpc "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"net"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Server exposes endpoints as gRPC services, along with the standard health and
+// reflection services.
+type Server struct {
+	ctx       context.Context
+	cfg       *ServerConfig
+	methods   []*Method
+	injectors *types.ContextInjectors
+	server    *grpc.Server
+	health    *health.Server
+	listener  net.Listener
+	files     *protoregistry.Files
+}
+
+// RegisterEndpoints adds a method for each of the supported endpoints.
+func (s *Server) RegisterEndpoints(endpoints restops.Endpoints) {
+	for _, e := range endpoints {
+		method, err := NewMethod(e)
+		if err != nil {
+			logger.WithError(err).Debugf("Skipping endpoint %s %s", e.Method, e.Path)
+			continue
+		}
+		s.RegisterMethod(method)
+	}
+}
+
+// RegisterMethod adds a method to be served.
+func (s *Server) RegisterMethod(method *Method) {
+	method.Package = s.Package()
+	s.methods = append(s.methods, method)
+}
+
+// RegisterInjector adds a context injector to be applied to each request.
+func (s *Server) RegisterInjector(injector types.ContextInjector) {
+	s.injectors.Register(injector)
+}
+
+// Package returns the protobuf package of the served services.
+func (s *Server) Package() string {
+	var parts []string
+	for _, part := range strings.Split(s.cfg.Package, ".") {
+		if part != "" {
+			parts = append(parts, strings.ToLower(identifier(strings.ReplaceAll(part, "-", "_"))))
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// Files returns the registry of the generated protobuf file descriptors.
+func (s *Server) Files() *protoregistry.Files {
+	return s.files
+}
+
+// Methods returns the registered methods.
+func (s *Server) Methods() []*Method {
+	return s.methods
+}
+
+// Build generates the protobuf descriptors of the registered methods, and
+// creates the gRPC server.
+func (s *Server) Build() (err error) {
+	pkg := s.Package()
+	filePath := path.Join(strings.ReplaceAll(pkg, ".", "/"), "api.proto")
+
+	b := NewDescriptorBuilder(pkg, filePath)
+
+	services := make(map[string]*descriptorpb.ServiceDescriptorProto)
+	var serviceNames []string
+	for _, method := range s.methods {
+		if err = method.Describe(b); err != nil {
+			return errors.Wrapf(err, "Failed to describe method %q", method.FullMethod())
+		}
+
+		service, ok := services[method.Service]
+		if !ok {
+			service = &descriptorpb.ServiceDescriptorProto{
+				Name: proto.String(method.Service),
+			}
+			services[method.Service] = service
+			serviceNames = append(serviceNames, method.Service)
+		}
+		service.Method = append(service.Method, method.MethodDescriptor())
+	}
+
+	sort.Strings(serviceNames)
+	for _, serviceName := range serviceNames {
+		b.File().Service = append(b.File().Service, services[serviceName])
+	}
+
+	file, err := protodesc.NewFile(b.File(), protoregistry.GlobalFiles)
+	if err != nil {
+		return errors.Wrap(err, "Failed to create file descriptor")
+	}
+
+	for _, method := range s.methods {
+		if err = method.Resolve(file); err != nil {
+			return errors.Wrapf(err, "Failed to resolve method %q", method.FullMethod())
+		}
+	}
+
+	s.files = new(protoregistry.Files)
+	if err = s.files.RegisterFile(file); err != nil {
+		return errors.Wrap(err, "Failed to register file descriptor")
+	}
+
+	var options []grpc.ServerOption
+	if s.cfg.Tls.Enabled {
+		tlsConfig, err := s.cfg.Tls.TlsConfig(s.ctx)
+		if err != nil {
+			return errors.Wrap(err, "Failed to create TLS configuration")
+		}
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	options = append(options, grpc.ChainUnaryInterceptor(
+		ContextInterceptor(s.ctx, s.injectors),
+		RecoveryInterceptor,
+		TracingInterceptor,
+		AuthenticationInterceptor(s.cfg.Authentication)))
+
+	s.server = grpc.NewServer(options...)
+
+	for _, serviceName := range serviceNames {
+		serviceDesc := &grpc.ServiceDesc{
+			ServiceName: pkg + "." + serviceName,
+			HandlerType: (*interface{})(nil),
+			Metadata:    filePath,
+		}
+		for _, method := range s.methods {
+			if method.Service == serviceName {
+				serviceDesc.Methods = append(serviceDesc.Methods, method.MethodDesc())
+			}
+		}
+		s.server.RegisterService(serviceDesc, s)
+	}
+
+	s.health = health.NewServer()
+	grpc_health_v1.RegisterHealthServer(s.server, s.health)
+
+	if s.cfg.Reflection {
+		v1alphagrpc.RegisterServerReflectionServer(s.server, reflection.NewServer(reflection.ServerOptions{
+			Services:           s.server,
+			DescriptorResolver: resolverChain{s.files, protoregistry.GlobalFiles},
+		}))
+	}
+
+	return nil
+}
+
+// Serve builds the gRPC server and starts it listening in the background.
+func (s *Server) Serve(ctx context.Context) (err error) {
+	s.ctx = trace.UntracedContextFromContext(ctx)
+
+	if err = s.Build(); err != nil {
+		return err
+	}
+
+	for _, method := range s.methods {
+		logger.Infof("Registered gRPC method %s", method.FullMethod())
+	}
+
+	if s.cfg.Disconnected {
+		return nil
+	}
+
+	if s.listener, err = net.Listen("tcp", s.cfg.GenericAddress()); err != nil {
+		return errors.Wrap(err, "Failed to listen for gRPC connections")
+	}
+
+	return s.ServeListener(ctx, s.listener)
+}
+
+// ServeListener serves the built gRPC server on the supplied listener in the background.
+func (s *Server) ServeListener(ctx context.Context, listener net.Listener) error {
+	s.health.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
+
+	go func() {
+		logger.Infof("Serving gRPC on %s", s.cfg.Address())
+
+		err := s.server.Serve(listener)
+		if err == nil || err == grpc.ErrServerStopped {
+			logger.Info("gRPC server exited normally")
+		} else {
+			logger.WithError(err).Error("gRPC server exited abnormally")
+			background.ErrorReporterFromContext(ctx).Fatal(err)
+		}
+	}()
+
+	return nil
+}
+
+// StopServing gracefully stops the running background gRPC server.
+func (s *Server) StopServing(ctx context.Context) error {
+	if s.server == nil {
+		return nil
+	}
+
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.server.Stop()
+	}
+
+	return nil
+}
+
+// resolverChain resolves descriptors from the generated files, falling back
+// to the global registry for well-known types.
+type resolverChain []*protoregistry.Files
+
+func (r resolverChain) FindFileByPath(filePath string) (protoreflect.FileDescriptor, error) {
+	for _, files := range r {
+		if fd, err := files.FindFileByPath(filePath); err == nil {
+			return fd, nil
+		}
+	}
+	return nil, protoregistry.NotFound
+}
+
+func (r resolverChain) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	for _, files := range r {
+		if d, err := files.FindDescriptorByName(name); err == nil {
+			return d, nil
+		}
+	}
+	return nil, protoregistry.NotFound
+}
+
+// NewServer creates a new gRPC server.
+func NewServer(cfg *ServerConfig, ctx context.Context) *Server {
+	return &Server{
+		ctx:       ctx,
+		cfg:       cfg,
+		injectors: new(types.ContextInjectors),
+	}
+}
@@ -0,0 +1,456 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package grpcops
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops"
+	"cto-github.cisco.com/NFV-BU/go-msx/schema/openapi"
+	"cto-github.cisco.com/NFV-BU/go-msx/security"
+	"cto-github.cisco.com/NFV-BU/go-msx/testhelpers/securitytest"
+	"cto-github.cisco.com/NFV-BU/go-msx/trace"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	v1alphagrpc "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"net"
+	"net/http"
+	"testing"
+)
+
+type testDevice struct {
+	Name  string   `json:"name"`
+	Count int      `json:"count"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+func newTestServer(t *testing.T, endpoints ...*restops.Endpoint) (*Server, *grpc.ClientConn) {
+	return newTestServerWithAuthentication(t, AuthenticationConfig{Whitelist: []string{"/**"}}, endpoints...)
+}
+
+func newTestServerWithAuthentication(t *testing.T, authentication AuthenticationConfig, endpoints ...*restops.Endpoint) (*Server, *grpc.ClientConn) {
+	ctx := trace.ContextWithUntracedContext(context.Background())
+	restops.RegisterPortFieldValidationSchemaFunc(openapi.GetJsonValidationSchema)
+
+	server := NewServer(&ServerConfig{
+		Enabled:        true,
+		Package:        "msx.test-service",
+		Reflection:     true,
+		Authentication: authentication,
+	}, ctx)
+
+	for _, endpoint := range endpoints {
+		e, err := endpoint.Build()
+		require.NoError(t, err)
+		server.RegisterEndpoints(restops.Endpoints{e})
+	}
+
+	require.NoError(t, server.Build())
+
+	listener := bufconn.Listen(1024 * 1024)
+	require.NoError(t, server.ServeListener(ctx, listener))
+	t.Cleanup(func() {
+		_ = server.StopServing(ctx)
+	})
+
+	conn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return server, conn
+}
+
+func newUpdateDeviceEndpoint() *restops.Endpoint {
+	type inputs struct {
+		DeviceId string     `req:"path"`
+		Force    bool       `req:"query,optional"`
+		Tenant   string     `req:"header,optional"`
+		Body     testDevice `req:"body"`
+	}
+
+	type outputs struct {
+		Location string     `resp:"header=Location"`
+		Body     testDevice `resp:"body"`
+	}
+
+	return restops.NewEndpoint(http.MethodPut, "api", "v1", "devices", "{deviceId}").
+		WithOperationId("updateDevice").
+		WithTags("Devices").
+		WithHandler(func(inp *inputs) (out outputs, err error) {
+			if inp.DeviceId == "missing" {
+				return out, restops.NewStatusCodeError(errors.New("Device not found"), http.StatusNotFound)
+			}
+
+			out.Location = "/api/v1/devices/" + inp.DeviceId
+			out.Body = inp.Body
+			if inp.Force {
+				out.Body.Tags = append(out.Body.Tags, "forced")
+			}
+			if inp.Tenant != "" {
+				out.Body.Tags = append(out.Body.Tags, inp.Tenant)
+			}
+			return
+		})
+}
+
+func TestServer_Package(t *testing.T) {
+	tests := []struct {
+		name string
+		pkg  string
+		want string
+	}{
+		{name: "Simple", pkg: "msx.api", want: "msx.api"},
+		{name: "Dashes", pkg: "msx.test-service", want: "msx.test_service"},
+		{name: "Empty", pkg: "msx..api", want: "msx.api"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer(&ServerConfig{Package: tt.pkg}, context.Background())
+			assert.Equal(t, tt.want, s.Package())
+		})
+	}
+}
+
+type testAsyncExecutor struct{}
+
+func (testAsyncExecutor) Outputs() interface{} {
+	return struct {
+		Body string `resp:"body"`
+	}{}
+}
+
+func (testAsyncExecutor) Submit(context.Context, *restops.Endpoint, restops.AsyncCall) (interface{}, error) {
+	return nil, errors.New("Not implemented")
+}
+
+func TestServer_RegisterEndpoints(t *testing.T) {
+	server, _ := newTestServer(t,
+		newUpdateDeviceEndpoint(),
+		restops.NewEndpoint(http.MethodGet, "api", "v1", "raw").
+			WithOperationId("raw").
+			WithHttpHandler(func(w http.ResponseWriter, r *http.Request) {}),
+		restops.NewEndpoint(http.MethodPost, "api", "v1", "devices").
+			WithOperationId("createDevice").
+			WithTags("Devices").
+			WithAsync(testAsyncExecutor{}).
+			WithHandler(func() error { return nil }))
+
+	require.Len(t, server.Methods(), 1)
+	assert.Equal(t, "/msx.test_service.Devices/UpdateDevice", server.Methods()[0].FullMethod())
+
+	d, err := server.Files().FindDescriptorByName("msx.test_service.Devices")
+	require.NoError(t, err)
+	service, ok := d.(protoreflect.ServiceDescriptor)
+	require.True(t, ok)
+	assert.Equal(t, 1, service.Methods().Len())
+}
+
+func TestServer_Invoke(t *testing.T) {
+	server, conn := newTestServer(t, newUpdateDeviceEndpoint())
+	method := server.Methods()[0]
+
+	tests := []struct {
+		name         string
+		request      string
+		metadata     metadata.MD
+		wantCode     codes.Code
+		wantResponse string
+		wantLocation string
+	}{
+		{
+			name:         "Success",
+			request:      `{"deviceId":"abc","force":true,"body":{"name":"router","count":2}}`,
+			metadata:     metadata.Pairs("tenant", "tenant-1"),
+			wantCode:     codes.OK,
+			wantResponse: `{"body":{"name":"router","count":2,"tags":["forced","tenant-1"]}}`,
+			wantLocation: "/api/v1/devices/abc",
+		},
+		{
+			name:     "NotFound",
+			request:  `{"deviceId":"missing","body":{"name":"router","count":1}}`,
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "MissingPath",
+			request:  `{"body":{"name":"router","count":1}}`,
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := dynamicpb.NewMessage(method.Input)
+			require.NoError(t, JsonToMessage([]byte(tt.request), request))
+			response := dynamicpb.NewMessage(method.Output)
+
+			ctx := context.Background()
+			if tt.metadata != nil {
+				ctx = metadata.NewOutgoingContext(ctx, tt.metadata)
+			}
+
+			var header metadata.MD
+			err := conn.Invoke(ctx, method.FullMethod(), request, response, grpc.Header(&header))
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode != codes.OK {
+				return
+			}
+
+			data, err := MessageToJson(response)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.wantResponse, string(data))
+			assert.Equal(t, []string{tt.wantLocation}, header.Get("location"))
+		})
+	}
+}
+
+func TestServer_Preconditions(t *testing.T) {
+	endpoint := newUpdateDeviceEndpoint().
+		WithEntityVersion(func(ctx context.Context, inputs interface{}) (restops.EntityVersion, error) {
+			return restops.EntityVersion{ETag: restops.EntityTag{Value: "v2"}}, nil
+		})
+
+	server, conn := newTestServer(t, endpoint)
+	method := server.Methods()[0]
+
+	tests := []struct {
+		name     string
+		metadata metadata.MD
+		wantCode codes.Code
+	}{
+		{
+			name:     "Unconditional",
+			wantCode: codes.OK,
+		},
+		{
+			name:     "Matched",
+			metadata: metadata.Pairs("if-match", `"v2"`),
+			wantCode: codes.OK,
+		},
+		{
+			name:     "Failed",
+			metadata: metadata.Pairs("if-match", `"v1"`),
+			wantCode: codes.FailedPrecondition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := dynamicpb.NewMessage(method.Input)
+			require.NoError(t, JsonToMessage([]byte(`{"deviceId":"abc","body":{"name":"router","count":1}}`), request))
+
+			ctx := context.Background()
+			if tt.metadata != nil {
+				ctx = metadata.NewOutgoingContext(ctx, tt.metadata)
+			}
+
+			err := conn.Invoke(ctx, method.FullMethod(), request, dynamicpb.NewMessage(method.Output))
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestServer_Middleware(t *testing.T) {
+	endpoint := newUpdateDeviceEndpoint().
+		WithMiddleware(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("X-Deny") != "" {
+					http.Error(w, "denied", http.StatusTooManyRequests)
+					return
+				}
+				next.ServeHTTP(w, r)
+			})
+		})
+
+	server, conn := newTestServer(t, endpoint)
+	method := server.Methods()[0]
+
+	request := dynamicpb.NewMessage(method.Input)
+	require.NoError(t, JsonToMessage([]byte(`{"deviceId":"abc","body":{"name":"router","count":1}}`), request))
+
+	err := conn.Invoke(context.Background(), method.FullMethod(), request, dynamicpb.NewMessage(method.Output))
+	assert.NoError(t, err)
+
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("x-deny", "true"))
+	err = conn.Invoke(ctx, method.FullMethod(), request, dynamicpb.NewMessage(method.Output))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, "denied", status.Convert(err).Message())
+}
+
+func TestServer_Injectors(t *testing.T) {
+	type contextKey int
+	const injectedKey contextKey = 0
+
+	var injected any
+	endpoint := restops.NewEndpoint(http.MethodGet, "api", "v1", "injected").
+		WithOperationId("getInjected").
+		WithInjector(func(ctx context.Context) context.Context {
+			return context.WithValue(ctx, injectedKey, "value")
+		}).
+		WithHandler(func(ctx context.Context) error {
+			injected = ctx.Value(injectedKey)
+			return nil
+		})
+
+	server, conn := newTestServer(t, endpoint)
+	method := server.Methods()[0]
+	assert.Equal(t, "/msx.test_service.Api/GetInjected", method.FullMethod())
+
+	err := conn.Invoke(context.Background(), method.FullMethod(),
+		dynamicpb.NewMessage(method.Input), dynamicpb.NewMessage(method.Output))
+	assert.NoError(t, err)
+	assert.Equal(t, "value", injected)
+}
+
+func TestServer_Health(t *testing.T) {
+	_, conn := newTestServer(t, newUpdateDeviceEndpoint())
+
+	response, err := grpc_health_v1.NewHealthClient(conn).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.Status)
+}
+
+func TestServer_Authentication(t *testing.T) {
+	tokenProvider := new(security.MockTokenProvider)
+	tokenProvider.
+		On("UserContextFromToken", mock.Anything, "client-token").
+		Return(&security.UserContext{
+			UserName:    "client",
+			Token:       "client-token",
+			Authorities: []string{"ROLE_CLIENT"},
+			Scopes:      []string{"read", "write"},
+		}, nil)
+	tokenProvider.
+		On("UserContextFromToken", mock.Anything, "user-token").
+		Return(&security.UserContext{
+			UserName:    "user",
+			Token:       "user-token",
+			Authorities: []string{"ROLE_USER"},
+		}, nil)
+	security.SetTokenProvider(tokenProvider)
+	security.SetTokenDetailsProvider(securitytest.NewMockTokenDetailsProvider())
+
+	server, conn := newTestServerWithAuthentication(t, AuthenticationConfig{
+		Whitelist: []string{"/grpc.health.v1.Health/*"},
+	}, newUpdateDeviceEndpoint())
+	method := server.Methods()[0]
+
+	tests := []struct {
+		name     string
+		metadata metadata.MD
+		wantCode codes.Code
+	}{
+		{
+			name:     "Anonymous",
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "Forbidden",
+			metadata: metadata.Pairs("authorization", "Bearer user-token"),
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "Client",
+			metadata: metadata.Pairs("authorization", "Bearer client-token"),
+			wantCode: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := dynamicpb.NewMessage(method.Input)
+			require.NoError(t, JsonToMessage([]byte(`{"deviceId":"abc","body":{"name":"router","count":1}}`), request))
+
+			ctx := context.Background()
+			if tt.metadata != nil {
+				ctx = metadata.NewOutgoingContext(ctx, tt.metadata)
+			}
+
+			err := conn.Invoke(ctx, method.FullMethod(), request, dynamicpb.NewMessage(method.Output))
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+
+	t.Run("Whitelisted", func(t *testing.T) {
+		response, err := grpc_health_v1.NewHealthClient(conn).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.Status)
+	})
+}
+
+func configFromValues(t *testing.T, values map[string]string) *config.Config {
+	cfg := config.NewConfig(config.NewInMemoryProvider("static", values))
+	require.NoError(t, cfg.Load(context.Background()))
+	return cfg
+}
+
+func TestNewServerFromConfig_Disabled(t *testing.T) {
+	cfg := configFromValues(t, map[string]string{})
+	_, err := NewServerFromConfig(cfg, context.Background())
+	assert.ErrorIs(t, err, ErrDisabled)
+}
+
+func TestNewServerFromConfig(t *testing.T) {
+	cfg := configFromValues(t, map[string]string{
+		"server.grpc.enabled": "true",
+		"server.grpc.port":    "9191",
+		"info.app.name":       "someservice",
+	})
+	s, err := NewServerFromConfig(cfg, context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "msx.someservice", s.Package())
+	assert.Equal(t, "0.0.0.0:9191", s.cfg.GenericAddress())
+}
+
+func TestServer_Reflection(t *testing.T) {
+	_, conn := newTestServer(t, newUpdateDeviceEndpoint())
+
+	client, err := v1alphagrpc.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	require.NoError(t, err)
+	defer client.CloseSend()
+
+	err = client.Send(&v1alphagrpc.ServerReflectionRequest{
+		MessageRequest: &v1alphagrpc.ServerReflectionRequest_ListServices{},
+	})
+	require.NoError(t, err)
+
+	response, err := client.Recv()
+	require.NoError(t, err)
+
+	var services []string
+	for _, service := range response.GetListServicesResponse().GetService() {
+		services = append(services, service.GetName())
+	}
+	assert.Contains(t, services, "msx.test_service.Devices")
+	assert.Contains(t, services, "grpc.health.v1.Health")
+
+	err = client.Send(&v1alphagrpc.ServerReflectionRequest{
+		MessageRequest: &v1alphagrpc.ServerReflectionRequest_FileContainingSymbol{
+			FileContainingSymbol: "msx.test_service.Devices",
+		},
+	})
+	require.NoError(t, err)
+
+	response, err = client.Recv()
+	require.NoError(t, err)
+	assert.NotEmpty(t, response.GetFileDescriptorResponse().GetFileDescriptorProto())
+}
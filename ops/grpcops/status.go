@@ -0,0 +1,78 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package grpcops
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+)
+
+var httpStatusCodes = map[int]codes.Code{
+	http.StatusBadRequest:            codes.InvalidArgument,
+	http.StatusUnauthorized:          codes.Unauthenticated,
+	http.StatusForbidden:             codes.PermissionDenied,
+	http.StatusNotFound:              codes.NotFound,
+	http.StatusMethodNotAllowed:      codes.Unimplemented,
+	http.StatusNotAcceptable:         codes.InvalidArgument,
+	http.StatusRequestTimeout:        codes.DeadlineExceeded,
+	http.StatusConflict:              codes.AlreadyExists,
+	http.StatusGone:                  codes.NotFound,
+	http.StatusPreconditionFailed:    codes.FailedPrecondition,
+	http.StatusRequestEntityTooLarge: codes.ResourceExhausted,
+	http.StatusUnsupportedMediaType:  codes.InvalidArgument,
+	http.StatusUnprocessableEntity:   codes.InvalidArgument,
+	http.StatusPreconditionRequired:  codes.FailedPrecondition,
+	http.StatusTooManyRequests:       codes.ResourceExhausted,
+	499:                              codes.Canceled,
+	http.StatusInternalServerError:   codes.Internal,
+	http.StatusNotImplemented:        codes.Unimplemented,
+	http.StatusBadGateway:            codes.Unavailable,
+	http.StatusServiceUnavailable:    codes.Unavailable,
+	http.StatusGatewayTimeout:        codes.DeadlineExceeded,
+}
+
+// CodeFromHttpStatus returns the gRPC status code equivalent to an HTTP status code.
+func CodeFromHttpStatus(statusCode int) codes.Code {
+	if code, ok := httpStatusCodes[statusCode]; ok {
+		return code
+	}
+
+	switch {
+	case statusCode < 400:
+		return codes.OK
+	case statusCode < 500:
+		return codes.FailedPrecondition
+	default:
+		return codes.Unknown
+	}
+}
+
+// StatusFromError converts an error into a gRPC status.  Errors without an
+// HTTP status code are converted using the supplied ErrorConverter, or the
+// default restops error status mapping when none is supplied.
+func StatusFromError(err error, converter restops.ErrorConverter) *status.Status {
+	if err == nil {
+		return nil
+	}
+
+	if s, ok := status.FromError(err); ok {
+		return s
+	}
+
+	var statusCodeProvider restops.StatusCodeProvider
+	if !errors.As(err, &statusCodeProvider) {
+		if converter == nil {
+			converter = restops.ErrorStatusCoderConverter{
+				ErrorStatusCoder: restops.ErrorStatusCoderFunc(restops.DefaultErrorStatusCoder),
+			}
+		}
+		statusCodeProvider = converter.Convert(err)
+	}
+
+	return status.New(CodeFromHttpStatus(statusCodeProvider.StatusCode()), err.Error())
+}
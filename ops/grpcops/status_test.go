@@ -0,0 +1,89 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package grpcops
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"testing"
+)
+
+func TestCodeFromHttpStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		want       codes.Code
+	}{
+		{name: "OK", statusCode: http.StatusOK, want: codes.OK},
+		{name: "BadRequest", statusCode: http.StatusBadRequest, want: codes.InvalidArgument},
+		{name: "Unauthorized", statusCode: http.StatusUnauthorized, want: codes.Unauthenticated},
+		{name: "Forbidden", statusCode: http.StatusForbidden, want: codes.PermissionDenied},
+		{name: "NotFound", statusCode: http.StatusNotFound, want: codes.NotFound},
+		{name: "Conflict", statusCode: http.StatusConflict, want: codes.AlreadyExists},
+		{name: "TooManyRequests", statusCode: http.StatusTooManyRequests, want: codes.ResourceExhausted},
+		{name: "Teapot", statusCode: http.StatusTeapot, want: codes.FailedPrecondition},
+		{name: "InternalServerError", statusCode: http.StatusInternalServerError, want: codes.Internal},
+		{name: "ServiceUnavailable", statusCode: http.StatusServiceUnavailable, want: codes.Unavailable},
+		{name: "Unknown", statusCode: 599, want: codes.Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CodeFromHttpStatus(tt.statusCode))
+		})
+	}
+}
+
+func TestStatusFromError(t *testing.T) {
+	someError := errors.New("some error")
+
+	tests := []struct {
+		name        string
+		err         error
+		converter   restops.ErrorConverter
+		wantCode    codes.Code
+		wantMessage string
+	}{
+		{
+			name:        "Status",
+			err:         status.Error(codes.Aborted, "aborted"),
+			wantCode:    codes.Aborted,
+			wantMessage: "aborted",
+		},
+		{
+			name:        "StatusCodeError",
+			err:         restops.NewStatusCodeError(someError, http.StatusNotFound),
+			wantCode:    codes.NotFound,
+			wantMessage: "some error",
+		},
+		{
+			name:        "Default",
+			err:         someError,
+			wantCode:    codes.InvalidArgument,
+			wantMessage: "some error",
+		},
+		{
+			name: "Converter",
+			err:  someError,
+			converter: restops.ErrorConverterFunc(func(err error) restops.StatusCodeError {
+				return restops.NewStatusCodeError(err, http.StatusConflict)
+			}),
+			wantCode:    codes.AlreadyExists,
+			wantMessage: "some error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := StatusFromError(tt.err, tt.converter)
+			assert.Equal(t, tt.wantCode, s.Code())
+			assert.Equal(t, tt.wantMessage, s.Message())
+		})
+	}
+}
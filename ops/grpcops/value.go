@@ -0,0 +1,267 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package grpcops
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"strings"
+)
+
+const wellKnownPackage = "google.protobuf"
+
+// MessageToJson converts a message into the JSON document represented by its go type.
+func MessageToJson(m protoreflect.Message) ([]byte, error) {
+	value, err := messageToValue(m)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// JsonToMessage populates a message from the JSON document representing its go type.
+func JsonToMessage(data []byte, m protoreflect.Message) error {
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+
+	if value == nil {
+		return nil
+	}
+
+	object, ok := value.(map[string]any)
+	if !ok {
+		return errors.Errorf("Expected JSON object for message %q", m.Descriptor().FullName())
+	}
+
+	return objectToMessage(object, m)
+}
+
+func isWellKnown(md protoreflect.MessageDescriptor) bool {
+	return md.ParentFile().Package() == wellKnownPackage
+}
+
+func messageToValue(m protoreflect.Message) (result any, err error) {
+	if isWellKnown(m.Descriptor()) {
+		var data []byte
+		if data, err = protojson.Marshal(m.Interface()); err != nil {
+			return
+		}
+		err = json.Unmarshal(data, &result)
+		return
+	}
+
+	object := make(map[string]any)
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		object[fd.JSONName()], err = fieldToValue(fd, v)
+		return err == nil
+	})
+
+	return object, err
+}
+
+func fieldToValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) (any, error) {
+	switch {
+	case fd.IsList():
+		list := v.List()
+		results := make([]any, 0, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			item, err := singularToValue(fd, list.Get(i))
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, item)
+		}
+		return results, nil
+
+	case fd.IsMap():
+		var err error
+		results := make(map[string]any)
+		v.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			results[k.String()], err = singularToValue(fd.MapValue(), v)
+			return err == nil
+		})
+		return results, err
+
+	default:
+		return singularToValue(fd, v)
+	}
+}
+
+func singularToValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) (any, error) {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageToValue(v.Message())
+	case protoreflect.EnumKind:
+		return int32(v.Enum()), nil
+	case protoreflect.BytesKind:
+		return v.Bytes(), nil
+	default:
+		return v.Interface(), nil
+	}
+}
+
+func objectToMessage(object map[string]any, m protoreflect.Message) error {
+	fields := m.Descriptor().Fields()
+	for key, value := range object {
+		fd := fields.ByJSONName(key)
+		if fd == nil {
+			fd = fields.ByName(protoreflect.Name(key))
+		}
+		if fd == nil || value == nil {
+			continue
+		}
+
+		if err := valueToField(value, fd, m); err != nil {
+			return errors.Wrapf(err, "Failed to convert field %q", key)
+		}
+	}
+
+	return nil
+}
+
+func valueToField(value any, fd protoreflect.FieldDescriptor, m protoreflect.Message) error {
+	switch {
+	case fd.IsList():
+		items, ok := value.([]any)
+		if !ok {
+			return errors.Errorf("Expected JSON array, got %T", value)
+		}
+		list := m.Mutable(fd).List()
+		for _, item := range items {
+			v, err := valueToSingular(item, fd, list.NewElement)
+			if err != nil {
+				return err
+			}
+			list.Append(v)
+		}
+
+	case fd.IsMap():
+		entries, ok := value.(map[string]any)
+		if !ok {
+			return errors.Errorf("Expected JSON object, got %T", value)
+		}
+		mapValue := m.Mutable(fd).Map()
+		for k, item := range entries {
+			key, err := valueToSingular(k, fd.MapKey(), nil)
+			if err != nil {
+				return err
+			}
+			v, err := valueToSingular(item, fd.MapValue(), mapValue.NewValue)
+			if err != nil {
+				return err
+			}
+			mapValue.Set(key.MapKey(), v)
+		}
+
+	default:
+		v, err := valueToSingular(value, fd, func() protoreflect.Value {
+			return m.NewField(fd)
+		})
+		if err != nil {
+			return err
+		}
+		m.Set(fd, v)
+	}
+
+	return nil
+}
+
+func valueToSingular(value any, fd protoreflect.FieldDescriptor, newMessage func() protoreflect.Value) (result protoreflect.Value, err error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		var b bool
+		b, err = cast.ToBoolE(value)
+		result = protoreflect.ValueOfBool(b)
+
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		var i int32
+		i, err = cast.ToInt32E(jsonScalar(value))
+		result = protoreflect.ValueOfInt32(i)
+
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		var i int64
+		i, err = cast.ToInt64E(jsonScalar(value))
+		result = protoreflect.ValueOfInt64(i)
+
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		var i uint32
+		i, err = cast.ToUint32E(jsonScalar(value))
+		result = protoreflect.ValueOfUint32(i)
+
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		var i uint64
+		i, err = cast.ToUint64E(jsonScalar(value))
+		result = protoreflect.ValueOfUint64(i)
+
+	case protoreflect.FloatKind:
+		var f float32
+		f, err = cast.ToFloat32E(jsonScalar(value))
+		result = protoreflect.ValueOfFloat32(f)
+
+	case protoreflect.DoubleKind:
+		var f float64
+		f, err = cast.ToFloat64E(jsonScalar(value))
+		result = protoreflect.ValueOfFloat64(f)
+
+	case protoreflect.StringKind:
+		var s string
+		s, err = cast.ToStringE(value)
+		result = protoreflect.ValueOfString(s)
+
+	case protoreflect.BytesKind:
+		var s string
+		if s, err = cast.ToStringE(value); err == nil {
+			var data []byte
+			data, err = base64.StdEncoding.DecodeString(s)
+			result = protoreflect.ValueOfBytes(data)
+		}
+
+	case protoreflect.EnumKind:
+		var i int32
+		i, err = cast.ToInt32E(jsonScalar(value))
+		result = protoreflect.ValueOfEnum(protoreflect.EnumNumber(i))
+
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		result = newMessage()
+		if isWellKnown(fd.Message()) {
+			var data []byte
+			if data, err = json.Marshal(value); err == nil {
+				err = protojson.Unmarshal(data, result.Message().Interface())
+			}
+		} else if object, ok := value.(map[string]any); ok {
+			err = objectToMessage(object, result.Message())
+		} else {
+			err = errors.Errorf("Expected JSON object, got %T", value)
+		}
+
+	default:
+		err = errors.Errorf("Unsupported field kind %q", fd.Kind())
+	}
+
+	return
+}
+
+// jsonScalar unwraps JSON numbers and numeric strings for conversion.
+func jsonScalar(value any) any {
+	switch typedValue := value.(type) {
+	case json.Number:
+		if strings.ContainsAny(typedValue.String(), ".eE") {
+			f, _ := typedValue.Float64()
+			return f
+		}
+		return typedValue.String()
+	default:
+		return value
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package grpcops
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"reflect"
+	"testing"
+)
+
+func TestJsonToMessage(t *testing.T) {
+	b := NewDescriptorBuilder("msx.test", "msx/test/api.proto")
+	_, err := b.Message(reflect.TypeOf(testRecord{}), "Hint")
+	require.NoError(t, err)
+
+	file, err := protodesc.NewFile(b.File(), protoregistry.GlobalFiles)
+	require.NoError(t, err)
+	md := file.Messages().ByName("TestRecord")
+
+	tests := []struct {
+		name    string
+		json    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Scalars",
+			json: `{"name":"alpha","size":0,"data":"AQID"}`,
+			want: `{"name":"alpha","size":0,"data":"AQID"}`,
+		},
+		{
+			name: "Collections",
+			json: `{"labels":{"a":"b"},"children":[{"value":1.5}],"matrix":[[1,2],[3]]}`,
+			want: `{"labels":{"a":"b"},"children":[{"value":1.5}],"matrix":[[1,2],[3]]}`,
+		},
+		{
+			name: "Dynamic",
+			json: `{"extra":{"nested":[true,null,"x"]}}`,
+			want: `{"extra":{"nested":[true,null,"x"]}}`,
+		},
+		{
+			name: "WellKnown",
+			json: `{"id":"00000000-0000-0000-0000-000000000001","created":"2023-01-02T03:04:05Z"}`,
+			want: `{"id":"00000000-0000-0000-0000-000000000001","created":"2023-01-02T03:04:05Z"}`,
+		},
+		{
+			name:    "NotObject",
+			json:    `[1]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := dynamicpb.NewMessage(md)
+			err := JsonToMessage([]byte(tt.json), m)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			data, err := MessageToJson(m)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
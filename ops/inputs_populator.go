@@ -46,6 +46,19 @@ func (p InputsPopulator) PopulateInputs() (interface{}, error) {
 	return nil, nil
 }
 
+// PopulateFields populates the input port struct without validating the result.
+func (p InputsPopulator) PopulateFields() (interface{}, error) {
+	if err := p.populateInputPortStruct(); err != nil {
+		return nil, err
+	}
+
+	if p.portStruct != nil {
+		return *p.portStruct, nil
+	}
+
+	return nil, nil
+}
+
 func (p InputsPopulator) populateInputPortStruct() (err error) {
 	if p.portStruct == nil {
 		return nil
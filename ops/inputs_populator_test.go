@@ -484,3 +484,42 @@ func TestInputsPopulator_PopulateInputs_Content(t *testing.T) {
 		})
 	}
 }
+
+type populateFieldsInputs struct {
+	A *string `test:"populator"`
+}
+
+func (i populateFieldsInputs) Validate() error {
+	return errors.New("inputs error")
+}
+
+func TestInputsPopulator_PopulateFields(t *testing.T) {
+	pr := PortReflector{
+		Direction: PortDirectionIn,
+		FieldGroups: map[string]FieldGroup{
+			FieldGroupPopulator: {
+				Cardinality:   types.CardinalityZeroToMany(),
+				AllowedShapes: types.NewStringSet(FieldShapePrimitive),
+			},
+		},
+		FieldTypeReflector: NewDefaultPortFieldTypeReflector(PortDirectionIn),
+	}
+
+	port, err := pr.ReflectPortStruct(PortTypeTest, reflect.TypeOf(populateFieldsInputs{}))
+	if !assert.NoError(t, err) {
+		assert.FailNow(t, "Failed to reflect port struct", err.Error())
+	}
+
+	decoder := TestMapDecoder{Values: map[string]string{
+		"a": "123",
+	}}
+
+	_, err = NewInputsPopulator(port, decoder).PopulateInputs()
+	assert.Error(t, err)
+
+	got, err := NewInputsPopulator(port, decoder).PopulateFields()
+	assert.NoError(t, err)
+	assert.Equal(t, &populateFieldsInputs{
+		A: types.NewStringPtr("123"),
+	}, got)
+}
@@ -321,9 +321,14 @@ func (i PortFieldExtractor) extractElement(pfet PortFieldElementType, fv reflect
 }
 
 func (i PortFieldExtractor) rootPortFieldElement() PortFieldElementType {
+	// Copy the type: the port field is shared between concurrent extractions
+	pft := new(PortFieldType)
+	*pft = i.portField.Type
+	pft.Optional = i.portField.Optional
+
 	return PortFieldElementType{
 		Indices:       i.portField.Indices,
-		PortFieldType: i.portField.Type.SetOptional(i.portField.Optional),
+		PortFieldType: pft,
 	}
 }
 
@@ -1266,3 +1266,20 @@ func TestPortFieldExtractor_ExtractValue(t *testing.T) {
 		})
 	}
 }
+
+func TestPortFieldExtractor_ExtractValue_SharedPortField(t *testing.T) {
+	type outputs struct {
+		Name string
+	}
+
+	portField := &PortField{
+		Name:     "name",
+		Indices:  []int{0},
+		Optional: true,
+		Type:     *PortFieldTypeFromType(reflect.TypeOf(""), FieldShapePrimitive),
+	}
+
+	_, err := NewPortFieldExtractor(portField, outputs{Name: "value"}).ExtractValue()
+	assert.NoError(t, err)
+	assert.False(t, portField.Type.Optional, "Port field type must not be modified by extraction")
+}
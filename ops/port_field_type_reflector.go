@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"github.com/iancoleman/strcase"
 	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
 	"io"
 	"mime/multipart"
 	"reflect"
@@ -47,6 +48,8 @@ var OptionalOfStringInstance types.Optional[string]
 var OptionalOfStringType = reflect.TypeOf(&OptionalOfStringInstance).Elem()
 var AnyInstance any
 var AnyType = reflect.TypeOf(&AnyInstance).Elem()
+var ProtoMessageInstance proto.Message
+var ProtoMessageType = reflect.TypeOf(&ProtoMessageInstance).Elem()
 
 type PortFieldTypeReflector interface {
 	ReflectPortFieldType(reflect.Type) (*PortFieldType, error)
@@ -164,6 +167,10 @@ func (r DefaultPortFieldTypeReflector) reflectDirect(t reflect.Type) (portFieldT
 		portFieldType = PortFieldTypeFromType(t, FieldShapeContent)
 		portFieldType.WithHandlerType(IoReadCloserType)
 		return
+	case pt.Implements(ProtoMessageType):
+		// Protobuf messages carry internal state and are encoded as a whole
+		portFieldType = PortFieldTypeFromType(t, FieldShapeObject)
+		return
 	}
 
 	// Kinds
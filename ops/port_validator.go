@@ -12,6 +12,7 @@ import (
 	"github.com/pkg/errors"
 	jsv "github.com/santhosh-tekuri/jsonschema/v5"
 	"path"
+	"sort"
 	"strings"
 )
 
@@ -64,6 +65,37 @@ func (e *ValidationFailure) MarshalJSON() ([]byte, error) {
 	return json.Marshal(e.ToPojo())
 }
 
+// Violations flattens the failure tree into individual failures, ordered by location.
+func (e *ValidationFailure) Violations() []Violation {
+	var results []Violation
+	for _, failure := range e.Failures {
+		results = append(results, Violation{
+			Pointer: e.Path,
+			Detail:  failure,
+		})
+	}
+
+	for _, child := range e.Children {
+		results = append(results, child.Violations()...)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Pointer < results[j].Pointer
+	})
+
+	return results
+}
+
+// Rebase prefixes the location of the failure and its descendants, for example to
+// locate a field value failure within the request.
+func (e *ValidationFailure) Rebase(prefix string) *ValidationFailure {
+	e.Path = prefix + e.Path
+	for _, child := range e.Children {
+		child.Rebase(prefix)
+	}
+	return e
+}
+
 func (e *ValidationFailure) Apply(err error) *ValidationFailure {
 	switch typedErr := err.(type) {
 	case *jsv.ValidationError:
@@ -126,6 +158,12 @@ func (e *ValidationFailure) applyJsvValidationError(err *jsv.ValidationError) *V
 	return e
 }
 
+// Violation is a single validation failure, located by a JSON Pointer.
+type Violation struct {
+	Pointer string `json:"pointer"`
+	Detail  string `json:"detail"`
+}
+
 func NewValidationFailure(p string) *ValidationFailure {
 	if len(p) > 0 && p[0] != '/' {
 		p = "/" + p
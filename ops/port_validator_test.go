@@ -169,3 +169,24 @@ func TestValidationFailure_Apply(t *testing.T) {
 		})
 	}
 }
+
+func TestValidationFailure_Violations(t *testing.T) {
+	failure := NewValidationFailure("").Apply(types.ErrorMap{
+		"name": types.ErrorList{
+			errors.New("Missing required value"),
+		},
+		"count": types.ErrorMap{
+			"0": errors.New("Must be positive"),
+		},
+	})
+
+	want := []Violation{
+		{Pointer: "/body/count/0", Detail: "Must be positive"},
+		{Pointer: "/body/name", Detail: "Missing required value"},
+	}
+
+	got := failure.Rebase("/body").Violations()
+	assert.True(t,
+		reflect.DeepEqual(want, got),
+		testhelpers.Diff(want, got))
+}
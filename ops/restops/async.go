@@ -0,0 +1,106 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package restops
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"github.com/emicklei/go-restful"
+	"github.com/pkg/errors"
+	"net/http"
+)
+
+// AsyncCall executes an asynchronous endpoint handler, returning the success body of its outputs.
+type AsyncCall func(ctx context.Context) (result interface{}, err error)
+
+// AsyncExecutor runs endpoint handlers after their requests have been accepted.
+type AsyncExecutor interface {
+	// Outputs returns an output port struct describing the 202 Accepted response.
+	Outputs() interface{}
+	// Submit schedules the call, returning a populated output port struct.
+	Submit(ctx context.Context, e *Endpoint, call AsyncCall) (outputs interface{}, err error)
+}
+
+// WithAsync executes the handler using the supplied executor, responding with
+// 202 Accepted once the handler has been scheduled.
+func (e *Endpoint) WithAsync(executor AsyncExecutor) *Endpoint {
+	e.Async = executor
+	return e
+}
+
+func (e *Endpoint) applyAsync(analyzer *EndpointHandlerTypesAnalyzer) error {
+	if e.Async == nil {
+		return nil
+	}
+
+	if e.Unmanaged || analyzer.writesResponse() {
+		return errors.Errorf("Asynchronous handler for operation %q must not write the response", e.OperationID)
+	}
+
+	// Retain the handler body as the operation result
+	if e.Response.Port != nil {
+		e.asyncResult = e.Response.Port.Fields.First(PortFieldIsSuccessBody)
+	}
+
+	codes := e.Response.Codes
+	codes.Success = []int{http.StatusAccepted}
+
+	e.Response.Envelope = false
+	e.Response.Success = EndpointResponseContent{}
+	e.Response = e.Response.
+		WithOutputs(e.Async.Outputs()).
+		WithResponseCodes(codes)
+
+	return nil
+}
+
+// asyncResultBody extracts the success body from the handler outputs.
+func (e *Endpoint) asyncResultBody(outputs interface{}) (interface{}, error) {
+	if e.asyncResult == nil || outputs == nil {
+		return nil, nil
+	}
+
+	fv, err := ops.NewPortFieldExtractor(e.asyncResult, outputs).ExtractValue()
+	if err != nil {
+		return nil, err
+	}
+
+	return fv.Interface(), nil
+}
+
+// callAsync submits the endpoint handler to the endpoint executor, and injects
+// the accepted outputs into the request.
+func callAsync(ctx context.Context, e *Endpoint, request *restful.Request) error {
+	inputs := InputsFromRequest(request)
+	decoder := EndpointRequestDecoderFromRequest(request)
+
+	outputs, err := e.Async.Submit(ctx, e, func(ctx context.Context) (interface{}, error) {
+		// Isolate the handler from the completed request
+		jobRequest := restful.NewRequest(request.Request.WithContext(ctx))
+		jobRequest = RequestWithEndpoint(jobRequest, e)
+		jobRequest = RequestWithInputs(jobRequest, inputs)
+		jobRequest = RequestWithEndpointRequestDecoder(jobRequest, decoder)
+
+		handlerContext := &EndpointHandlerContext{
+			request:    jobRequest,
+			inputType:  e.Inputs.Value(),
+			outputType: e.Outputs.Value(),
+		}
+
+		ctx = types.ContextWithHandlerContext(ctx, handlerContext)
+		if err := e.Handler.Call(ctx); err != nil {
+			return nil, err
+		}
+
+		return e.asyncResultBody(OutputsFromRequest(jobRequest))
+	})
+	if err != nil {
+		return err
+	}
+
+	RequestWithOutputs(request, outputs)
+	return nil
+}
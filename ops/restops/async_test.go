@@ -0,0 +1,168 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package restops
+
+import (
+	"bytes"
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops"
+	"cto-github.cisco.com/NFV-BU/go-msx/schema/js"
+	"github.com/emicklei/go-restful"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type asyncTestOutputs struct {
+	Location string `resp:"header"`
+	Body     string `resp:"body"`
+}
+
+// asyncTestExecutor executes the call during submission, recording its outcome.
+type asyncTestExecutor struct {
+	result interface{}
+	err    error
+}
+
+func (a *asyncTestExecutor) Outputs() interface{} {
+	return asyncTestOutputs{}
+}
+
+func (a *asyncTestExecutor) Submit(ctx context.Context, e *Endpoint, call AsyncCall) (interface{}, error) {
+	a.result, a.err = call(context.Background())
+	return asyncTestOutputs{
+		Location: "/api/v1/operations/" + e.OperationID,
+		Body:     "accepted",
+	}, nil
+}
+
+func TestEndpoint_WithAsync(t *testing.T) {
+	type inputs struct {
+		Name string `req:"query"`
+	}
+
+	type outputs struct {
+		Body map[string]string `resp:"body"`
+	}
+
+	tests := []struct {
+		name    string
+		handler interface{}
+		wantErr bool
+	}{
+		{
+			name:    "Outputs",
+			handler: func(inputs) (outputs, error) { return outputs{}, nil },
+		},
+		{
+			name:    "Error",
+			handler: func(inputs) error { return nil },
+		},
+		{
+			name:    "Unmanaged",
+			handler: func(http.ResponseWriter, *http.Request) {},
+			wantErr: true,
+		},
+		{
+			name:    "ResponseEncoder",
+			handler: func(ResponseEncoder, inputs) error { return nil },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewEndpoint(http.MethodPost, "api", "v1", "entities").
+				WithOperationId("createEntity").
+				WithHandler(tt.handler).
+				WithResponseCodes(CreateResponseCodes).
+				WithAsync(new(asyncTestExecutor)).
+				Build()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, []int{http.StatusAccepted}, e.Response.Codes.Success)
+			assert.Equal(t, CreateResponseCodes.Error, e.Response.Codes.Error)
+			assert.Contains(t, e.Response.Success.Headers, "Location")
+			assert.Equal(t, "", e.Response.Success.Payload.Value())
+		})
+	}
+}
+
+func TestEndpointController_Async(t *testing.T) {
+	type inputs struct {
+		Name string `req:"body"`
+	}
+
+	type outputs struct {
+		Body map[string]string `resp:"body"`
+	}
+
+	errHandler := errors.New("Handler failed")
+
+	tests := []struct {
+		name       string
+		body       string
+		wantResult interface{}
+		wantErr    error
+	}{
+		{
+			name:       "Success",
+			body:       `"widget"`,
+			wantResult: map[string]string{"name": "widget"},
+		},
+		{
+			name:    "Failure",
+			body:    `"fail"`,
+			wantErr: errHandler,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			RegisterPortFieldValidationSchemaFunc(func(field *ops.PortField) (js.ValidationSchema, error) {
+				return js.ValidationSchema{}, nil
+			})
+
+			executor := new(asyncTestExecutor)
+
+			e, err := NewEndpoint(http.MethodPost, "/api/v1/entities").
+				WithOperationId("createEntity").
+				WithResponseCodes(CreateResponseCodes).
+				WithHandler(func(ctx context.Context, inp *inputs) (out outputs, err error) {
+					if inp.Name == "fail" {
+						return out, errHandler
+					}
+					out.Body = map[string]string{"name": inp.Name}
+					return
+				}).
+				WithAsync(executor).
+				Build()
+			assert.NoError(t, err)
+
+			container := restful.NewContainer()
+			ws := new(restful.WebService)
+			ws.Path("/api")
+			ws.Route(RouteBuilderFromEndpoint(ws, e))
+			container.Add(ws)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/entities", bytes.NewBufferString(tt.body))
+			req.Header.Set(HeaderContentType, MediaTypeJson)
+			req.Header.Set(HeaderAccept, MediaTypeJson)
+			rec := httptest.NewRecorder()
+			container.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusAccepted, rec.Code)
+			assert.Equal(t, "/api/v1/operations/createEntity", rec.Header().Get("Location"))
+			assert.Equal(t, "accepted", rec.Body.String())
+
+			assert.Equal(t, tt.wantResult, executor.result)
+			assert.ErrorIs(t, executor.err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package restops
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/ops"
+	"github.com/pkg/errors"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnsupportedMediaType = errors.New("Unsupported media type")
+	ErrNotAcceptable        = errors.New("No acceptable media type")
+	ErrProtobufCodecType    = errors.New("Protobuf codec requires a proto.Message body")
+)
+
+// Codecs are the structured media types which may be negotiated for entity bodies.
+var Codecs = []string{
+	MediaTypeJson,
+	MediaTypeXml,
+	MediaTypeYaml,
+	MediaTypeCbor,
+	MediaTypeProtobuf,
+}
+
+// IsCodecMediaType returns true if entities can be marshaled to and from the media type.
+func IsCodecMediaType(mediaType string) bool {
+	if mediaType == MediaTypeBinary {
+		return false
+	}
+	_, err := ops.NewMarshaler(mediaType)
+	return err == nil
+}
+
+// CheckContentType returns a 415 status error if the media type of the supplied
+// Content-Type header is not one of the consumed media types.
+func CheckContentType(contentType string, consumes []string) error {
+	if len(consumes) == 0 {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return NewStatusCodeError(
+			errors.Wrap(ErrUnsupportedMediaType, contentType),
+			http.StatusUnsupportedMediaType)
+	}
+
+	for _, consumed := range consumes {
+		if (acceptRange{mediaType: strings.ToLower(consumed)}).matches(mediaType) {
+			return nil
+		}
+	}
+
+	return NewStatusCodeError(
+		errors.Wrap(ErrUnsupportedMediaType, mediaType),
+		http.StatusUnsupportedMediaType)
+}
+
+type acceptRange struct {
+	mediaType string
+	quality   float64
+	index     int
+}
+
+func (a acceptRange) matches(mediaType string) bool {
+	if a.mediaType == "*/*" {
+		return true
+	}
+
+	if strings.HasSuffix(a.mediaType, "/*") {
+		return strings.HasPrefix(mediaType, strings.TrimSuffix(a.mediaType, "*"))
+	}
+
+	return a.mediaType == mediaType
+}
+
+func (a acceptRange) specificity() int {
+	switch {
+	case a.mediaType == "*/*":
+		return 0
+	case strings.HasSuffix(a.mediaType, "/*"):
+		return 1
+	default:
+		return 2
+	}
+}
+
+func parseAccept(accept string) (results []acceptRange) {
+	for i, part := range strings.Split(accept, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+
+		results = append(results, acceptRange{
+			mediaType: strings.ToLower(mediaType),
+			quality:   quality,
+			index:     i,
+		})
+	}
+
+	// Most specific ranges take precedence when assigning quality
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].specificity() > results[j].specificity()
+	})
+
+	return results
+}
+
+// NegotiateMediaType selects the offered media type most preferred by the supplied
+// Accept header.  Offers are listed in server preference order, which breaks ties.
+// An empty Accept header accepts the first offer.
+func NegotiateMediaType(accept string, offers []string) (string, error) {
+	if len(offers) == 0 {
+		return "", nil
+	}
+
+	if strings.TrimSpace(accept) == "" {
+		return offers[0], nil
+	}
+
+	ranges := parseAccept(accept)
+
+	var best string
+	var bestQuality float64
+	for _, offer := range offers {
+		for _, r := range ranges {
+			if !r.matches(strings.ToLower(offer)) {
+				continue
+			}
+
+			if r.quality > bestQuality {
+				best, bestQuality = offer, r.quality
+			}
+			break
+		}
+	}
+
+	if best == "" {
+		return "", NewStatusCodeError(
+			errors.Wrap(ErrNotAcceptable, accept),
+			http.StatusNotAcceptable)
+	}
+
+	return best, nil
+}
+
+// uniqueMediaTypes returns the non-empty media types in order, without duplicates.
+func uniqueMediaTypes(mediaTypes ...string) (results []string) {
+	seen := make(map[string]struct{})
+	for _, mediaType := range mediaTypes {
+		if mediaType == "" {
+			continue
+		}
+		if _, ok := seen[mediaType]; ok {
+			continue
+		}
+		seen[mediaType] = struct{}{}
+		results = append(results, mediaType)
+	}
+	return
+}
@@ -0,0 +1,128 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package restops
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestNegotiateMediaType(t *testing.T) {
+	offers := []string{MediaTypeJson, MediaTypeYaml, MediaTypeCbor}
+
+	tests := []struct {
+		name    string
+		accept  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Empty",
+			want: MediaTypeJson,
+		},
+		{
+			name:   "Wildcard",
+			accept: "*/*",
+			want:   MediaTypeJson,
+		},
+		{
+			name:   "Exact",
+			accept: "application/cbor",
+			want:   MediaTypeCbor,
+		},
+		{
+			name:   "Quality",
+			accept: "application/json;q=0.2, application/yaml;q=0.8, */*;q=0.1",
+			want:   MediaTypeYaml,
+		},
+		{
+			name:   "SpecificOverridesWildcard",
+			accept: "application/*;q=0.5, application/json;q=0",
+			want:   MediaTypeYaml,
+		},
+		{
+			name:   "Parameters",
+			accept: "application/yaml; charset=utf-8",
+			want:   MediaTypeYaml,
+		},
+		{
+			name:    "NotAcceptable",
+			accept:  "text/html",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NegotiateMediaType(tt.accept, offers)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrNotAcceptable)
+				assert.Equal(t, http.StatusNotAcceptable, err.(StatusCodeError).StatusCode())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCheckContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		consumes    []string
+		wantErr     bool
+	}{
+		{
+			name:        "Unrestricted",
+			contentType: MediaTypeCbor,
+		},
+		{
+			name:        "Consumed",
+			contentType: ContentTypeJson,
+			consumes:    []string{MediaTypeJson, MediaTypeYaml},
+		},
+		{
+			name:        "Wildcard",
+			contentType: MediaTypeCbor,
+			consumes:    []string{"*/*"},
+		},
+		{
+			name:        "Unsupported",
+			contentType: MediaTypeCbor,
+			consumes:    []string{MediaTypeJson},
+			wantErr:     true,
+		},
+		{
+			name:        "Invalid",
+			contentType: "application/",
+			consumes:    []string{MediaTypeJson},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckContentType(tt.contentType, tt.consumes)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrUnsupportedMediaType)
+				assert.Equal(t, http.StatusUnsupportedMediaType, err.(StatusCodeError).StatusCode())
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestIsCodecMediaType(t *testing.T) {
+	for _, mediaType := range Codecs {
+		assert.True(t, IsCodecMediaType(mediaType), mediaType)
+	}
+	assert.False(t, IsCodecMediaType(MediaTypeBinary))
+	assert.False(t, IsCodecMediaType(MediaTypeMultipartForm))
+}
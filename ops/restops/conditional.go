@@ -0,0 +1,374 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package restops
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"encoding/hex"
+	"github.com/pkg/errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var ErrPreconditionFailed = errors.New("Precondition failed")
+
+// EntityTagMode specifies how entity tags are generated for responses without
+// a version output.
+type EntityTagMode int
+
+const (
+	// EntityTagNone does not generate entity tags from response content.
+	EntityTagNone EntityTagMode = iota
+	// EntityTagStrong generates strong entity tags from response content.
+	EntityTagStrong
+	// EntityTagWeak generates weak entity tags from response content.
+	EntityTagWeak
+)
+
+// EntityTag is an HTTP entity tag, as used by the ETag, If-Match and
+// If-None-Match headers.
+type EntityTag struct {
+	Value string
+	Weak  bool
+}
+
+func (t EntityTag) IsEmpty() bool {
+	return t.Value == ""
+}
+
+func (t EntityTag) IsAny() bool {
+	return t.Value == "*"
+}
+
+func (t EntityTag) String() string {
+	switch {
+	case t.IsEmpty():
+		return ""
+	case t.IsAny():
+		return "*"
+	case t.Weak:
+		return `W/"` + t.Value + `"`
+	default:
+		return `"` + t.Value + `"`
+	}
+}
+
+// StrongMatch compares the entity tags using strong comparison.
+func (t EntityTag) StrongMatch(other EntityTag) bool {
+	return !t.Weak && !other.Weak && t.Value == other.Value
+}
+
+// WeakMatch compares the entity tags using weak comparison.
+func (t EntityTag) WeakMatch(other EntityTag) bool {
+	return t.Value == other.Value
+}
+
+// NewEntityTag creates an entity tag from a version identifier.
+func NewEntityTag(version string, weak bool) EntityTag {
+	return NewContentEntityTag([]byte(version), weak)
+}
+
+// NewContentEntityTag creates an entity tag from the representation content.
+func NewContentEntityTag(content []byte, weak bool) EntityTag {
+	sum := sha256.Sum256(content)
+	return EntityTag{
+		Value: hex.EncodeToString(sum[:16]),
+		Weak:  weak,
+	}
+}
+
+// ParseEntityTag parses a single entity tag.
+func ParseEntityTag(value string) (EntityTag, error) {
+	value = strings.TrimSpace(value)
+	if value == "*" {
+		return EntityTag{Value: value}, nil
+	}
+
+	var result EntityTag
+	if strings.HasPrefix(value, "W/") {
+		result.Weak = true
+		value = value[2:]
+	}
+
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return EntityTag{}, errors.Errorf("Invalid entity tag %q", value)
+	}
+
+	result.Value = value[1 : len(value)-1]
+	if result.Value == "" || strings.Contains(result.Value, `"`) {
+		return EntityTag{}, errors.Errorf("Invalid entity tag %q", value)
+	}
+
+	return result, nil
+}
+
+// EntityTags is a list of entity tags from an If-Match or If-None-Match header.
+type EntityTags []EntityTag
+
+// ParseEntityTags parses a comma-separated list of entity tags, ignoring invalid entries.
+func ParseEntityTags(value string) EntityTags {
+	var results = EntityTags{}
+	for _, part := range strings.Split(value, ",") {
+		if tag, err := ParseEntityTag(part); err == nil {
+			results = append(results, tag)
+		}
+	}
+	return results
+}
+
+// StrongMatch returns true if any of the entity tags strongly match the specified tag.
+func (t EntityTags) StrongMatch(tag EntityTag) bool {
+	for _, candidate := range t {
+		if candidate.IsAny() && !tag.IsEmpty() || candidate.StrongMatch(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// WeakMatch returns true if any of the entity tags weakly match the specified tag.
+func (t EntityTags) WeakMatch(tag EntityTag) bool {
+	for _, candidate := range t {
+		if candidate.IsAny() && !tag.IsEmpty() || candidate.WeakMatch(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// EntityVersion describes the current version of a resource.
+type EntityVersion struct {
+	ETag     EntityTag
+	Modified time.Time
+}
+
+// EntityVersionFunc retrieves the current version of the resource targeted by
+// an unsafe request, to evaluate If-Match and If-Unmodified-Since preconditions.
+type EntityVersionFunc func(ctx context.Context, inputs interface{}) (EntityVersion, error)
+
+// Preconditions contains the conditional request headers.
+type Preconditions struct {
+	IfMatch           EntityTags
+	IfNoneMatch       EntityTags
+	IfModifiedSince   types.Optional[time.Time]
+	IfUnmodifiedSince types.Optional[time.Time]
+}
+
+func (p Preconditions) IsEmpty() bool {
+	return p.IfMatch == nil &&
+		p.IfNoneMatch == nil &&
+		!p.IfModifiedSince.IsPresent() &&
+		!p.IfUnmodifiedSince.IsPresent()
+}
+
+// NotModified returns true if a safe request should be answered with 304 Not Modified.
+func (p Preconditions) NotModified(version EntityVersion) bool {
+	if p.IfNoneMatch != nil {
+		return p.IfNoneMatch.WeakMatch(version.ETag)
+	}
+
+	if p.IfModifiedSince.IsPresent() && !version.Modified.IsZero() {
+		return !version.Modified.Truncate(time.Second).After(p.IfModifiedSince.Value())
+	}
+
+	return false
+}
+
+// Check returns ErrPreconditionFailed if an unsafe request should not be applied
+// to the current version of the resource.
+func (p Preconditions) Check(version EntityVersion) error {
+	if p.IfMatch != nil {
+		if !p.IfMatch.StrongMatch(version.ETag) {
+			return ErrPreconditionFailed
+		}
+	} else if p.IfUnmodifiedSince.IsPresent() && !version.Modified.IsZero() {
+		if version.Modified.Truncate(time.Second).After(p.IfUnmodifiedSince.Value()) {
+			return ErrPreconditionFailed
+		}
+	}
+
+	if p.IfNoneMatch != nil && p.IfNoneMatch.WeakMatch(version.ETag) {
+		return ErrPreconditionFailed
+	}
+
+	return nil
+}
+
+// NewPreconditions parses the conditional request headers.
+func NewPreconditions(header http.Header) Preconditions {
+	var result Preconditions
+
+	if values, ok := header[HeaderIfMatch]; ok {
+		result.IfMatch = ParseEntityTags(strings.Join(values, ","))
+	}
+
+	if values, ok := header[HeaderIfNoneMatch]; ok {
+		result.IfNoneMatch = ParseEntityTags(strings.Join(values, ","))
+	}
+
+	if t, err := http.ParseTime(header.Get(HeaderIfModifiedSince)); err == nil {
+		result.IfModifiedSince = types.OptionalOf(t)
+	}
+
+	if t, err := http.ParseTime(header.Get(HeaderIfUnmodifiedSince)); err == nil {
+		result.IfUnmodifiedSince = types.OptionalOf(t)
+	}
+
+	return result
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+func isConditionalWriteMethod(method string) bool {
+	return method == http.MethodPut || method == http.MethodPatch || method == http.MethodDelete
+}
+
+// ConditionalMiddleware answers safe requests with 304 Not Modified when the
+// response entity tag or modification time satisfies the request preconditions.
+// Entity tags are generated from the response content for responses without
+// an ETag header, as specified by the mode.
+func ConditionalMiddleware(mode EntityTagMode) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isSafeMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			preconditions := NewPreconditions(r.Header)
+			if mode == EntityTagNone && preconditions.IsEmpty() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &conditionalResponseWriter{ResponseWriter: w}
+			next.ServeHTTP(cw, r)
+
+			if err := cw.complete(mode, preconditions); err != nil {
+				logger.WithContext(r.Context()).WithError(err).Error("Failed to write conditional response")
+			}
+		})
+	}
+}
+
+// conditionalResponseWriter buffers the response until the preconditions are evaluated.
+type conditionalResponseWriter struct {
+	http.ResponseWriter
+	code int
+	body bytes.Buffer
+}
+
+func (w *conditionalResponseWriter) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+}
+
+func (w *conditionalResponseWriter) Write(data []byte) (int, error) {
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+	return w.body.Write(data)
+}
+
+func (w *conditionalResponseWriter) complete(mode EntityTagMode, preconditions Preconditions) error {
+	code := w.code
+	if code == 0 {
+		code = http.StatusOK
+	}
+
+	header := w.Header()
+
+	if code == http.StatusOK {
+		if mode != EntityTagNone && header.Get(HeaderETag) == "" {
+			header.Set(HeaderETag, NewContentEntityTag(w.body.Bytes(), mode == EntityTagWeak).String())
+		}
+
+		var version EntityVersion
+		version.ETag, _ = ParseEntityTag(header.Get(HeaderETag))
+		version.Modified, _ = http.ParseTime(header.Get(HeaderLastModified))
+
+		if preconditions.NotModified(version) {
+			header.Del(HeaderContentType)
+			header.Del(HeaderContentLength)
+			header.Del(HeaderContentEncoding)
+			w.ResponseWriter.WriteHeader(http.StatusNotModified)
+			return nil
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+	_, err := w.ResponseWriter.Write(w.body.Bytes())
+	return err
+}
+
+type preconditionsContextKey int
+
+const contextKeyPreconditions preconditionsContextKey = iota
+
+// ContextWithPreconditions returns a context carrying the preconditions of an unsafe request.
+func ContextWithPreconditions(ctx context.Context, preconditions Preconditions) context.Context {
+	return context.WithValue(ctx, contextKeyPreconditions, preconditions)
+}
+
+// PreconditionsFromContext returns the preconditions of the unsafe request being handled.
+func PreconditionsFromContext(ctx context.Context) (Preconditions, bool) {
+	preconditions, ok := ctx.Value(contextKeyPreconditions).(Preconditions)
+	return preconditions, ok
+}
+
+// CheckPreconditions evaluates the If-Match, If-None-Match and If-Unmodified-Since
+// preconditions of an unsafe request against the current version of the resource.
+//
+// The version is read before the handler is called, outside any transaction of the
+// handler, so a concurrent update may still be applied between the check and the
+// handler.  The returned context carries the preconditions, allowing the handler to
+// repeat the check using CheckEntityVersion against the version it reads within the
+// transaction applying the update.
+func CheckPreconditions(ctx context.Context, e *Endpoint, r *http.Request, inputs interface{}) (context.Context, error) {
+	if !e.IsConditionalWrite() {
+		return ctx, nil
+	}
+
+	preconditions := NewPreconditions(r.Header)
+	if preconditions.IsEmpty() {
+		return ctx, nil
+	}
+
+	version, err := e.EntityVersion(ctx, inputs)
+	if err != nil {
+		return ctx, err
+	}
+
+	if err = preconditions.Check(version); err != nil {
+		return ctx, NewStatusCodeError(err, http.StatusPreconditionFailed)
+	}
+
+	return ContextWithPreconditions(ctx, preconditions), nil
+}
+
+// CheckEntityVersion evaluates the preconditions of the unsafe request being handled
+// against the version of the resource read by the handler.  Handlers should read the
+// version within the transaction applying the update (e.g. using SELECT ... FOR UPDATE,
+// or an UPDATE conditional on the version) to guarantee compare-and-set semantics.
+// Returns nil if the request has no preconditions.
+func CheckEntityVersion(ctx context.Context, version EntityVersion) error {
+	preconditions, ok := PreconditionsFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	if err := preconditions.Check(version); err != nil {
+		return NewStatusCodeError(err, http.StatusPreconditionFailed)
+	}
+
+	return nil
+}
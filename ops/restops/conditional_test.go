@@ -0,0 +1,546 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package restops
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseEntityTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    EntityTag
+		wantErr bool
+	}{
+		{
+			name:  "Strong",
+			value: `"abc"`,
+			want:  EntityTag{Value: "abc"},
+		},
+		{
+			name:  "Weak",
+			value: ` W/"abc" `,
+			want:  EntityTag{Value: "abc", Weak: true},
+		},
+		{
+			name:  "Any",
+			value: "*",
+			want:  EntityTag{Value: "*"},
+		},
+		{
+			name:    "Unquoted",
+			value:   "abc",
+			wantErr: true,
+		},
+		{
+			name:    "Empty",
+			value:   `""`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseEntityTag(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseEntityTags(t *testing.T) {
+	got := ParseEntityTags(`"a", W/"b", c, "d"`)
+	assert.Equal(t, EntityTags{
+		{Value: "a"},
+		{Value: "b", Weak: true},
+		{Value: "d"},
+	}, got)
+
+	assert.NotNil(t, ParseEntityTags(""))
+}
+
+func TestEntityTag_String(t *testing.T) {
+	assert.Equal(t, `"abc"`, EntityTag{Value: "abc"}.String())
+	assert.Equal(t, `W/"abc"`, EntityTag{Value: "abc", Weak: true}.String())
+	assert.Equal(t, "*", EntityTag{Value: "*"}.String())
+	assert.Equal(t, "", EntityTag{}.String())
+}
+
+func TestEntityTags_Match(t *testing.T) {
+	strong := EntityTag{Value: "abc"}
+	weak := EntityTag{Value: "abc", Weak: true}
+
+	tests := []struct {
+		name       string
+		tags       EntityTags
+		tag        EntityTag
+		wantStrong bool
+		wantWeak   bool
+	}{
+		{
+			name:       "StrongStrong",
+			tags:       EntityTags{strong},
+			tag:        strong,
+			wantStrong: true,
+			wantWeak:   true,
+		},
+		{
+			name:       "StrongWeak",
+			tags:       EntityTags{strong},
+			tag:        weak,
+			wantStrong: false,
+			wantWeak:   true,
+		},
+		{
+			name:       "Different",
+			tags:       EntityTags{{Value: "def"}},
+			tag:        strong,
+			wantStrong: false,
+			wantWeak:   false,
+		},
+		{
+			name:       "Any",
+			tags:       EntityTags{{Value: "*"}},
+			tag:        weak,
+			wantStrong: true,
+			wantWeak:   true,
+		},
+		{
+			name:       "AnyMissing",
+			tags:       EntityTags{{Value: "*"}},
+			tag:        EntityTag{},
+			wantStrong: false,
+			wantWeak:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantStrong, tt.tags.StrongMatch(tt.tag))
+			assert.Equal(t, tt.wantWeak, tt.tags.WeakMatch(tt.tag))
+		})
+	}
+}
+
+func TestNewEntityTag(t *testing.T) {
+	a := NewEntityTag("1", false)
+	b := NewEntityTag("1", true)
+	c := NewEntityTag("2", false)
+
+	assert.Len(t, a.Value, 32)
+	assert.Equal(t, a.Value, b.Value)
+	assert.True(t, b.Weak)
+	assert.NotEqual(t, a.Value, c.Value)
+}
+
+func TestPreconditions_NotModified(t *testing.T) {
+	modified := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	version := EntityVersion{
+		ETag:     EntityTag{Value: "abc"},
+		Modified: modified,
+	}
+
+	tests := []struct {
+		name          string
+		preconditions Preconditions
+		want          bool
+	}{
+		{
+			name: "Empty",
+			want: false,
+		},
+		{
+			name: "IfNoneMatch",
+			preconditions: Preconditions{
+				IfNoneMatch: EntityTags{{Value: "abc", Weak: true}},
+			},
+			want: true,
+		},
+		{
+			name: "IfNoneMatchChanged",
+			preconditions: Preconditions{
+				IfNoneMatch:     EntityTags{{Value: "def"}},
+				IfModifiedSince: types.OptionalOf(modified),
+			},
+			want: false,
+		},
+		{
+			name: "IfModifiedSince",
+			preconditions: Preconditions{
+				IfModifiedSince: types.OptionalOf(modified),
+			},
+			want: true,
+		},
+		{
+			name: "IfModifiedSinceChanged",
+			preconditions: Preconditions{
+				IfModifiedSince: types.OptionalOf(modified.Add(-time.Minute)),
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.preconditions.NotModified(version))
+		})
+	}
+}
+
+func TestPreconditions_Check(t *testing.T) {
+	modified := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	version := EntityVersion{
+		ETag:     EntityTag{Value: "abc"},
+		Modified: modified,
+	}
+
+	tests := []struct {
+		name          string
+		preconditions Preconditions
+		wantErr       bool
+	}{
+		{
+			name: "Empty",
+		},
+		{
+			name: "IfMatch",
+			preconditions: Preconditions{
+				IfMatch: EntityTags{{Value: "abc"}},
+			},
+		},
+		{
+			name: "IfMatchWeak",
+			preconditions: Preconditions{
+				IfMatch: EntityTags{{Value: "abc", Weak: true}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "IfMatchChanged",
+			preconditions: Preconditions{
+				IfMatch: EntityTags{{Value: "def"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "IfUnmodifiedSince",
+			preconditions: Preconditions{
+				IfUnmodifiedSince: types.OptionalOf(modified),
+			},
+		},
+		{
+			name: "IfUnmodifiedSinceChanged",
+			preconditions: Preconditions{
+				IfUnmodifiedSince: types.OptionalOf(modified.Add(-time.Minute)),
+			},
+			wantErr: true,
+		},
+		{
+			name: "IfNoneMatchAny",
+			preconditions: Preconditions{
+				IfNoneMatch: EntityTags{{Value: "*"}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.preconditions.Check(version)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrPreconditionFailed)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewPreconditions(t *testing.T) {
+	modified := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	header := http.Header{}
+	header.Set(HeaderIfMatch, `"a", "b"`)
+	header.Set(HeaderIfModifiedSince, modified.Format(http.TimeFormat))
+
+	got := NewPreconditions(header)
+	assert.Equal(t, EntityTags{{Value: "a"}, {Value: "b"}}, got.IfMatch)
+	assert.Nil(t, got.IfNoneMatch)
+	assert.True(t, got.IfModifiedSince.IsPresent())
+	assert.True(t, modified.Equal(got.IfModifiedSince.Value()))
+	assert.False(t, got.IfUnmodifiedSince.IsPresent())
+
+	assert.True(t, NewPreconditions(http.Header{}).IsEmpty())
+}
+
+func TestConditionalMiddleware(t *testing.T) {
+	body := []byte(`{"key":"value"}`)
+	contentTag := NewContentEntityTag(body, false)
+
+	handler := func(code int, header http.Header) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for k, v := range header {
+				for _, vv := range v {
+					w.Header().Add(k, vv)
+				}
+			}
+			w.Header().Set(HeaderContentType, ContentTypeJson)
+			w.WriteHeader(code)
+			_, _ = w.Write(body)
+		})
+	}
+
+	tests := []struct {
+		name     string
+		mode     EntityTagMode
+		method   string
+		request  http.Header
+		code     int
+		response http.Header
+		wantCode int
+		wantETag string
+		wantBody bool
+	}{
+		{
+			name:     "Passthrough",
+			mode:     EntityTagNone,
+			method:   http.MethodGet,
+			code:     http.StatusOK,
+			wantCode: http.StatusOK,
+			wantBody: true,
+		},
+		{
+			name:     "GeneratedETag",
+			mode:     EntityTagStrong,
+			method:   http.MethodGet,
+			code:     http.StatusOK,
+			wantCode: http.StatusOK,
+			wantETag: contentTag.String(),
+			wantBody: true,
+		},
+		{
+			name:   "GeneratedETagNotModified",
+			mode:   EntityTagWeak,
+			method: http.MethodGet,
+			request: http.Header{
+				HeaderIfNoneMatch: {contentTag.String()},
+			},
+			code:     http.StatusOK,
+			wantCode: http.StatusNotModified,
+			wantETag: `W/"` + contentTag.Value + `"`,
+		},
+		{
+			name:   "ExistingETagNotModified",
+			mode:   EntityTagNone,
+			method: http.MethodGet,
+			request: http.Header{
+				HeaderIfNoneMatch: {`"v1"`},
+			},
+			response: http.Header{
+				HeaderETag: {`"v1"`},
+			},
+			code:     http.StatusOK,
+			wantCode: http.StatusNotModified,
+			wantETag: `"v1"`,
+		},
+		{
+			name:   "ExistingETagModified",
+			mode:   EntityTagNone,
+			method: http.MethodGet,
+			request: http.Header{
+				HeaderIfNoneMatch: {`"v1"`},
+			},
+			response: http.Header{
+				HeaderETag: {`"v2"`},
+			},
+			code:     http.StatusOK,
+			wantCode: http.StatusOK,
+			wantETag: `"v2"`,
+			wantBody: true,
+		},
+		{
+			name:   "LastModifiedNotModified",
+			mode:   EntityTagNone,
+			method: http.MethodGet,
+			request: http.Header{
+				HeaderIfModifiedSince: {"Mon, 02 Jan 2023 03:04:05 GMT"},
+			},
+			response: http.Header{
+				HeaderLastModified: {"Mon, 02 Jan 2023 03:04:05 GMT"},
+			},
+			code:     http.StatusOK,
+			wantCode: http.StatusNotModified,
+		},
+		{
+			name:   "Error",
+			mode:   EntityTagStrong,
+			method: http.MethodGet,
+			request: http.Header{
+				HeaderIfNoneMatch: {"*"},
+			},
+			code:     http.StatusNotFound,
+			wantCode: http.StatusNotFound,
+			wantBody: true,
+		},
+		{
+			name:   "Unsafe",
+			mode:   EntityTagStrong,
+			method: http.MethodPut,
+			request: http.Header{
+				HeaderIfNoneMatch: {"*"},
+			},
+			code:     http.StatusOK,
+			wantCode: http.StatusOK,
+			wantBody: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/items", nil)
+			for k, v := range tt.request {
+				for _, vv := range v {
+					req.Header.Add(k, vv)
+				}
+			}
+			rec := httptest.NewRecorder()
+
+			ConditionalMiddleware(tt.mode)(handler(tt.code, tt.response)).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, tt.wantETag, rec.Header().Get(HeaderETag))
+			if tt.wantBody {
+				assert.Equal(t, body, rec.Body.Bytes())
+				assert.Equal(t, ContentTypeJson, rec.Header().Get(HeaderContentType))
+			} else {
+				assert.Empty(t, rec.Body.Bytes())
+				assert.Empty(t, rec.Header().Get(HeaderContentType))
+			}
+		})
+	}
+}
+
+func TestCheckPreconditions(t *testing.T) {
+	version := EntityVersion{ETag: EntityTag{Value: "abc"}}
+	versionFunc := func(ctx context.Context, inputs interface{}) (EntityVersion, error) {
+		return version, nil
+	}
+	errVersion := errors.New("Version lookup failed")
+
+	tests := []struct {
+		name     string
+		endpoint *Endpoint
+		header   http.Header
+		wantCtx  bool
+		wantErr  error
+		wantCode int
+	}{
+		{
+			name:     "NotConditional",
+			endpoint: NewEndpoint(http.MethodPut, "api", "v1", "items"),
+			header:   http.Header{HeaderIfMatch: {`"def"`}},
+		},
+		{
+			name: "NoPreconditions",
+			endpoint: NewEndpoint(http.MethodPut, "api", "v1", "items").
+				WithEntityVersion(versionFunc),
+			header: http.Header{},
+		},
+		{
+			name: "Match",
+			endpoint: NewEndpoint(http.MethodPut, "api", "v1", "items").
+				WithEntityVersion(versionFunc),
+			header:  http.Header{HeaderIfMatch: {`"abc"`}},
+			wantCtx: true,
+		},
+		{
+			name: "Mismatch",
+			endpoint: NewEndpoint(http.MethodPut, "api", "v1", "items").
+				WithEntityVersion(versionFunc),
+			header:   http.Header{HeaderIfMatch: {`"def"`}},
+			wantErr:  ErrPreconditionFailed,
+			wantCode: http.StatusPreconditionFailed,
+		},
+		{
+			name: "VersionError",
+			endpoint: NewEndpoint(http.MethodDelete, "api", "v1", "items").
+				WithEntityVersion(func(ctx context.Context, inputs interface{}) (EntityVersion, error) {
+					return EntityVersion{}, errVersion
+				}),
+			header:  http.Header{HeaderIfMatch: {`"abc"`}},
+			wantErr: errVersion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.endpoint.Method, "/api/v1/items", nil)
+			req.Header = tt.header
+
+			ctx, err := CheckPreconditions(context.Background(), tt.endpoint, req, nil)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				_, ok := PreconditionsFromContext(ctx)
+				assert.Equal(t, tt.wantCtx, ok)
+				return
+			}
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantCode != 0 {
+				var statusCodeErr StatusCodeProvider
+				assert.True(t, errors.As(err, &statusCodeErr))
+				assert.Equal(t, tt.wantCode, statusCodeErr.StatusCode())
+			}
+		})
+	}
+}
+
+func TestCheckEntityVersion(t *testing.T) {
+	preconditions := Preconditions{IfMatch: EntityTags{{Value: "abc"}}}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		version  EntityVersion
+		wantCode int
+	}{
+		{
+			name:    "NoPreconditions",
+			ctx:     context.Background(),
+			version: EntityVersion{ETag: EntityTag{Value: "def"}},
+		},
+		{
+			name:    "Match",
+			ctx:     ContextWithPreconditions(context.Background(), preconditions),
+			version: EntityVersion{ETag: EntityTag{Value: "abc"}},
+		},
+		{
+			name:     "Mismatch",
+			ctx:      ContextWithPreconditions(context.Background(), preconditions),
+			version:  EntityVersion{ETag: EntityTag{Value: "def"}},
+			wantCode: http.StatusPreconditionFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckEntityVersion(tt.ctx, tt.version)
+			if tt.wantCode == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, ErrPreconditionFailed)
+			var statusCodeErr StatusCodeProvider
+			assert.True(t, errors.As(err, &statusCodeErr))
+			assert.Equal(t, tt.wantCode, statusCodeErr.StatusCode())
+		})
+	}
+}
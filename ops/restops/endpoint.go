@@ -63,8 +63,15 @@ type Endpoint struct {
 	Response       EndpointResponse
 	Handler        *types.Handler
 	ErrorConverter ErrorConverter
+	Codecs         []string
+	EntityTags     EntityTagMode
+	EntityVersion  EntityVersionFunc
+	Patch          PatchSourceFunc
+	Async          AsyncExecutor
 	Unmanaged      bool
 	ops.Documentors[Endpoint]
+
+	asyncResult *ops.PortField
 }
 
 func (e *Endpoint) WithDocumentor(d ...ops.Documentor[Endpoint]) *Endpoint {
@@ -152,11 +159,68 @@ func (e *Endpoint) WithResponseDefaultError(errorBody any) *Endpoint {
 	return e
 }
 
+// WithProblemDetails returns errors as RFC 7807 problem details (application/problem+json).
+func (e *Endpoint) WithProblemDetails() *Endpoint {
+	e.Response = e.Response.WithError(e.Response.Error.
+		WithMime(MediaTypeProblemJson).
+		WithPayload(ProblemDetails{}))
+	return e
+}
+
 func (e *Endpoint) WithPermissionAnyOf(perms ...string) *Endpoint {
 	e.Permissions = perms
 	return e
 }
 
+// WithCodecs specifies additional media types to be negotiated for structured
+// request and response bodies, such as MediaTypeYaml or MediaTypeCbor.
+func (e *Endpoint) WithCodecs(mimes ...string) *Endpoint {
+	e.Codecs = append(e.Codecs, mimes...)
+	return e
+}
+
+// WithEntityTags generates entity tags from the response content of safe
+// requests, allowing clients to make conditional requests using If-None-Match.
+func (e *Endpoint) WithEntityTags(mode EntityTagMode) *Endpoint {
+	e.EntityTags = mode
+	return e
+}
+
+// WithEntityVersion enables If-Match and If-Unmodified-Since preconditions for
+// unsafe requests, using the supplied function to retrieve the current version.
+func (e *Endpoint) WithEntityVersion(fn EntityVersionFunc) *Endpoint {
+	e.EntityVersion = fn
+	return e
+}
+
+// WithPatch accepts JSON Patch and JSON Merge Patch request bodies, applying them to
+// the current resource representation returned by the supplied function.
+func (e *Endpoint) WithPatch(source PatchSourceFunc) *Endpoint {
+	e.Patch = source
+	return e
+}
+
+// IsConditionalRead returns true if safe requests to the endpoint may be answered
+// with 304 Not Modified.
+func (e *Endpoint) IsConditionalRead() bool {
+	if !isSafeMethod(e.Method) {
+		return false
+	}
+
+	if e.EntityTags != EntityTagNone {
+		return true
+	}
+
+	port := e.Response.Port
+	return port != nil && (port.Fields.First(PortFieldIsVersion) != nil || port.Fields.First(PortFieldIsModified) != nil)
+}
+
+// IsConditionalWrite returns true if unsafe requests to the endpoint are subject
+// to preconditions.
+func (e *Endpoint) IsConditionalWrite() bool {
+	return isConditionalWriteMethod(e.Method) && e.EntityVersion != nil
+}
+
 func (e *Endpoint) WithInjector(injectors ...types.ContextInjector) *Endpoint {
 	e.Injectors = append(e.Injectors, injectors...)
 	return e
@@ -244,6 +308,10 @@ func (e *Endpoint) Build() (*Endpoint, error) {
 		}
 	}
 
+	if err := e.applyAsync(analyzer); err != nil {
+		return nil, err
+	}
+
 	if e.Response.Error.Mime == "" && !e.Response.Envelope {
 		defaultError := e.Response.DefaultError.OrElse(webservice.ErrorV8{})
 		e.Response.Error = e.Response.Error.
@@ -251,6 +319,12 @@ func (e *Endpoint) Build() (*Endpoint, error) {
 			WithPayload(defaultError)
 	}
 
+	if err := e.applyCodecs(); err != nil {
+		return nil, err
+	}
+	e.applyConditional()
+	e.applyPatch()
+
 	argsTypeSet := analyzer.ArgsTypeSet()
 	returnsTypeSet := analyzer.ReturnsTypeSet()
 
@@ -272,6 +346,127 @@ func (e *Endpoint) Build() (*Endpoint, error) {
 	return e, nil
 }
 
+func (e *Endpoint) applyCodecs() error {
+	if len(e.Codecs) == 0 {
+		return nil
+	}
+
+	protobuf := false
+	for _, codec := range e.Codecs {
+		protobuf = protobuf || codec == MediaTypeProtobuf
+	}
+
+	if IsCodecMediaType(e.Request.Body.Mime) {
+		if protobuf && e.Request.Port != nil {
+			if err := validateProtobufPortFields(e.Request.Port.Fields.All(PortFieldIsBody)); err != nil {
+				return errors.Wrapf(err, "Invalid request body for operation %q", e.OperationID)
+			}
+		}
+		e.Request.Body = e.Request.Body.WithMimes(e.Codecs...)
+	}
+
+	if e.Response.Envelope {
+		// Envelope is always JSON
+		return nil
+	}
+
+	if IsCodecMediaType(e.Response.Success.Mime) {
+		if protobuf && e.Response.Port != nil {
+			if err := validateProtobufPortFields(e.Response.Port.Fields.All(PortFieldIsSuccessBody)); err != nil {
+				return errors.Wrapf(err, "Invalid response body for operation %q", e.OperationID)
+			}
+		}
+		e.Response.Success = e.Response.Success.WithMimes(e.Codecs...)
+	}
+
+	if IsCodecMediaType(e.Response.Error.Mime) {
+		errorCodecs := e.Codecs
+		if protobuf && !isProtobufPayload(e.Response.Error.Payload) {
+			// Error payloads which are not protobuf messages are never offered as protobuf
+			errorCodecs = nil
+			for _, codec := range e.Codecs {
+				if codec != MediaTypeProtobuf {
+					errorCodecs = append(errorCodecs, codec)
+				}
+			}
+		}
+		e.Response.Error = e.Response.Error.WithMimes(errorCodecs...)
+	}
+
+	return nil
+}
+
+func isProtoMessageType(t reflect.Type) bool {
+	return t != nil && (t.Implements(ops.ProtoMessageType) || reflect.PtrTo(t).Implements(ops.ProtoMessageType))
+}
+
+func isProtobufPayload(payload types.Optional[interface{}]) bool {
+	return payload.IsPresent() && isProtoMessageType(reflect.TypeOf(payload.Value()))
+}
+
+func validateProtobufPortFields(fields ops.PortFields) error {
+	for _, field := range fields {
+		if field.Type.Type == nil {
+			continue
+		}
+		if !isProtoMessageType(field.Type.Type) {
+			return errors.Wrapf(ErrProtobufCodecType, "Field %q has type %s", field.Name, field.Type.Type)
+		}
+	}
+	return nil
+}
+
+func (e *Endpoint) applyConditional() {
+	if e.IsConditionalRead() {
+		if e.EntityTags != EntityTagNone {
+			e.Response = e.Response.WithSuccessHeader(HeaderETag, EntityTagResponseHeader())
+		}
+
+		e.Request = e.Request.
+			WithParameter(conditionalRequestParameter(HeaderIfNoneMatch,
+				"Respond with 304 Not Modified if the current entity tag matches")).
+			WithParameter(conditionalRequestParameter(HeaderIfModifiedSince,
+				"Respond with 304 Not Modified if not modified since the specified time"))
+	}
+
+	if e.IsConditionalWrite() {
+		e.Request = e.Request.
+			WithParameter(conditionalRequestParameter(HeaderIfMatch,
+				"Respond with 412 Precondition Failed unless the current entity tag matches")).
+			WithParameter(conditionalRequestParameter(HeaderIfUnmodifiedSince,
+				"Respond with 412 Precondition Failed if modified since the specified time")).
+			WithParameter(conditionalRequestParameter(HeaderIfNoneMatch,
+				"Respond with 412 Precondition Failed if the current entity tag matches"))
+
+		codes := e.Response.Codes
+		if !types.ComparableSlice[int](codes.Error).Contains(http.StatusPreconditionFailed) {
+			codes.Error = append(append([]int{}, codes.Error...), http.StatusPreconditionFailed)
+			e.Response = e.Response.WithResponseCodes(codes)
+		}
+	}
+}
+
+func (e *Endpoint) applyPatch() {
+	if e.Patch == nil || !e.Request.HasBody() {
+		return
+	}
+
+	e.Request.Body = e.Request.Body.WithMimes(MediaTypeJsonPatch, MediaTypeMergePatch)
+
+	codes := e.Response.Codes
+	if !types.ComparableSlice[int](codes.Error).Contains(http.StatusConflict) {
+		codes.Error = append(append([]int{}, codes.Error...), http.StatusConflict)
+		e.Response = e.Response.WithResponseCodes(codes)
+	}
+}
+
+func conditionalRequestParameter(name, description string) EndpointRequestParameter {
+	return NewEndpointRequestParameter(name, FieldGroupHttpHeader).
+		WithDescription(description).
+		WithRequired(false).
+		WithPayload("")
+}
+
 // Builder
 
 type EndpointBuilder interface {
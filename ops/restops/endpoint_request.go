@@ -91,7 +91,7 @@ func (r EndpointRequest) PatchParameter(name string, fn func(p EndpointRequestPa
 }
 
 func (r EndpointRequest) Consumes() []string {
-	return []string{r.Body.Mime}
+	return r.Body.MediaTypes()
 }
 
 func (r EndpointRequest) HasBody() bool {
@@ -247,6 +247,7 @@ type EndpointRequestBody struct {
 	Description string
 	Required    bool
 	Mime        string
+	Mimes       []string
 	Payload     types.Optional[interface{}]
 	Example     types.Optional[interface{}]
 	FormFields  []EndpointRequestBodyFormField
@@ -266,6 +267,17 @@ func (b EndpointRequestBody) WithFormField(field EndpointRequestBodyFormField) E
 	return b
 }
 
+// WithMimes specifies additional media types accepted for the body.
+func (b EndpointRequestBody) WithMimes(mimes ...string) EndpointRequestBody {
+	b.Mimes = append(b.Mimes, mimes...)
+	return b
+}
+
+// MediaTypes returns the default media type followed by any additional media types.
+func (b EndpointRequestBody) MediaTypes() []string {
+	return uniqueMediaTypes(append([]string{b.Mime}, b.Mimes...)...)
+}
+
 func (b EndpointRequestBody) HasFormField() bool {
 	return len(b.FormFields) > 0
 }
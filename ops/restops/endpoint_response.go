@@ -100,12 +100,7 @@ func (r EndpointResponse) HasBody() bool {
 }
 
 func (r EndpointResponse) Produces() []string {
-	var mimeTypes = make(types.StringSet)
-	mimeTypes.AddAll(r.Success.Mime, r.Error.Mime)
-	if _, ok := mimeTypes[""]; ok {
-		delete(mimeTypes, "")
-	}
-	return mimeTypes.Values()
+	return uniqueMediaTypes(append(r.Success.MediaTypes(), r.Error.MediaTypes()...)...)
 }
 
 func (r EndpointResponse) withEnumCodes(enum string) EndpointResponse {
@@ -190,6 +185,10 @@ func (r EndpointResponse) withPortField(field *ops.PortField) EndpointResponse {
 		}
 	} else if PortFieldIsPaging(field) {
 		result.Success = result.Success.withPortFieldPaging(field)
+	} else if PortFieldIsVersion(field) {
+		result = result.WithSuccessHeader(HeaderETag, EntityTagResponseHeader())
+	} else if PortFieldIsModified(field) {
+		result = result.WithSuccessHeader(HeaderLastModified, LastModifiedResponseHeader())
 	}
 
 	return result
@@ -270,6 +269,8 @@ func DefaultResponseCodes(method string) EndpointResponseCodes {
 
 type EndpointResponseContent struct {
 	Mime    string
+	Mimes   []string
+	Stream  bool
 	Headers map[string]EndpointResponseHeader
 	Paging  types.Optional[interface{}]
 	Payload types.Optional[interface{}]
@@ -320,7 +321,22 @@ func (c EndpointResponseContent) WithMime(mime string) EndpointResponseContent {
 	return c
 }
 
+// WithMimes specifies additional media types which may be negotiated for the content.
+func (c EndpointResponseContent) WithMimes(mimes ...string) EndpointResponseContent {
+	c.Mimes = append(c.Mimes, mimes...)
+	return c
+}
+
+// MediaTypes returns the default media type followed by any additional media types.
+func (c EndpointResponseContent) MediaTypes() []string {
+	return uniqueMediaTypes(append([]string{c.Mime}, c.Mimes...)...)
+}
+
 func (c EndpointResponseContent) withPortFieldBody(field *ops.PortField) EndpointResponseContent {
+	if field.Type.Type.Implements(streamerType) {
+		return c.withPortFieldStream(field)
+	}
+
 	payload := types.Instantiate(field.Type.Type)
 	content := c.WithPayload(payload)
 
@@ -333,6 +349,24 @@ func (c EndpointResponseContent) withPortFieldBody(field *ops.PortField) Endpoin
 	return content
 }
 
+// withPortFieldStream describes a streamed body using its item type.  Both
+// Server-Sent Events and NDJSON are offered, preferring the mime option if specified.
+func (c EndpointResponseContent) withPortFieldStream(field *ops.PortField) EndpointResponseContent {
+	stream := reflect.Zero(refl.DeepIndirect(field.Type.Type)).Interface().(Streamer)
+	payload := types.Instantiate(refl.DeepIndirect(stream.StreamItemType()))
+	content := c.WithPayload(payload)
+
+	mime := MediaTypeEventStream
+	if mimeOverride := field.Options["mime"]; mimeOverride != "" {
+		mime = mimeOverride
+	}
+	content = content.WithMime(mime)
+	content.Mimes = uniqueMediaTypes(MediaTypeEventStream, MediaTypeNdjson)
+	content.Stream = true
+
+	return content
+}
+
 func (c EndpointResponseContent) withPortFieldPaging(field *ops.PortField) EndpointResponseContent {
 	payload := types.Instantiate(field.Type.Type)
 	content := c.WithPaging(payload)
@@ -421,6 +455,22 @@ func NewEndpointResponseHeader() EndpointResponseHeader {
 	return EndpointResponseHeader{}
 }
 
+// EntityTagResponseHeader describes the ETag response header.
+func EntityTagResponseHeader() EndpointResponseHeader {
+	return NewEndpointResponseHeader().
+		WithDescription("Entity tag of the current representation").
+		WithRequired(false).
+		WithPayload("")
+}
+
+// LastModifiedResponseHeader describes the Last-Modified response header.
+func LastModifiedResponseHeader() EndpointResponseHeader {
+	return NewEndpointResponseHeader().
+		WithDescription("Modification time of the current representation").
+		WithRequired(false).
+		WithPayload("")
+}
+
 func EndpointResponseHeaderFromPortField(pf *ops.PortField) EndpointResponseHeader {
 	header := NewEndpointResponseHeader().
 		WithRequired(!pf.Optional).
@@ -9,6 +9,7 @@ import (
 	"cto-github.cisco.com/NFV-BU/go-msx/types"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 	"net/http"
 	"reflect"
 	"strings"
@@ -272,6 +273,12 @@ func TestEndpoint_WithPermissionAnyOf(t *testing.T) {
 	assert.Equal(t, "VIEW_TESTS", f.Permissions[0])
 }
 
+func TestEndpoint_WithCodecs(t *testing.T) {
+	e := NewEndpoint(http.MethodGet, "a", "b", "c")
+	f := e.WithCodecs(MediaTypeYaml, MediaTypeCbor)
+	assert.Equal(t, []string{MediaTypeYaml, MediaTypeCbor}, f.Codecs)
+}
+
 func TestEndpoint_WithHandler(t *testing.T) {
 	e := NewEndpoint(http.MethodGet, "a", "b", "c")
 	f := e.WithHandler(func() {})
@@ -328,3 +335,84 @@ func TestEndpoint_Build_ImplicitPorts(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, f)
 }
+
+func TestEndpoint_Build_Codecs(t *testing.T) {
+	e := NewEndpoint(http.MethodPost, "a", "b", "c").
+		WithOperationId("testOperation").
+		WithCodecs(MediaTypeYaml).
+		WithHandler(func(inp struct {
+			Body map[string]string `req:"body"`
+		}) (out struct {
+			Body map[string]string `resp:"body"`
+		}, err error) {
+			return
+		})
+	f, err := e.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{MediaTypeJson, MediaTypeYaml}, f.Request.Consumes())
+	assert.Equal(t, []string{MediaTypeJson, MediaTypeYaml}, f.Response.Produces())
+}
+
+func TestEndpoint_Build_CodecsProtobuf(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  interface{}
+		wantErr  bool
+		consumes []string
+		produces []string
+	}{
+		{
+			name: "ProtoMessage",
+			handler: func(inp struct {
+				Body *wrapperspb.StringValue `req:"body"`
+			}) (out struct {
+				Body *wrapperspb.StringValue `resp:"body"`
+			}, err error) {
+				return
+			},
+			consumes: []string{MediaTypeJson, MediaTypeProtobuf},
+			produces: []string{MediaTypeJson, MediaTypeProtobuf},
+		},
+		{
+			name: "RequestNotProtoMessage",
+			handler: func(inp struct {
+				Body map[string]string `req:"body"`
+			}) (out struct {
+				Body *wrapperspb.StringValue `resp:"body"`
+			}, err error) {
+				return
+			},
+			wantErr: true,
+		},
+		{
+			name: "ResponseNotProtoMessage",
+			handler: func(inp struct {
+				Body *wrapperspb.StringValue `req:"body"`
+			}) (out struct {
+				Body map[string]string `resp:"body"`
+			}, err error) {
+				return
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEndpoint(http.MethodPost, "a", "b", "c").
+				WithOperationId("testOperation").
+				WithCodecs(MediaTypeProtobuf).
+				WithHandler(tt.handler)
+			f, err := e.Build()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrProtobufCodecType)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.consumes, f.Request.Consumes())
+			assert.Equal(t, tt.produces, f.Response.Produces())
+			assert.Equal(t, []string{MediaTypeJson}, f.Response.Error.MediaTypes())
+		})
+	}
+}
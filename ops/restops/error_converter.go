@@ -129,6 +129,29 @@ func MappedErrorStatusCode(err error) types.Optional[int] {
 	return types.OptionalEmpty[int]()
 }
 
+var (
+	defaultStatusCodeErrors    = map[int]error{}
+	defaultStatusCodeErrorsMtx sync.RWMutex
+)
+
+// SetMappedStatusCodeError registers the error to be reported by clients
+// receiving a response with the specified status code.
+func SetMappedStatusCodeError(statusCode int, err error) {
+	defaultStatusCodeErrorsMtx.Lock()
+	defer defaultStatusCodeErrorsMtx.Unlock()
+	defaultStatusCodeErrors[statusCode] = err
+}
+
+func MappedStatusCodeError(statusCode int) types.Optional[error] {
+	defaultStatusCodeErrorsMtx.RLock()
+	defer defaultStatusCodeErrorsMtx.RUnlock()
+
+	if err, ok := defaultStatusCodeErrors[statusCode]; ok {
+		return types.OptionalOf(err)
+	}
+	return types.OptionalEmpty[error]()
+}
+
 func DefaultErrorStatusCoder(err error) int {
 	optionalStatusCode := MappedErrorStatusCode(err)
 	return optionalStatusCode.OrElse(http.StatusBadRequest)
@@ -54,3 +54,19 @@ func Test_statusError(t *testing.T) {
 	assert.Equal(t, e.cause, e.Cause())
 	assert.Equal(t, e.cause, e.Unwrap())
 }
+
+func TestMappedStatusCodeError(t *testing.T) {
+	e := errors.New("some error")
+	SetMappedStatusCodeError(http.StatusTeapot, e)
+	defer func() {
+		defaultStatusCodeErrorsMtx.Lock()
+		delete(defaultStatusCodeErrors, http.StatusTeapot)
+		defaultStatusCodeErrorsMtx.Unlock()
+	}()
+
+	mapped := MappedStatusCodeError(http.StatusTeapot)
+	assert.True(t, mapped.IsPresent())
+	assert.Equal(t, e, mapped.Value())
+
+	assert.False(t, MappedStatusCodeError(http.StatusBadGateway).IsPresent())
+}
@@ -6,8 +6,17 @@ package restops
 
 // Common headers
 const (
+	HeaderAccept             = "Accept"
+	HeaderCacheControl       = "Cache-Control"
 	HeaderContentType        = "Content-Type"
 	HeaderContentEncoding    = "Content-Encoding"
 	HeaderContentDisposition = "Content-Disposition"
+	HeaderContentLength      = "Content-Length"
 	HeaderSetCookie          = "Set-Cookie"
+	HeaderETag               = "ETag"
+	HeaderLastModified       = "Last-Modified"
+	HeaderIfMatch            = "If-Match"
+	HeaderIfNoneMatch        = "If-None-Match"
+	HeaderIfModifiedSince    = "If-Modified-Since"
+	HeaderIfUnmodifiedSince  = "If-Unmodified-Since"
 )
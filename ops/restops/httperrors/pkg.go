@@ -15,10 +15,22 @@ import (
 func init() {
 	restops.SetMappedErrorStatusCode(ops.ErrValidationFailed, http.StatusBadRequest)
 	restops.SetMappedErrorStatusCode(ops.ErrMissingRequiredValue, http.StatusBadRequest)
+	restops.SetMappedErrorStatusCode(restops.ErrUnsupportedMediaType, http.StatusUnsupportedMediaType)
+	restops.SetMappedErrorStatusCode(restops.ErrNotAcceptable, http.StatusNotAcceptable)
+	restops.SetMappedErrorStatusCode(restops.ErrPreconditionFailed, http.StatusPreconditionFailed)
+	restops.SetMappedErrorStatusCode(restops.ErrInvalidPatch, http.StatusBadRequest)
+	restops.SetMappedErrorStatusCode(restops.ErrPatchConflict, http.StatusConflict)
 
 	restops.SetMappedErrorStatusCode(rbac.ErrTenantDoesNotExist, http.StatusUnauthorized)
 	restops.SetMappedErrorStatusCode(rbac.ErrUserDoesNotHaveTenantAccess, http.StatusBadRequest)
 
 	restops.SetMappedErrorStatusCode(repository.ErrAlreadyExists, http.StatusConflict)
 	restops.SetMappedErrorStatusCode(repository.ErrNotFound, http.StatusNotFound)
+
+	restops.SetMappedStatusCodeError(http.StatusBadRequest, ops.ErrValidationFailed)
+	restops.SetMappedStatusCodeError(http.StatusUnsupportedMediaType, restops.ErrUnsupportedMediaType)
+	restops.SetMappedStatusCodeError(http.StatusNotAcceptable, restops.ErrNotAcceptable)
+	restops.SetMappedStatusCodeError(http.StatusPreconditionFailed, restops.ErrPreconditionFailed)
+	restops.SetMappedStatusCodeError(http.StatusConflict, repository.ErrAlreadyExists)
+	restops.SetMappedStatusCodeError(http.StatusNotFound, repository.ErrNotFound)
 }
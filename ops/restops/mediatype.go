@@ -8,11 +8,19 @@ package restops
 const (
 	MediaTypeJson           = "application/json"
 	MediaTypeXml            = "text/xml"
+	MediaTypeYaml           = "application/yaml"
+	MediaTypeCbor           = "application/cbor"
+	MediaTypeProtobuf       = "application/x-protobuf"
 	MediaTypeBinary         = "application/octet-stream"
 	MediaTypeFormUrlencoded = "application/x-www-form-urlencoded"
 	MediaTypeMultipartForm  = "multipart/form-data"
 	MediaTypeMultipartMixed = "multipart/mixed"
 	MediaTypeTextPlain      = "text/plain"
+	MediaTypeEventStream    = "text/event-stream"
+	MediaTypeNdjson         = "application/x-ndjson"
+	MediaTypeJsonPatch      = "application/json-patch+json"
+	MediaTypeMergePatch     = "application/merge-patch+json"
+	MediaTypeProblemJson    = "application/problem+json"
 )
 
 // Content types
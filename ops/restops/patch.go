@@ -0,0 +1,175 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package restops
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops"
+	"encoding/json"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/pkg/errors"
+	"net/http"
+)
+
+var (
+	ErrInvalidPatch  = errors.New("Invalid patch document")
+	ErrPatchConflict = errors.New("Patch conflicts with the current resource")
+)
+
+// PatchSourceFunc retrieves the current representation of the resource targeted by
+// a PATCH request.  The inputs are populated from all request values except the body.
+type PatchSourceFunc func(ctx context.Context, inputs interface{}) (interface{}, error)
+
+// PatchOperation is a JSON Patch (RFC 6902) operation, used to document JSON Patch request bodies.
+type PatchOperation struct {
+	Op    string      `json:"op" required:"true" enum:"add,remove,replace,move,copy,test"`
+	Path  string      `json:"path" required:"true"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+var patchOperationKinds = map[string]bool{
+	"add":     true,
+	"remove":  true,
+	"replace": true,
+	"move":    true,
+	"copy":    true,
+	"test":    true,
+}
+
+// IsPatchMediaType returns true if the media type is a JSON Patch or JSON Merge Patch document.
+func IsPatchMediaType(mediaType string) bool {
+	return mediaType == MediaTypeJsonPatch || mediaType == MediaTypeMergePatch
+}
+
+// ApplyPatch applies a JSON Patch or JSON Merge Patch document to the JSON document.
+// Malformed patches return a 400 status error, and JSON Patch operations which cannot
+// be applied to the document return a 409 status error.
+func ApplyPatch(mediaType string, document, patch []byte) ([]byte, error) {
+	switch mediaType {
+	case MediaTypeJsonPatch:
+		operations, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, NewStatusCodeError(
+				errors.Wrap(ErrInvalidPatch, err.Error()),
+				http.StatusBadRequest)
+		}
+
+		for _, operation := range operations {
+			if !patchOperationKinds[operation.Kind()] {
+				return nil, NewStatusCodeError(
+					errors.Wrapf(ErrInvalidPatch, "Unknown operation %q", operation.Kind()),
+					http.StatusBadRequest)
+			}
+		}
+
+		result, err := operations.Apply(document)
+		if err != nil {
+			return nil, NewStatusCodeError(
+				errors.Wrap(ErrPatchConflict, err.Error()),
+				http.StatusConflict)
+		}
+
+		return result, nil
+
+	case MediaTypeMergePatch:
+		result, err := jsonpatch.MergePatch(document, patch)
+		if err != nil {
+			return nil, NewStatusCodeError(
+				errors.Wrap(ErrInvalidPatch, err.Error()),
+				http.StatusBadRequest)
+		}
+
+		return result, nil
+
+	default:
+		return nil, NewStatusCodeError(
+			errors.Wrap(ErrUnsupportedMediaType, mediaType),
+			http.StatusUnsupportedMediaType)
+	}
+}
+
+// PatchRequestDecoder decodes the request body as the patched resource representation.
+type PatchRequestDecoder struct {
+	ops.InputDecoder
+	document []byte
+}
+
+func (d PatchRequestDecoder) DecodeContent(pf *ops.PortField) (ops.Content, error) {
+	if pf.Group != FieldGroupHttpBody {
+		return d.InputDecoder.DecodeContent(pf)
+	}
+
+	return ops.NewContentFromBytes(ops.NewContentOptions(MediaTypeJson), d.document), nil
+}
+
+// NewPatchRequestDecoder applies a JSON Patch or JSON Merge Patch request body to the current
+// representation of the resource, as retrieved by the endpoint patch source.  The returned
+// decoder supplies the patched representation as the request body, so that it is validated
+// and populated in the same way as a full replacement.  Requests without a patch body
+// are decoded unchanged.
+func NewPatchRequestDecoder(ctx context.Context, e *Endpoint, decoder ops.InputDecoder) (ops.InputDecoder, error) {
+	if e.Patch == nil || e.Request.Port == nil {
+		return decoder, nil
+	}
+
+	bodyPortField := e.Request.Port.Fields.First(PortFieldIsBody)
+	if bodyPortField == nil {
+		return decoder, nil
+	}
+
+	content, err := decoder.DecodeContent(bodyPortField)
+	if err != nil {
+		return nil, err
+	}
+
+	if !content.IsPresent() {
+		return decoder, nil
+	}
+
+	mediaType, err := content.BaseMediaType()
+	if err != nil || !IsPatchMediaType(mediaType) {
+		return decoder, nil
+	}
+
+	patch, err := content.ReadBytes()
+	if err != nil {
+		return nil, NewStatusCodeError(err, http.StatusBadRequest)
+	}
+
+	// Populate the inputs identifying the resource
+	sourcePort := &ops.Port{
+		Type:       e.Request.Port.Type,
+		StructType: e.Request.Port.StructType,
+		Fields: e.Request.Port.Fields.All(func(pf *ops.PortField) bool {
+			return !PortFieldIsBody(pf)
+		}),
+	}
+
+	inputs, err := ops.NewInputsPopulator(sourcePort, decoder).PopulateFields()
+	if err != nil {
+		return nil, NewStatusCodeError(err, http.StatusBadRequest)
+	}
+
+	current, err := e.Patch(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	document, err := json.Marshal(current)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to encode current resource")
+	}
+
+	document, err = ApplyPatch(mediaType, document, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	return PatchRequestDecoder{
+		InputDecoder: decoder,
+		document:     document,
+	}, nil
+}
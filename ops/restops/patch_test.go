@@ -0,0 +1,235 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package restops
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestIsPatchMediaType(t *testing.T) {
+	assert.True(t, IsPatchMediaType(MediaTypeJsonPatch))
+	assert.True(t, IsPatchMediaType(MediaTypeMergePatch))
+	assert.False(t, IsPatchMediaType(MediaTypeJson))
+}
+
+func TestApplyPatch(t *testing.T) {
+	const document = `{"name":"widget","count":1,"tags":["a"]}`
+
+	tests := []struct {
+		name      string
+		mediaType string
+		patch     string
+		want      string
+		wantErr   error
+		wantCode  int
+	}{
+		{
+			name:      "JsonPatch",
+			mediaType: MediaTypeJsonPatch,
+			patch:     `[{"op":"replace","path":"/count","value":2},{"op":"add","path":"/tags/-","value":"b"}]`,
+			want:      `{"name":"widget","count":2,"tags":["a","b"]}`,
+		},
+		{
+			name:      "JsonPatchTestFailed",
+			mediaType: MediaTypeJsonPatch,
+			patch:     `[{"op":"test","path":"/count","value":2}]`,
+			wantErr:   ErrPatchConflict,
+			wantCode:  http.StatusConflict,
+		},
+		{
+			name:      "JsonPatchMissingPath",
+			mediaType: MediaTypeJsonPatch,
+			patch:     `[{"op":"remove","path":"/missing"}]`,
+			wantErr:   ErrPatchConflict,
+			wantCode:  http.StatusConflict,
+		},
+		{
+			name:      "JsonPatchUnknownOperation",
+			mediaType: MediaTypeJsonPatch,
+			patch:     `[{"op":"increment","path":"/count"}]`,
+			wantErr:   ErrInvalidPatch,
+			wantCode:  http.StatusBadRequest,
+		},
+		{
+			name:      "JsonPatchMalformed",
+			mediaType: MediaTypeJsonPatch,
+			patch:     `{"op":"replace"}`,
+			wantErr:   ErrInvalidPatch,
+			wantCode:  http.StatusBadRequest,
+		},
+		{
+			name:      "MergePatch",
+			mediaType: MediaTypeMergePatch,
+			patch:     `{"count":3,"tags":null}`,
+			want:      `{"name":"widget","count":3}`,
+		},
+		{
+			name:      "MergePatchMalformed",
+			mediaType: MediaTypeMergePatch,
+			patch:     `{"count":`,
+			wantErr:   ErrInvalidPatch,
+			wantCode:  http.StatusBadRequest,
+		},
+		{
+			name:      "UnsupportedMediaType",
+			mediaType: MediaTypeJson,
+			patch:     `{}`,
+			wantErr:   ErrUnsupportedMediaType,
+			wantCode:  http.StatusUnsupportedMediaType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyPatch(tt.mediaType, []byte(document), []byte(tt.patch))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				var statusCodeErr StatusCodeProvider
+				assert.True(t, errors.As(err, &statusCodeErr))
+				assert.Equal(t, tt.wantCode, statusCodeErr.StatusCode())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestNewPatchRequestDecoder(t *testing.T) {
+	type body struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+
+	type inputs struct {
+		EntityId string `req:"path"`
+		Body     body   `req:"body"`
+	}
+
+	current := body{Name: "widget", Count: 1}
+	errSource := errors.New("Source failed")
+
+	newEndpoint := func(source PatchSourceFunc) *Endpoint {
+		e, err := NewEndpoint(http.MethodPatch, "api", "v1", "entities", "{entityId}").
+			WithHandler(func(inputs) error { return nil }).
+			WithPatch(source).
+			Build()
+		assert.NoError(t, err)
+		return e
+	}
+
+	source := func(ctx context.Context, in interface{}) (interface{}, error) {
+		assert.Equal(t, "abc", in.(*inputs).EntityId)
+		return current, nil
+	}
+
+	tests := []struct {
+		name        string
+		endpoint    *Endpoint
+		contentType string
+		body        string
+		want        *inputs
+		wantErr     error
+	}{
+		{
+			name:        "MergePatch",
+			endpoint:    newEndpoint(source),
+			contentType: MediaTypeMergePatch,
+			body:        `{"count":2}`,
+			want:        &inputs{EntityId: "abc", Body: body{Name: "widget", Count: 2}},
+		},
+		{
+			name:        "JsonPatch",
+			endpoint:    newEndpoint(source),
+			contentType: MediaTypeJsonPatch,
+			body:        `[{"op":"replace","path":"/name","value":"gadget"}]`,
+			want:        &inputs{EntityId: "abc", Body: body{Name: "gadget", Count: 1}},
+		},
+		{
+			name:        "Replacement",
+			endpoint:    newEndpoint(source),
+			contentType: ContentTypeJson,
+			body:        `{"name":"gadget"}`,
+			want:        &inputs{EntityId: "abc", Body: body{Name: "gadget"}},
+		},
+		{
+			name:        "Conflict",
+			endpoint:    newEndpoint(source),
+			contentType: MediaTypeJsonPatch,
+			body:        `[{"op":"test","path":"/count","value":2}]`,
+			wantErr:     ErrPatchConflict,
+		},
+		{
+			name: "SourceError",
+			endpoint: newEndpoint(func(ctx context.Context, inputs interface{}) (interface{}, error) {
+				return nil, errSource
+			}),
+			contentType: MediaTypeMergePatch,
+			body:        `{"count":2}`,
+			wantErr:     errSource,
+		},
+		{
+			name: "UnsupportedMediaType",
+			endpoint: types.May(NewEndpoint(http.MethodPatch, "api", "v1", "entities", "{entityId}").
+				WithHandler(func(inputs) error { return nil }).
+				Build()),
+			contentType: MediaTypeMergePatch,
+			body:        `{"count":2}`,
+			wantErr:     ErrUnsupportedMediaType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataSource := MockRequestDataSource{
+				headers:        http.Header{HeaderContentType: {tt.contentType}},
+				pathParameters: map[string]string{"entityId": "abc"},
+				body:           []byte(tt.body),
+			}
+
+			var decoder ops.InputDecoder = NewEndpointRequestDecoder(dataSource, tt.endpoint.Request.Consumes())
+			decoder, err := NewPatchRequestDecoder(context.Background(), tt.endpoint, decoder)
+			if err == nil {
+				_, err = ops.NewInputsPopulator(tt.endpoint.Request.Port, decoder).PopulateInputs()
+			}
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			got, err := ops.NewInputsPopulator(tt.endpoint.Request.Port, decoder).PopulateInputs()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEndpoint_WithPatch(t *testing.T) {
+	type inputs struct {
+		Body map[string]string `req:"body"`
+	}
+
+	e, err := NewEndpoint(http.MethodPatch, "api", "v1", "entities").
+		WithHandler(func(inputs) error { return nil }).
+		WithResponseCodes(UpdateResponseCodes).
+		WithPatch(func(ctx context.Context, inputs interface{}) (interface{}, error) {
+			return nil, nil
+		}).
+		Build()
+	assert.NoError(t, err)
+
+	assert.Equal(t,
+		[]string{MediaTypeJson, MediaTypeJsonPatch, MediaTypeMergePatch},
+		e.Request.Consumes())
+	assert.Contains(t, e.Response.Codes.Error, http.StatusConflict)
+	assert.NotContains(t, UpdateResponseCodes.Error, http.StatusConflict)
+}
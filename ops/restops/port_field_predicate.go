@@ -39,6 +39,14 @@ func PortFieldIsCode(pf *ops.PortField) bool {
 	return pf.Group == FieldGroupHttpCode
 }
 
+func PortFieldIsVersion(pf *ops.PortField) bool {
+	return pf.Group == FieldGroupHttpVersion
+}
+
+func PortFieldIsModified(pf *ops.PortField) bool {
+	return pf.Group == FieldGroupHttpModified
+}
+
 func PortFieldIsForm(pf *ops.PortField) bool {
 	return pf.Group == FieldGroupHttpForm
 }
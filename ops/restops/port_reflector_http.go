@@ -22,6 +22,9 @@ const (
 	FieldGroupHttpBody   = "body"
 	FieldGroupHttpPaging = "paging"
 	FieldGroupHttpCode   = "code"
+
+	FieldGroupHttpVersion  = "version"
+	FieldGroupHttpModified = "modified"
 )
 
 type PortReflector struct{}
@@ -118,14 +121,40 @@ func (r PortReflector) ReflectOutputPort(st reflect.Type) (*ops.Port, error) {
 					ops.FieldShapeObject,
 				),
 			},
+			FieldGroupHttpVersion: {
+				Cardinality: types.CardinalityZeroToOne(),
+				AllowedShapes: types.NewStringSet(
+					ops.FieldShapePrimitive,
+				),
+			},
+			FieldGroupHttpModified: {
+				Cardinality: types.CardinalityZeroToOne(),
+				AllowedShapes: types.NewStringSet(
+					ops.FieldShapePrimitive,
+				),
+			},
 		},
 		FieldPostProcessor: r.postProcessField,
-		FieldTypeReflector: ops.NewDefaultPortFieldTypeReflector(ops.PortDirectionOut),
+		FieldTypeReflector: r.outputFieldTypeReflector(),
 	}
 
 	return reflector.ReflectPortStruct(PortTypeResponse, st)
 }
 
+func (r PortReflector) outputFieldTypeReflector() ops.PortFieldTypeReflector {
+	fieldTypeReflector := ops.NewDefaultPortFieldTypeReflector(ops.PortDirectionOut)
+	fieldTypeReflector.OnReflectDirect = ops.PortFieldTypeReflectorFunc(r.reflectStreamFieldType)
+	return fieldTypeReflector
+}
+
+// reflectStreamFieldType identifies streamed response bodies as content.
+func (r PortReflector) reflectStreamFieldType(t reflect.Type) (*ops.PortFieldType, error) {
+	if t.Implements(streamerType) {
+		return ops.PortFieldTypeFromType(t, ops.FieldShapeContent), nil
+	}
+	return new(ops.PortFieldType), nil
+}
+
 func (r PortReflector) postProcessField(pf *ops.PortField, sf reflect.StructField) {
 	if PortReflectorPostProcessField != nil {
 		PortReflectorPostProcessField(pf, sf)
@@ -137,6 +166,10 @@ func (r PortReflector) postProcessField(pf *ops.PortField, sf reflect.StructFiel
 		delete(pf.Options, "optional")
 	}
 
+	if pf.Group == FieldGroupHttpVersion || pf.Group == FieldGroupHttpModified {
+		pf.Optional = true
+	}
+
 	if pf.Group == FieldGroupHttpHeader {
 		pf.Peer = textproto.CanonicalMIMEHeaderKey(strcase.ToKebab(pf.Name))
 	}
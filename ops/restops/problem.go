@@ -0,0 +1,58 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package restops
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/ops"
+	"cto-github.cisco.com/NFV-BU/go-msx/webservice"
+	"github.com/pkg/errors"
+	"net/http"
+)
+
+const ProblemTypeDefault = "about:blank"
+
+// ProblemDetails is an RFC 7807 error payload.  Validation failures are listed
+// individually, each located by a JSON Pointer within the request.
+type ProblemDetails struct {
+	Type     string          `json:"type"`
+	Title    string          `json:"title"`
+	Status   int             `json:"status"`
+	Detail   string          `json:"detail,omitempty"`
+	Instance string          `json:"instance,omitempty"`
+	Code     string          `json:"code,omitempty"`
+	Errors   []ops.Violation `json:"errors,omitempty"`
+}
+
+func (p ProblemDetails) Example() interface{} {
+	return ProblemDetails{
+		Type:     ProblemTypeDefault,
+		Title:    http.StatusText(http.StatusBadRequest),
+		Status:   http.StatusBadRequest,
+		Detail:   ops.ErrValidationFailed.Error(),
+		Instance: "/api/v1/devices",
+		Code:     ops.CodeInvalid,
+		Errors: []ops.Violation{
+			{Pointer: "/query/pageSize", Detail: "must be >= 1 but found 0"},
+		},
+	}
+}
+
+func (p *ProblemDetails) SetError(code int, err error, path string) {
+	p.Type = ProblemTypeDefault
+	p.Title = http.StatusText(code)
+	p.Status = code
+	p.Detail = err.Error()
+	p.Instance = path
+
+	var errorCoder webservice.ErrorCoder
+	if errors.As(err, &errorCoder) {
+		p.Code = errorCoder.Code()
+	}
+
+	var validationFailure *ops.ValidationFailure
+	if errors.As(err, &validationFailure) {
+		p.Errors = validationFailure.Violations()
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package restops
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/ops"
+	"cto-github.cisco.com/NFV-BU/go-msx/testhelpers"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"cto-github.cisco.com/NFV-BU/go-msx/webservice"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestProblemDetails_SetError(t *testing.T) {
+	validationFailure := ops.NewValidationFailure("").
+		Apply(types.ErrorMap{"name": errors.New("Missing required value")}).
+		Rebase("/body")
+
+	tests := []struct {
+		name string
+		code int
+		err  error
+		want ProblemDetails
+	}{
+		{
+			name: "Error",
+			code: http.StatusNotFound,
+			err:  errors.New("Entity not found"),
+			want: ProblemDetails{
+				Type:     ProblemTypeDefault,
+				Title:    "Not Found",
+				Status:   http.StatusNotFound,
+				Detail:   "Entity not found",
+				Instance: "/api/v1/entities",
+			},
+		},
+		{
+			name: "CodedError",
+			code: http.StatusConflict,
+			err:  webservice.NewCodedError("BIZ001", errors.New("Entity in busy state")),
+			want: ProblemDetails{
+				Type:     ProblemTypeDefault,
+				Title:    "Conflict",
+				Status:   http.StatusConflict,
+				Detail:   "Entity in busy state",
+				Instance: "/api/v1/entities",
+				Code:     "BIZ001",
+			},
+		},
+		{
+			name: "ValidationFailure",
+			code: http.StatusBadRequest,
+			err:  NewStatusCodeError(validationFailure, http.StatusBadRequest),
+			want: ProblemDetails{
+				Type:     ProblemTypeDefault,
+				Title:    "Bad Request",
+				Status:   http.StatusBadRequest,
+				Detail:   "Validation failure",
+				Instance: "/api/v1/entities",
+				Code:     ops.CodeInvalid,
+				Errors: []ops.Violation{
+					{Pointer: "/body/name", Detail: "Missing required value"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ProblemDetails{}
+			got.SetError(tt.code, tt.err, "/api/v1/entities")
+			assert.True(t,
+				reflect.DeepEqual(tt.want, got),
+				testhelpers.Diff(tt.want, got))
+		})
+	}
+}
+
+func TestEndpoint_WithProblemDetails(t *testing.T) {
+	e, err := NewEndpoint(http.MethodGet, "api", "v1", "entities").
+		WithHandler(func() error { return nil }).
+		WithResponseCodes(GetResponseCodes).
+		WithResponseDefaultError(webservice.ErrorV8{}).
+		WithProblemDetails().
+		Build()
+	assert.NoError(t, err)
+
+	assert.Equal(t, MediaTypeProblemJson, e.Response.Error.Mime)
+	assert.Equal(t, ProblemDetails{}, e.Response.Error.Payload.Value())
+}
@@ -25,6 +25,7 @@ const (
 type EndpointRequestDecoder struct {
 	DataSource         RequestDataSource
 	Body               []byte
+	Consumes           []string
 	defaultContentType string
 	defaultEncoding    string
 }
@@ -59,6 +60,10 @@ func (d *EndpointRequestDecoder) DecodeContent(pf *ops.PortField) (result ops.Co
 		var payload []byte
 
 		bodyContentOptions := d.DataSource.BodyContentOptions(d.defaultContentType, d.defaultEncoding)
+		if err = CheckContentType(bodyContentOptions.MimeType, d.Consumes); err != nil {
+			return
+		}
+
 		payload, err = d.DataSource.Body()
 		if err != nil {
 			return
@@ -536,3 +541,19 @@ func NewRequestDecoder(source RequestDataSource) ops.InputDecoder {
 		defaultContentType: MediaTypeJson,
 	}
 }
+
+// NewEndpointRequestDecoder returns a decoder accepting request bodies of the
+// consumed media types.  Requests without a Content-Type are decoded using the
+// first consumed media type.
+func NewEndpointRequestDecoder(source RequestDataSource, consumes []string) ops.InputDecoder {
+	defaultContentType := MediaTypeJson
+	if len(consumes) > 0 {
+		defaultContentType = consumes[0]
+	}
+
+	return &EndpointRequestDecoder{
+		DataSource:         source,
+		Consumes:           consumes,
+		defaultContentType: defaultContentType,
+	}
+}
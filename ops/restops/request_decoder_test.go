@@ -1200,3 +1200,77 @@ value2
 		})
 	}
 }
+
+func TestEndpointRequestDecoder_DecodeContent(t *testing.T) {
+	type entity struct {
+		Name string `json:"name"`
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        entity
+		wantErr     error
+	}{
+		{
+			name:        "Json",
+			contentType: ContentTypeJson,
+			body:        `{"name":"alpha"}`,
+			want:        entity{Name: "alpha"},
+		},
+		{
+			name:        "Yaml",
+			contentType: MediaTypeYaml,
+			body:        "name: alpha\n",
+			want:        entity{Name: "alpha"},
+		},
+		{
+			name: "Default",
+			body: `{"name":"alpha"}`,
+			want: entity{Name: "alpha"},
+		},
+		{
+			name:        "Unsupported",
+			contentType: MediaTypeCbor,
+			body:        `{}`,
+			wantErr:     ErrUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := make(http.Header)
+			if tt.contentType != "" {
+				headers.Set(HeaderContentType, tt.contentType)
+			}
+
+			d := NewEndpointRequestDecoder(MockRequestDataSource{
+				headers: headers,
+				body:    []byte(tt.body),
+			}, []string{MediaTypeJson, MediaTypeYaml})
+
+			pf := ops.PortField{
+				Group: FieldGroupHttpBody,
+				Type: ops.PortFieldType{
+					Shape: ops.FieldShapeContent,
+					Type:  reflect.TypeOf(entity{}),
+				},
+			}
+
+			content, err := d.DecodeContent(&pf)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				var statusCodeError StatusCodeError
+				assert.ErrorAs(t, err, &statusCodeError)
+				assert.Equal(t, http.StatusUnsupportedMediaType, statusCodeError.StatusCode())
+				return
+			}
+
+			assert.NoError(t, err)
+			var got entity
+			assert.NoError(t, content.ReadEntity(&got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
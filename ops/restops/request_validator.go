@@ -8,12 +8,15 @@ import (
 	"cto-github.cisco.com/NFV-BU/go-msx/ops"
 	"cto-github.cisco.com/NFV-BU/go-msx/schema/js"
 	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"encoding/json"
 	"github.com/pkg/errors"
 	jsv "github.com/santhosh-tekuri/jsonschema/v5"
 	"github.com/swaggest/jsonschema-go"
 	"io"
 	"mime/multipart"
+	"reflect"
 	"strconv"
+	"strings"
 )
 
 type RequestValidator struct {
@@ -48,7 +51,9 @@ func (v RequestValidator) ValidateRequest() (err error) {
 		if validationErr != nil {
 			switch typedErr := validationErr.(type) {
 			case *jsv.ValidationError:
-				errs.Children[field.Name] = ops.NewValidationFailure(typedErr.InstanceLocation).Apply(typedErr)
+				errs.Children[field.Name] = ops.NewValidationFailure(typedErr.InstanceLocation).
+					Apply(typedErr).
+					Rebase(RequestFieldPointer(field))
 			default:
 				return validationErr
 			}
@@ -62,6 +67,18 @@ func (v RequestValidator) ValidateRequest() (err error) {
 	return nil
 }
 
+// RequestFieldPointer returns the JSON Pointer locating the field value within the request,
+// such as "/query/pageSize" or "/body".
+func RequestFieldPointer(field *ops.PortField) string {
+	pointer := "/" + field.Group
+	if field.Group != FieldGroupHttpBody && field.Peer != "" {
+		pointer += "/" + jsonPointerEscaper.Replace(field.Peer)
+	}
+	return pointer
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
 func (v RequestValidator) ValidateField(field *ops.PortField) (err error) {
 	var validationSchema js.ValidationSchema
 	validationSchema, err = GetPortFieldValidationSchema(field)
@@ -233,13 +250,32 @@ func (v RequestValidator) GetPayloadAsParsedJson(field *ops.PortField) (interfac
 		return nil, err
 	}
 
-	if contentType != MediaTypeJson {
-		// Need to round-trip via the field DTO
+	if contentType == MediaTypeJson {
+		var parsed interface{}
+		if err = content.ReadEntity(&parsed); err != nil {
+			return nil, err
+		}
+
+		return parsed, nil
+	}
+
+	if !IsCodecMediaType(contentType) {
 		return nil, errors.Errorf("Unsupported content format for JSON Schema validation: %s", contentType)
 	}
 
+	// Round-trip via the field DTO
+	dto := reflect.New(field.Type.Type).Interface()
+	if err = content.ReadEntity(dto); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(dto)
+	if err != nil {
+		return nil, err
+	}
+
 	var parsed interface{}
-	if err = content.ReadEntity(&parsed); err != nil {
+	if err = json.Unmarshal(data, &parsed); err != nil {
 		return nil, err
 	}
 
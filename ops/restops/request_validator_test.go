@@ -10,6 +10,7 @@ import (
 	"cto-github.cisco.com/NFV-BU/go-msx/schema/js"
 	"cto-github.cisco.com/NFV-BU/go-msx/testhelpers"
 	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/swaggest/jsonschema-go"
 	"mime/multipart"
@@ -106,6 +107,65 @@ func TestRequestValidator_ValidateRequest(t *testing.T) {
 	}
 }
 
+func TestRequestValidator_ValidateRequest_Violations(t *testing.T) {
+	RegisterPortFieldValidationSchemaFunc(func(field *ops.PortField) (schema js.ValidationSchema, err error) {
+		return js.NewValidationSchemaFromJsonSchema(js.StringSchema().WithPattern(`^\d+$`))
+	})
+
+	v := testNewRequestValidator(t,
+		struct {
+			XCount string `req:"header"`
+			B      string `req:"query"`
+		}{},
+		MockRequestDataSource{
+			headers: map[string][]string{
+				"X-Count": {"one"},
+			},
+			query: map[string][]string{
+				"b": {"two"},
+			},
+		})
+
+	err := v.ValidateRequest()
+	var validationFailure *ops.ValidationFailure
+	assert.True(t, errors.As(err, &validationFailure))
+
+	var got []string
+	for _, violation := range validationFailure.Violations() {
+		got = append(got, violation.Pointer)
+	}
+	assert.Equal(t, []string{"/header/X-Count", "/query/b"}, got)
+}
+
+func TestRequestFieldPointer(t *testing.T) {
+	tests := []struct {
+		name  string
+		field *ops.PortField
+		want  string
+	}{
+		{
+			name:  "Body",
+			field: &ops.PortField{Group: FieldGroupHttpBody},
+			want:  "/body",
+		},
+		{
+			name:  "Query",
+			field: &ops.PortField{Group: FieldGroupHttpQuery, Peer: "pageSize"},
+			want:  "/query/pageSize",
+		},
+		{
+			name:  "Escaped",
+			field: &ops.PortField{Group: FieldGroupHttpForm, Peer: "a/b~c"},
+			want:  "/form/a~1b~0c",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RequestFieldPointer(tt.field))
+		})
+	}
+}
+
 func TestRequestValidator_GetFieldValue(t *testing.T) {
 	type testStruct struct {
 		Primitive string                  `req:"header"`
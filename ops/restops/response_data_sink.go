@@ -22,9 +22,19 @@ type ResponseDataSink interface {
 	WriteBodyEntity(entity interface{}) error
 }
 
+// StreamingResponseDataSink is a ResponseDataSink able to deliver a body incrementally.
+type StreamingResponseDataSink interface {
+	ResponseDataSink
+	ContentType() string
+	// WriteBodyChunk writes and flushes part of the body, sending the status and
+	// headers before the first chunk.
+	WriteBodyChunk(data []byte) error
+}
+
 type RestfulResponseDataSink struct {
 	Status   int
 	Response *restful.Response
+	started  bool
 }
 
 func (r *RestfulResponseDataSink) SetHeader(name string, value string) {
@@ -76,6 +86,27 @@ func (r *RestfulResponseDataSink) WriteBodyEntity(entity interface{}) error {
 	return r.WriteBody(buffer)
 }
 
+func (r *RestfulResponseDataSink) ContentType() string {
+	return r.Response.Header().Get(HeaderContentType)
+}
+
+func (r *RestfulResponseDataSink) WriteBodyChunk(data []byte) (err error) {
+	if !r.started {
+		if r.Status == 0 {
+			r.Status = http.StatusOK
+		}
+		r.Response.WriteHeader(r.Status)
+		r.started = true
+	}
+
+	if _, err = r.Response.Write(data); err != nil {
+		return err
+	}
+
+	r.Response.Flush()
+	return nil
+}
+
 func NewRestfulResponseDataSink(resp *restful.Response) *RestfulResponseDataSink {
 	return &RestfulResponseDataSink{
 		Response: resp,
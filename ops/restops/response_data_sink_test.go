@@ -184,3 +184,20 @@ func TestRestfulResponseDataSink_WriteBodyEntity(t *testing.T) {
 	assert.Equal(t, http.StatusOK, result.StatusCode)
 	assert.Equal(t, responseBodyBytes, resultBody)
 }
+
+func TestRestfulResponseDataSink_WriteBodyChunk(t *testing.T) {
+	resp := httptest.NewRecorder()
+	r := &RestfulResponseDataSink{
+		Status:   http.StatusAccepted,
+		Response: restful.NewResponse(resp),
+	}
+	r.Response.AddHeader(HeaderContentType, MediaTypeNdjson)
+
+	assert.NoError(t, r.WriteBodyChunk([]byte("{}\n")))
+	assert.True(t, resp.Flushed)
+	assert.NoError(t, r.WriteBodyChunk([]byte("[]\n")))
+
+	assert.Equal(t, MediaTypeNdjson, r.ContentType())
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.Equal(t, "{}\n[]\n", resp.Body.String())
+}
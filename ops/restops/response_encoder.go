@@ -6,9 +6,12 @@ package restops
 
 import (
 	"bytes"
+	"context"
 	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"github.com/pkg/errors"
 	"github.com/spf13/cast"
 	"io"
+	"mime"
 	"sort"
 	"strings"
 )
@@ -17,13 +20,16 @@ type ResponseEncoder interface {
 	EncodeHeaderPrimitive(name string, values types.Optional[string], style string, explode bool) (err error)
 	EncodeHeaderArray(name string, values []string, style string, explode bool) (err error)
 	EncodeHeaderObject(name string, value types.Pojo, style string, explode bool) (err error)
+	NegotiateMime(offers []string) (mime string, err error)
 	EncodeMime(mime string) (err error)
 	EncodeCode(code int) (err error)
 	EncodeBody(body interface{}) error
 }
 
 type EndpointResponseEncoder struct {
-	Sink ResponseDataSink
+	Sink    ResponseDataSink
+	Accept  string
+	Context context.Context
 }
 
 func (o EndpointResponseEncoder) EncodeHeaderPrimitive(name string, value types.Optional[string], _ string, _ bool) (err error) {
@@ -91,6 +97,13 @@ func (o EndpointResponseEncoder) EncodeCode(code int) (err error) {
 	return nil
 }
 
+// NegotiateMime selects the offered media type preferred by the request Accept header.
+// Returns a 406 status error when none of the offers are acceptable, even if only one
+// media type is offered.
+func (o EndpointResponseEncoder) NegotiateMime(offers []string) (mime string, err error) {
+	return NegotiateMediaType(o.Accept, offers)
+}
+
 func (o EndpointResponseEncoder) EncodeMime(mime string) (err error) {
 	switch mime {
 	case MediaTypeJson:
@@ -113,6 +126,8 @@ func (o EndpointResponseEncoder) EncodeBody(body interface{}) (err error) {
 		err = o.encodeBodyString(typedBody)
 	case types.TextMarshaler:
 		err = o.encodeBodyTextMarshaler(typedBody)
+	case Streamer:
+		err = o.encodeBodyStream(typedBody)
 	case nil:
 		err = o.encodeNoBody()
 	default:
@@ -148,6 +163,69 @@ func (o EndpointResponseEncoder) encodeBodyTextMarshaler(body types.TextMarshale
 	return o.encodeBodyString(bodyString)
 }
 
+func (o EndpointResponseEncoder) encodeBodyStream(stream Streamer) (err error) {
+	sink, ok := o.Sink.(StreamingResponseDataSink)
+	if !ok {
+		return errors.Wrapf(ErrStreamingNotSupported, "%T", o.Sink)
+	}
+
+	ctx := o.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(sink.ContentType())
+	ndjson := mediaType == MediaTypeNdjson
+
+	encodeItem := encodeServerSentEvent
+	if ndjson {
+		encodeItem = encodeNdjsonItem
+	} else {
+		sink.SetHeader(HeaderCacheControl, "no-cache")
+	}
+
+	// Send the headers before waiting for the first item
+	if err = sink.WriteBodyChunk(nil); err != nil {
+		return err
+	}
+
+	for {
+		item, more, err := stream.NextItem(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				logger.WithContext(ctx).Debug("Client disconnected from response stream")
+				return nil
+			}
+
+			// Headers have already been sent, so report the failure in-band
+			logger.WithContext(ctx).WithError(err).Error("Response stream failed")
+			if !ndjson {
+				data, _ := encodeServerSentEvent(ServerSentEvent{
+					Event: "error",
+					Data:  types.Pojo{"message": err.Error()},
+				})
+				_ = sink.WriteBodyChunk(data)
+			}
+			return nil
+		}
+
+		if !more {
+			return nil
+		}
+
+		data, err := encodeItem(item)
+		if err != nil {
+			logger.WithContext(ctx).WithError(err).Errorf("Failed to encode response stream item %T", item)
+			return nil
+		}
+
+		if err = sink.WriteBodyChunk(data); err != nil {
+			logger.WithContext(ctx).WithError(err).Debug("Failed to write response stream item")
+			return nil
+		}
+	}
+}
+
 func (o EndpointResponseEncoder) encodeNoBody() error {
 	return o.Sink.WriteBody(nil)
 }
@@ -157,3 +235,13 @@ func NewResponseEncoder(sink ResponseDataSink) ResponseEncoder {
 		Sink: sink,
 	}
 }
+
+// NewNegotiatingResponseEncoder returns an encoder negotiating response media types
+// using the supplied Accept header.  Streamed responses stop when the context is done.
+func NewNegotiatingResponseEncoder(ctx context.Context, sink ResponseDataSink, accept string) ResponseEncoder {
+	return &EndpointResponseEncoder{
+		Sink:    sink,
+		Accept:  accept,
+		Context: ctx,
+	}
+}
@@ -106,3 +106,55 @@ func TestEndpointResponseEncoder_EncodeBody_TextMarshaler(t *testing.T) {
 
 	assert.Equal(t, wantBodyBytes, gotBodyBytes)
 }
+
+func TestEndpointResponseEncoder_NegotiateMime(t *testing.T) {
+	tests := []struct {
+		name     string
+		accept   string
+		offers   []string
+		wantMime string
+		wantErr  error
+	}{
+		{
+			name:     "NoOffers",
+			accept:   MediaTypeXml,
+			wantMime: "",
+		},
+		{
+			name:     "SingleOffer",
+			accept:   MediaTypeJson,
+			offers:   []string{MediaTypeJson},
+			wantMime: MediaTypeJson,
+		},
+		{
+			name:     "SingleOfferNoAccept",
+			offers:   []string{MediaTypeJson},
+			wantMime: MediaTypeJson,
+		},
+		{
+			name:    "SingleOfferNotAcceptable",
+			accept:  MediaTypeXml,
+			offers:  []string{MediaTypeJson},
+			wantErr: ErrNotAcceptable,
+		},
+		{
+			name:     "MultipleOffers",
+			accept:   MediaTypeXml,
+			offers:   []string{MediaTypeJson, MediaTypeXml},
+			wantMime: MediaTypeXml,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoder := EndpointResponseEncoder{Accept: tt.accept}
+			mime, err := encoder.NegotiateMime(tt.offers)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMime, mime)
+		})
+	}
+}
@@ -14,12 +14,16 @@ import (
 	"github.com/spf13/cast"
 	"net/http"
 	"reflect"
+	"time"
 )
 
 type OutputsPopulator struct {
 	Endpoint *Endpoint
 	Outputs  *interface{}
 	Error    error
+	// SuccessMediaType is the success media type negotiated before the handler
+	// was called.  When empty, the success media type is negotiated on demand.
+	SuccessMediaType string
 
 	Observer  ResponseObserver
 	Encoder   ResponseEncoder
@@ -45,6 +49,23 @@ func (p *OutputsPopulator) PopulateOutputs() (err error) {
 		return err
 	}
 
+	// Evaluate content type
+	var mediaType string
+	if code != 204 {
+		mediaType, err = p.EvaluateMediaType(code)
+		if errors.Is(err, ErrNotAcceptable) && p.Error == nil {
+			// Respond with the error instead
+			p.Error = err
+			if code, err = p.EvaluateResponseCode(); err != nil {
+				return err
+			}
+			mediaType, err = p.EvaluateMediaType(code)
+		}
+		if err != nil {
+			return errors.Wrap(err, "Failed to generate media type")
+		}
+	}
+
 	// Notify observers on exit
 	defer p.notifyObserver(code)
 
@@ -59,13 +80,8 @@ func (p *OutputsPopulator) PopulateOutputs() (err error) {
 		return errors.Wrap(err, "Failed to set response headers")
 	}
 
-	// Evaluate content type
+	// Encode content type
 	if code != 204 {
-		var mediaType string
-		mediaType, err = p.EvaluateMediaType(code)
-		if err != nil {
-			return errors.Wrap(err, "Failed to generate media type")
-		}
 		err = p.Encoder.EncodeMime(mediaType)
 		if err != nil {
 			return errors.Wrap(err, "Failed to set media type")
@@ -294,6 +310,13 @@ func (p *OutputsPopulator) PopulateHeaders() (err error) {
 		headerPortFields = p.Endpoint.Response.Port.Fields.All(PortFieldIsSuccessHeader)
 	}
 
+	// Set entity version headers from outputs
+	if p.Error == nil {
+		if err = p.PopulateVersionHeaders(); err != nil {
+			return err
+		}
+	}
+
 	// Set headers from outputs
 	for _, headerPortField := range headerPortFields {
 		extractor := p.extractor(headerPortField)
@@ -339,12 +362,79 @@ func (p *OutputsPopulator) PopulateHeaders() (err error) {
 	return nil
 }
 
+// PopulateVersionHeaders sets the ETag and Last-Modified headers from the
+// entity version outputs.
+func (p *OutputsPopulator) PopulateVersionHeaders() (err error) {
+	if versionPortField := p.Endpoint.Response.Port.Fields.First(PortFieldIsVersion); versionPortField != nil {
+		var version types.Optional[string]
+		if version, err = p.extractor(versionPortField).ExtractPrimitive(); err != nil {
+			return err
+		}
+
+		if version.IsPresent() && version.Value() != "" {
+			weak, _ := versionPortField.BoolOption("weak")
+			weak = weak || p.Endpoint.EntityTags == EntityTagWeak
+			tag := NewEntityTag(version.Value(), weak)
+			if err = p.Encoder.EncodeHeaderPrimitive(HeaderETag, types.OptionalOf(tag.String()), "", false); err != nil {
+				return errors.Wrapf(err, "Failed to encode header %q", HeaderETag)
+			}
+		}
+	}
+
+	if modifiedPortField := p.Endpoint.Response.Port.Fields.First(PortFieldIsModified); modifiedPortField != nil {
+		var value reflect.Value
+		if value, err = p.extractor(modifiedPortField).ExtractValue(); err != nil {
+			return err
+		}
+
+		if modified := timeFromValue(value); !modified.IsZero() {
+			header := modified.UTC().Format(http.TimeFormat)
+			if err = p.Encoder.EncodeHeaderPrimitive(HeaderLastModified, types.OptionalOf(header), "", false); err != nil {
+				return errors.Wrapf(err, "Failed to encode header %q", HeaderLastModified)
+			}
+		}
+	}
+
+	return nil
+}
+
+func timeFromValue(value reflect.Value) time.Time {
+	if !value.IsValid() {
+		return time.Time{}
+	}
+
+	switch v := value.Interface().(type) {
+	case time.Time:
+		return v
+	case *time.Time:
+		if v != nil {
+			return *v
+		}
+	case types.Time:
+		return v.ToTimeTime()
+	case *types.Time:
+		if v != nil {
+			return v.ToTimeTime()
+		}
+	}
+
+	return time.Time{}
+}
+
 func (p *OutputsPopulator) EvaluateMediaType(code int) (mediaType string, err error) {
 	if p.Endpoint.Response.Envelope {
 		return MediaTypeJson, nil
 	} else if code <= 399 {
-		return p.Endpoint.Response.Success.Mime, nil
-	} else {
+		if p.SuccessMediaType != "" {
+			return p.SuccessMediaType, nil
+		}
+		return p.Encoder.NegotiateMime(p.Endpoint.Response.Success.MediaTypes())
+	}
+
+	// Errors are always returned, falling back to the default media type
+	mediaType, err = p.Encoder.NegotiateMime(p.Endpoint.Response.Error.MediaTypes())
+	if errors.Is(err, ErrNotAcceptable) {
 		return p.Endpoint.Response.Error.Mime, nil
 	}
+	return
 }
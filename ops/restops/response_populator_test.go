@@ -14,10 +14,12 @@ import (
 	"cto-github.cisco.com/NFV-BU/go-msx/webservice"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"io"
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestOutputsPopulator_PopulateOutputs(t *testing.T) {
@@ -607,3 +609,188 @@ func TestOutputsPopulator_EvaluateHeaders(t *testing.T) {
 		})
 	}
 }
+
+func TestOutputsPopulator_EvaluateMediaType(t *testing.T) {
+	type outputs struct {
+		Body map[string]string `resp:"body"`
+	}
+
+	endpoint := types.May[*Endpoint](NewEndpoint(http.MethodGet, "a").
+		WithHandler(func() {}).
+		WithOutputs(outputs{}).
+		WithCodecs(MediaTypeYaml, MediaTypeCbor).
+		Build())
+
+	tests := []struct {
+		name       string
+		accept     string
+		negotiated string
+		code       int
+		want       string
+		wantErr    error
+	}{
+		{
+			name: "NoAccept",
+			code: 200,
+			want: MediaTypeJson,
+		},
+		{
+			name:   "Wildcard",
+			accept: "*/*",
+			code:   200,
+			want:   MediaTypeJson,
+		},
+		{
+			name:   "Preferred",
+			accept: "application/json;q=0.5, application/yaml",
+			code:   200,
+			want:   MediaTypeYaml,
+		},
+		{
+			name:    "NotAcceptable",
+			accept:  "text/html",
+			code:    200,
+			wantErr: ErrNotAcceptable,
+		},
+		{
+			name:   "ErrorFallback",
+			accept: "text/html",
+			code:   400,
+			want:   MediaTypeJson,
+		},
+		{
+			name:       "Negotiated",
+			accept:     "application/yaml",
+			negotiated: MediaTypeCbor,
+			code:       200,
+			want:       MediaTypeCbor,
+		},
+		{
+			name:       "NegotiatedError",
+			accept:     "application/yaml",
+			negotiated: MediaTypeCbor,
+			code:       400,
+			want:       MediaTypeYaml,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &OutputsPopulator{
+				Endpoint:         endpoint,
+				SuccessMediaType: tt.negotiated,
+				Encoder: EndpointResponseEncoder{
+					Sink:   NewHttpResponseDataSink(&http.Response{Header: make(http.Header)}),
+					Accept: tt.accept,
+				},
+			}
+
+			got, err := p.EvaluateMediaType(tt.code)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestOutputsPopulator_PopulateOutputs_NotAcceptable(t *testing.T) {
+	type outputs struct {
+		Body map[string]string `resp:"body"`
+	}
+
+	observer := new(MockResponseObserver)
+	observer.On("Error", http.StatusNotAcceptable, mock.Anything).Return()
+
+	r := &http.Response{Header: make(http.Header)}
+	p := &OutputsPopulator{
+		Endpoint: types.May[*Endpoint](NewEndpoint(http.MethodGet, "a").
+			WithHandler(func() {}).
+			WithOutputs(outputs{}).
+			WithCodecs(MediaTypeYaml).
+			Build()),
+		Outputs:  types.OptionalOf[interface{}](outputs{}).ValueInterfacePtr(),
+		Observer: observer,
+		Encoder: EndpointResponseEncoder{
+			Sink:   NewHttpResponseDataSink(r),
+			Accept: "text/html",
+		},
+		Describer: RestfulRequestDescriber{},
+	}
+
+	err := p.PopulateOutputs()
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotAcceptable, r.StatusCode)
+	assert.Equal(t, ContentTypeJson, r.Header.Get(HeaderContentType))
+	assert.ErrorIs(t, p.Error, ErrNotAcceptable)
+	observer.AssertExpectations(t)
+}
+
+func TestOutputsPopulator_PopulateOutputs_Version(t *testing.T) {
+	type outputs struct {
+		Version  string            `resp:"version"`
+		Modified types.Time        `resp:"modified"`
+		Body     map[string]string `resp:"body"`
+	}
+
+	modified := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		mode         EntityTagMode
+		outputs      outputs
+		wantETag     string
+		wantModified string
+	}{
+		{
+			name: "Strong",
+			outputs: outputs{
+				Version:  "42",
+				Modified: types.NewTime(modified),
+			},
+			wantETag:     NewEntityTag("42", false).String(),
+			wantModified: "Mon, 02 Jan 2023 03:04:05 GMT",
+		},
+		{
+			name: "Weak",
+			mode: EntityTagWeak,
+			outputs: outputs{
+				Version: "42",
+			},
+			wantETag: NewEntityTag("42", true).String(),
+		},
+		{
+			name: "Empty",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			observer := new(MockResponseObserver)
+			observer.On("Success", http.StatusOK).Return()
+
+			r := &http.Response{Header: make(http.Header)}
+			p := &OutputsPopulator{
+				Endpoint: types.May[*Endpoint](NewEndpoint(http.MethodGet, "a").
+					WithHandler(func() {}).
+					WithOutputs(outputs{}).
+					WithEntityTags(tt.mode).
+					Build()),
+				Outputs:  types.OptionalOf[interface{}](tt.outputs).ValueInterfacePtr(),
+				Observer: observer,
+				Encoder: EndpointResponseEncoder{
+					Sink: NewHttpResponseDataSink(r),
+				},
+				Describer: RestfulRequestDescriber{},
+			}
+
+			err := p.PopulateOutputs()
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, r.StatusCode)
+			assert.Equal(t, tt.wantETag, r.Header.Get(HeaderETag))
+			assert.Equal(t, tt.wantModified, r.Header.Get(HeaderLastModified))
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package restops
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var ErrStreamingNotSupported = errors.New("Response sink does not support streaming")
+
+// StreamIterator returns the next item of a stream.  When the stream is exhausted,
+// ok is false.
+type StreamIterator[I any] func(ctx context.Context) (item I, ok bool, err error)
+
+// Streamer is a response body delivered incrementally.  Each item is encoded and
+// flushed to the client as it becomes available.
+type Streamer interface {
+	StreamItemType() reflect.Type
+	NextItem(ctx context.Context) (item any, ok bool, err error)
+}
+
+var streamerType = reflect.TypeOf((*Streamer)(nil)).Elem()
+
+// Stream is an output port body type delivering a sequence of items, using either
+// Server-Sent Events or newline-delimited JSON depending on the negotiated media type.
+//
+//	type exportDevicesResponse struct {
+//	    Body restops.Stream[api.DeviceResponse] `resp:"body"`
+//	}
+type Stream[I any] struct {
+	items    <-chan I
+	iterator StreamIterator[I]
+}
+
+func (s Stream[I]) StreamItemType() reflect.Type {
+	return reflect.TypeOf((*I)(nil)).Elem()
+}
+
+func (s Stream[I]) NextItem(ctx context.Context) (item any, ok bool, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	switch {
+	case s.items != nil:
+		select {
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		case item, ok = <-s.items:
+			return
+		}
+
+	case s.iterator != nil:
+		return s.iterator(ctx)
+
+	default:
+		return nil, false, nil
+	}
+}
+
+// NewChannelStream returns a Stream delivering items received from the channel
+// until it is closed.
+func NewChannelStream[I any](items <-chan I) Stream[I] {
+	return Stream[I]{items: items}
+}
+
+// NewIteratorStream returns a Stream delivering items returned by the iterator
+// until it is exhausted or fails.
+func NewIteratorStream[I any](iterator StreamIterator[I]) Stream[I] {
+	return Stream[I]{iterator: iterator}
+}
+
+// ServerSentEvent may be used as a Stream item to control the event fields.  When
+// streamed as NDJSON, only the Data is written.
+type ServerSentEvent struct {
+	Id    string
+	Event string
+	Retry int
+	Data  any
+}
+
+func serverSentEventFromItem(item any) ServerSentEvent {
+	switch typedItem := item.(type) {
+	case ServerSentEvent:
+		return typedItem
+	case *ServerSentEvent:
+		return *typedItem
+	default:
+		return ServerSentEvent{Data: item}
+	}
+}
+
+func encodeServerSentEvent(item any) ([]byte, error) {
+	event := serverSentEventFromItem(item)
+
+	var data string
+	switch typedData := event.Data.(type) {
+	case string:
+		data = typedData
+	default:
+		bytesData, err := json.Marshal(typedData)
+		if err != nil {
+			return nil, err
+		}
+		data = string(bytesData)
+	}
+
+	var buffer bytes.Buffer
+	if event.Id != "" {
+		buffer.WriteString("id: " + event.Id + "\n")
+	}
+	if event.Event != "" {
+		buffer.WriteString("event: " + event.Event + "\n")
+	}
+	if event.Retry > 0 {
+		buffer.WriteString("retry: " + strconv.Itoa(event.Retry) + "\n")
+	}
+	for _, line := range strings.Split(data, "\n") {
+		buffer.WriteString("data: " + line + "\n")
+	}
+	buffer.WriteString("\n")
+	return buffer.Bytes(), nil
+}
+
+func encodeNdjsonItem(item any) ([]byte, error) {
+	data, err := json.Marshal(serverSentEventFromItem(item).Data)
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,148 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package restops
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"github.com/emicklei/go-restful"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type streamItem struct {
+	Name string `json:"name"`
+}
+
+func newTestIteratorStream(items []streamItem, err error) Stream[streamItem] {
+	var i int
+	return NewIteratorStream(func(ctx context.Context) (item streamItem, ok bool, e error) {
+		if i < len(items) {
+			item, ok = items[i], true
+			i++
+			return
+		}
+		return item, false, err
+	})
+}
+
+func TestEndpointResponseEncoder_EncodeBody_Stream(t *testing.T) {
+	items := []streamItem{{Name: "alpha"}, {Name: "beta"}}
+
+	tests := []struct {
+		name     string
+		mime     string
+		stream   func() Streamer
+		wantBody string
+	}{
+		{
+			name: "ServerSentEvents",
+			mime: MediaTypeEventStream,
+			stream: func() Streamer {
+				ch := make(chan streamItem, len(items))
+				for _, item := range items {
+					ch <- item
+				}
+				close(ch)
+				return NewChannelStream(ch)
+			},
+			wantBody: "data: {\"name\":\"alpha\"}\n\ndata: {\"name\":\"beta\"}\n\n",
+		},
+		{
+			name: "Ndjson",
+			mime: MediaTypeNdjson,
+			stream: func() Streamer {
+				return newTestIteratorStream(items, nil)
+			},
+			wantBody: "{\"name\":\"alpha\"}\n{\"name\":\"beta\"}\n",
+		},
+		{
+			name: "EventFields",
+			mime: MediaTypeEventStream,
+			stream: func() Streamer {
+				ch := make(chan ServerSentEvent, 1)
+				ch <- ServerSentEvent{Id: "1", Event: "progress", Retry: 500, Data: "50%\ncomplete"}
+				close(ch)
+				return NewChannelStream(ch)
+			},
+			wantBody: "id: 1\nevent: progress\nretry: 500\ndata: 50%\ndata: complete\n\n",
+		},
+		{
+			name: "Failure",
+			mime: MediaTypeEventStream,
+			stream: func() Streamer {
+				return newTestIteratorStream(items[:1], errors.New("Export failed"))
+			},
+			wantBody: "data: {\"name\":\"alpha\"}\n\nevent: error\ndata: {\"message\":\"Export failed\"}\n\n",
+		},
+		{
+			name: "FailureNdjson",
+			mime: MediaTypeNdjson,
+			stream: func() Streamer {
+				return newTestIteratorStream(items[:1], errors.New("Export failed"))
+			},
+			wantBody: "{\"name\":\"alpha\"}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := httptest.NewRecorder()
+			encoder := EndpointResponseEncoder{
+				Sink: NewRestfulResponseDataSink(restful.NewResponse(resp)),
+			}
+
+			assert.NoError(t, encoder.EncodeMime(tt.mime))
+			assert.NoError(t, encoder.EncodeBody(tt.stream()))
+			assert.Equal(t, http.StatusOK, resp.Code)
+			assert.True(t, resp.Flushed)
+			assert.Equal(t, tt.wantBody, resp.Body.String())
+		})
+	}
+}
+
+func TestEndpointResponseEncoder_EncodeBody_StreamCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan streamItem)
+	go func() {
+		ch <- streamItem{Name: "alpha"}
+		cancel()
+	}()
+
+	resp := httptest.NewRecorder()
+	encoder := NewNegotiatingResponseEncoder(ctx, NewRestfulResponseDataSink(restful.NewResponse(resp)), "")
+
+	assert.NoError(t, encoder.EncodeMime(MediaTypeNdjson))
+	assert.NoError(t, encoder.EncodeBody(NewChannelStream(ch)))
+	assert.Equal(t, "{\"name\":\"alpha\"}\n", resp.Body.String())
+}
+
+func TestEndpointResponseEncoder_EncodeBody_StreamNotSupported(t *testing.T) {
+	encoder := EndpointResponseEncoder{
+		Sink: &MockResponseDataSink{headers: make(http.Header)},
+	}
+
+	err := encoder.EncodeBody(NewChannelStream(make(chan streamItem)))
+	assert.ErrorIs(t, err, ErrStreamingNotSupported)
+}
+
+func TestEndpointResponse_WithOutputs_Stream(t *testing.T) {
+	e := types.May[*Endpoint](NewEndpoint(http.MethodGet, "a").
+		WithHandler(func() {}).
+		WithOutputs(struct {
+			Body Stream[*streamItem] `resp:"body"`
+		}{}).
+		Build())
+
+	success := e.Response.Success
+	assert.True(t, success.Stream)
+	assert.Equal(t, []string{MediaTypeEventStream, MediaTypeNdjson}, success.MediaTypes())
+	assert.Equal(t, reflect.TypeOf(streamItem{}), reflect.TypeOf(success.Payload.Value()))
+	assert.Equal(t, []string{MediaTypeEventStream, MediaTypeNdjson, MediaTypeJson}, e.Response.Produces())
+}
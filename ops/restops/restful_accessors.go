@@ -18,6 +18,7 @@ const (
 	AttributeKeyOutputs                 = "Outputs"
 	AttributeKeyEndpointRequestDecoder  = "EndpointRequestDecoder"
 	AttributeKeyEndpointResponseEncoder = "EndpointResponseEncoder"
+	AttributeKeySuccessMediaType        = "SuccessMediaType"
 )
 
 // Route
@@ -81,3 +82,13 @@ func RequestWithEndpointResponseEncoder(request *restful.Request, encoder Respon
 func EndpointResponseEncoderFromRequest(request *restful.Request) ResponseEncoder {
 	return request.Attribute(AttributeKeyEndpointResponseEncoder).(ResponseEncoder)
 }
+
+func RequestWithSuccessMediaType(request *restful.Request, mediaType string) *restful.Request {
+	request.SetAttribute(AttributeKeySuccessMediaType, mediaType)
+	return request
+}
+
+func SuccessMediaTypeFromRequest(request *restful.Request) string {
+	mediaType, _ := request.Attribute(AttributeKeySuccessMediaType).(string)
+	return mediaType
+}
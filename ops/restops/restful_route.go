@@ -92,6 +92,9 @@ func InjectEndpointContextFilter(injectors types.ContextInjectors) restful.Filte
 func InjectEndpointRequestDecoder(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
 	dataSource := NewRestfulRequestDataSource(request)
 	decoder := NewRequestDecoder(dataSource)
+	if e, ok := request.Attribute(AttributeKeyEndpoint).(*Endpoint); ok && e.Request.HasBody() {
+		decoder = NewEndpointRequestDecoder(dataSource, e.Request.Consumes())
+	}
 	request = RequestWithEndpointRequestDecoder(request, decoder)
 	chain.ProcessFilter(request, response)
 }
@@ -99,7 +102,10 @@ func InjectEndpointRequestDecoder(request *restful.Request, response *restful.Re
 // InjectEndpointResponseEncoder supplies the response data sink
 func InjectEndpointResponseEncoder(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
 	dataSink := NewRestfulResponseDataSink(response)
-	encoder := NewResponseEncoder(dataSink)
+	encoder := NewNegotiatingResponseEncoder(
+		request.Request.Context(),
+		dataSink,
+		request.HeaderParameter(HeaderAccept))
 	request = RequestWithEndpointResponseEncoder(request, encoder)
 	chain.ProcessFilter(request, response)
 }
@@ -121,6 +127,18 @@ type RequestPopulator interface {
 	PopulateInputs(endpoint Endpoint) (interface{}, error)
 }
 
+// EndpointConditionalFilter answers conditional safe requests with 304 Not Modified
+func EndpointConditionalFilter(e *Endpoint) restful.FilterFunction {
+	return func(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
+		if !e.IsConditionalRead() || e.Response.Success.Stream {
+			chain.ProcessFilter(request, response)
+			return
+		}
+
+		EndpointMiddlewaresFilter(Middlewares{ConditionalMiddleware(e.EntityTags)})(request, response, chain)
+	}
+}
+
 // EndpointRequestFilter validates and injects the inputs into the request
 func EndpointRequestFilter(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
 	var err error
@@ -134,13 +152,27 @@ func EndpointRequestFilter(request *restful.Request, response *restful.Response,
 	// Retrieve the endpoint
 	e := EndpointFromRequest(request)
 
+	// Refuse unacceptable requests before the handler is called
+	var mediaType string
+	if mediaType, err = NegotiateSuccessMediaType(e, EndpointResponseEncoderFromRequest(request)); err != nil {
+		webservice.RequestWithError(request, err)
+		return
+	}
+	request = RequestWithSuccessMediaType(request, mediaType)
+
 	// Retrieve the decoder
 	decoder := EndpointRequestDecoderFromRequest(request)
 
+	// Apply any patch document to the current resource
+	if decoder, err = NewPatchRequestDecoder(request.Request.Context(), e, decoder); err != nil {
+		return
+	}
+	request = RequestWithEndpointRequestDecoder(request, decoder)
+
 	// Validate request according to the endpoint port schemas
 	validator := NewRequestValidator(e.Request.Port, decoder)
 	if err = validator.ValidateRequest(); err != nil {
-		webservice.RequestWithError(request, webservice.NewBadRequestError(err))
+		webservice.RequestWithError(request, requestError(err))
 		return
 	}
 
@@ -148,7 +180,7 @@ func EndpointRequestFilter(request *restful.Request, response *restful.Response,
 	var inputs interface{}
 	populator := ops.NewInputsPopulator(e.Request.Port, decoder)
 	if inputs, err = populator.PopulateInputs(); err != nil {
-		webservice.RequestWithError(request, webservice.NewBadRequestError(err))
+		webservice.RequestWithError(request, requestError(err))
 		return
 	}
 
@@ -167,6 +199,28 @@ func EndpointRequestFilter(request *restful.Request, response *restful.Response,
 	chain.ProcessFilter(request, response)
 }
 
+// NegotiateSuccessMediaType selects the success response media type accepted by
+// the request.  Unmanaged endpoints write their own responses, and are not negotiated.
+func NegotiateSuccessMediaType(e *Endpoint, encoder ResponseEncoder) (string, error) {
+	if e.Unmanaged {
+		return "", nil
+	} else if e.Response.Envelope {
+		return MediaTypeJson, nil
+	}
+
+	return encoder.NegotiateMime(e.Response.Success.MediaTypes())
+}
+
+// requestError retains the status of errors such as unsupported media types,
+// and otherwise reports a bad request.
+func requestError(err error) error {
+	var statusCodeError StatusCodeError
+	if errors.As(err, &statusCodeError) {
+		return statusCodeError
+	}
+	return webservice.NewBadRequestError(err)
+}
+
 // EndpointResponseFilter validates and extracts the outputs into the response
 func EndpointResponseFilter(request *restful.Request, response *restful.Response, chain *restful.FilterChain) {
 	var err error
@@ -227,9 +281,10 @@ func EndpointResponseFilter(request *restful.Request, response *restful.Response
 
 	// Create a new populator
 	populator := OutputsPopulator{
-		Endpoint: e,
-		Outputs:  &outputs,
-		Error:    responseError,
+		Endpoint:         e,
+		Outputs:          &outputs,
+		Error:            responseError,
+		SuccessMediaType: SuccessMediaTypeFromRequest(request),
 
 		Observer:  observer,
 		Encoder:   encoder,
@@ -257,7 +312,18 @@ func EndpointController(endpoint *Endpoint) (fn restful.RouteFunction) {
 		ctx = types.ContextWithHandlerContext(ctx, handlerContext)
 		request.Request.WithContext(ctx)
 
-		err := endpoint.Handler.Call(ctx)
+		// Evaluate preconditions before applying unsafe requests
+		ctx, err := CheckPreconditions(ctx, endpoint, request.Request, InputsFromRequest(request))
+		if err != nil {
+			webservice.RequestWithError(request, err)
+			return
+		}
+
+		if endpoint.Async != nil {
+			err = callAsync(ctx, endpoint, request)
+		} else {
+			err = endpoint.Handler.Call(ctx)
+		}
 		if err != nil {
 			webservice.RequestWithError(request, err)
 		}
@@ -391,6 +457,7 @@ func RouteBuilderFromEndpoint(svc *restful.WebService, e *Endpoint) *restful.Rou
 		Do(RouteBuilderRequestBodyFromEndpoint(e)).
 		Do(RouteBuilderResponsesFromEndpoint(e)).
 		Filter(EndpointMiddlewaresFilter(e.Middleware)).
+		Filter(EndpointConditionalFilter(e)).
 		Filter(InjectEndpointRequestDecoder).
 		Filter(InjectEndpointResponseEncoder).
 		Filter(EndpointResponseFilter).
@@ -443,6 +510,19 @@ func (i *EndpointHandlerTypesAnalyzer) hasErrorReturnValue() bool {
 	return false
 }
 
+// writesResponse returns true if the handler accepts an argument for writing the response.
+func (i *EndpointHandlerTypesAnalyzer) writesResponse() bool {
+	handlerFuncType := reflect.ValueOf(i.handlerFunc).Type()
+	for n := 0; n < handlerFuncType.NumIn(); n++ {
+		switch handlerFuncType.In(n) {
+		case restfulResponsePointerType, httpResponseWriterType, endpointResponseEncoderType:
+			return true
+		}
+	}
+
+	return false
+}
+
 func (i *EndpointHandlerTypesAnalyzer) ArgsTypeSet() types.TypeSet {
 	ts := types.NewTypeSet(
 		restfulRequestPointerType,
@@ -7,11 +7,14 @@ package restops
 import (
 	"context"
 	"cto-github.cisco.com/NFV-BU/go-msx/ops"
+	"cto-github.cisco.com/NFV-BU/go-msx/schema/js"
 	"cto-github.cisco.com/NFV-BU/go-msx/testhelpers/webservicetest"
 	"cto-github.cisco.com/NFV-BU/go-msx/types"
 	"cto-github.cisco.com/NFV-BU/go-msx/webservice"
 	"github.com/emicklei/go-restful"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 	"net/http"
 	"testing"
 )
@@ -226,3 +229,176 @@ func TestEndpointHandler_Endpoint(t *testing.T) {
 
 	assert.True(t, called)
 }
+
+func TestEndpointRequestFilter_Negotiation(t *testing.T) {
+	type outputs struct {
+		Body map[string]string `resp:"body"`
+	}
+
+	tests := []struct {
+		name       string
+		accept     string
+		wantStatus int
+		wantType   string
+		wantCalled bool
+	}{
+		{
+			name:       "Acceptable",
+			accept:     MediaTypeCbor,
+			wantStatus: http.StatusOK,
+			wantType:   MediaTypeCbor,
+			wantCalled: true,
+		},
+		{
+			// Acceptable for errors only
+			name:       "NotAcceptable",
+			accept:     MediaTypeJson,
+			wantStatus: http.StatusNotAcceptable,
+			wantType:   ContentTypeJson,
+			wantCalled: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			e, err := NewEndpoint("POST", "/api/v1/entity").
+				WithOperationId("createEntity").
+				WithOutputs(outputs{}).
+				WithHandler(func() (outputs, error) {
+					called = true
+					return outputs{}, nil
+				}).
+				Build()
+			assert.NoError(t, err)
+
+			e.Response.Success.Mime = MediaTypeYaml
+			e.Response.Success.Mimes = []string{MediaTypeCbor}
+
+			new(webservicetest.RouteBuilderTest).
+				WithRequestMethod("POST").
+				WithRequestHeader(HeaderAccept, tt.accept).
+				WithRouteBuilderDo(RouteBuilderResponsesFromEndpoint(e)).
+				WithRouteFilter(InjectRequestEndpointFilter(e)).
+				WithRouteFilter(InjectEndpointRequestDecoder).
+				WithRouteFilter(InjectEndpointResponseEncoder).
+				WithRouteFilter(EndpointResponseFilter).
+				WithRouteFilter(EndpointRequestFilter).
+				WithRouteTarget(EndpointController(e)).
+				WithResponsePredicate(webservicetest.ResponseHasStatus(tt.wantStatus)).
+				WithResponsePredicate(webservicetest.ResponseHasHeader(HeaderContentType, tt.wantType)).
+				Test(t)
+
+			assert.Equal(t, tt.wantCalled, called)
+		})
+	}
+}
+
+func TestEndpointRequestFilter_Negotiation_SingleOffer(t *testing.T) {
+	type outputs struct {
+		Body map[string]string `resp:"body"`
+	}
+
+	tests := []struct {
+		name       string
+		accept     string
+		wantStatus int
+		wantCalled bool
+	}{
+		{
+			name:       "Acceptable",
+			accept:     MediaTypeJson,
+			wantStatus: http.StatusOK,
+			wantCalled: true,
+		},
+		{
+			name:       "Any",
+			accept:     "*/*",
+			wantStatus: http.StatusOK,
+			wantCalled: true,
+		},
+		{
+			name:       "NotAcceptable",
+			accept:     MediaTypeXml,
+			wantStatus: http.StatusNotAcceptable,
+			wantCalled: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			e, err := NewEndpoint("POST", "/api/v1/entity").
+				WithOperationId("createEntity").
+				WithOutputs(outputs{}).
+				WithHandler(func() (outputs, error) {
+					called = true
+					return outputs{}, nil
+				}).
+				Build()
+			assert.NoError(t, err)
+
+			e.Response.Success.Mime = MediaTypeJson
+			e.Response.Success.Mimes = nil
+
+			new(webservicetest.RouteBuilderTest).
+				WithRequestMethod("POST").
+				WithRequestHeader(HeaderAccept, tt.accept).
+				WithRouteBuilderDo(RouteBuilderResponsesFromEndpoint(e)).
+				WithRouteFilter(InjectRequestEndpointFilter(e)).
+				WithRouteFilter(InjectEndpointRequestDecoder).
+				WithRouteFilter(InjectEndpointResponseEncoder).
+				WithRouteFilter(EndpointResponseFilter).
+				WithRouteFilter(EndpointRequestFilter).
+				WithRouteTarget(EndpointController(e)).
+				WithResponsePredicate(webservicetest.ResponseHasStatus(tt.wantStatus)).
+				Test(t)
+
+			assert.Equal(t, tt.wantCalled, called)
+		})
+	}
+}
+
+func TestEndpointController_Protobuf(t *testing.T) {
+	type inputs struct {
+		Body *wrapperspb.StringValue `req:"body"`
+	}
+
+	type outputs struct {
+		Body *wrapperspb.StringValue `resp:"body"`
+	}
+
+	RegisterPortFieldValidationSchemaFunc(func(field *ops.PortField) (js.ValidationSchema, error) {
+		return js.ValidationSchema{}, nil
+	})
+
+	e, err := NewEndpoint("POST", "/api/v1/entity").
+		WithOperationId("createEntity").
+		WithCodecs(MediaTypeProtobuf).
+		WithHandler(func(inp *inputs) (out outputs, err error) {
+			out.Body = wrapperspb.String(inp.Body.GetValue() + "-reply")
+			return
+		}).
+		Build()
+	assert.NoError(t, err)
+
+	body, err := proto.Marshal(wrapperspb.String("request"))
+	assert.NoError(t, err)
+
+	new(webservicetest.RouteBuilderTest).
+		WithRequestMethod("POST").
+		WithRequestHeader(HeaderContentType, MediaTypeProtobuf).
+		WithRequestHeader(HeaderAccept, MediaTypeProtobuf).
+		WithRequestBody(body).
+		WithRouteBuilderDo(RouteBuilderResponsesFromEndpoint(e)).
+		WithRouteFilter(InjectRequestEndpointFilter(e)).
+		WithRouteFilter(InjectEndpointRequestDecoder).
+		WithRouteFilter(InjectEndpointResponseEncoder).
+		WithRouteFilter(EndpointResponseFilter).
+		WithRouteFilter(EndpointRequestFilter).
+		WithRouteTarget(EndpointController(e)).
+		WithResponsePredicate(webservicetest.ResponseHasStatus(http.StatusOK)).
+		WithResponsePredicate(webservicetest.ResponseHasHeader(HeaderContentType, MediaTypeProtobuf)).
+		WithResponsePredicate(webservicetest.ResponseHasBodySubstring("request-reply")).
+		Test(t)
+}
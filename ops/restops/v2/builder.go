@@ -24,6 +24,7 @@ const (
 	EndpointArchetypeRetrieve = "retrieve"
 	EndpointArchetypeCreate   = "create"
 	EndpointArchetypeUpdate   = "update"
+	EndpointArchetypePatch    = "patch"
 	EndpointArchetypeDelete   = "delete"
 	EndpointArchetypeCommand  = "command"
 )
@@ -33,6 +34,7 @@ var archetypes = map[string]EndpointArchetype{
 	EndpointArchetypeRetrieve: {Verb: http.MethodGet, Codes: restops.GetResponseCodes},
 	EndpointArchetypeCreate:   {Verb: http.MethodPost, Codes: restops.CreateResponseCodes},
 	EndpointArchetypeUpdate:   {Verb: http.MethodPut, Codes: restops.UpdateResponseCodes},
+	EndpointArchetypePatch:    {Verb: http.MethodPatch, Codes: restops.UpdateResponseCodes},
 	EndpointArchetypeDelete:   {Verb: http.MethodDelete, Codes: restops.DeleteResponseCodes},
 	EndpointArchetypeCommand:  {Verb: http.MethodPost, Codes: restops.GetResponseCodes},
 }
@@ -48,6 +50,7 @@ type EndpointBuilder struct {
 	Outputs types.Optional[interface{}]
 
 	Handler interface{}
+	Patch   restops.PatchSourceFunc
 }
 
 func (b *EndpointBuilder) WithId(operationId string) *EndpointBuilder {
@@ -106,6 +109,11 @@ func (b *EndpointBuilder) WithHandler(handler interface{}) *EndpointBuilder {
 	return b
 }
 
+func (b *EndpointBuilder) WithPatch(source restops.PatchSourceFunc) *EndpointBuilder {
+	b.Patch = source
+	return b
+}
+
 func (b *EndpointBuilder) Build() (e *restops.Endpoint, err error) {
 	arch, ok := archetypes[b.Archetype]
 	if !ok {
@@ -127,6 +135,10 @@ func (b *EndpointBuilder) Build() (e *restops.Endpoint, err error) {
 		e.WithOutputs(b.Outputs.ValueInterface())
 	}
 
+	if b.Patch != nil {
+		e.WithPatch(b.Patch)
+	}
+
 	e, err = e.Build()
 	if err != nil {
 		return
@@ -161,6 +173,10 @@ func NewUpdateEndpointBuilder(pathParts ...string) *EndpointBuilder {
 	return NewEndpointBuilder(EndpointArchetypeUpdate, pathParts...)
 }
 
+func NewPatchEndpointBuilder(pathParts ...string) *EndpointBuilder {
+	return NewEndpointBuilder(EndpointArchetypePatch, pathParts...)
+}
+
 func NewDeleteEndpointBuilder(pathParts ...string) *EndpointBuilder {
 	return NewEndpointBuilder(EndpointArchetypeDelete, pathParts...)
 }
@@ -24,6 +24,7 @@ const (
 	EndpointArchetypeRetrieve     = "retrieve"
 	EndpointArchetypeCreate       = "create"
 	EndpointArchetypeUpdate       = "update"
+	EndpointArchetypePatch        = "patch"
 	EndpointArchetypeDelete       = "delete"
 	EndpointArchetypeCommand      = "command"
 	EndpointArchetypeAsyncCommand = "asyncCommand"
@@ -34,6 +35,7 @@ var archetypes = map[string]EndpointArchetype{
 	EndpointArchetypeRetrieve:     {Verb: http.MethodGet, Codes: restops.GetResponseCodes},
 	EndpointArchetypeCreate:       {Verb: http.MethodPost, Codes: restops.CreateResponseCodes},
 	EndpointArchetypeUpdate:       {Verb: http.MethodPut, Codes: restops.UpdateResponseCodes},
+	EndpointArchetypePatch:        {Verb: http.MethodPatch, Codes: restops.UpdateResponseCodes},
 	EndpointArchetypeDelete:       {Verb: http.MethodDelete, Codes: restops.NoContentResponseCodes},
 	EndpointArchetypeCommand:      {Verb: http.MethodPost, Codes: restops.GetResponseCodes},
 	EndpointArchetypeAsyncCommand: {Verb: http.MethodPost, Codes: restops.AcceptResponseCodes},
@@ -49,7 +51,10 @@ type EndpointBuilder struct {
 	Inputs  types.Optional[interface{}]
 	Outputs types.Optional[interface{}]
 
-	Handler interface{}
+	Handler        interface{}
+	Patch          restops.PatchSourceFunc
+	ProblemDetails bool
+	Async          restops.AsyncExecutor
 }
 
 func (b *EndpointBuilder) WithId(operationId string) *EndpointBuilder {
@@ -108,6 +113,23 @@ func (b *EndpointBuilder) WithHandler(handler interface{}) *EndpointBuilder {
 	return b
 }
 
+func (b *EndpointBuilder) WithPatch(source restops.PatchSourceFunc) *EndpointBuilder {
+	b.Patch = source
+	return b
+}
+
+// WithProblemDetails returns errors as RFC 7807 problem details instead of the v8 Error payload.
+func (b *EndpointBuilder) WithProblemDetails() *EndpointBuilder {
+	b.ProblemDetails = true
+	return b
+}
+
+// WithAsync executes the handler using the supplied executor, responding with 202 Accepted.
+func (b *EndpointBuilder) WithAsync(executor restops.AsyncExecutor) *EndpointBuilder {
+	b.Async = executor
+	return b
+}
+
 func (b *EndpointBuilder) Build() (*restops.Endpoint, error) {
 	arch, ok := archetypes[b.Archetype]
 	if !ok {
@@ -130,6 +152,18 @@ func (b *EndpointBuilder) Build() (*restops.Endpoint, error) {
 		e.WithOutputs(b.Outputs.ValueInterface())
 	}
 
+	if b.Patch != nil {
+		e.WithPatch(b.Patch)
+	}
+
+	if b.ProblemDetails {
+		e.WithProblemDetails()
+	}
+
+	if b.Async != nil {
+		e.WithAsync(b.Async)
+	}
+
 	return e.Build()
 }
 
@@ -157,6 +191,10 @@ func NewUpdateEndpointBuilder(pathParts ...string) *EndpointBuilder {
 	return NewEndpointBuilder(EndpointArchetypeUpdate, pathParts...)
 }
 
+func NewPatchEndpointBuilder(pathParts ...string) *EndpointBuilder {
+	return NewEndpointBuilder(EndpointArchetypePatch, pathParts...)
+}
+
 func NewDeleteEndpointBuilder(pathParts ...string) *EndpointBuilder {
 	return NewEndpointBuilder(EndpointArchetypeDelete, pathParts...)
 }
@@ -22,6 +22,8 @@ type ChannelSubscriber struct {
 	dispatcher       stream.Dispatcher
 	dispatchTable    map[stream.MetadataHeader]stream.ListenerAction
 	dispatchTableMtx sync.RWMutex
+	partitionKeys    map[stream.MetadataHeader]stream.PartitionKeyFunc
+	partitionKey     stream.PartitionKeyFunc
 	documentors      ops.Documentors[ChannelSubscriber]
 }
 
@@ -46,6 +48,8 @@ func (p *ChannelSubscriber) AddMessageConsumer(mc MessageConsumer) (err error) {
 	p.dispatchTableMtx.Lock()
 	defer p.dispatchTableMtx.Unlock()
 
+	keyer, _ := mc.(stream.PartitionKeyer)
+
 	if p.dispatchHeader.IsPresent() {
 		var dispatchValues []string
 		dispatchValues, err = mc.MetadataFilterValues(p.dispatchHeader.Value())
@@ -55,6 +59,12 @@ func (p *ChannelSubscriber) AddMessageConsumer(mc MessageConsumer) (err error) {
 
 		for _, v := range dispatchValues {
 			p.dispatchTable[stream.MetadataHeader(v)] = mc.OnMessage
+			if keyer != nil {
+				if p.partitionKeys == nil {
+					p.partitionKeys = make(map[stream.MetadataHeader]stream.PartitionKeyFunc)
+				}
+				p.partitionKeys[stream.MetadataHeader(v)] = keyer.PartitionKey
+			}
 		}
 
 		p.dispatcher, err = stream.NewMetadataDispatcherIndirect(p.dispatchHeader.Value(), p.lookupListenerAction)
@@ -65,6 +75,9 @@ func (p *ChannelSubscriber) AddMessageConsumer(mc MessageConsumer) (err error) {
 		return errors.New("Cannot register multiple message consumers on the same channel without setting a dispatch header")
 	} else {
 		p.dispatcher = messageConsumerDispatcher{messageConsumer: mc}
+		if keyer != nil {
+			p.partitionKey = keyer.PartitionKey
+		}
 	}
 
 	return nil
@@ -77,6 +90,22 @@ func (p *ChannelSubscriber) lookupListenerAction(value string) stream.ListenerAc
 	return p.dispatchTable[stream.MetadataHeader(value)]
 }
 
+// PartitionKey returns the partition key of the message from the message consumer
+// it will be dispatched to.
+func (p *ChannelSubscriber) PartitionKey(msg *message.Message) (string, error) {
+	p.dispatchTableMtx.RLock()
+	partitionKey := p.partitionKey
+	if p.dispatchHeader.IsPresent() {
+		partitionKey = p.partitionKeys[stream.MetadataHeader(msg.Metadata.Get(p.dispatchHeader.Value()))]
+	}
+	p.dispatchTableMtx.RUnlock()
+
+	if partitionKey == nil {
+		return "", nil
+	}
+	return partitionKey(msg)
+}
+
 func (p *ChannelSubscriber) OnMessage(msg *message.Message) error {
 	if p.dispatcher == nil {
 		return errors.Errorf("No consumers registered for channel %q", p.name)
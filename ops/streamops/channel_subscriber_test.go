@@ -230,6 +230,97 @@ func TestChannelSubscriber_OnMessage(t *testing.T) {
 	}
 }
 
+func TestChannelSubscriber_PartitionKey(t *testing.T) {
+	ctx := configtest.ContextWithNewInMemoryConfig(context.Background(), map[string]string{
+		"spring.application.name": "TestChannelSubscriber",
+	})
+	channel, err := NewChannel(ctx, "MY_TOPIC")
+	assert.NoError(t, err)
+
+	newMessage := func(number string) *message.Message {
+		msg := message.NewMessage(types.MustNewUUID().String(), []byte(`{"id":"payload-key"}`))
+		msg.Metadata.Set("number", number)
+		msg.Metadata.Set("deviceId", "metadata-key")
+		return msg
+	}
+
+	tests := []struct {
+		name           string
+		dispatchHeader types.Optional[string]
+		msg            *message.Message
+		want           string
+	}{
+		{
+			name:           "SingleSubscriber",
+			dispatchHeader: types.OptionalEmpty[string](),
+			msg:            newMessage("one"),
+			want:           "metadata-key",
+		},
+		{
+			name:           "MultiHeaderSubscriberMetadata",
+			dispatchHeader: types.OptionalOf("number"),
+			msg:            newMessage("one"),
+			want:           "metadata-key",
+		},
+		{
+			name:           "MultiHeaderSubscriberPayload",
+			dispatchHeader: types.OptionalOf("number"),
+			msg:            newMessage("two"),
+			want:           "payload-key",
+		},
+		{
+			name:           "MultiHeaderSubscriberUnordered",
+			dispatchHeader: types.OptionalOf("number"),
+			msg:            newMessage("three"),
+			want:           "",
+		},
+		{
+			name:           "MultiHeaderSubscriberUnknown",
+			dispatchHeader: types.OptionalOf("number"),
+			msg:            newMessage("four"),
+			want:           "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs, err := NewChannelSubscriber(ctx, channel, "channel-subscriber", tt.dispatchHeader)
+			assert.NoError(t, err)
+
+			builder, err := NewMessageSubscriberBuilder(ctx, cs, "message-subscriber-1")
+			assert.NoError(t, err)
+			_, err = builder.
+				WithMetadataFilterValues("number", "one").
+				WithPartitionKeyMetadata("deviceId").
+				WithHandler(func() {}).
+				Build()
+			assert.NoError(t, err)
+
+			if tt.dispatchHeader.IsPresent() {
+				builder, err = NewMessageSubscriberBuilder(ctx, cs, "message-subscriber-2")
+				assert.NoError(t, err)
+				_, err = builder.
+					WithMetadataFilterValues("number", "two").
+					WithPartitionKeyPayload("id").
+					WithHandler(func() {}).
+					Build()
+				assert.NoError(t, err)
+
+				builder, err = NewMessageSubscriberBuilder(ctx, cs, "message-subscriber-3")
+				assert.NoError(t, err)
+				_, err = builder.
+					WithMetadataFilterValues("number", "three").
+					WithHandler(func() {}).
+					Build()
+				assert.NoError(t, err)
+			}
+
+			got, err := cs.PartitionKey(tt.msg)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestChannelSubscriber_Documentor(t *testing.T) {
 	ctx := configtest.ContextWithNewInMemoryConfig(context.Background(), map[string]string{
 		"spring.application.name": "TestChannelSubscriber",
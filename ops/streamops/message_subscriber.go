@@ -26,6 +26,7 @@ type MessageSubscriberBuilder struct {
 	Filters              types.ActionFilters
 	Documentors          ops.Documentors[MessageSubscriber]
 	MetadataFilterValues map[string][]string
+	PartitionKey         stream.PartitionKeyFunc
 }
 
 func (o *MessageSubscriberBuilder) WithInputs(portStruct interface{}) *MessageSubscriberBuilder {
@@ -62,6 +63,23 @@ func (o *MessageSubscriberBuilder) WithMetadataFilterValues(headerName string, v
 	return o
 }
 
+// WithPartitionKey orders the processing of messages sharing a partition key
+// when the channel is consumed concurrently.
+func (o *MessageSubscriberBuilder) WithPartitionKey(fn stream.PartitionKeyFunc) *MessageSubscriberBuilder {
+	o.PartitionKey = fn
+	return o
+}
+
+// WithPartitionKeyMetadata uses the value of the specified header as the partition key.
+func (o *MessageSubscriberBuilder) WithPartitionKeyMetadata(headerName string) *MessageSubscriberBuilder {
+	return o.WithPartitionKey(stream.MetadataPartitionKey(headerName))
+}
+
+// WithPartitionKeyPayload uses the value of the specified payload field as the partition key.
+func (o *MessageSubscriberBuilder) WithPartitionKeyPayload(fieldPath string) *MessageSubscriberBuilder {
+	return o.WithPartitionKey(PayloadPartitionKey(o.ChannelSubscriber.Channel(), fieldPath))
+}
+
 var ErrMessageSubscriberBuildFailure = errors.New("Missing value for subscriber field")
 
 func (o *MessageSubscriberBuilder) Build() (ms *MessageSubscriber, err error) {
@@ -107,6 +125,7 @@ func (o *MessageSubscriberBuilder) Build() (ms *MessageSubscriber, err error) {
 		filters:              o.Filters,
 		documentors:          o.Documentors,
 		metadataFilterValues: o.MetadataFilterValues,
+		partitionKey:         o.PartitionKey,
 	}
 
 	if err = result.channelSubscriber.AddMessageConsumer(result); err != nil {
@@ -148,6 +167,7 @@ type MessageSubscriber struct {
 	filters              types.ActionFilters
 	documentors          ops.Documentors[MessageSubscriber]
 	metadataFilterValues map[string][]string
+	partitionKey         stream.PartitionKeyFunc
 }
 
 func (o MessageSubscriber) Name() string {
@@ -221,6 +241,15 @@ func (o *MessageSubscriber) MetadataFilterValues(headerName string) ([]string, e
 		o.name)
 }
 
+// PartitionKey returns the partition key of the message, or an empty string if
+// the subscriber does not order its messages.
+func (o MessageSubscriber) PartitionKey(msg *message.Message) (string, error) {
+	if o.partitionKey == nil {
+		return "", nil
+	}
+	return o.partitionKey(msg)
+}
+
 func (o MessageSubscriber) inputs(msg *message.Message) (result interface{}, err error) {
 	if o.inputPort == nil {
 		return
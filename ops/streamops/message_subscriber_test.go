@@ -8,6 +8,7 @@ import (
 	"context"
 	"cto-github.cisco.com/NFV-BU/go-msx/ops"
 	"cto-github.cisco.com/NFV-BU/go-msx/security/service"
+	"cto-github.cisco.com/NFV-BU/go-msx/stream"
 	"cto-github.cisco.com/NFV-BU/go-msx/testhelpers"
 	"cto-github.cisco.com/NFV-BU/go-msx/testhelpers/configtest"
 	"cto-github.cisco.com/NFV-BU/go-msx/types"
@@ -141,6 +142,37 @@ func TestMessageSubscriberBuilder_WithMetadataFilterValues(t *testing.T) {
 	assert.Equal(t, builder.MetadataFilterValues["eventType"], []string{"up", "down"})
 }
 
+func TestMessageSubscriberBuilder_WithPartitionKeyMetadata(t *testing.T) {
+	deps := NewTestMessageSubscriberBuilderDependencies(t)
+
+	builder, err := NewMessageSubscriberBuilder(deps.Ctx, deps.ChannelSubscriber, deps.Name)
+	assert.NoError(t, err)
+	builder.WithPartitionKeyMetadata("deviceId")
+
+	msg := message.NewMessage(types.MustNewUUID().String(), []byte{})
+	msg.Metadata.Set("deviceId", "device-1")
+
+	assert.NotNil(t, builder.PartitionKey)
+	key, err := builder.PartitionKey(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, "device-1", key)
+}
+
+func TestMessageSubscriberBuilder_WithPartitionKeyPayload(t *testing.T) {
+	deps := NewTestMessageSubscriberBuilderDependencies(t)
+
+	builder, err := NewMessageSubscriberBuilder(deps.Ctx, deps.ChannelSubscriber, deps.Name)
+	assert.NoError(t, err)
+	builder.WithPartitionKeyPayload("device.id")
+
+	msg := message.NewMessage(types.MustNewUUID().String(), []byte(`{"device":{"id":"device-1"}}`))
+
+	assert.NotNil(t, builder.PartitionKey)
+	key, err := builder.PartitionKey(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, "device-1", key)
+}
+
 func TestMessageSubscriberBuilder_Build(t *testing.T) {
 	deps := NewTestMessageSubscriberBuilderDependencies(t)
 
@@ -349,6 +381,45 @@ func TestMessageSubscriber_OnMessage(t *testing.T) {
 	assert.ErrorContains(t, err, "some error")
 }
 
+func TestMessageSubscriber_PartitionKey(t *testing.T) {
+	msg := message.NewMessage(types.MustNewUUID().String(), []byte{})
+	msg.Metadata.Set("deviceId", "device-1")
+
+	tests := []struct {
+		name         string
+		partitionKey stream.PartitionKeyFunc
+		want         string
+	}{
+		{
+			name: "Unordered",
+			want: "",
+		},
+		{
+			name:         "Metadata",
+			partitionKey: stream.MetadataPartitionKey("deviceId"),
+			want:         "device-1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := NewTestMessageSubscriberDependencies(t)
+
+			builder, err := NewMessageSubscriberBuilder(deps.Ctx, deps.ChannelSubscriber, deps.Name)
+			assert.NoError(t, err)
+
+			ms, err := builder.
+				WithHandler(deps.Handler).
+				WithPartitionKey(tt.partitionKey).
+				Build()
+			assert.NoError(t, err)
+
+			got, err := ms.PartitionKey(msg)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestRegisterMessageSubscriber(t *testing.T) {
 	deps := NewTestMessageSubscriberDependencies(t)
 
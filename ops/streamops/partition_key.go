@@ -0,0 +1,50 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package streamops
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/ops"
+	"cto-github.cisco.com/NFV-BU/go-msx/stream"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+	"strings"
+)
+
+// PayloadPartitionKey returns a PartitionKeyFunc reading the partition key from
+// a field of the message payload.  Nested fields are separated by periods,
+// for example "device.id".
+func PayloadPartitionKey(channel *Channel, fieldPath string) stream.PartitionKeyFunc {
+	fieldNames := strings.Split(fieldPath, ".")
+
+	return func(msg *message.Message) (string, error) {
+		decoder := NewMessageDecoder(
+			NewMessageDataSource(channel.Name(), msg),
+			channel.DefaultContentType(),
+			channel.DefaultContentEncoding())
+
+		content, err := decoder.DecodeContent(&ops.PortField{Group: FieldGroupStreamBody})
+		if err != nil {
+			return "", err
+		}
+
+		var value interface{}
+		if err = content.ReadEntity(&value); err != nil {
+			return "", errors.Wrap(err, "Failed to decode message payload")
+		}
+
+		for _, fieldName := range fieldNames {
+			fields, ok := value.(map[string]interface{})
+			if !ok {
+				return "", errors.Errorf("Partition key field %q not found in message payload", fieldPath)
+			}
+			if value, ok = fields[fieldName]; !ok {
+				return "", errors.Errorf("Partition key field %q not found in message payload", fieldPath)
+			}
+		}
+
+		return cast.ToStringE(value)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package streamops
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/testhelpers/configtest"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPayloadPartitionKey(t *testing.T) {
+	ctx := configtest.ContextWithNewInMemoryConfig(context.Background(), map[string]string{
+		"spring.application.name": "TestPayloadPartitionKey",
+	})
+	channel, err := NewChannel(ctx, "MY_TOPIC")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		fieldPath string
+		payload   string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "String",
+			fieldPath: "deviceId",
+			payload:   `{"deviceId":"device-1"}`,
+			want:      "device-1",
+		},
+		{
+			name:      "Number",
+			fieldPath: "tenant",
+			payload:   `{"tenant":42}`,
+			want:      "42",
+		},
+		{
+			name:      "Nested",
+			fieldPath: "device.id",
+			payload:   `{"device":{"id":"device-2"}}`,
+			want:      "device-2",
+		},
+		{
+			name:      "Missing",
+			fieldPath: "device.id",
+			payload:   `{"device":"device-3"}`,
+			wantErr:   true,
+		},
+		{
+			name:      "Invalid",
+			fieldPath: "deviceId",
+			payload:   `{`,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := message.NewMessage(types.MustNewUUID().String(), []byte(tt.payload))
+
+			got, err := PayloadPartitionKey(channel, tt.fieldPath)(msg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
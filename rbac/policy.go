@@ -0,0 +1,245 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package rbac
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"fmt"
+	"strings"
+)
+
+// Subject describes the caller being authorized.
+type Subject struct {
+	UserName    string
+	TenantId    types.UUID
+	Tenants     []types.UUID
+	Roles       []string
+	Permissions []string
+}
+
+func (s Subject) HasPermission(permission string) bool {
+	for _, p := range s.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Subject) HasTenant(tenantId types.UUID) bool {
+	if s.TenantId != nil && s.TenantId.Equals(tenantId) {
+		return true
+	}
+
+	for _, id := range s.Tenants {
+		if id.Equals(tenantId) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resource describes the target of an action.
+type Resource struct {
+	Type       string
+	Id         string
+	TenantId   types.UUID
+	Attributes map[string]interface{}
+}
+
+// AuthorizationRequest is evaluated by a policy to decide whether the subject may
+// perform the action on the resource.
+type AuthorizationRequest struct {
+	Subject  Subject
+	Action   string
+	Resource Resource
+}
+
+// Decision is the outcome of evaluating a policy, along with the reasons for it.
+type Decision struct {
+	Allowed bool
+	Reasons []string
+}
+
+func allow(format string, args ...interface{}) Decision {
+	return Decision{Allowed: true, Reasons: []string{fmt.Sprintf(format, args...)}}
+}
+
+func deny(format string, args ...interface{}) Decision {
+	return Decision{Allowed: false, Reasons: []string{fmt.Sprintf(format, args...)}}
+}
+
+func (d Decision) String() string {
+	result := "deny"
+	if d.Allowed {
+		result = "allow"
+	}
+	return fmt.Sprintf("%s (%s)", result, strings.Join(d.Reasons, "; "))
+}
+
+// Policy decides whether an authorization request is allowed.
+type Policy interface {
+	Evaluate(ctx context.Context, request AuthorizationRequest) (Decision, error)
+}
+
+type PolicyFunc func(ctx context.Context, request AuthorizationRequest) (Decision, error)
+
+func (f PolicyFunc) Evaluate(ctx context.Context, request AuthorizationRequest) (Decision, error) {
+	return f(ctx, request)
+}
+
+// Allow returns a policy which allows every request.
+func Allow() Policy {
+	return PolicyFunc(func(ctx context.Context, request AuthorizationRequest) (Decision, error) {
+		return allow("always allowed"), nil
+	})
+}
+
+// Deny returns a policy which denies every request.
+func Deny() Policy {
+	return PolicyFunc(func(ctx context.Context, request AuthorizationRequest) (Decision, error) {
+		return deny("always denied"), nil
+	})
+}
+
+// Permission returns a policy allowing subjects holding the permission.
+func Permission(permission string) Policy {
+	return PolicyFunc(func(ctx context.Context, request AuthorizationRequest) (Decision, error) {
+		if request.Subject.HasPermission(permission) {
+			return allow("has permission %s", permission), nil
+		}
+		return deny("missing permission %s", permission), nil
+	})
+}
+
+// AnyPermission returns a policy allowing subjects holding any of the permissions.
+func AnyPermission(permissions ...string) Policy {
+	var policies []Policy
+	for _, permission := range permissions {
+		policies = append(policies, Permission(permission))
+	}
+	return AnyOf(policies...)
+}
+
+// AllOf returns a policy allowing requests allowed by every policy.  Evaluation stops
+// at the first denial.
+func AllOf(policies ...Policy) Policy {
+	return PolicyFunc(func(ctx context.Context, request AuthorizationRequest) (Decision, error) {
+		result := Decision{Allowed: true}
+		for _, policy := range policies {
+			decision, err := policy.Evaluate(ctx, request)
+			if err != nil {
+				return Decision{}, err
+			}
+
+			if !decision.Allowed {
+				return decision, nil
+			}
+
+			result.Reasons = append(result.Reasons, decision.Reasons...)
+		}
+		return result, nil
+	})
+}
+
+// AnyOf returns a policy allowing requests allowed by at least one policy.  Evaluation
+// stops at the first approval.
+func AnyOf(policies ...Policy) Policy {
+	return PolicyFunc(func(ctx context.Context, request AuthorizationRequest) (Decision, error) {
+		result := Decision{Allowed: false}
+		for _, policy := range policies {
+			decision, err := policy.Evaluate(ctx, request)
+			if err != nil {
+				return Decision{}, err
+			}
+
+			if decision.Allowed {
+				return decision, nil
+			}
+
+			result.Reasons = append(result.Reasons, decision.Reasons...)
+		}
+
+		if len(policies) == 0 {
+			result.Reasons = []string{"no alternatives allowed"}
+		}
+		return result, nil
+	})
+}
+
+// Not returns a policy allowing requests denied by the supplied policy.
+func Not(policy Policy) Policy {
+	return PolicyFunc(func(ctx context.Context, request AuthorizationRequest) (Decision, error) {
+		decision, err := policy.Evaluate(ctx, request)
+		if err != nil {
+			return Decision{}, err
+		}
+
+		result := Decision{Allowed: !decision.Allowed}
+		for _, reason := range decision.Reasons {
+			result.Reasons = append(result.Reasons, "not: "+reason)
+		}
+		return result, nil
+	})
+}
+
+// ResourceTenant returns a policy allowing subjects with access to the resource tenant:
+// subjects with ACCESS_ALL_TENANTS, subjects assigned the tenant, and subjects assigned
+// an ancestor of the tenant according to the TenantHierarchyApi.
+func ResourceTenant() Policy {
+	return PolicyFunc(func(ctx context.Context, request AuthorizationRequest) (Decision, error) {
+		tenantId := request.Resource.TenantId
+		if tenantId == nil {
+			return deny("resource has no tenant"), nil
+		}
+
+		if request.Subject.HasPermission(PermissionAccessAllTenants) {
+			return allow("has permission %s", PermissionAccessAllTenants), nil
+		}
+
+		if request.Subject.HasTenant(tenantId) {
+			return allow("assigned tenant %s", tenantId), nil
+		}
+
+		tenantHierarchy, err := GetTenantHierarchyApi(ctx)
+		if err != nil {
+			return Decision{}, err
+		}
+
+		ancestors, err := tenantHierarchy.Ancestors(ctx, tenantId)
+		if err != nil {
+			return Decision{}, err
+		}
+
+		for _, ancestorId := range ancestors {
+			if request.Subject.HasTenant(ancestorId) {
+				return allow("assigned ancestor tenant %s of tenant %s", ancestorId, tenantId), nil
+			}
+		}
+
+		return deny("no access to tenant %s", tenantId), nil
+	})
+}
+
+// Attribute returns a policy allowing requests where the named resource attribute
+// has one of the specified values.  Values are compared using their string form.
+func Attribute(name string, values ...string) Policy {
+	return PolicyFunc(func(ctx context.Context, request AuthorizationRequest) (Decision, error) {
+		value, ok := request.Resource.Attributes[name]
+		if !ok {
+			return deny("resource attribute %s not set", name), nil
+		}
+
+		stringValue := fmt.Sprint(value)
+		for _, v := range values {
+			if v == stringValue {
+				return allow("resource attribute %s is %q", name, stringValue), nil
+			}
+		}
+
+		return deny("resource attribute %s is %q, expected one of %q", name, stringValue, values), nil
+	})
+}
@@ -0,0 +1,139 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package rbac
+
+import (
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidPolicy = errors.New("Invalid policy")
+
+// PolicySpec declares a policy in a policy document.  Exactly one field must be set.
+type PolicySpec struct {
+	AllOf      []PolicySpec   `json:"allOf,omitempty"`
+	AnyOf      []PolicySpec   `json:"anyOf,omitempty"`
+	Not        *PolicySpec    `json:"not,omitempty"`
+	Permission string         `json:"permission,omitempty"`
+	Tenant     bool           `json:"tenant,omitempty"`
+	Attribute  *AttributeSpec `json:"attribute,omitempty"`
+	Allow      *bool          `json:"allow,omitempty"`
+}
+
+type AttributeSpec struct {
+	Name   string   `json:"name"`
+	Equals []string `json:"equals"`
+}
+
+func (s PolicySpec) Policy() (Policy, error) {
+	var policies []Policy
+	var count int
+
+	if s.AllOf != nil {
+		count++
+		children, err := specPolicies(s.AllOf)
+		if err != nil {
+			return nil, errors.Wrap(err, "allOf")
+		}
+		policies = append(policies, AllOf(children...))
+	}
+
+	if s.AnyOf != nil {
+		count++
+		children, err := specPolicies(s.AnyOf)
+		if err != nil {
+			return nil, errors.Wrap(err, "anyOf")
+		}
+		policies = append(policies, AnyOf(children...))
+	}
+
+	if s.Not != nil {
+		count++
+		child, err := s.Not.Policy()
+		if err != nil {
+			return nil, errors.Wrap(err, "not")
+		}
+		policies = append(policies, Not(child))
+	}
+
+	if s.Permission != "" {
+		count++
+		policies = append(policies, Permission(s.Permission))
+	}
+
+	if s.Tenant {
+		count++
+		policies = append(policies, ResourceTenant())
+	}
+
+	if s.Attribute != nil {
+		count++
+		if s.Attribute.Name == "" {
+			return nil, errors.Wrap(ErrInvalidPolicy, "attribute: name required")
+		}
+		policies = append(policies, Attribute(s.Attribute.Name, s.Attribute.Equals...))
+	}
+
+	if s.Allow != nil {
+		count++
+		if *s.Allow {
+			policies = append(policies, Allow())
+		} else {
+			policies = append(policies, Deny())
+		}
+	}
+
+	if count != 1 {
+		return nil, errors.Wrapf(ErrInvalidPolicy, "Expected exactly one rule, found %d", count)
+	}
+
+	return policies[0], nil
+}
+
+func specPolicies(specs []PolicySpec) ([]Policy, error) {
+	var results []Policy
+	for i, spec := range specs {
+		policy, err := spec.Policy()
+		if err != nil {
+			return nil, errors.Wrapf(err, "[%d]", i)
+		}
+		results = append(results, policy)
+	}
+	return results, nil
+}
+
+// PolicyDocument declares policies for a set of actions.
+type PolicyDocument struct {
+	Policies map[string]PolicySpec `json:"policies"`
+}
+
+// ParsePolicyDocument parses a YAML or JSON policy document, such as:
+//
+//	policies:
+//	  device:manage:
+//	    allOf:
+//	      - permission: MANAGE_DEVICE
+//	      - tenant: true
+//	      - not:
+//	          attribute:
+//	            name: locked
+//	            equals: ["true"]
+func ParsePolicyDocument(data []byte) (map[string]Policy, error) {
+	var document PolicyDocument
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, errors.Wrap(err, "Failed to parse policy document")
+	}
+
+	var results = make(map[string]Policy)
+	for action, spec := range document.Policies {
+		policy, err := spec.Policy()
+		if err != nil {
+			return nil, errors.Wrapf(err, "Policy %q", action)
+		}
+		results[action] = policy
+	}
+
+	return results, nil
+}
@@ -0,0 +1,253 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package rbac
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/audit/auditlog"
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
+	"cto-github.cisco.com/NFV-BU/go-msx/security"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"fmt"
+	"github.com/pkg/errors"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	configRootPolicy = "rbac.policy"
+
+	auditResourceAuthorization = "authorization"
+)
+
+var ErrAccessDenied = errors.New("Access denied")
+
+// PolicyConfig configures the default policy engine.
+type PolicyConfig struct {
+	AdminBypass bool     `config:"default=true"`
+	Documents   []string `config:"optional"`
+}
+
+// Engine evaluates authorization requests.  Alternative engines (e.g. delegating to
+// an external policy agent) can be installed using SetEngine.
+type Engine interface {
+	Decide(ctx context.Context, request AuthorizationRequest) (Decision, error)
+}
+
+// PolicyEngine evaluates authorization requests using the policy registered for the
+// requested action.  Requests for actions without a policy are denied.
+type PolicyEngine struct {
+	mtx         sync.RWMutex
+	policies    map[string]Policy
+	adminBypass bool
+}
+
+// Register sets the policy for the action, replacing any existing policy.
+func (e *PolicyEngine) Register(action string, policy Policy) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	e.policies[action] = policy
+}
+
+// SetAdminBypass controls whether subjects with IS_API_ADMIN are allowed all actions.
+func (e *PolicyEngine) SetAdminBypass(adminBypass bool) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	e.adminBypass = adminBypass
+}
+
+// LoadDocument registers each of the policies in a YAML or JSON policy document.
+func (e *PolicyEngine) LoadDocument(data []byte) error {
+	policies, err := ParsePolicyDocument(data)
+	if err != nil {
+		return err
+	}
+
+	for action, policy := range policies {
+		e.Register(action, policy)
+	}
+
+	return nil
+}
+
+func (e *PolicyEngine) Decide(ctx context.Context, request AuthorizationRequest) (Decision, error) {
+	e.mtx.RLock()
+	policy, ok := e.policies[request.Action]
+	adminBypass := e.adminBypass
+	e.mtx.RUnlock()
+
+	if adminBypass && request.Subject.HasPermission(PermissionIsApiAdmin) {
+		return allow("has permission %s", PermissionIsApiAdmin), nil
+	}
+
+	if !ok {
+		return deny("no policy for action %q", request.Action), nil
+	}
+
+	return policy.Evaluate(ctx, request)
+}
+
+func NewPolicyEngine() *PolicyEngine {
+	return &PolicyEngine{
+		policies:    make(map[string]Policy),
+		adminBypass: true,
+	}
+}
+
+var policyEngine = NewPolicyEngine()
+var engine Engine = policyEngine
+var engineMtx sync.RWMutex
+
+// DefaultPolicyEngine returns the built-in policy engine.
+func DefaultPolicyEngine() *PolicyEngine {
+	return policyEngine
+}
+
+// RegisterPolicy sets the policy for the action in the built-in policy engine.
+func RegisterPolicy(action string, policy Policy) {
+	policyEngine.Register(action, policy)
+}
+
+// SetEngine replaces the engine used by Authorize.
+func SetEngine(e Engine) {
+	if e == nil {
+		return
+	}
+
+	engineMtx.Lock()
+	defer engineMtx.Unlock()
+	engine = e
+}
+
+func currentEngine() Engine {
+	engineMtx.RLock()
+	defer engineMtx.RUnlock()
+	return engine
+}
+
+// DecisionError is returned when an authorization request is denied, and explains why.
+type DecisionError struct {
+	Action   string
+	Decision Decision
+}
+
+func (e *DecisionError) Error() string {
+	return fmt.Sprintf("%s for action %q: %s", ErrAccessDenied.Error(), e.Action, strings.Join(e.Decision.Reasons, "; "))
+}
+
+func (e *DecisionError) Is(target error) bool {
+	return target == ErrAccessDenied
+}
+
+// Unwrap exposes the reasons for the decision, which are included in error responses.
+func (e *DecisionError) Unwrap() error {
+	var reasons types.ErrorList
+	for _, reason := range e.Decision.Reasons {
+		reasons = append(reasons, errors.New(reason))
+	}
+	return reasons
+}
+
+// NewSubject creates a subject from the security context of the current user.
+func NewSubject(ctx context.Context) (Subject, error) {
+	userContextDetails, err := security.NewUserContextDetails(ctx)
+	if err != nil {
+		return Subject{}, err
+	}
+
+	subject := Subject{
+		TenantId:    userContextDetails.TenantId,
+		Tenants:     userContextDetails.Tenants,
+		Roles:       userContextDetails.Roles,
+		Permissions: userContextDetails.Permissions,
+	}
+
+	if userContext := security.UserContextFromContext(ctx); userContext != nil {
+		subject.UserName = userContext.UserName
+	}
+
+	return subject, nil
+}
+
+// Authorize evaluates whether the current user may perform the action on the resource.
+// Each decision is recorded in the audit log.  Returns a *DecisionError matching
+// ErrAccessDenied when the request is denied.
+func Authorize(ctx context.Context, action string, resource Resource) error {
+	subject, err := NewSubject(ctx)
+	if err != nil {
+		return err
+	}
+
+	return AuthorizeSubject(ctx, AuthorizationRequest{
+		Subject:  subject,
+		Action:   action,
+		Resource: resource,
+	})
+}
+
+// AuthorizeSubject evaluates an authorization request for an explicit subject.
+func AuthorizeSubject(ctx context.Context, request AuthorizationRequest) error {
+	decision, err := currentEngine().Decide(ctx, request)
+	if err != nil {
+		auditlog.Error(logger, ctx, auditResourceAuthorization, request.Action, err).
+			Error("Authorization failed")
+		return err
+	}
+
+	entry := auditlog.Entry(logger, ctx, auditResourceAuthorization, request.Action, auditlog.StateSuccess)
+	if !decision.Allowed {
+		entry = auditlog.Failure(logger, ctx, auditResourceAuthorization, request.Action)
+	}
+
+	entry = entry.
+		WithField("decision", decision.String()).
+		WithField("resourceType", request.Resource.Type).
+		WithField("resourceId", request.Resource.Id)
+	if request.Resource.TenantId != nil {
+		entry = entry.WithField("tenantId", request.Resource.TenantId.String())
+	}
+
+	if decision.Allowed {
+		entry.Info("Authorization allowed")
+		return nil
+	}
+
+	entry.Warn("Authorization denied")
+	return &DecisionError{
+		Action:   request.Action,
+		Decision: decision,
+	}
+}
+
+func init() {
+	config.RegisterSchema[PolicyConfig](configRootPolicy)
+}
+
+// ConfigurePolicyEngine applies the policy configuration to the built-in policy engine,
+// loading any configured policy documents.
+func ConfigurePolicyEngine(ctx context.Context) error {
+	var policyConfig PolicyConfig
+	if err := config.MustFromContext(ctx).Populate(&policyConfig, configRootPolicy); err != nil {
+		return err
+	}
+
+	policyEngine.SetAdminBypass(policyConfig.AdminBypass)
+
+	for _, fileName := range policyConfig.Documents {
+		data, err := os.ReadFile(fileName)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to read policy document %q", fileName)
+		}
+
+		if err = policyEngine.LoadDocument(data); err != nil {
+			return errors.Wrapf(err, "Failed to load policy document %q", fileName)
+		}
+
+		logger.WithContext(ctx).Infof("Loaded authorization policy document %q", fileName)
+	}
+
+	return nil
+}
@@ -0,0 +1,319 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package rbac
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestPolicy_Evaluate(t *testing.T) {
+	subject := Subject{
+		Permissions: []string{"VIEW_DEVICE", "MANAGE_DEVICE"},
+		Tenants:     []types.UUID{testRootTenantId},
+	}
+
+	resource := Resource{
+		Type:       "device",
+		TenantId:   testRootTenantId,
+		Attributes: map[string]interface{}{"locked": true, "kind": "router"},
+	}
+
+	tests := []struct {
+		name        string
+		policy      Policy
+		wantAllowed bool
+		wantReasons []string
+	}{
+		{
+			name:        "Allow",
+			policy:      Allow(),
+			wantAllowed: true,
+		},
+		{
+			name:   "Deny",
+			policy: Deny(),
+		},
+		{
+			name:        "Permission",
+			policy:      Permission("VIEW_DEVICE"),
+			wantAllowed: true,
+			wantReasons: []string{"has permission VIEW_DEVICE"},
+		},
+		{
+			name:        "PermissionMissing",
+			policy:      Permission("DELETE_DEVICE"),
+			wantReasons: []string{"missing permission DELETE_DEVICE"},
+		},
+		{
+			name:        "AnyPermission",
+			policy:      AnyPermission("DELETE_DEVICE", "MANAGE_DEVICE"),
+			wantAllowed: true,
+			wantReasons: []string{"has permission MANAGE_DEVICE"},
+		},
+		{
+			name:   "AllOf",
+			policy: AllOf(Permission("VIEW_DEVICE"), Permission("DELETE_DEVICE"), Permission("OTHER")),
+			wantReasons: []string{
+				"missing permission DELETE_DEVICE",
+			},
+		},
+		{
+			name:   "AnyOfDenied",
+			policy: AnyOf(Permission("DELETE_DEVICE"), Attribute("kind", "switch")),
+			wantReasons: []string{
+				"missing permission DELETE_DEVICE",
+				`resource attribute kind is "router", expected one of ["switch"]`,
+			},
+		},
+		{
+			name:        "AnyOfEmpty",
+			policy:      AnyOf(),
+			wantReasons: []string{"no alternatives allowed"},
+		},
+		{
+			name:        "Not",
+			policy:      Not(Attribute("locked", "true")),
+			wantReasons: []string{`not: resource attribute locked is "true"`},
+		},
+		{
+			name:        "AttributeMissing",
+			policy:      Attribute("owner", "me"),
+			wantReasons: []string{"resource attribute owner not set"},
+		},
+		{
+			name:        "ResourceTenant",
+			policy:      ResourceTenant(),
+			wantAllowed: true,
+			wantReasons: []string{"assigned tenant " + testRootTenantId.String()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, err := tt.policy.Evaluate(context.Background(), AuthorizationRequest{
+				Subject:  subject,
+				Action:   "device:manage",
+				Resource: resource,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantAllowed, decision.Allowed)
+			if tt.wantReasons != nil {
+				assert.Equal(t, tt.wantReasons, decision.Reasons)
+			}
+		})
+	}
+}
+
+func TestResourceTenant(t *testing.T) {
+	otherTenantId := types.MustParseUUID("215927cd-95b9-4e21-b29f-ef9bdaff9cbe")
+
+	tests := []struct {
+		name        string
+		subject     Subject
+		tenantId    types.UUID
+		wantAllowed bool
+	}{
+		{
+			name:     "NoTenant",
+			subject:  Subject{Tenants: []types.UUID{testRootTenantId}},
+			tenantId: nil,
+		},
+		{
+			name:        "AccessAllTenants",
+			subject:     Subject{Permissions: []string{PermissionAccessAllTenants}},
+			tenantId:    testChildTenantId,
+			wantAllowed: true,
+		},
+		{
+			name:        "Ancestor",
+			subject:     Subject{Tenants: []types.UUID{testRootTenantId}},
+			tenantId:    testChildTenantId,
+			wantAllowed: true,
+		},
+		{
+			name:     "Unrelated",
+			subject:  Subject{Tenants: []types.UUID{otherTenantId}},
+			tenantId: testChildTenantId,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := new(MockTenantHierarchyApi)
+			api.
+				On("Ancestors", mock.Anything, testChildTenantId).
+				Return([]types.UUID{testRootTenantId}, nil)
+			ctx := ContextWithTenantHierarchy(context.Background(), api)
+
+			decision, err := ResourceTenant().Evaluate(ctx, AuthorizationRequest{
+				Subject:  tt.subject,
+				Resource: Resource{TenantId: tt.tenantId},
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantAllowed, decision.Allowed)
+		})
+	}
+}
+
+func TestParsePolicyDocument(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		wantErr  bool
+	}{
+		{
+			name: "Valid",
+			document: `
+policies:
+  device:manage:
+    allOf:
+      - permission: MANAGE_DEVICE
+      - tenant: true
+      - not:
+          attribute:
+            name: locked
+            equals: ["true"]
+  device:view:
+    anyOf:
+      - permission: VIEW_DEVICE
+      - allow: false
+`,
+		},
+		{
+			name: "MultipleRules",
+			document: `
+policies:
+  device:manage:
+    permission: MANAGE_DEVICE
+    tenant: true
+`,
+			wantErr: true,
+		},
+		{
+			name: "NoRules",
+			document: `
+policies:
+  device:manage:
+    allOf:
+      - {}
+`,
+			wantErr: true,
+		},
+		{
+			name: "AttributeName",
+			document: `
+policies:
+  device:manage:
+    attribute:
+      equals: ["true"]
+`,
+			wantErr: true,
+		},
+		{
+			name:     "Malformed",
+			document: `policies: [`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policies, err := ParsePolicyDocument([]byte(tt.document))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, policies, 2)
+		})
+	}
+}
+
+func TestPolicyEngine_Decide(t *testing.T) {
+	engine := NewPolicyEngine()
+	assert.NoError(t, engine.LoadDocument([]byte(`
+policies:
+  device:manage:
+    permission: MANAGE_DEVICE
+`)))
+
+	tests := []struct {
+		name        string
+		adminBypass bool
+		action      string
+		permissions []string
+		wantAllowed bool
+	}{
+		{
+			name:        "Allowed",
+			action:      "device:manage",
+			permissions: []string{"MANAGE_DEVICE"},
+			wantAllowed: true,
+		},
+		{
+			name:        "Denied",
+			action:      "device:manage",
+			permissions: []string{"VIEW_DEVICE"},
+		},
+		{
+			name:        "NoPolicy",
+			action:      "device:delete",
+			permissions: []string{"MANAGE_DEVICE"},
+		},
+		{
+			name:        "AdminBypass",
+			adminBypass: true,
+			action:      "device:delete",
+			permissions: []string{PermissionIsApiAdmin},
+			wantAllowed: true,
+		},
+		{
+			name:        "AdminNoBypass",
+			action:      "device:delete",
+			permissions: []string{PermissionIsApiAdmin},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine.SetAdminBypass(tt.adminBypass)
+			decision, err := engine.Decide(context.Background(), AuthorizationRequest{
+				Subject: Subject{Permissions: tt.permissions},
+				Action:  tt.action,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantAllowed, decision.Allowed)
+			assert.NotEmpty(t, decision.Reasons)
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	ctx := context.Background()
+	setMockTokenDetailsProvider(ctx, nil, []string{"VIEW_DEVICE"})
+
+	RegisterPolicy("test:authorize", AllOf(Permission("VIEW_DEVICE"), Permission("MANAGE_DEVICE")))
+
+	err := Authorize(ctx, "test:authorize", Resource{Type: "device"})
+	assert.True(t, errors.Is(err, ErrAccessDenied))
+
+	var decisionError *DecisionError
+	assert.True(t, errors.As(err, &decisionError))
+	assert.Equal(t, "test:authorize", decisionError.Action)
+
+	var reasons types.ErrorList
+	assert.True(t, errors.As(err, &reasons))
+	assert.Len(t, reasons, 1)
+	assert.Equal(t, "missing permission MANAGE_DEVICE", reasons[0].Error())
+
+	RegisterPolicy("test:authorize", Permission("VIEW_DEVICE"))
+	assert.NoError(t, Authorize(ctx, "test:authorize", Resource{Type: "device"}))
+}
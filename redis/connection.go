@@ -42,6 +42,10 @@ func (c ConnectionConfig) Address() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
 
+func init() {
+	config.RegisterSchema[ConnectionConfig](configRootRedis)
+}
+
 // NewConnectionConfigFromConfig creates a new redis configuration using the specified configuration source.
 func NewConnectionConfigFromConfig(cfg *config.Config) (*ConnectionConfig, error) {
 	connectionConfig := &ConnectionConfig{}
@@ -52,16 +52,32 @@ func NewRetry(ctx context.Context, cfg RetryConfig) Retry {
 	}
 }
 
-func NewRetryConfigFromConfig(cfg *config.Config, root string) (*RetryConfig, error) {
-	var retryConfig RetryConfig
-	if err := cfg.Populate(&retryConfig, root); err != nil {
+// NewRetryConfigBinding returns the shared binding of the RetryConfig at the specified root.
+// The binding tracks configuration changes; use Binding.Current to retrieve the latest value.
+func NewRetryConfigBinding(cfg *config.Config, root string) (*config.Binding[RetryConfig], error) {
+	binding, err := config.Bind[RetryConfig](cfg, root)
+	if err != nil {
 		return nil, errors.Wrap(err, "Failed to populate default retry configuration")
 	}
 
+	return binding, nil
+}
+
+// NewRetryConfigFromConfig returns a copy of the RetryConfig currently bound at the specified root.
+// The copy is not updated by later configuration changes; components which retain their retry
+// settings should hold the binding returned by NewRetryConfigBinding instead.
+func NewRetryConfigFromConfig(cfg *config.Config, root string) (*RetryConfig, error) {
+	binding, err := NewRetryConfigBinding(cfg, root)
+	if err != nil {
+		return nil, err
+	}
+
+	retryConfig := *binding.Current()
 	return &retryConfig, nil
 }
 
-// NewRetryFromConfig returns a new Retry instance configured from the default RetryConfig in the specified *config.Config
+// NewRetryFromConfig returns a new Retry instance configured from the default RetryConfig in the specified *config.Config.
+// Each call uses the latest configuration values.
 func NewRetryFromConfig(ctx context.Context, cfg *config.Config) (*Retry, error) {
 	retryConfig, err := NewRetryConfigFromConfig(cfg, configRootRetry)
 	if err != nil {
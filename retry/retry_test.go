@@ -317,6 +317,30 @@ func TestNewRetryConfigFromConfig(t *testing.T) {
 	}
 }
 
+func TestNewRetryConfigBinding(t *testing.T) {
+	cfg := configtest.NewInMemoryConfig(map[string]string{
+		"some.random.spot.attempts": "7",
+	})
+
+	binding, err := NewRetryConfigBinding(cfg, "some.random.spot")
+	if err != nil {
+		t.Fatalf("NewRetryConfigBinding() error = %v", err)
+	}
+
+	if got := binding.Current().Attempts; got != 7 {
+		t.Errorf("NewRetryConfigBinding() Attempts = %v, want 7", got)
+	}
+
+	shared, err := config.Bind[RetryConfig](cfg, "some.random.spot")
+	if err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+
+	if shared != binding {
+		t.Errorf("NewRetryConfigBinding() did not return the shared binding")
+	}
+}
+
 func TestRetry_GetCurrentDelay_NoDelay(t *testing.T) {
 	clock := types.NewMockClock()
 	ctx := types.ContextWithClock(context.Background(), clock)
@@ -191,6 +191,10 @@ var defaultSecretConfig = SecretConfig{
 	},
 }
 
+func init() {
+	config.RegisterSchema[SecretConfig](configRootSanitizeSecret)
+}
+
 func NewSecretConfig(ctx context.Context) (*SecretConfig, error) {
 	var cfg SecretConfig
 	if err := config.FromContext(ctx).Populate(&cfg, configRootSanitizeSecret); err != nil {
@@ -55,6 +55,10 @@ type TasksConfig struct {
 	Tasks map[string]TaskConfig
 }
 
+func init() {
+	config.RegisterSchema[TasksConfig](configRootScheduled)
+}
+
 func newTasksConfig(ctx context.Context) (*TasksConfig, error) {
 	var cfg TasksConfig
 	if err := config.MustFromContext(ctx).Populate(&cfg, configRootScheduled); err != nil {
@@ -72,16 +72,9 @@ func FindRequiredJsonFields(valueType reflect.Type) []string {
 			r = structField.Type == reflect.TypeOf(types.UUID{})
 		}
 
-		jsonTag, ok := structField.Tag.Lookup("json")
-		name := ""
-		if ok {
-			res := strings.Split(jsonTag, ",")
-			overrideName := res[0]
-
-			if overrideName == "-" {
-				continue
-			}
-			name = overrideName
+		name, ok := jsonFieldName(structField)
+		if !ok {
+			continue
 		}
 
 		requiredTag, ok := structField.Tag.Lookup("required")
@@ -95,10 +88,6 @@ func FindRequiredJsonFields(valueType reflect.Type) []string {
 		}
 
 		if r {
-			if name == "" {
-				name = strcase.ToLowerCamel(structField.Name)
-			}
-
 			required = append(required, name)
 		}
 	}
@@ -106,6 +95,62 @@ func FindRequiredJsonFields(valueType reflect.Type) []string {
 	return required
 }
 
+// jsonFieldName returns the serialized name of a struct field, or false if the
+// field is excluded from serialization.
+func jsonFieldName(structField reflect.StructField) (string, bool) {
+	jsonTag, ok := structField.Tag.Lookup("json")
+	if ok {
+		res := strings.Split(jsonTag, ",")
+		overrideName := res[0]
+
+		if overrideName == "-" {
+			return "", false
+		}
+
+		if overrideName != "" {
+			return overrideName, true
+		}
+	}
+
+	return strcase.ToLowerCamel(structField.Name), true
+}
+
+// FindDependentRequiredJsonFields returns the dependentRequired constraints declared on
+// the fields of a struct.  Each field tagged with `dependentRequired` lists the
+// comma-separated names of the properties required whenever the field is present.
+func FindDependentRequiredJsonFields(valueType reflect.Type) map[string][]string {
+	valueType = refl.DeepIndirect(valueType)
+
+	if valueType.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var dependentRequired map[string][]string
+	for i := 0; i < valueType.NumField(); i++ {
+		structField := valueType.Field(i)
+
+		dependentTag, ok := structField.Tag.Lookup("dependentRequired")
+		if !ok || dependentTag == "" {
+			continue
+		}
+
+		name, ok := jsonFieldName(structField)
+		if !ok {
+			continue
+		}
+
+		if dependentRequired == nil {
+			dependentRequired = make(map[string][]string)
+		}
+
+		for _, dependent := range strings.Split(dependentTag, ",") {
+			dependentRequired[name] = append(dependentRequired[name], strings.TrimSpace(dependent))
+		}
+	}
+
+	return dependentRequired
+}
+
 func StructRequiredInterceptor() ReflectContextOptionFunc {
 	return jsonschema.InterceptType(func(value reflect.Value, schema *jsonschema.Schema) (bool, error) {
 		required := FindRequiredJsonFields(value.Type())
@@ -119,6 +164,19 @@ func StructRequiredInterceptor() ReflectContextOptionFunc {
 	})
 }
 
+func DependentRequiredInterceptor() ReflectContextOptionFunc {
+	return jsonschema.InterceptType(func(value reflect.Value, schema *jsonschema.Schema) (bool, error) {
+		dependentRequired := FindDependentRequiredJsonFields(value.Type())
+
+		if len(dependentRequired) > 0 {
+			schema.WithExtraPropertiesItem(KeywordDependentRequired, dependentRequired)
+		}
+
+		// Continue with type interceptor chain
+		return false, nil
+	})
+}
+
 func ExampleInterceptor() ReflectContextOptionFunc {
 	return jsonschema.InterceptType(func(value reflect.Value, schema *jsonschema.Schema) (bool, error) {
 		valueIface := value.Interface()
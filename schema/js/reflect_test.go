@@ -215,3 +215,67 @@ func TestExampleInterceptor(t *testing.T) {
 		})
 	}
 }
+
+func TestFindDependentRequiredJsonFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  map[string][]string
+	}{
+		{
+			name: "None",
+			value: struct {
+				A string
+			}{},
+			want: nil,
+		},
+		{
+			name: "Dependents",
+			value: struct {
+				A string `json:"alpha" dependentRequired:"b, c"`
+				B string
+				C string
+			}{},
+			want: map[string][]string{
+				"alpha": {"b", "c"},
+			},
+		},
+		{
+			name: "SkipField",
+			value: struct {
+				A string `json:"-" dependentRequired:"b"`
+				B string
+			}{},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FindDependentRequiredJsonFields(reflect.TypeOf(tt.value))
+			assert.True(t,
+				reflect.DeepEqual(tt.want, got),
+				testhelpers.Diff(tt.want, got))
+		})
+	}
+}
+
+func TestDependentRequiredInterceptor(t *testing.T) {
+	type dependentStruct struct {
+		A string `dependentRequired:"b"`
+		B string
+	}
+
+	rc := &jsonschema.ReflectContext{}
+	DependentRequiredInterceptor()(rc)
+
+	s := &jsonschema.Schema{}
+	cont, err := rc.InterceptType(reflect.ValueOf(dependentStruct{}), s)
+	assert.False(t, cont)
+	assert.NoError(t, err)
+
+	want := map[string][]string{"a": {"b"}}
+	got := s.ExtraProperties[KeywordDependentRequired]
+	assert.True(t,
+		reflect.DeepEqual(want, got),
+		testhelpers.Diff(want, got))
+}
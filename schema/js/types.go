@@ -15,6 +15,10 @@ const (
 	FormatBinary   = "binary"
 )
 
+const (
+	KeywordDependentRequired = "dependentRequired"
+)
+
 type DefNameExposer interface {
 	JSONSchemaDefName() string
 }
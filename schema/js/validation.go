@@ -12,6 +12,7 @@ import (
 	jsv "github.com/santhosh-tekuri/jsonschema/v5"
 	"github.com/swaggest/jsonschema-go"
 	"sync"
+	"time"
 )
 
 type ValidationSchema struct {
@@ -127,7 +128,68 @@ func (s ValidationSchema) Validate(value interface{}) error {
 }
 
 var validationCompilerMtx sync.Mutex
-var validationCompiler = jsv.NewCompiler()
+var validationCompiler = newValidationCompiler()
+
+func newValidationCompiler() *jsv.Compiler {
+	compiler := jsv.NewCompiler()
+	compiler.Draft = jsv.Draft2020
+	compiler.RegisterExtension(extensionValidationFormat, nil, validationFormatCompiler{})
+	return compiler
+}
+
+const extensionValidationFormat = "validationFormat"
+
+// validationFormatOverrides replaces the library format assertions for this compiler only.
+// Durations are serialized using Go duration syntax (e.g. "1h30m") rather than ISO 8601.
+var validationFormatOverrides = map[string]func(interface{}) bool{
+	FormatDuration: isDuration,
+}
+
+// validationFormatCompiler asserts the "format" keyword, which the library treats as an
+// annotation by default, without modifying the library-wide format table.
+type validationFormatCompiler struct{}
+
+func (validationFormatCompiler) Compile(_ jsv.CompilerContext, m map[string]interface{}) (jsv.ExtSchema, error) {
+	name, ok := m["format"].(string)
+	if !ok {
+		return nil, nil
+	}
+
+	format, ok := validationFormatOverrides[name]
+	if !ok {
+		format, ok = jsv.Formats[name]
+	}
+
+	if !ok {
+		// Unknown formats are annotations only
+		return nil, nil
+	}
+
+	return validationFormatSchema{name: name, format: format}, nil
+}
+
+type validationFormatSchema struct {
+	name   string
+	format func(interface{}) bool
+}
+
+func (s validationFormatSchema) Validate(ctx jsv.ValidationContext, v interface{}) error {
+	if s.format(v) {
+		return nil
+	}
+
+	return ctx.Error("format", "%v is not valid %q", v, s.name)
+}
+
+func isDuration(value interface{}) bool {
+	s, ok := value.(string)
+	if !ok {
+		return true
+	}
+
+	_, err := time.ParseDuration(s)
+	return err == nil
+}
 
 func NewValidationSchemaFromJsonSchema(schema *jsonschema.Schema) (vs ValidationSchema, err error) {
 	validationCompilerMtx.Lock()
@@ -115,3 +115,58 @@ func TestValidationSchema_Types(t *testing.T) {
 		})
 	}
 }
+
+func TestNewValidationSchemaFromJsonSchema_Format(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		value   interface{}
+		wantErr bool
+	}{
+		{
+			name:   "Email",
+			format: "email",
+			value:  "someone@example.com",
+		},
+		{
+			name:    "InvalidEmail",
+			format:  "email",
+			value:   "someone",
+			wantErr: true,
+		},
+		{
+			name:   "Duration",
+			format: js.FormatDuration,
+			value:  "1h30m",
+		},
+		{
+			name:    "IsoDuration",
+			format:  js.FormatDuration,
+			value:   "PT1H30M",
+			wantErr: true,
+		},
+		{
+			name:   "Unknown",
+			format: "int32",
+			value:  "anything",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vs, err := js.NewValidationSchemaFromJsonSchema(js.StringSchema().WithFormat(tt.format))
+			assert.NoError(t, err)
+
+			err = vs.Validate(tt.value)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestNewValidationSchemaFromJsonSchema_FormatIsolated(t *testing.T) {
+	_, err := js.NewValidationSchemaFromJsonSchema(js.StringSchema().WithFormat(js.FormatDuration))
+	assert.NoError(t, err)
+
+	// The library-wide format table retains ISO 8601 durations for other compilers
+	assert.True(t, jsv.Formats[js.FormatDuration]("PT1H30M"))
+	assert.False(t, jsv.Formats[js.FormatDuration]("1h30m"))
+}
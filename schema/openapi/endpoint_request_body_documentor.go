@@ -124,13 +124,21 @@ func (d *EndpointRequestBodyDocumentor) Document(b *restops.EndpointRequestBody)
 		examplePtr = &example
 	}
 
-	d.RequestBody.
-		WithRequired(b.Required).
-		WithContentItem(b.Mime, openapi3.MediaType{
+	d.RequestBody.WithRequired(b.Required)
+	for _, mime := range b.MediaTypes() {
+		if mime == restops.MediaTypeJsonPatch {
+			if err = d.documentJsonPatch(); err != nil {
+				return
+			}
+			continue
+		}
+
+		d.RequestBody.WithContentItem(mime, openapi3.MediaType{
 			Schema:  schemaOrRef,
 			Example: examplePtr,
 			//Encoding: b.Encoding,
 		})
+	}
 
 	if b.Description != "" {
 		d.RequestBody.WithDescription(b.Description)
@@ -143,6 +151,20 @@ func (d *EndpointRequestBodyDocumentor) Document(b *restops.EndpointRequestBody)
 	return nil
 }
 
+// documentJsonPatch describes a JSON Patch request body as a list of patch operations.
+func (d *EndpointRequestBodyDocumentor) documentJsonPatch() error {
+	schemaOrRef, err := Reflect([]restops.PatchOperation{})
+	if err != nil {
+		return err
+	}
+
+	d.RequestBody.WithContentItem(restops.MediaTypeJsonPatch, openapi3.MediaType{
+		Schema: schemaOrRef,
+	})
+
+	return nil
+}
+
 func (d *EndpointRequestBodyDocumentor) Result() *openapi3.RequestBody {
 	return d.RequestBody
 }
@@ -54,6 +54,36 @@ func TestEndpointRequestBodyDocumentor_Document(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Codecs",
+			doc:  new(EndpointRequestBodyDocumentor),
+			requestBody: restops.EndpointRequestBody{
+				Mime:     webservice.MIME_JSON,
+				Mimes:    []string{restops.MediaTypeYaml},
+				Payload:  types.OptionalOf[interface{}](""),
+				Required: true,
+			},
+			want: &openapi3.RequestBody{
+				Content: map[string]openapi3.MediaType{
+					webservice.MIME_JSON: {
+						Schema: func() *openapi3.SchemaOrRef {
+							sr := NewSchemaOrRefPtr(StringSchema())
+							sr.Schema.ReflectType = reflect.TypeOf("")
+							return sr
+						}(),
+					},
+					restops.MediaTypeYaml: {
+						Schema: func() *openapi3.SchemaOrRef {
+							sr := NewSchemaOrRefPtr(StringSchema())
+							sr.Schema.ReflectType = reflect.TypeOf("")
+							return sr
+						}(),
+					},
+				},
+				Required: types.NewBoolPtr(true),
+			},
+			wantErr: false,
+		},
 		{
 			name: "Mutator",
 			doc: new(EndpointRequestBodyDocumentor).WithMutator(
@@ -71,6 +71,17 @@ func (d *EndpointResponseDocumentor) Document(r *restops.EndpointResponse) error
 			*successResponseOrRef)
 	}
 
+	if d.Endpoint != nil && d.Endpoint.IsConditionalRead() {
+		notModifiedResponseOrRef, err := d.documentNotModifiedResponse(r.Success)
+		if err != nil {
+			return err
+		}
+
+		d.Responses.WithMapOfResponseOrRefValuesItem(
+			strconv.Itoa(http.StatusNotModified),
+			*notModifiedResponseOrRef)
+	}
+
 	for _, code := range r.Codes.Error {
 		errorResponseOrRef, err := d.documentResponseContent(r.Error, r.Envelope, code)
 		if err != nil {
@@ -325,6 +336,32 @@ func (d *EndpointResponseDocumentor) envelope(c restops.EndpointResponseContent,
 	return envelopeSchemaOrRef, &envelopeInstance, nil
 }
 
+// documentNotModifiedResponse documents the body-less response to a satisfied
+// conditional request, including the entity version headers.
+func (d *EndpointResponseDocumentor) documentNotModifiedResponse(c restops.EndpointResponseContent) (*openapi3.ResponseOrRef, error) {
+	var result = new(openapi3.Response)
+
+	result.Description = http.StatusText(http.StatusNotModified)
+
+	for _, name := range []string{restops.HeaderETag, restops.HeaderLastModified} {
+		header, ok := c.Headers[name]
+		if !ok {
+			continue
+		}
+
+		headerOrRef, err := d.documentHeader(header)
+		if err != nil {
+			return nil, errors.Wrap(err, name)
+		}
+
+		result.WithHeadersItem(name, *headerOrRef)
+	}
+
+	return &openapi3.ResponseOrRef{
+		Response: result,
+	}, nil
+}
+
 func (d *EndpointResponseDocumentor) documentHeaders(c restops.EndpointResponseContent, code int, response *openapi3.Response) error {
 	for name, header := range c.Headers {
 		headerOrRef, err := d.documentHeader(header)
@@ -373,19 +410,23 @@ func (d *EndpointResponseDocumentor) documentRawResponse(c restops.EndpointRespo
 	var result = new(openapi3.Response)
 
 	result.Description = http.StatusText(code)
+	if c.Stream {
+		// Streamed item schema is documented for each media type
+		result.Description += " - streamed response, with each event or line containing one item"
+	}
 
 	if err := d.documentHeaders(c, code, result); err != nil {
 		return nil, err
 	}
 
-	mime := c.Mime
+	mimes := c.MediaTypes()
 	payload := c.Payload
 	if !payload.IsPresent() {
 		if code >= 400 {
 			payload = types.OptionalOf[interface{}](new(webservice.ErrorV8))
 		}
-		if mime == "" {
-			mime = webservice.MIME_JSON
+		if len(mimes) == 0 {
+			mimes = []string{webservice.MIME_JSON}
 		}
 	}
 
@@ -399,12 +440,14 @@ func (d *EndpointResponseDocumentor) documentRawResponse(c restops.EndpointRespo
 				examplePtr = &example
 			}
 
-			result.WithContentItem(mime,
-				openapi3.MediaType{
-					Schema:  schemaOrRef,
-					Example: examplePtr,
-					//Encoding: c.Encoding,
-				})
+			for _, mime := range mimes {
+				result.WithContentItem(mime,
+					openapi3.MediaType{
+						Schema:  schemaOrRef,
+						Example: examplePtr,
+						//Encoding: c.Encoding,
+					})
+			}
 		}
 	} else if payload.IsPresent() && payload.Value() != nil {
 		schemaOrRef, err := Reflect(payload.Value())
@@ -434,12 +477,14 @@ func (d *EndpointResponseDocumentor) documentRawResponse(c restops.EndpointRespo
 			exampleValuePtr = &exampleValue
 		}
 
-		result.WithContentItem(mime,
-			openapi3.MediaType{
-				Schema:  schemaOrRef,
-				Example: exampleValuePtr,
-				//Encoding: c.Encoding,
-			})
+		for _, mime := range mimes {
+			result.WithContentItem(mime,
+				openapi3.MediaType{
+					Schema:  schemaOrRef,
+					Example: exampleValuePtr,
+					//Encoding: c.Encoding,
+				})
+		}
 	}
 
 	return &openapi3.ResponseOrRef{
@@ -5,6 +5,7 @@
 package openapi
 
 import (
+	"context"
 	"cto-github.cisco.com/NFV-BU/go-msx/integration"
 	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops"
 	"cto-github.cisco.com/NFV-BU/go-msx/paging"
@@ -126,6 +127,45 @@ func TestEndpointResponseDocumentor(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Stream",
+			response: restops.EndpointResponse{
+				Success: restops.EndpointResponseContent{
+					Mime:    restops.MediaTypeEventStream,
+					Mimes:   []string{restops.MediaTypeNdjson},
+					Stream:  true,
+					Payload: types.OptionalOf[interface{}](""),
+				},
+				Codes: restops.EndpointResponseCodes{
+					Success: []int{200},
+					Error:   []int{400},
+				},
+			},
+			want: map[string]*openapi3.Response{
+				"200": new(openapi3.Response).
+					WithDescription("OK - streamed response, with each event or line containing one item").
+					WithContent(map[string]openapi3.MediaType{
+						restops.MediaTypeEventStream: {
+							Schema: func() *openapi3.SchemaOrRef {
+								s := NewSchemaOrRefPtr(StringSchema())
+								s.Schema.ReflectType = reflect.TypeOf("")
+								return s
+							}(),
+						},
+						restops.MediaTypeNdjson: {
+							Schema: func() *openapi3.SchemaOrRef {
+								s := NewSchemaOrRefPtr(StringSchema())
+								s.Schema.ReflectType = reflect.TypeOf("")
+								return s
+							}(),
+						},
+					}),
+				"400": new(openapi3.Response).
+					WithDescription("Bad Request").
+					WithContent(errorContent),
+			},
+			wantErr: false,
+		},
 		{
 			name: "Mutator",
 			doc: new(EndpointResponseDocumentor).WithMutator(
@@ -406,3 +446,59 @@ func TestEndpointResponseDocumentor_Result(t *testing.T) {
 	por := doc.Result()
 	assert.NotNil(t, por)
 }
+
+func TestEndpointResponseDocumentor_NotModified(t *testing.T) {
+	endpoint, err := restops.NewEndpoint(http.MethodGet, "api", "v1", "items").
+		WithHandler(func() {}).
+		WithOutputs(struct {
+			Body string `resp:"body"`
+		}{}).
+		WithResponseCodes(restops.GetResponseCodes).
+		WithEntityTags(restops.EntityTagStrong).
+		Build()
+	assert.NoError(t, err)
+
+	doc := new(EndpointResponseDocumentor).WithEndpoint(endpoint)
+	err = doc.Document(&endpoint.Response)
+	assert.NoError(t, err)
+
+	responses := doc.Result().MapOfResponseOrRefValues
+
+	success := responses["200"].Response
+	assert.NotNil(t, success)
+	assert.Contains(t, success.Headers, restops.HeaderETag)
+
+	notModified := responses["304"].Response
+	assert.NotNil(t, notModified)
+	assert.Equal(t, "Not Modified", notModified.Description)
+	assert.Empty(t, notModified.Content)
+	assert.Contains(t, notModified.Headers, restops.HeaderETag)
+	assert.NotContains(t, notModified.Headers, restops.HeaderLastModified)
+
+	_, ok := responses["412"]
+	assert.False(t, ok)
+}
+
+func TestEndpointResponseDocumentor_PreconditionFailed(t *testing.T) {
+	endpoint, err := restops.NewEndpoint(http.MethodPut, "api", "v1", "items").
+		WithHandler(func() {}).
+		WithResponseCodes(restops.UpdateResponseCodes).
+		WithEntityVersion(func(ctx context.Context, inputs interface{}) (restops.EntityVersion, error) {
+			return restops.EntityVersion{}, nil
+		}).
+		Build()
+	assert.NoError(t, err)
+
+	doc := new(EndpointResponseDocumentor).WithEndpoint(endpoint)
+	err = doc.Document(&endpoint.Response)
+	assert.NoError(t, err)
+
+	responses := doc.Result().MapOfResponseOrRefValues
+
+	_, ok := responses["304"]
+	assert.False(t, ok)
+
+	preconditionFailed, ok := responses["412"]
+	assert.True(t, ok)
+	assert.Equal(t, "Precondition Failed", preconditionFailed.Response.Description)
+}
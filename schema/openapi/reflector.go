@@ -23,6 +23,7 @@ var reflectorOptions = []func(*jsonschema.ReflectContext){
 	js.TypeTitleInterceptor(),
 	OverrideSchema(),
 	js.StructRequiredInterceptor(),
+	js.DependentRequiredInterceptor(),
 	js.EnvelopNullability(),
 	js.ExampleInterceptor(),
 }
@@ -72,9 +73,41 @@ type Patcher interface {
 func ConvertToOpenApiSchema(schema jsonschema.Schema) openapi3.SchemaOrRef {
 	s := openapi3.SchemaOrRef{}
 	s.FromJSONSchema(schema.ToSchemaOrBool())
+	convertConstraints(schema, s)
 	return s
 }
 
+// convertConstraints carries over the validation constraints which have no direct
+// OpenAPI 3.0 equivalent, so the documentation describes what is enforced.
+func convertConstraints(schema jsonschema.Schema, osr openapi3.SchemaOrRef) {
+	os := osr.Schema
+	if os == nil || schema.Ref != nil {
+		return
+	}
+
+	// JSON Schema exclusive bounds are numeric; OpenAPI 3.0 exclusive bounds are boolean modifiers
+	if schema.ExclusiveMaximum != nil {
+		os.Maximum = schema.ExclusiveMaximum
+	}
+	if schema.ExclusiveMinimum != nil {
+		os.Minimum = schema.ExclusiveMinimum
+	}
+
+	if dependentRequired, ok := schema.ExtraProperties[js.KeywordDependentRequired]; ok {
+		os.WithMapOfAnythingItem("x-"+js.KeywordDependentRequired, dependentRequired)
+	}
+
+	for name, property := range schema.Properties {
+		if property.TypeObject != nil {
+			convertConstraints(*property.TypeObject, os.Properties[name])
+		}
+	}
+
+	if schema.Items != nil && schema.Items.SchemaOrBool != nil && schema.Items.SchemaOrBool.TypeObject != nil && os.Items != nil {
+		convertConstraints(*schema.Items.SchemaOrBool.TypeObject, *os.Items)
+	}
+}
+
 func PromoteNullableSchema(osr openapi3.SchemaOrRef) openapi3.SchemaOrRef {
 	if osr.Schema == nil {
 		return osr
@@ -147,6 +180,10 @@ func Reflect(value interface{}) (*openapi3.SchemaOrRef, error) {
 		return nil, err
 	}
 
+	return convertReflectedSchema(value, schema), nil
+}
+
+func convertReflectedSchema(value interface{}, schema jsonschema.Schema) *openapi3.SchemaOrRef {
 	s := ConvertToOpenApiSchema(schema)
 	s = PromoteNullableSchema(s)
 
@@ -163,29 +200,19 @@ func Reflect(value interface{}) (*openapi3.SchemaOrRef, error) {
 		}
 	}
 
-	return &s, nil
+	return &s
 }
 
 func addOpenApiSchema(portField *ops.PortField, sf reflect.StructField) error {
 	value := reflect.Zero(sf.Type).Interface()
 
-	openApiSchemaOrRef, err := Reflect(value)
-	if err != nil {
-		logger.WithError(err).Errorf("Failed to reflect field %q", portField.Name)
-	}
-
-	openApiSchema := openApiSchemaOrRef.Schema
-	if openApiSchema != nil {
-		if e := portField.Enum(); e != nil {
-			openApiSchema.Enum = e
-		}
-
-		if err = PopulateFieldsFromTags(openApiSchema, portField.Tags()); err != nil {
-			return err
-		}
+	// Derive the documented schema from the constrained json schema
+	schema := jsonSchemaFromPortField(portField)
+	if schema == nil {
+		return errors.Errorf("Json schema not found for field %q", portField.Name)
 	}
 
-	portFieldWithOpenApiSchema(portField, openApiSchemaOrRef)
+	portFieldWithOpenApiSchema(portField, convertReflectedSchema(value, *schema))
 	return nil
 }
 
@@ -204,6 +231,22 @@ func ReflectOpenApiJson(value interface{}) (jsonschema.Schema, error) {
 	return s, nil
 }
 
+// populatePortFieldSchema applies the constraints declared by a port field to its reflected
+// json schema.  Both the documentation and validation schemas are populated here, so that
+// the published schema describes exactly what is enforced.
+func populatePortFieldSchema(schema *jsonschema.Schema, field *ops.PortField, sf reflect.StructField) error {
+	if schema.Ref != nil {
+		// Named types cannot be augmented in-place
+		return nil
+	}
+
+	if e := field.Enum(); e != nil {
+		schema.Enum = e
+	}
+
+	return js.PopulateFieldsFromTags(schema, sf.Tag)
+}
+
 func addJsonSchema(portField *ops.PortField, sf reflect.StructField) error {
 	value := reflect.Zero(sf.Type).Interface()
 
@@ -212,10 +255,8 @@ func addJsonSchema(portField *ops.PortField, sf reflect.StructField) error {
 		return err
 	}
 
-	if schema.Ref == nil {
-		if err = js.PopulateFieldsFromTags(&schema, portField.Tags()); err != nil {
-			return err
-		}
+	if err = populatePortFieldSchema(&schema, portField, sf); err != nil {
+		return err
 	}
 
 	portFieldWithJsonSchema(portField, &schema)
@@ -249,14 +290,9 @@ func reflectForJsonValidationSchema(field *ops.PortField, sf reflect.StructField
 		schema.WithDefinitionsItem(k, s)
 	}
 
-	// Add any enumerations
-	if e := field.Enum(); e != nil {
-		schema.Enum = e
-	}
-
-	// Add any tag fields
-	if err = js.PopulateFieldsFromTags(&schema, sf.Tag); err != nil {
-		logger.WithError(err).Errorf("Failed to populate tags onto %q", field.Name)
+	// Add any enumerations and tag fields
+	if err = populatePortFieldSchema(&schema, field, sf); err != nil {
+		err = errors.Wrapf(err, "Failed to populate tags onto %q", field.Name)
 	}
 
 	return
@@ -0,0 +1,111 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package openapi
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/ops"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+type reflectorTestContact struct {
+	Email string `json:"email,omitempty" optional:"true" format:"email" dependentRequired:"name"`
+	Name  string `json:"name,omitempty" optional:"true"`
+}
+
+func reflectorTestPortField(t *testing.T, portStruct interface{}, name string) *ops.PortField {
+	port, err := restops.PortReflector{}.ReflectInputPort(reflect.TypeOf(portStruct))
+	assert.NoError(t, err)
+
+	field := port.Fields.First(ops.PortFieldHasName(name))
+	assert.NotNil(t, field)
+	return field
+}
+
+func TestPostProcessPortField(t *testing.T) {
+	type inputs struct {
+		Name    string               `req:"query" pattern:"^[a-z]+$" minLength:"2"`
+		Level   int                  `req:"query" enum:"1,2"`
+		Ratio   float64              `req:"query" exclusiveMaximum:"1"`
+		Timeout types.Duration       `req:"query"`
+		Contact reflectorTestContact `req:"body"`
+	}
+
+	tests := []struct {
+		name    string
+		field   string
+		doc     string
+		valid   interface{}
+		invalid interface{}
+	}{
+		{
+			name:    "Pattern",
+			field:   "Name",
+			doc:     `{"type":"string","minLength":2,"pattern":"^[a-z]+$"}`,
+			valid:   "ab",
+			invalid: "A",
+		},
+		{
+			name:    "IntegerEnum",
+			field:   "Level",
+			doc:     `{"type":"integer","enum":[1,2]}`,
+			valid:   2,
+			invalid: 3,
+		},
+		{
+			name:    "ExclusiveMaximum",
+			field:   "Ratio",
+			doc:     `{"type":"number","maximum":1,"exclusiveMaximum":true}`,
+			valid:   0.5,
+			invalid: 1,
+		},
+		{
+			name:    "Format",
+			field:   "Timeout",
+			doc:     `{"$ref":"#/components/schemas/Duration"}`,
+			valid:   "1h30m",
+			invalid: "PT1H",
+		},
+		{
+			name:    "DependentRequired",
+			field:   "Contact",
+			doc:     `{"$ref":"#/components/schemas/openapi.reflectorTestContact"}`,
+			valid:   map[string]interface{}{"email": "jo@example.com", "name": "Jo"},
+			invalid: map[string]interface{}{"email": "jo@example.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := reflectorTestPortField(t, inputs{}, tt.field)
+
+			doc, err := json.Marshal(openApiSchemaFromPortField(field))
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.doc, string(doc))
+
+			vs, err := GetJsonValidationSchema(field)
+			assert.NoError(t, err)
+			assert.NoError(t, vs.Validate(tt.valid))
+			assert.Error(t, vs.Validate(tt.invalid))
+		})
+	}
+}
+
+func TestConvertToOpenApiSchema_DependentRequired(t *testing.T) {
+	field := reflectorTestPortField(t, struct {
+		Contact reflectorTestContact `req:"body"`
+	}{}, "Contact")
+	assert.NotNil(t, field)
+
+	schema, ok := LookupSchema("openapi.reflectorTestContact")
+	assert.True(t, ok)
+	assert.Equal(t,
+		map[string][]string{"email": {"name"}},
+		schema.MapOfAnything["x-dependentRequired"])
+	assert.Equal(t, "email", *schema.Properties["email"].Schema.Format)
+}
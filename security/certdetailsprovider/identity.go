@@ -0,0 +1,291 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package certdetailsprovider
+
+import (
+	"context"
+	"crypto/x509"
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
+	"cto-github.cisco.com/NFV-BU/go-msx/log"
+	"cto-github.cisco.com/NFV-BU/go-msx/security"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"github.com/bmatcuk/doublestar"
+	"github.com/pkg/errors"
+	"sort"
+)
+
+const configRootCertificateIdentity = "security.certificate.identity"
+
+var (
+	logger = log.NewLogger("msx.security.certdetailsprovider")
+
+	ErrDisabled              = errors.New("Certificate identity mapping disabled")
+	ErrCertificateNotFound   = errors.New("Certificate not found in user context")
+	ErrNoMatchingIdentity    = errors.New("Certificate does not match any identity rule")
+	ErrPathNotAllowed        = errors.New("Certificate identity not allowed to access path")
+	ErrInvalidIdentityRule   = errors.New("Invalid certificate identity rule")
+	ErrInvalidIdentityTenant = errors.New("Invalid certificate identity tenant")
+)
+
+// IdentityRuleConfig maps matching client certificates to a service identity.  Each
+// criterion is a list of patterns, at least one of which must match.  All specified
+// criteria must match.  Wildcards ("*") never cross component boundaries: URIs are
+// matched by scheme, host label and path segment, DNS names by label, and
+// distinguished names by individual attribute.
+type IdentityRuleConfig struct {
+	Subjects    []string `config:"optional"`
+	CommonNames []string `config:"optional"`
+	Uris        []string `config:"optional"`
+	DnsNames    []string `config:"optional"`
+	Issuers     []string `config:"optional"`
+	Identity    string   `config:"optional"`
+	Roles       []string `config:"optional"`
+	Permissions []string `config:"optional"`
+	Tenants     []string `config:"optional"`
+	Paths       []string `config:"optional"`
+}
+
+// IdentityMapperConfig configures certificate identity mapping.  Rules are evaluated
+// in name order, and the first matching rule is applied.
+type IdentityMapperConfig struct {
+	Enabled bool `config:"default=false"`
+	Rules   map[string]IdentityRuleConfig
+}
+
+// Identity is the service identity mapped from a client certificate.
+type Identity struct {
+	Rule        string
+	Name        string
+	Roles       []string
+	Permissions []string
+	Tenants     []types.UUID
+	Paths       []string
+}
+
+// AllowsPath returns true if the identity may access the path.  Identities without
+// a path allow-list may access any path.
+func (i Identity) AllowsPath(path string) bool {
+	if len(i.Paths) == 0 {
+		return true
+	}
+
+	for _, pattern := range i.Paths {
+		if matches, err := doublestar.Match(pattern, path); err == nil && matches {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IdentityRule is a compiled IdentityRuleConfig.
+type IdentityRule struct {
+	name        string
+	cfg         IdentityRuleConfig
+	subjects    distinguishedNamePatternList
+	commonNames valuePatternList
+	uris        uriPatternList
+	dnsNames    valuePatternList
+	issuers     distinguishedNamePatternList
+	tenants     []types.UUID
+}
+
+// Matches returns true if the certificate satisfies every criterion of the rule.
+func (r IdentityRule) Matches(cert *x509.Certificate) bool {
+	if r.subjects != nil && !r.subjects.matchAny(cert.Subject) {
+		return false
+	}
+
+	if r.commonNames != nil && !r.commonNames.matchAny(cert.Subject.CommonName) {
+		return false
+	}
+
+	if r.uris != nil && !r.uris.matchAny(cert.URIs...) {
+		return false
+	}
+
+	if r.dnsNames != nil && !r.dnsNames.matchAny(cert.DNSNames...) {
+		return false
+	}
+
+	if r.issuers != nil && !r.issuers.matchAny(cert.Issuer) {
+		return false
+	}
+
+	return true
+}
+
+// Identity returns the identity granted to the certificate by the rule.  Unless
+// configured, the identity is named after the certificate common name, or its
+// first SAN URI (such as a SPIFFE ID).
+func (r IdentityRule) Identity(cert *x509.Certificate) Identity {
+	name := r.cfg.Identity
+	if name == "" {
+		name = cert.Subject.CommonName
+	}
+	if name == "" && len(cert.URIs) > 0 {
+		name = cert.URIs[0].String()
+	}
+
+	return Identity{
+		Rule:        r.name,
+		Name:        name,
+		Roles:       r.cfg.Roles,
+		Permissions: r.cfg.Permissions,
+		Tenants:     r.tenants,
+		Paths:       r.cfg.Paths,
+	}
+}
+
+func NewIdentityRule(name string, cfg IdentityRuleConfig) (*IdentityRule, error) {
+	rule := &IdentityRule{
+		name:        name,
+		cfg:         cfg,
+		commonNames: newValuePatternList(cfg.CommonNames),
+		dnsNames:    newDnsPatternList(cfg.DnsNames),
+	}
+
+	var err error
+	if rule.subjects, err = newDistinguishedNamePatternList(cfg.Subjects); err != nil {
+		return nil, errors.Wrapf(ErrInvalidIdentityRule, "Rule %q subjects: %s", name, err.Error())
+	}
+
+	if rule.uris, err = newUriPatternList(cfg.Uris); err != nil {
+		return nil, errors.Wrapf(ErrInvalidIdentityRule, "Rule %q uris: %s", name, err.Error())
+	}
+
+	if rule.issuers, err = newDistinguishedNamePatternList(cfg.Issuers); err != nil {
+		return nil, errors.Wrapf(ErrInvalidIdentityRule, "Rule %q issuers: %s", name, err.Error())
+	}
+
+	if rule.subjects == nil && rule.commonNames == nil && rule.uris == nil &&
+		rule.dnsNames == nil && rule.issuers == nil {
+		return nil, errors.Wrapf(ErrInvalidIdentityRule, "Rule %q has no matching criteria", name)
+	}
+
+	for _, pattern := range cfg.Paths {
+		if _, err := doublestar.Match(pattern, pattern); err != nil {
+			return nil, errors.Wrapf(ErrInvalidIdentityRule, "Rule %q has invalid path pattern %q", name, pattern)
+		}
+	}
+
+	for _, tenantId := range cfg.Tenants {
+		tenantUuid, err := types.ParseUUID(tenantId)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidIdentityTenant, "Rule %q tenant %q", name, tenantId)
+		}
+		rule.tenants = append(rule.tenants, tenantUuid)
+	}
+
+	return rule, nil
+}
+
+// IdentityMapper maps client certificates to service identities using configured rules.
+type IdentityMapper struct {
+	rules []*IdentityRule
+}
+
+// Identity returns the identity of the first rule matching the certificate.
+func (m *IdentityMapper) Identity(cert *x509.Certificate) (Identity, error) {
+	if cert == nil {
+		return Identity{}, ErrCertificateNotFound
+	}
+
+	for _, rule := range m.rules {
+		if rule.Matches(cert) {
+			return rule.Identity(cert), nil
+		}
+	}
+
+	return Identity{}, errors.Wrapf(ErrNoMatchingIdentity, "Subject %q", cert.Subject.String())
+}
+
+// CertificateDetails implements CertificateMapper, reporting the roles, permissions
+// and tenants of the mapped identity.
+func (m *IdentityMapper) CertificateDetails(ctx context.Context, userContext *security.UserContext) (*security.UserContextDetails, error) {
+	identity, err := m.Identity(userContext.Certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	details := security.NewUserContextDetailsFromUserContext(userContext)
+	details.Username = &identity.Name
+	details.Roles = append([]string{}, identity.Roles...)
+	details.Permissions = append([]string{}, identity.Permissions...)
+	details.Tenants = append([]types.UUID{}, identity.Tenants...)
+	return details, nil
+}
+
+// MapUserContext implements UserContextMapper, applying the mapped identity to the
+// user context and enforcing the path allow-list of the identity.
+func (m *IdentityMapper) MapUserContext(ctx context.Context, userContext *security.UserContext, path string) (*security.UserContext, error) {
+	identity, err := m.Identity(userContext.Certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	if !identity.AllowsPath(path) {
+		return nil, errors.Wrapf(ErrPathNotAllowed, "Identity %q path %q", identity.Name, path)
+	}
+
+	logger.WithContext(ctx).Debugf("Client certificate mapped to identity %q using rule %q", identity.Name, identity.Rule)
+
+	result := userContext.Clone()
+	result.UserName = identity.Name
+	result.Roles = identity.Roles
+	return result, nil
+}
+
+func NewIdentityMapper(cfg IdentityMapperConfig) (*IdentityMapper, error) {
+	var names []string
+	for name := range cfg.Rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	mapper := new(IdentityMapper)
+	for _, name := range names {
+		rule, err := NewIdentityRule(name, cfg.Rules[name])
+		if err != nil {
+			return nil, err
+		}
+		mapper.rules = append(mapper.rules, rule)
+	}
+
+	return mapper, nil
+}
+
+func init() {
+	config.RegisterSchema[IdentityMapperConfig](configRootCertificateIdentity)
+}
+
+func NewIdentityMapperConfig(cfg *config.Config) (*IdentityMapperConfig, error) {
+	var mapperConfig IdentityMapperConfig
+	if err := cfg.Populate(&mapperConfig, configRootCertificateIdentity); err != nil {
+		return nil, err
+	}
+	return &mapperConfig, nil
+}
+
+// RegisterIdentityMapper registers the rule-based certificate mapper when enabled.
+func RegisterIdentityMapper(ctx context.Context) error {
+	mapperConfig, err := NewIdentityMapperConfig(config.MustFromContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	if !mapperConfig.Enabled {
+		return ErrDisabled
+	}
+
+	identityMapper, err := NewIdentityMapper(*mapperConfig)
+	if err != nil {
+		return err
+	}
+
+	logger.WithContext(ctx).Infof("Registering certificate identity mapper with %d rules", len(identityMapper.rules))
+	RegisterCertificateMapper(identityMapper)
+	return nil
+}
@@ -0,0 +1,300 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package certdetailsprovider
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"cto-github.cisco.com/NFV-BU/go-msx/security"
+	"cto-github.cisco.com/NFV-BU/go-msx/testhelpers/configtest"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+)
+
+var testTenantId = types.MustParseUUID("215927cd-95b9-4e21-b29f-ef9bdaff9cbf")
+
+func newTestCertificate(commonName string, uris ...string) *x509.Certificate {
+	cert := &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:   commonName,
+			Organization: []string{"Cisco"},
+		},
+		Issuer: pkix.Name{
+			CommonName: "MSX Internal CA",
+		},
+		DNSNames: []string{commonName + ".svc.cluster.local"},
+	}
+
+	for _, uri := range uris {
+		parsed, _ := url.Parse(uri)
+		cert.URIs = append(cert.URIs, parsed)
+	}
+
+	return cert
+}
+
+func newTestIdentityMapper(t *testing.T) *IdentityMapper {
+	mapper, err := NewIdentityMapper(IdentityMapperConfig{
+		Enabled: true,
+		Rules: map[string]IdentityRuleConfig{
+			"a-inventory": {
+				Uris:        []string{"spiffe://msx.local/ns/*/sa/inventory"},
+				Issuers:     []string{"CN=MSX Internal CA"},
+				Identity:    "inventoryservice",
+				Roles:       []string{"SERVICE"},
+				Permissions: []string{"VIEW_DEVICE"},
+				Tenants:     []string{testTenantId.String()},
+				Paths:       []string{"/api/v1/devices/**"},
+			},
+			"b-platform": {
+				CommonNames: []string{"*service"},
+				Subjects:    []string{"O=Cisco"},
+				Permissions: []string{"IS_API_ADMIN"},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	return mapper
+}
+
+func TestIdentityMapper_Identity(t *testing.T) {
+	mapper := newTestIdentityMapper(t)
+
+	tests := []struct {
+		name     string
+		cert     *x509.Certificate
+		wantRule string
+		wantName string
+		wantErr  error
+	}{
+		{
+			name:     "Spiffe",
+			cert:     newTestCertificate("", "spiffe://msx.local/ns/vms/sa/inventory"),
+			wantRule: "a-inventory",
+			wantName: "inventoryservice",
+		},
+		{
+			name:     "CommonName",
+			cert:     newTestCertificate("manageservice"),
+			wantRule: "b-platform",
+			wantName: "manageservice",
+		},
+		{
+			name:    "NoMatch",
+			cert:    newTestCertificate("client", "spiffe://other.local/ns/vms/sa/inventory"),
+			wantErr: ErrNoMatchingIdentity,
+		},
+		{
+			name:    "NoCertificate",
+			wantErr: ErrCertificateNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := mapper.Identity(tt.cert)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantRule, identity.Rule)
+			assert.Equal(t, tt.wantName, identity.Name)
+		})
+	}
+}
+
+func TestIdentityRule_Matches(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  IdentityRuleConfig
+		cert *x509.Certificate
+		want bool
+	}{
+		{
+			name: "UriHostWildcard",
+			cfg:  IdentityRuleConfig{Uris: []string{"spiffe://*.example.org/*"}},
+			cert: newTestCertificate("", "spiffe://a.example.org/svc"),
+			want: true,
+		},
+		{
+			name: "UriHostInPath",
+			cfg:  IdentityRuleConfig{Uris: []string{"spiffe://*.example.org/*"}},
+			cert: newTestCertificate("", "spiffe://evil.com/a.example.org/svc"),
+			want: false,
+		},
+		{
+			name: "UriHostLabels",
+			cfg:  IdentityRuleConfig{Uris: []string{"spiffe://*.example.org/*"}},
+			cert: newTestCertificate("", "spiffe://a.evil.com.example.org/svc"),
+			want: false,
+		},
+		{
+			name: "UriPathSegment",
+			cfg:  IdentityRuleConfig{Uris: []string{"spiffe://msx.local/ns/*/sa/inventory"}},
+			cert: newTestCertificate("", "spiffe://msx.local/ns/a/b/sa/inventory"),
+			want: false,
+		},
+		{
+			name: "UriPathSegments",
+			cfg:  IdentityRuleConfig{Uris: []string{"spiffe://msx.local/**"}},
+			cert: newTestCertificate("", "spiffe://msx.local/ns/vms/sa/inventory"),
+			want: true,
+		},
+		{
+			name: "UriScheme",
+			cfg:  IdentityRuleConfig{Uris: []string{"spiffe://msx.local/**"}},
+			cert: newTestCertificate("", "https://msx.local/ns/vms/sa/inventory"),
+			want: false,
+		},
+		{
+			name: "UriUserInfo",
+			cfg:  IdentityRuleConfig{Uris: []string{"spiffe://*.example.org/*"}},
+			cert: newTestCertificate("", "spiffe://a.example.org@evil.com/svc"),
+			want: false,
+		},
+		{
+			name: "DnsNameLabel",
+			cfg:  IdentityRuleConfig{DnsNames: []string{"*.svc.cluster.local"}},
+			cert: newTestCertificate("inventory"),
+			want: true,
+		},
+		{
+			name: "DnsNameLabels",
+			cfg:  IdentityRuleConfig{DnsNames: []string{"*.cluster.local"}},
+			cert: newTestCertificate("inventory"),
+			want: false,
+		},
+		{
+			name: "SubjectAttributes",
+			cfg:  IdentityRuleConfig{Subjects: []string{"CN=*,O=Cisco"}},
+			cert: newTestCertificate("inventoryservice"),
+			want: true,
+		},
+		{
+			name: "SubjectEscapedSeparator",
+			cfg:  IdentityRuleConfig{Subjects: []string{"CN=*,O=Cisco"}},
+			cert: &x509.Certificate{Subject: pkix.Name{CommonName: "evil,O=Cisco"}},
+			want: false,
+		},
+		{
+			name: "SubjectEscapedValue",
+			cfg:  IdentityRuleConfig{Subjects: []string{`CN=evil\,O=Cisco`}},
+			cert: &x509.Certificate{Subject: pkix.Name{CommonName: "evil,O=Cisco"}},
+			want: true,
+		},
+		{
+			name: "IssuerWildcard",
+			cfg:  IdentityRuleConfig{Issuers: []string{"CN=MSX Internal*"}},
+			cert: newTestCertificate("inventoryservice"),
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := NewIdentityRule(tt.name, tt.cfg)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, rule.Matches(tt.cert))
+		})
+	}
+}
+
+func TestIdentityMapper_MapUserContext(t *testing.T) {
+	mapper := newTestIdentityMapper(t)
+	ctx := context.Background()
+	userContext := &security.UserContext{
+		UserName:    "",
+		Certificate: newTestCertificate("", "spiffe://msx.local/ns/vms/sa/inventory"),
+	}
+
+	mapped, err := mapper.MapUserContext(ctx, userContext, "/api/v1/devices/1234")
+	assert.NoError(t, err)
+	assert.Equal(t, "inventoryservice", mapped.UserName)
+	assert.Equal(t, []string{"SERVICE"}, mapped.Roles)
+
+	_, err = mapper.MapUserContext(ctx, userContext, "/api/v1/tenants")
+	assert.ErrorIs(t, err, ErrPathNotAllowed)
+
+	details, err := mapper.CertificateDetails(ctx, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "inventoryservice", *details.Username)
+	assert.Equal(t, []string{"VIEW_DEVICE"}, details.Permissions)
+	assert.Equal(t, []types.UUID{testTenantId}, details.Tenants)
+}
+
+func TestIdentityMapperRegistered(t *testing.T) {
+	previous := mapper
+	defer func() { mapper = previous }()
+
+	mapper = nil
+	assert.False(t, IdentityMapperRegistered())
+
+	RegisterCertificateMapper(newTestIdentityMapper(t))
+	assert.True(t, IdentityMapperRegistered())
+}
+
+func TestNewIdentityRule_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  IdentityRuleConfig
+	}{
+		{
+			name: "NoCriteria",
+			cfg:  IdentityRuleConfig{Identity: "anything"},
+		},
+		{
+			name: "InvalidTenant",
+			cfg:  IdentityRuleConfig{CommonNames: []string{"a"}, Tenants: []string{"not-a-uuid"}},
+		},
+		{
+			name: "InvalidUri",
+			cfg:  IdentityRuleConfig{Uris: []string{"msx.local/*"}},
+		},
+		{
+			name: "InvalidSubject",
+			cfg:  IdentityRuleConfig{Subjects: []string{"*O=Cisco*"}},
+		},
+		{
+			name: "InvalidIssuer",
+			cfg:  IdentityRuleConfig{Issuers: []string{"CN"}},
+		},
+		{
+			name: "InvalidPath",
+			cfg:  IdentityRuleConfig{CommonNames: []string{"a"}, Paths: []string{"/api/["}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewIdentityRule(tt.name, tt.cfg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewIdentityMapperConfig(t *testing.T) {
+	cfg := configtest.NewInMemoryConfig(map[string]string{
+		"security.certificate.identity.enabled":                     "true",
+		"security.certificate.identity.rules.inventory.uris":        "spiffe://msx.local/*",
+		"security.certificate.identity.rules.inventory.permissions": "VIEW_DEVICE,MANAGE_DEVICE",
+		"security.certificate.identity.rules.inventory.paths":       "/api/**",
+		"security.certificate.identity.rules.platform.common-names": "*service",
+	})
+
+	mapperConfig, err := NewIdentityMapperConfig(cfg)
+	assert.NoError(t, err)
+	assert.True(t, mapperConfig.Enabled)
+	assert.Len(t, mapperConfig.Rules, 2)
+	assert.Equal(t, []string{"VIEW_DEVICE", "MANAGE_DEVICE"}, mapperConfig.Rules["inventory"].Permissions)
+
+	mapper, err := NewIdentityMapper(*mapperConfig)
+	assert.NoError(t, err)
+	assert.Len(t, mapper.rules, 2)
+}
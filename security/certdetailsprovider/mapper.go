@@ -20,3 +20,27 @@ func RegisterCertificateMapper(c CertificateMapper) {
 		mapper = c
 	}
 }
+
+// UserContextMapper is optionally implemented by a CertificateMapper to customize the
+// user context derived from a certificate, and restrict the paths it may access.
+type UserContextMapper interface {
+	MapUserContext(ctx context.Context, userContext *security.UserContext, path string) (*security.UserContext, error)
+}
+
+// MapUserContext applies the registered mapper to the user context derived from a
+// certificate.  The user context is returned unchanged when the mapper does not
+// implement UserContextMapper.
+func MapUserContext(ctx context.Context, userContext *security.UserContext, path string) (*security.UserContext, error) {
+	userContextMapper, ok := mapper.(UserContextMapper)
+	if !ok {
+		return userContext, nil
+	}
+	return userContextMapper.MapUserContext(ctx, userContext, path)
+}
+
+// IdentityMapperRegistered returns true if the rule-based IdentityMapper is registered,
+// in which case certificates presented directly by TLS clients may be authenticated.
+func IdentityMapperRegistered() bool {
+	_, ok := mapper.(*IdentityMapper)
+	return ok
+}
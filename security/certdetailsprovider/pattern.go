@@ -0,0 +1,327 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package certdetailsprovider
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"github.com/bmatcuk/doublestar"
+	"github.com/pkg/errors"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// newWildcardExpression compiles a pattern where "*" matches any sequence of
+// characters other than the separator.  An empty separator allows "*" to match
+// any sequence of characters.
+func newWildcardExpression(pattern string, separator string, caseInsensitive bool) *regexp.Regexp {
+	wildcard := `.*`
+	if separator != "" {
+		wildcard = `[^` + regexp.QuoteMeta(separator) + `]*`
+	}
+
+	expression := strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, wildcard)
+	if caseInsensitive {
+		expression = "(?i)" + expression
+	}
+
+	return regexp.MustCompile("^" + expression + "$")
+}
+
+// valuePatternList matches single values, such as a common name or DNS name.
+type valuePatternList []*regexp.Regexp
+
+func (l valuePatternList) matchAny(values ...string) bool {
+	for _, pattern := range l {
+		for _, value := range values {
+			if pattern.MatchString(value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// newValuePatternList compiles patterns matching an unstructured value, where "*"
+// matches any sequence of characters.
+func newValuePatternList(patterns []string) valuePatternList {
+	var results valuePatternList
+	for _, pattern := range patterns {
+		results = append(results, newWildcardExpression(pattern, "", false))
+	}
+	return results
+}
+
+// newDnsPatternList compiles DNS name patterns, where "*" matches within a single label.
+func newDnsPatternList(patterns []string) valuePatternList {
+	var results valuePatternList
+	for _, pattern := range patterns {
+		results = append(results, newWildcardExpression(pattern, ".", true))
+	}
+	return results
+}
+
+// uriPattern matches a URI component-wise.  The scheme, user info, port, query and
+// fragment must match exactly; "*" matches within a single host label, and path
+// patterns follow doublestar syntax, where "*" matches within a single path
+// segment and "**" matches any number of segments.
+type uriPattern struct {
+	scheme   string
+	user     string
+	host     *regexp.Regexp
+	port     string
+	path     string
+	query    string
+	fragment string
+}
+
+func (p uriPattern) matches(uri *url.URL) bool {
+	if uri == nil || !strings.EqualFold(p.scheme, uri.Scheme) {
+		return false
+	}
+
+	if p.user != uri.User.String() || p.port != uri.Port() {
+		return false
+	}
+
+	if p.query != uri.RawQuery || p.fragment != uri.Fragment {
+		return false
+	}
+
+	if !p.host.MatchString(uri.Hostname()) {
+		return false
+	}
+
+	path := uri.Path
+	if path == "" {
+		path = "/"
+	}
+
+	matches, err := doublestar.Match(p.path, path)
+	return err == nil && matches
+}
+
+type uriPatternList []uriPattern
+
+func (l uriPatternList) matchAny(uris ...*url.URL) bool {
+	for _, pattern := range l {
+		for _, uri := range uris {
+			if pattern.matches(uri) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func newUriPatternList(patterns []string) (uriPatternList, error) {
+	var results uriPatternList
+	for _, pattern := range patterns {
+		parsed, err := url.Parse(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid URI pattern %q", pattern)
+		}
+
+		if parsed.Scheme == "" || parsed.Host == "" {
+			return nil, errors.Errorf("URI pattern %q must include a scheme and host", pattern)
+		}
+
+		path := parsed.Path
+		if path == "" {
+			path = "/"
+		}
+
+		if _, err = doublestar.Match(path, path); err != nil {
+			return nil, errors.Wrapf(err, "Invalid URI path pattern %q", pattern)
+		}
+
+		results = append(results, uriPattern{
+			scheme:   parsed.Scheme,
+			user:     parsed.User.String(),
+			host:     newWildcardExpression(parsed.Hostname(), ".", true),
+			port:     parsed.Port(),
+			path:     path,
+			query:    parsed.RawQuery,
+			fragment: parsed.Fragment,
+		})
+	}
+	return results, nil
+}
+
+var distinguishedNameAttributeTypes = map[string]asn1.ObjectIdentifier{
+	"C":            {2, 5, 4, 6},
+	"O":            {2, 5, 4, 10},
+	"OU":           {2, 5, 4, 11},
+	"CN":           {2, 5, 4, 3},
+	"SERIALNUMBER": {2, 5, 4, 5},
+	"L":            {2, 5, 4, 7},
+	"ST":           {2, 5, 4, 8},
+	"STREET":       {2, 5, 4, 9},
+	"POSTALCODE":   {2, 5, 4, 17},
+}
+
+type attributePattern struct {
+	attributeType asn1.ObjectIdentifier
+	value         *regexp.Regexp
+}
+
+// distinguishedNamePattern matches individual attributes of a distinguished name.
+// Each attribute of the pattern must match an attribute of the same type in the
+// name; attributes not mentioned by the pattern are not constrained.
+type distinguishedNamePattern []attributePattern
+
+func (p distinguishedNamePattern) matches(name pkix.Name) bool {
+	var attributes []pkix.AttributeTypeAndValue
+	for _, rdn := range name.ToRDNSequence() {
+		attributes = append(attributes, rdn...)
+	}
+
+	for _, pattern := range p {
+		matched := false
+		for _, attribute := range attributes {
+			if !attribute.Type.Equal(pattern.attributeType) {
+				continue
+			}
+			if value, ok := attribute.Value.(string); ok && pattern.value.MatchString(value) {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+type distinguishedNamePatternList []distinguishedNamePattern
+
+func (l distinguishedNamePatternList) matchAny(name pkix.Name) bool {
+	for _, pattern := range l {
+		if pattern.matches(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func newDistinguishedNamePatternList(patterns []string) (distinguishedNamePatternList, error) {
+	var results distinguishedNamePatternList
+	for _, pattern := range patterns {
+		result, err := newDistinguishedNamePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// newDistinguishedNamePattern parses an RFC 4514 style pattern such as
+// "CN=*service,O=Cisco".  Commas and plus signs within a value must be escaped
+// with a backslash; "*" matches any sequence of characters within a value.
+func newDistinguishedNamePattern(pattern string) (distinguishedNamePattern, error) {
+	attributes, err := splitDistinguishedName(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var result distinguishedNamePattern
+	for _, attribute := range attributes {
+		typeName, value, ok := strings.Cut(attribute, "=")
+		if !ok {
+			return nil, errors.Errorf("Distinguished name pattern %q attribute %q has no value", pattern, attribute)
+		}
+
+		attributeType, err := parseAttributeType(strings.TrimSpace(typeName))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Distinguished name pattern %q", pattern)
+		}
+
+		result = append(result, attributePattern{
+			attributeType: attributeType,
+			value:         newWildcardExpression(unescapeAttributeValue(strings.TrimSpace(value)), "", false),
+		})
+	}
+
+	if len(result) == 0 {
+		return nil, errors.Errorf("Distinguished name pattern %q has no attributes", pattern)
+	}
+
+	return result, nil
+}
+
+// splitDistinguishedName splits the pattern into attribute assignments at each
+// unescaped comma or plus sign.  Escape sequences are retained.
+func splitDistinguishedName(pattern string) ([]string, error) {
+	var results []string
+	var current strings.Builder
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			current.WriteRune(r)
+			escaped = true
+		case r == ',' || r == '+':
+			results = append(results, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	if escaped {
+		return nil, errors.Errorf("Distinguished name pattern %q ends with an escape", pattern)
+	}
+
+	if current.Len() > 0 || len(results) > 0 {
+		results = append(results, current.String())
+	}
+
+	return results, nil
+}
+
+func unescapeAttributeValue(value string) string {
+	var result strings.Builder
+	escaped := false
+	for _, r := range value {
+		if !escaped && r == '\\' {
+			escaped = true
+			continue
+		}
+		result.WriteRune(r)
+		escaped = false
+	}
+	return result.String()
+}
+
+func parseAttributeType(typeName string) (asn1.ObjectIdentifier, error) {
+	if oid, ok := distinguishedNameAttributeTypes[strings.ToUpper(typeName)]; ok {
+		return oid, nil
+	}
+
+	// Dotted object identifier, such as 1.2.840.113549.1.9.1
+	var oid asn1.ObjectIdentifier
+	for _, component := range strings.Split(typeName, ".") {
+		value, err := strconv.Atoi(component)
+		if err != nil || value < 0 {
+			return nil, errors.Errorf("Unknown attribute type %q", typeName)
+		}
+		oid = append(oid, value)
+	}
+
+	if len(oid) < 2 {
+		return nil, errors.Errorf("Unknown attribute type %q", typeName)
+	}
+
+	return oid, nil
+}
@@ -7,6 +7,7 @@ package httprequest
 import (
 	"cto-github.cisco.com/NFV-BU/go-msx/log"
 	"cto-github.cisco.com/NFV-BU/go-msx/security"
+	"encoding/pem"
 	"github.com/pkg/errors"
 	"net/http"
 	"net/url"
@@ -16,6 +17,7 @@ import (
 const headerAuthorization = "Authorization"
 const headerValuePrefixBearer = "Bearer "
 const headerSslCert = "X-Ssl-Cert"
+const pemTypeCertificate = "CERTIFICATE"
 
 var logger = log.NewLogger("msx.security.httprequest")
 var ErrNotFound = errors.New("Authorization not found in request")
@@ -57,3 +59,16 @@ func ExtractCertificate(req *http.Request) (certificate string, err error) {
 	// Reverse the nginx encoding
 	return url.QueryUnescape(certificates[0])
 }
+
+// ExtractPeerCertificate returns the PEM-encoded x509 certificate presented by the client
+// during the TLS handshake
+func ExtractPeerCertificate(req *http.Request) (certificate string, err error) {
+	if req.TLS == nil || len(req.TLS.PeerCertificates) == 0 {
+		return "", ErrNotFound
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  pemTypeCertificate,
+		Bytes: req.TLS.PeerCertificates[0].Raw,
+	})), nil
+}
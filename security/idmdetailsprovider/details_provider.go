@@ -11,6 +11,7 @@ import (
 	"cto-github.cisco.com/NFV-BU/go-msx/log"
 	"cto-github.cisco.com/NFV-BU/go-msx/security"
 	"github.com/pkg/errors"
+	"time"
 )
 
 const (
@@ -28,8 +29,8 @@ type IdmTokenDetailsProviderConfig struct {
 
 type TokenDetailsProvider struct {
 	cfg          IdmTokenDetailsProviderConfig
-	detailsCache lru.Cache
-	activeCache  lru.Cache
+	detailsCache lru.TtlCache
+	activeCache  lru.TtlCache
 	fetcher      detailsFetcher
 }
 
@@ -87,7 +88,7 @@ func (t *TokenDetailsProvider) fetchActiveSeparate(ctx context.Context, token st
 	if err != nil {
 		return
 	}
-	t.activeCache.Set(token, active)
+	t.activeCache.SetWithTtl(token, active, t.cacheTtl(ctx, t.cfg.ActiveCache))
 	return active, nil
 }
 
@@ -99,11 +100,20 @@ func (t *TokenDetailsProvider) fetchDetails(ctx context.Context, token string) (
 		return nil, err
 	}
 
-	t.detailsCache.Set(token, details)
-	t.activeCache.Set(token, details.Active)
+	t.detailsCache.SetWithTtl(token, details, t.cacheTtl(ctx, t.cfg.DetailsCache))
+	t.activeCache.SetWithTtl(token, details.Active, t.cacheTtl(ctx, t.cfg.ActiveCache))
 	return details, nil
 }
 
+// cacheTtl ensures cached token state does not outlive the token.
+func (t *TokenDetailsProvider) cacheTtl(ctx context.Context, cacheConfig lru.CacheConfig) time.Duration {
+	return security.TokenCacheTtl(security.UserContextFromContext(ctx), cacheConfig.Ttl)
+}
+
+func init() {
+	config.RegisterSchema[IdmTokenDetailsProviderConfig](configRootIdmTokenDetailsProvider)
+}
+
 func NewIdmTokenDetailsProviderConfig(cfg *config.Config) (*IdmTokenDetailsProviderConfig, error) {
 	var providerConfig IdmTokenDetailsProviderConfig
 	if err := cfg.Populate(&providerConfig, configRootIdmTokenDetailsProvider); err != nil {
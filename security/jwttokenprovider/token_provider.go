@@ -39,6 +39,12 @@ const (
 	jwtClaimTenantId    = "tenantId"
 	jwtClaimRoles       = "roles"
 	jwtClaimAuthorities = "authorities"
+	jwtClaimJti         = "jti"
+	jwtClaimIssuer      = "iss"
+	jwtClaimSubject     = "sub"
+	jwtClaimExpires     = "exp"
+	jwtClaimIssuedAt    = "iat"
+	jwtClaimClientId    = "client_id"
 
 	defaultJwtClaimAuthorities = "ROLE_CLIENT"
 
@@ -66,6 +72,8 @@ var (
 )
 
 func init() {
+	config.RegisterSchema[TokenProviderConfig](configRootJwtTokenProvider)
+
 	jwt.TimeFunc = func() time.Time {
 		return time.Now().Add(5 * time.Second)
 	}
@@ -121,6 +129,12 @@ func (j *TokenProvider) UserContextFromToken(ctx context.Context, token string)
 		TenantId: tenantUuid,
 		Scopes:   types.InterfaceSliceToStringSlice(scope.([]interface{})),
 		Token:    token[:],
+		Jti:      claimString(jwtClaims, jwtClaimJti),
+		Issuer:   claimString(jwtClaims, jwtClaimIssuer),
+		Subject:  claimString(jwtClaims, jwtClaimSubject),
+		ClientId: claimString(jwtClaims, jwtClaimClientId),
+		Exp:      claimInt(jwtClaims, jwtClaimExpires),
+		IssuedAt: claimInt(jwtClaims, jwtClaimIssuedAt),
 	}
 
 	//jwtClaimAuthorities is deprecated and is not present in the token issued by auth service
@@ -134,6 +148,23 @@ func (j *TokenProvider) UserContextFromToken(ctx context.Context, token string)
 	return uc, nil
 }
 
+func claimString(claims jwt.MapClaims, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+func claimInt(claims jwt.MapClaims, name string) int {
+	switch value := claims[name].(type) {
+	case float64:
+		return int(value)
+	case json.Number:
+		result, _ := value.Int64()
+		return int(result)
+	default:
+		return 0
+	}
+}
+
 func (j *TokenProvider) signingKeyFunc(ctx context.Context) (jwt.Keyfunc, error) {
 	switch j.cfg.KeySource {
 	case keySourcePem:
@@ -147,6 +178,9 @@ func (j *TokenProvider) signingKeyFunc(ctx context.Context) (jwt.Keyfunc, error)
 	case keySourceJwks:
 		return j.cachedSigningKeyFunc(ctx, j.jwksSigningKey), nil
 	default:
+		if fn := keySource(j.cfg.KeySource); fn != nil {
+			return j.cachedSigningKeyFunc(ctx, signingKeyFunc(fn)), nil
+		}
 		return nil, errors.Errorf("Unknown JWT Key Source: %s", j.cfg.KeySource)
 	}
 }
@@ -339,6 +373,25 @@ func (j *TokenProvider) jwksSigningKey(ctx context.Context, kid string) (interfa
 
 type signingKeyFunc func(context.Context, string) (interface{}, error)
 
+// KeySourceFunc returns the public key used to verify tokens signed using the key with the specified id.
+type KeySourceFunc func(ctx context.Context, kid string) (interface{}, error)
+
+var keySources = make(map[string]KeySourceFunc)
+var keySourcesMtx sync.RWMutex
+
+// RegisterKeySource makes an additional key source available to the token provider.
+func RegisterKeySource(name string, fn KeySourceFunc) {
+	keySourcesMtx.Lock()
+	defer keySourcesMtx.Unlock()
+	keySources[name] = fn
+}
+
+func keySource(name string) KeySourceFunc {
+	keySourcesMtx.RLock()
+	defer keySourcesMtx.RUnlock()
+	return keySources[name]
+}
+
 func (j *TokenProvider) cachedSigningKeyFunc(ctx context.Context, fn signingKeyFunc) jwt.Keyfunc {
 	return func(token *jwt.Token) (interface{}, error) {
 		kid, ok := token.Header[jwtHeaderKeyId].(string)
@@ -379,10 +432,14 @@ func RegisterTokenProvider(ctx context.Context) error {
 		return err
 	}
 
-	security.SetTokenProvider(&TokenProvider{
-		cfg:      jwtTokenProviderConfig,
-		keyCache: sync.Map{},
-	})
+	security.SetTokenProvider(NewTokenProvider(jwtTokenProviderConfig))
 
 	return nil
 }
+
+func NewTokenProvider(cfg *TokenProviderConfig) *TokenProvider {
+	return &TokenProvider{
+		cfg:      cfg,
+		keyCache: sync.Map{},
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package localissuer
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
+	"strings"
+	"time"
+)
+
+const (
+	configRootLocalIssuer = "security.issuer.local"
+	configKeyProfile      = "profile"
+
+	// profileLocal is the only profile under which the issuer may be activated.
+	profileLocal = "local"
+)
+
+// SubjectConfig describes the user represented by issued tokens, and the
+// permissions and tenants reported by the token details provider.
+type SubjectConfig struct {
+	UserName    string   `config:"default=admin"`
+	Email       string   `config:"optional"`
+	TenantId    string   `config:"optional"`
+	Tenants     []string `config:"optional"`
+	Roles       []string `config:"optional"`
+	Authorities []string `config:"default=ROLE_CLIENT"`
+	Scopes      []string `config:"default=read;write"`
+	Permissions []string `config:"optional"`
+}
+
+// IssuerConfig configures the local token issuer.  The issuer is only activated
+// when enabled and the local profile is active.
+type IssuerConfig struct {
+	Enabled   bool          `config:"default=false"`
+	Issuer    string        `config:"default=msx-local"`
+	ClientId  string        `config:"default=local-client"`
+	KeyId     string        `config:"default=local"`
+	KeyFile   string        `config:"optional"`
+	TokenTtl  time.Duration `config:"default=12h"`
+	JwksPath  string        `config:"default=/.well-known/jwks.json"`
+	TokenPath string        `config:"default=/v2/token"`
+	Subject   SubjectConfig
+}
+
+// LocalProfileActive returns true if the local profile is active.
+func LocalProfileActive(cfg *config.Config) (bool, error) {
+	activeProfiles, err := cfg.StringOr(configKeyProfile, "default")
+	if err != nil {
+		return false, err
+	}
+
+	for _, activeProfile := range strings.Split(activeProfiles, ",") {
+		if strings.TrimSpace(activeProfile) == profileLocal {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func init() {
+	config.RegisterSchema[IssuerConfig](configRootLocalIssuer)
+}
+
+func NewIssuerConfig(cfg *config.Config) (*IssuerConfig, error) {
+	var issuerConfig IssuerConfig
+	if err := cfg.Populate(&issuerConfig, configRootLocalIssuer); err != nil {
+		return nil, err
+	}
+	return &issuerConfig, nil
+}
@@ -0,0 +1,172 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package localissuer
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
+	"cto-github.cisco.com/NFV-BU/go-msx/security"
+	"cto-github.cisco.com/NFV-BU/go-msx/security/jwttokenprovider"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"cto-github.cisco.com/NFV-BU/go-msx/webservice"
+	"github.com/emicklei/go-restful"
+	"github.com/pkg/errors"
+	"path"
+	"strings"
+)
+
+const (
+	KeySourceLocal = "local"
+
+	formParamUserName = "username"
+	formParamTenantId = "tenant_id"
+
+	tokenTypeBearer = "bearer"
+	mimeFormEncoded = "application/x-www-form-urlencoded"
+)
+
+// TokenResponse follows the OAuth2 access token response format.
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope"`
+}
+
+type Controller struct {
+	cfg    *IssuerConfig
+	issuer *Issuer
+}
+
+func (c *Controller) jwks(req *restful.Request) (body interface{}, err error) {
+	return c.issuer.JsonWebKeys(), nil
+}
+
+func (c *Controller) token(req *restful.Request) (body interface{}, err error) {
+	if err = req.Request.ParseForm(); err != nil {
+		return nil, webservice.NewBadRequestError(err)
+	}
+
+	var tenantId types.UUID
+	if tenantIdParam := req.Request.Form.Get(formParamTenantId); tenantIdParam != "" {
+		if tenantId, err = types.ParseUUID(tenantIdParam); err != nil {
+			return nil, webservice.NewBadRequestError(errors.Wrap(err, "Invalid tenant_id"))
+		}
+	}
+
+	token, err := c.issuer.IssueDefault(req.Request.Form.Get(formParamUserName), tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	return TokenResponse{
+		AccessToken: token,
+		TokenType:   tokenTypeBearer,
+		ExpiresIn:   int(c.issuer.TokenTtl().Seconds()),
+		Scope:       strings.Join(c.cfg.Subject.Scopes, " "),
+	}, nil
+}
+
+// Register adds the JWKS and token endpoints to the web server.
+func (c *Controller) Register(server *webservice.WebServer) error {
+	jwksService, err := server.NewService(path.Dir(c.cfg.JwksPath))
+	if err != nil {
+		return err
+	}
+
+	jwksService.Route(jwksService.GET(path.Base(c.cfg.JwksPath)).
+		Operation("localissuer.jwks").
+		Doc("Retrieve local token issuer public keys").
+		Produces(restful.MIME_JSON).
+		To(webservice.RawController(c.jwks)).
+		Do(webservice.Returns200))
+
+	tokenService, err := server.NewService(path.Dir(c.cfg.TokenPath))
+	if err != nil {
+		return err
+	}
+
+	tokenService.Route(tokenService.POST(path.Base(c.cfg.TokenPath)).
+		Operation("localissuer.token").
+		Doc("Issue a local development token").
+		Consumes(mimeFormEncoded).
+		Produces(restful.MIME_JSON).
+		To(webservice.RawController(c.token)).
+		Do(webservice.Returns(200, 400)))
+
+	return nil
+}
+
+func NewController(cfg *IssuerConfig, issuer *Issuer) *Controller {
+	return &Controller{
+		cfg:    cfg,
+		issuer: issuer,
+	}
+}
+
+var defaultIssuer *Issuer
+var defaultIssuerConfig *IssuerConfig
+
+// DefaultIssuer returns the issuer registered by RegisterProviders, if any.
+func DefaultIssuer() *Issuer {
+	return defaultIssuer
+}
+
+// RegisterProviders creates the local issuer and installs it as the token provider and
+// token details provider, when enabled under the local profile.
+func RegisterProviders(ctx context.Context) error {
+	cfg := config.MustFromContext(ctx)
+	issuerConfig, err := NewIssuerConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	if !issuerConfig.Enabled {
+		return ErrDisabled
+	}
+
+	if active, err := LocalProfileActive(cfg); err != nil {
+		return err
+	} else if !active {
+		logger.WithContext(ctx).Warnf("Local token issuer requires the %q profile: disabled", profileLocal)
+		return ErrDisabled
+	}
+
+	logger.WithContext(ctx).Warn("Registering local token issuer: tokens are signed with a development key")
+
+	issuer, err := NewIssuer(issuerConfig)
+	if err != nil {
+		return err
+	}
+
+	detailsProvider, err := NewTokenDetailsProvider(issuerConfig.Subject)
+	if err != nil {
+		return err
+	}
+
+	jwttokenprovider.RegisterKeySource(KeySourceLocal, issuer.PublicKey)
+	security.SetTokenProvider(jwttokenprovider.NewTokenProvider(&jwttokenprovider.TokenProviderConfig{
+		KeySource: KeySourceLocal,
+	}))
+	security.SetTokenDetailsProvider(detailsProvider)
+
+	defaultIssuer = issuer
+	defaultIssuerConfig = issuerConfig
+	return nil
+}
+
+// RegisterController serves the JWKS and token endpoints of the local issuer.
+func RegisterController(ctx context.Context) error {
+	if defaultIssuer == nil {
+		return ErrDisabled
+	}
+
+	server := webservice.WebServerFromContext(ctx)
+	if server == nil {
+		return nil
+	}
+
+	return NewController(defaultIssuerConfig, defaultIssuer).Register(server)
+}
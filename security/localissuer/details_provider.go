@@ -0,0 +1,77 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package localissuer
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/security"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// TokenDetailsProvider reports the configured permissions and tenants for tokens
+// minted by the local issuer, in place of an IdM token introspection.
+type TokenDetailsProvider struct {
+	permissions []string
+	tenants     []types.UUID
+}
+
+func (p *TokenDetailsProvider) IsTokenActive(ctx context.Context) (bool, error) {
+	userContext := security.UserContextFromContext(ctx)
+	if userContext.Token == "" {
+		return false, errors.Wrap(security.ErrTokenNotFound, "Empty Token")
+	}
+
+	if userContext.Exp != 0 && int64(userContext.Exp) < time.Now().Unix() {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (p *TokenDetailsProvider) TokenDetails(ctx context.Context) (*security.UserContextDetails, error) {
+	userContext := security.UserContextFromContext(ctx)
+	if userContext.Token == "" {
+		return nil, errors.Wrap(security.ErrTokenNotFound, "Empty Token")
+	}
+
+	active, err := p.IsTokenActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	details := security.NewUserContextDetailsFromUserContext(userContext)
+	details.Active = active
+	details.Jti = &userContext.Jti
+	details.ClientId = &userContext.ClientId
+	details.Permissions = append([]string{}, p.permissions...)
+	details.Tenants = append([]types.UUID{}, p.tenants...)
+
+	if userContext.TenantId != nil && !userContext.TenantId.IsEmpty() {
+		details.TenantId = userContext.TenantId
+		if !details.HasTenantId(userContext.TenantId) {
+			details.Tenants = append(details.Tenants, userContext.TenantId)
+		}
+	}
+
+	return details, nil
+}
+
+func NewTokenDetailsProvider(cfg SubjectConfig) (*TokenDetailsProvider, error) {
+	var tenants []types.UUID
+	for _, tenantId := range cfg.Tenants {
+		tenantUuid, err := types.ParseUUID(tenantId)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid local issuer subject tenant %q", tenantId)
+		}
+		tenants = append(tenants, tenantUuid)
+	}
+
+	return &TokenDetailsProvider{
+		permissions: cfg.Permissions,
+		tenants:     tenants,
+	}, nil
+}
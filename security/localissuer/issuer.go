@@ -0,0 +1,213 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+// Package localissuer mints and verifies JWTs for local development and testing,
+// removing the dependency on a running IdM.  It must never be enabled in production.
+package localissuer
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"cto-github.cisco.com/NFV-BU/go-msx/integration/usermanagement"
+	"cto-github.cisco.com/NFV-BU/go-msx/log"
+	"cto-github.cisco.com/NFV-BU/go-msx/security"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"encoding/base64"
+	"encoding/pem"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pkg/errors"
+	"math/big"
+	"os"
+	"time"
+)
+
+const (
+	keyBits       = 2048
+	pemTypeRsaKey = "RSA PRIVATE KEY"
+	keyTypeRsa    = "RSA"
+)
+
+var (
+	logger = log.NewLogger("msx.security.localissuer")
+
+	ErrDisabled    = errors.New("Local token issuer disabled")
+	ErrKeyNotFound = errors.New("Key not found")
+	ErrInvalidKey  = errors.New("Invalid local issuer key")
+)
+
+// Issuer signs tokens containing user context claims.
+type Issuer struct {
+	cfg *IssuerConfig
+	key *rsa.PrivateKey
+}
+
+// Issue returns a signed token for the user context.  Unset token metadata
+// (issuer, subject, timestamps, id) is filled in from the issuer configuration.
+func (i *Issuer) Issue(userContext *security.UserContext) (string, error) {
+	now := time.Now()
+
+	jti := userContext.Jti
+	if jti == "" {
+		jti = types.MustNewUUID().String()
+	}
+
+	subject := userContext.Subject
+	if subject == "" {
+		subject = userContext.UserName
+	}
+
+	clientId := userContext.ClientId
+	if clientId == "" {
+		clientId = i.cfg.ClientId
+	}
+
+	tenantId := ""
+	if userContext.TenantId != nil {
+		tenantId = userContext.TenantId.String()
+	}
+
+	claims := jwt.MapClaims{
+		"user_name":   userContext.UserName,
+		"roles":       stringsOrEmpty(userContext.Roles),
+		"tenantId":    tenantId,
+		"scope":       stringsOrEmpty(userContext.Scopes),
+		"authorities": stringsOrEmpty(userContext.Authorities),
+		"firstName":   userContext.FirstName,
+		"lastName":    userContext.LastName,
+		"email":       userContext.Email,
+		"client_id":   clientId,
+		"iss":         i.cfg.Issuer,
+		"sub":         subject,
+		"iat":         now.Unix(),
+		"exp":         now.Add(i.cfg.TokenTtl).Unix(),
+		"jti":         jti,
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = i.cfg.KeyId
+	return token.SignedString(i.key)
+}
+
+// IssueDefault returns a signed token for the configured subject, optionally
+// overriding the user name and tenant.
+func (i *Issuer) IssueDefault(userName string, tenantId types.UUID) (string, error) {
+	userContext, err := i.DefaultUserContext()
+	if err != nil {
+		return "", err
+	}
+
+	if userName != "" {
+		userContext.UserName = userName
+	}
+
+	if tenantId != nil {
+		userContext.TenantId = tenantId
+	}
+
+	return i.Issue(userContext)
+}
+
+// DefaultUserContext returns the user context for the configured subject.
+func (i *Issuer) DefaultUserContext() (*security.UserContext, error) {
+	subjectConfig := i.cfg.Subject
+
+	var tenantId types.UUID
+	if subjectConfig.TenantId != "" {
+		var err error
+		if tenantId, err = types.ParseUUID(subjectConfig.TenantId); err != nil {
+			return nil, errors.Wrap(err, "Invalid local issuer subject tenant id")
+		}
+	}
+
+	return &security.UserContext{
+		UserName:    subjectConfig.UserName,
+		Email:       subjectConfig.Email,
+		TenantId:    tenantId,
+		Roles:       subjectConfig.Roles,
+		Scopes:      subjectConfig.Scopes,
+		Authorities: subjectConfig.Authorities,
+	}, nil
+}
+
+// PublicKey returns the key used to verify tokens signed by the issuer.
+func (i *Issuer) PublicKey(_ context.Context, kid string) (interface{}, error) {
+	if kid != i.cfg.KeyId {
+		return nil, errors.Wrapf(ErrKeyNotFound, "Key %q", kid)
+	}
+	return &i.key.PublicKey, nil
+}
+
+// JsonWebKeys returns the public key of the issuer as a JWKS document.
+func (i *Issuer) JsonWebKeys() usermanagement.JsonWebKeys {
+	publicKey := i.key.PublicKey
+	return usermanagement.JsonWebKeys{
+		Keys: []usermanagement.JsonWebKey{
+			{
+				KeyId:             i.cfg.KeyId,
+				KeyType:           keyTypeRsa,
+				RsaModulus:        base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+				RsaPublicExponent: base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+			},
+		},
+	}
+}
+
+func (i *Issuer) TokenTtl() time.Duration {
+	return i.cfg.TokenTtl
+}
+
+func stringsOrEmpty(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// loadKey reads the signing key from the key file, generating (and saving) a new
+// key when no file is configured or the file does not yet exist.
+func loadKey(keyFile string) (*rsa.PrivateKey, error) {
+	if keyFile != "" {
+		data, err := os.ReadFile(keyFile)
+		if err == nil {
+			block, _ := pem.Decode(data)
+			if block == nil || block.Type != pemTypeRsaKey {
+				return nil, errors.Wrapf(ErrInvalidKey, "Key file %q", keyFile)
+			}
+			return x509.ParsePKCS1PrivateKey(block.Bytes)
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyFile != "" {
+		data := pem.EncodeToMemory(&pem.Block{
+			Type:  pemTypeRsaKey,
+			Bytes: x509.MarshalPKCS1PrivateKey(key),
+		})
+		if err = os.WriteFile(keyFile, data, 0600); err != nil {
+			return nil, errors.Wrapf(err, "Failed to save key file %q", keyFile)
+		}
+	}
+
+	return key, nil
+}
+
+func NewIssuer(cfg *IssuerConfig) (*Issuer, error) {
+	key, err := loadKey(cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Issuer{
+		cfg: cfg,
+		key: key,
+	}, nil
+}
@@ -0,0 +1,164 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package localissuer
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
+	"cto-github.cisco.com/NFV-BU/go-msx/security"
+	"cto-github.cisco.com/NFV-BU/go-msx/security/jwttokenprovider"
+	"cto-github.cisco.com/NFV-BU/go-msx/testhelpers/configtest"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+var testTenantId = types.MustParseUUID("215927cd-95b9-4e21-b29f-ef9bdaff9cbf")
+
+func newTestIssuer(t *testing.T, values map[string]string) *Issuer {
+	issuerConfig, err := NewIssuerConfig(configtest.NewInMemoryConfig(values))
+	assert.NoError(t, err)
+
+	issuer, err := NewIssuer(issuerConfig)
+	assert.NoError(t, err)
+	return issuer
+}
+
+func TestIssuer_Issue(t *testing.T) {
+	issuer := newTestIssuer(t, map[string]string{
+		"security.issuer.local.subject.tenant-id": testTenantId.String(),
+		"security.issuer.local.subject.roles":     "NetworkAdmin",
+	})
+
+	token, err := issuer.IssueDefault("", nil)
+	assert.NoError(t, err)
+
+	jwttokenprovider.RegisterKeySource("test-issue", issuer.PublicKey)
+	tokenProvider := jwttokenprovider.NewTokenProvider(&jwttokenprovider.TokenProviderConfig{
+		KeySource: "test-issue",
+	})
+
+	userContext, err := tokenProvider.UserContextFromToken(context.Background(), token)
+	assert.NoError(t, err)
+	assert.Equal(t, "admin", userContext.UserName)
+	assert.Equal(t, testTenantId, userContext.TenantId)
+	assert.Equal(t, []string{"NetworkAdmin"}, userContext.Roles)
+	assert.Equal(t, []string{"read", "write"}, userContext.Scopes)
+	assert.Equal(t, []string{"ROLE_CLIENT"}, userContext.Authorities)
+
+	token, err = issuer.IssueDefault("other", nil)
+	assert.NoError(t, err)
+	userContext, err = tokenProvider.UserContextFromToken(context.Background(), token)
+	assert.NoError(t, err)
+	assert.Equal(t, "other", userContext.UserName)
+
+	other := newTestIssuer(t, nil)
+	otherToken, err := other.IssueDefault("", nil)
+	assert.NoError(t, err)
+	_, err = tokenProvider.UserContextFromToken(context.Background(), otherToken)
+	assert.Error(t, err)
+}
+
+func TestIssuer_JsonWebKeys(t *testing.T) {
+	issuer := newTestIssuer(t, nil)
+
+	jwks := issuer.JsonWebKeys()
+	jwk, err := jwks.KeyById("local")
+	assert.NoError(t, err)
+
+	publicKey, err := jwk.RsaPublicKey()
+	assert.NoError(t, err)
+	assert.True(t, publicKey.Equal(&issuer.key.PublicKey))
+}
+
+func TestNewIssuer_KeyFile(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "local-issuer.pem")
+	values := map[string]string{
+		"security.issuer.local.key-file": keyFile,
+	}
+
+	first := newTestIssuer(t, values)
+	second := newTestIssuer(t, values)
+	assert.True(t, first.key.Equal(second.key))
+}
+
+func TestTokenDetailsProvider_TokenDetails(t *testing.T) {
+	provider, err := NewTokenDetailsProvider(SubjectConfig{
+		Tenants:     []string{testTenantId.String()},
+		Permissions: []string{"VIEW_DEVICE"},
+	})
+	assert.NoError(t, err)
+
+	_, err = provider.TokenDetails(context.Background())
+	assert.ErrorIs(t, err, security.ErrTokenNotFound)
+
+	otherTenantId := types.MustParseUUID("215927cd-95b9-4e21-b29f-ef9bdaff9cbe")
+	ctx := security.ContextWithUserContext(context.Background(), &security.UserContext{
+		UserName: "admin",
+		TenantId: otherTenantId,
+		Token:    "token",
+	})
+
+	details, err := provider.TokenDetails(ctx)
+	assert.NoError(t, err)
+	assert.True(t, details.Active)
+	assert.Equal(t, []string{"VIEW_DEVICE"}, details.Permissions)
+	assert.Equal(t, []types.UUID{testTenantId, otherTenantId}, details.Tenants)
+	assert.Equal(t, otherTenantId, details.TenantId)
+}
+
+func TestRegisterProviders(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]string
+		wantErr error
+	}{
+		{
+			name:    "Disabled",
+			values:  map[string]string{"profile": "local"},
+			wantErr: ErrDisabled,
+		},
+		{
+			name: "WrongProfile",
+			values: map[string]string{
+				"profile":                       "production",
+				"security.issuer.local.enabled": "true",
+			},
+			wantErr: ErrDisabled,
+		},
+		{
+			name: "ConfiguredProfileIgnored",
+			values: map[string]string{
+				"profile":                        "production",
+				"security.issuer.local.enabled":  "true",
+				"security.issuer.local.profiles": "production",
+			},
+			wantErr: ErrDisabled,
+		},
+		{
+			name: "Enabled",
+			values: map[string]string{
+				"profile":                       "default,local",
+				"security.issuer.local.enabled": "true",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaultIssuer = nil
+			ctx := config.ContextWithConfig(context.Background(), configtest.NewInMemoryConfig(tt.values))
+			err := RegisterProviders(ctx)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, DefaultIssuer())
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, DefaultIssuer())
+			}
+		})
+	}
+}
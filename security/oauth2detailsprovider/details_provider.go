@@ -17,6 +17,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 const (
@@ -35,8 +36,8 @@ type ProviderConfig struct {
 
 type Provider struct {
 	cfg          ProviderConfig
-	detailsCache lru.Cache
-	activeCache  lru.Cache
+	detailsCache lru.TtlCache
+	activeCache  lru.TtlCache
 }
 
 func (t *Provider) IsTokenActive(ctx context.Context) (active bool, err error) {
@@ -74,11 +75,16 @@ func (t *Provider) fetchActive(ctx context.Context, token string) (active bool,
 		return false, err
 	}
 
-	t.activeCache.Set(token, active)
+	t.activeCache.SetWithTtl(token, details.Active, t.cacheTtl(ctx, t.cfg.ActiveCache))
 
 	return details.Active, nil
 }
 
+// cacheTtl ensures cached token state does not outlive the token.
+func (t *Provider) cacheTtl(ctx context.Context, cacheConfig lru.CacheConfig) time.Duration {
+	return security.TokenCacheTtl(security.UserContextFromContext(ctx), cacheConfig.Ttl)
+}
+
 func (t *Provider) TokenDetails(ctx context.Context) (details *security.UserContextDetails, err error) {
 	token := security.UserContextFromContext(ctx).Token
 	if token == "" {
@@ -150,7 +156,7 @@ func (t *Provider) fetch(ctx context.Context, token string) (details *security.U
 		return nil, err
 	}
 
-	t.detailsCache.Set(token, details)
+	t.detailsCache.SetWithTtl(token, details, t.cacheTtl(ctx, t.cfg.DetailsCache))
 
 	return
 }
@@ -187,6 +193,10 @@ func (t *Provider) parseTokenDetails(detailsBytes []byte) (result *security.User
 	return &tokenDetails, nil
 }
 
+func init() {
+	config.RegisterSchema[ProviderConfig](configRootOauth2TokenDetailsProvider)
+}
+
 func NewProviderConfig(ctx context.Context) (*ProviderConfig, error) {
 	var providerConfig ProviderConfig
 	if err := config.FromContext(ctx).Populate(&providerConfig, configRootOauth2TokenDetailsProvider); err != nil {
@@ -0,0 +1,120 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package security
+
+import (
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+// DefaultRevocationTtl is the period a revocation is retained when the expiry of the
+// revoked token is not known.
+const DefaultRevocationTtl = 24 * time.Hour
+
+var ErrTokenRevoked = errors.New("Token has been revoked")
+
+// RevocationList tracks revoked token ids (jti).  Each revocation is retained until
+// the revoked token would have expired, after which the token is rejected anyway.
+type RevocationList struct {
+	mtx     sync.RWMutex
+	revoked map[string]time.Time
+	now     func() time.Time
+	purged  time.Time
+}
+
+// Revoke adds the token id to the list until the specified expiry.
+func (l *RevocationList) Revoke(jti string, expires time.Time) {
+	if jti == "" {
+		return
+	}
+
+	now := l.now()
+	if expires.IsZero() {
+		expires = now.Add(DefaultRevocationTtl)
+	} else if !expires.After(now) {
+		return
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if current, ok := l.revoked[jti]; !ok || expires.After(current) {
+		l.revoked[jti] = expires
+	}
+
+	if now.Sub(l.purged) > time.Minute {
+		l.purge(now)
+	}
+}
+
+// IsRevoked returns true if the token id has been revoked and not yet expired.
+func (l *RevocationList) IsRevoked(jti string) bool {
+	if jti == "" {
+		return false
+	}
+
+	l.mtx.RLock()
+	defer l.mtx.RUnlock()
+
+	expires, ok := l.revoked[jti]
+	return ok && expires.After(l.now())
+}
+
+// Len returns the number of revocations currently retained.
+func (l *RevocationList) Len() int {
+	l.mtx.RLock()
+	defer l.mtx.RUnlock()
+	return len(l.revoked)
+}
+
+func (l *RevocationList) purge(now time.Time) {
+	for jti, expires := range l.revoked {
+		if !expires.After(now) {
+			delete(l.revoked, jti)
+		}
+	}
+	l.purged = now
+}
+
+func NewRevocationList() *RevocationList {
+	return &RevocationList{
+		revoked: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+var revocationList = NewRevocationList()
+
+// RevokeToken adds the token id to the default revocation list.
+func RevokeToken(jti string, expires time.Time) {
+	revocationList.Revoke(jti, expires)
+}
+
+// CheckTokenRevoked returns ErrTokenRevoked if the token of the user context has been revoked.
+func CheckTokenRevoked(userContext *UserContext) error {
+	if userContext != nil && revocationList.IsRevoked(userContext.Jti) {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
+// TokenCacheTtl returns the period for which details of the user context token may be
+// cached: the lesser of the configured ttl and the time remaining until the token expires.
+func TokenCacheTtl(userContext *UserContext, ttl time.Duration) time.Duration {
+	if userContext == nil || userContext.Exp == 0 {
+		return ttl
+	}
+
+	remaining := time.Until(time.Unix(int64(userContext.Exp), 0))
+	if remaining < 0 {
+		return 0
+	}
+
+	if remaining < ttl {
+		return remaining
+	}
+	return ttl
+}
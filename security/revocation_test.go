@@ -0,0 +1,91 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package security
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRevocationList(t *testing.T) {
+	now := time.Now()
+	list := NewRevocationList()
+	list.now = func() time.Time { return now }
+
+	list.Revoke("", now.Add(time.Hour))
+	list.Revoke("expired", now.Add(-time.Second))
+	list.Revoke("revoked", now.Add(time.Hour))
+	list.Revoke("unknown-expiry", time.Time{})
+
+	assert.False(t, list.IsRevoked(""))
+	assert.False(t, list.IsRevoked("expired"))
+	assert.False(t, list.IsRevoked("other"))
+	assert.True(t, list.IsRevoked("revoked"))
+	assert.True(t, list.IsRevoked("unknown-expiry"))
+	assert.Equal(t, 2, list.Len())
+
+	now = now.Add(2 * time.Hour)
+	assert.False(t, list.IsRevoked("revoked"))
+	assert.True(t, list.IsRevoked("unknown-expiry"))
+
+	list.Revoke("later", now.Add(time.Hour))
+	assert.Equal(t, 2, list.Len())
+}
+
+func TestCheckTokenRevoked(t *testing.T) {
+	RevokeToken("check-revoked", time.Now().Add(time.Hour))
+
+	assert.ErrorIs(t, CheckTokenRevoked(&UserContext{Jti: "check-revoked"}), ErrTokenRevoked)
+	assert.NoError(t, CheckTokenRevoked(&UserContext{Jti: "check-active"}))
+	assert.NoError(t, CheckTokenRevoked(nil))
+}
+
+func TestTokenCacheTtl(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		userContext *UserContext
+		ttl         time.Duration
+		wantMax     time.Duration
+		wantMin     time.Duration
+	}{
+		{
+			name:        "NoExpiry",
+			userContext: &UserContext{},
+			ttl:         5 * time.Minute,
+			wantMin:     5 * time.Minute,
+			wantMax:     5 * time.Minute,
+		},
+		{
+			name:        "ExpiresLater",
+			userContext: &UserContext{Exp: int(now.Add(time.Hour).Unix())},
+			ttl:         5 * time.Minute,
+			wantMin:     5 * time.Minute,
+			wantMax:     5 * time.Minute,
+		},
+		{
+			name:        "ExpiresSooner",
+			userContext: &UserContext{Exp: int(now.Add(time.Minute).Unix())},
+			ttl:         5 * time.Minute,
+			wantMin:     58 * time.Second,
+			wantMax:     time.Minute,
+		},
+		{
+			name:        "Expired",
+			userContext: &UserContext{Exp: int(now.Add(-time.Minute).Unix())},
+			ttl:         5 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TokenCacheTtl(tt.userContext, tt.ttl)
+			assert.GreaterOrEqual(t, got, tt.wantMin)
+			assert.LessOrEqual(t, got, tt.wantMax)
+		})
+	}
+}
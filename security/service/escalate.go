@@ -22,6 +22,10 @@ type SecurityAccountsDefaultSettings struct {
 	Password string `config:"default=system"`
 }
 
+func init() {
+	config.RegisterSchema[SecurityAccountsDefaultSettings](configRootIntegrationSecurityAccountsDefault)
+}
+
 func NewSecurityAccountsDefaultSettings(cfg *config.Config) (*SecurityAccountsDefaultSettings, error) {
 	securityAccountsConfig := &SecurityAccountsDefaultSettings{}
 	if err := cfg.LatestValues().Populate(securityAccountsConfig, configRootIntegrationSecurityAccountsDefault); err != nil {
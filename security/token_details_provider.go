@@ -37,6 +37,9 @@ func IsTokenActive(ctx context.Context) (bool, error) {
 	if tokenDetailsProvider == nil {
 		return false, ErrNotRegistered
 	}
+	if CheckTokenRevoked(UserContextFromContext(ctx)) != nil {
+		return false, nil
+	}
 	return tokenDetailsProvider.IsTokenActive(ctx)
 }
 
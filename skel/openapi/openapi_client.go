@@ -0,0 +1,58 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package openapi
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/integration/restclient/generator"
+	"cto-github.cisco.com/NFV-BU/go-msx/skel"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/pkg/errors"
+	"os"
+	"path"
+)
+
+var clientConfig = struct {
+	Package string
+	Service string
+}{}
+
+// GenerateClientOpenApi creates a typed integration client package from an OpenAPI 3.0 manifest.
+func GenerateClientOpenApi(args []string) error {
+	if len(args) == 0 {
+		return errors.New("No OpenAPI spec provided.")
+	}
+
+	bytes, err := os.ReadFile(args[0])
+	if err != nil {
+		return errors.Wrap(err, "Failed to read OpenAPI spec")
+	}
+
+	var loader = openapi3.NewSwaggerLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.LoadSwaggerFromURIFunc = loadSwaggerFromUri
+
+	swagger, err := loader.LoadSwaggerFromData(bytes)
+	if err != nil {
+		return errors.Wrap(err, "Failed to parse OpenAPI spec")
+	}
+
+	clientGenerator, err := generator.NewGenerator(swagger, generator.Options{
+		PackageName: clientConfig.Package,
+		ServiceName: clientConfig.Service,
+	})
+	if err != nil {
+		return err
+	}
+
+	targetDirectory := path.Join(
+		skel.Config().TargetDirectory(),
+		"internal",
+		"integration",
+		clientConfig.Package)
+
+	logger.Infof("Generating client package %q", targetDirectory)
+
+	return clientGenerator.WriteFiles(targetDirectory)
+}
@@ -7,6 +7,7 @@ package openapi
 import (
 	"cto-github.cisco.com/NFV-BU/go-msx/log"
 	"cto-github.cisco.com/NFV-BU/go-msx/skel"
+	"github.com/spf13/cobra"
 )
 
 var logger = log.NewPackageLogger()
@@ -14,4 +15,10 @@ var logger = log.NewPackageLogger()
 func init() {
 	skel.AddTarget("generate-webservices", "Create web services from swagger manifest", GenerateDomainOpenApi)
 	skel.AddTarget("generate-domain-openapi", "Create domains from OpenAPI 3.0 manifest", GenerateDomainOpenApi)
+
+	cmd := skel.AddTarget("generate-client-openapi", "Create integration client from OpenAPI 3.0 manifest", GenerateClientOpenApi)
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.Use = "generate-client-openapi <spec>"
+	cmd.Flags().StringVar(&clientConfig.Package, "package", "", "Client package name")
+	cmd.Flags().StringVar(&clientConfig.Service, "service", "", "Remote service name")
 }
@@ -0,0 +1,522 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package rest
+
+import (
+	"bytes"
+	"cto-github.cisco.com/NFV-BU/go-msx/skel"
+	"cto-github.cisco.com/NFV-BU/go-msx/skel/text"
+	"github.com/fatih/structtag"
+	"github.com/mcrawfo2/jennifer/jen"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const pkgSqldb = "cto-github.cisco.com/NFV-BU/go-msx/sqldb"
+
+func init() {
+	cmd := skel.AddTarget("generate-columns", "Generate typed column descriptors from model struct tags", GenerateColumns)
+	cmd.Args = cobra.MinimumNArgs(1)
+	cmd.Use = "generate-columns <model source file> [struct name...]"
+	cmd.Aliases = []string{"columns"}
+}
+
+// GenerateColumns is the CLI entry point for generating typed column descriptors
+func GenerateColumns(args []string) error {
+	sourceFile := args[0]
+	structNames := args[1:]
+
+	sourcePath := sourceFile
+	if !filepath.IsAbs(sourcePath) {
+		sourcePath = filepath.Join(skel.Config().TargetDirectory(), sourceFile)
+	}
+
+	generator, err := NewColumnsGenerator(sourcePath, structNames)
+	if err != nil {
+		return err
+	}
+
+	contents, err := generator.Render()
+	if err != nil {
+		return err
+	}
+
+	template := skel.Template{
+		Name:       "Columns",
+		DestFile:   strings.TrimSuffix(sourceFile, ".go") + "_columns.go",
+		SourceData: []byte(contents),
+		Format:     text.FileFormatGo,
+		Operation:  skel.OpAdd,
+	}
+
+	return template.Render(skel.NewEmptyRenderOptions())
+}
+
+// ModelColumn describes a single db-tagged field of a model struct
+type ModelColumn struct {
+	Field  string
+	Column string
+	Type   jen.Code
+}
+
+// ModelColumns describes the db-tagged fields of a model struct
+type ModelColumns struct {
+	Model   string
+	Columns []ModelColumn
+}
+
+// VariableName returns the name of the generated column descriptor variable,
+// matching the visibility of the model.
+func (m ModelColumns) VariableName() string {
+	return m.Model + "Columns"
+}
+
+type ColumnsGenerator struct {
+	Package string
+	Imports map[string]string
+	Models  []ModelColumns
+}
+
+func (g ColumnsGenerator) Render() (string, error) {
+	f := jen.NewFile(g.Package)
+	f.HeaderComment("Code generated by skel generate-columns. DO NOT EDIT.")
+	f.ImportName(pkgSqldb, "sqldb")
+	for name, importPath := range g.Imports {
+		if name == defaultImportName(importPath) {
+			f.ImportName(importPath, name)
+		} else {
+			f.ImportAlias(importPath, name)
+		}
+	}
+
+	for _, model := range g.Models {
+		var fields, values []jen.Code
+		for _, column := range model.Columns {
+			fields = append(fields,
+				jen.Id(column.Field).Qual(pkgSqldb, "Column").Types(column.Type))
+			values = append(values,
+				jen.Id(column.Field).Op(":").Qual(pkgSqldb, "NewColumn").Types(column.Type).Call(jen.Lit(column.Column)))
+		}
+
+		f.Commentf("%s describes the columns of %s", model.VariableName(), model.Model)
+		f.Var().Id(model.VariableName()).Op("=").Struct(fields...).Values(
+			jen.Custom(jen.Options{Close: "\n", Separator: ",", Multi: true}, values...))
+	}
+
+	var buf bytes.Buffer
+	if err := f.Render(&buf); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// modelStruct is a struct type declared in the model package, along with the
+// imports of the file declaring it.
+type modelStruct struct {
+	structType *ast.StructType
+	imports    map[string]string
+}
+
+func NewColumnsGenerator(sourceFile string, structNames []string) (*ColumnsGenerator, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, sourceFile, nil, parser.ParseComments)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse %q", sourceFile)
+	}
+
+	structs, err := packageStructs(fset, sourceFile, file)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool)
+	for _, structName := range structNames {
+		wanted[structName] = true
+	}
+
+	generator := &ColumnsGenerator{
+		Package: file.Name.Name,
+		Imports: fileImports(file),
+	}
+
+	resolver := columnResolver{
+		fset:      fset,
+		structs:   structs,
+		generator: generator,
+	}
+
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			_, ok := typeSpec.Type.(*ast.StructType)
+			if !ok || typeSpec.TypeParams != nil {
+				continue
+			}
+
+			if len(structNames) > 0 && !wanted[typeSpec.Name.Name] {
+				continue
+			}
+
+			columns, err := resolver.structColumns(typeSpec.Name.Name, "", nil)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(columns) == 0 {
+				continue
+			}
+
+			delete(wanted, typeSpec.Name.Name)
+			generator.Models = append(generator.Models, ModelColumns{
+				Model:   typeSpec.Name.Name,
+				Columns: columns,
+			})
+		}
+	}
+
+	for structName := range wanted {
+		return nil, errors.Errorf("Struct %q with db tags not found in %q", structName, sourceFile)
+	}
+
+	if len(generator.Models) == 0 {
+		return nil, errors.Errorf("No structs with db tags found in %q", sourceFile)
+	}
+
+	return generator, nil
+}
+
+// packageStructs collects the struct types declared in the package of the source file,
+// so that structs embedded from sibling files can be resolved.
+func packageStructs(fset *token.FileSet, sourceFile string, file *ast.File) (map[string]modelStruct, error) {
+	files := []*ast.File{file}
+
+	siblings, err := filepath.Glob(filepath.Join(filepath.Dir(sourceFile), "*.go"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to list package files for %q", sourceFile)
+	}
+
+	for _, sibling := range siblings {
+		if strings.HasSuffix(sibling, "_test.go") || filepath.Base(sibling) == filepath.Base(sourceFile) {
+			continue
+		}
+
+		siblingFile, err := parser.ParseFile(fset, sibling, nil, 0)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to parse %q", sibling)
+		}
+
+		if siblingFile.Name.Name != file.Name.Name {
+			continue
+		}
+
+		files = append(files, siblingFile)
+	}
+
+	results := make(map[string]modelStruct)
+	for _, packageFile := range files {
+		imports := fileImports(packageFile)
+		for _, decl := range packageFile.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				structType, ok := typeSpec.Type.(*ast.StructType)
+				if !ok || typeSpec.TypeParams != nil {
+					continue
+				}
+
+				results[typeSpec.Name.Name] = modelStruct{
+					structType: structType,
+					imports:    imports,
+				}
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// columnResolver flattens the db-tagged fields of a model struct, including those
+// promoted from embedded structs, following the goqu column mapping rules:
+//   - An untagged embedded struct contributes its columns directly
+//   - An embedded struct tagged `db:"name"` contributes its columns prefixed with "name."
+//   - An embedded struct tagged `db:"-"` is skipped
+//   - Fields declared by the outer struct shadow promoted fields of the same name
+type columnResolver struct {
+	fset      *token.FileSet
+	structs   map[string]modelStruct
+	generator *ColumnsGenerator
+}
+
+func (r columnResolver) structColumns(name string, prefix string, visiting []string) ([]ModelColumn, error) {
+	for _, visited := range visiting {
+		if visited == name {
+			return nil, errors.Errorf("Struct %q embeds itself via %s", name, strings.Join(visiting, "."))
+		}
+	}
+	visiting = append(visiting, name)
+
+	model, ok := r.structs[name]
+	if !ok {
+		return nil, errors.Errorf("Struct %q not found", name)
+	}
+
+	r.addImports(model.imports)
+
+	// Fields declared directly by this struct shadow promoted fields
+	declared := make(map[string]bool)
+	for _, field := range model.structType.Fields.List {
+		for _, fieldName := range field.Names {
+			declared[fieldName.Name] = true
+		}
+	}
+
+	var results []ModelColumn
+	seen := make(map[string]bool)
+	for _, field := range model.structType.Fields.List {
+		dbTag, err := fieldDbTag(field)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid tag on struct %q", name)
+		}
+
+		if len(field.Names) == 0 {
+			embedded, err := r.embeddedColumns(name, field, dbTag, prefix, visiting)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, column := range embedded {
+				if declared[column.Field] || seen[column.Field] {
+					continue
+				}
+				seen[column.Field] = true
+				results = append(results, column)
+			}
+			continue
+		}
+
+		if dbTag == "" || dbTag == "-" {
+			continue
+		}
+
+		fieldType, err := columnTypeCode(r.fset, field.Type, model.imports)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Unsupported type for field %s.%s", name, field.Names[0].Name)
+		}
+
+		for _, fieldName := range field.Names {
+			if !fieldName.IsExported() {
+				continue
+			}
+
+			seen[fieldName.Name] = true
+			results = append(results, ModelColumn{
+				Field:  fieldName.Name,
+				Column: prefix + dbTag,
+				Type:   fieldType,
+			})
+		}
+	}
+
+	return results, nil
+}
+
+func (r columnResolver) embeddedColumns(name string, field *ast.Field, dbTag string, prefix string, visiting []string) ([]ModelColumn, error) {
+	if dbTag == "-" {
+		return nil, nil
+	}
+
+	embeddedType := field.Type
+	if star, ok := embeddedType.(*ast.StarExpr); ok {
+		embeddedType = star.X
+	}
+
+	switch typed := embeddedType.(type) {
+	case *ast.Ident:
+		if _, ok := r.structs[typed.Name]; !ok {
+			// Embedded non-struct types behave as a field named after the type
+			if dbTag == "" || !typed.IsExported() {
+				return nil, nil
+			}
+
+			fieldType, err := columnTypeCode(r.fset, field.Type, r.structs[name].imports)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Unsupported type for field %s.%s", name, typed.Name)
+			}
+
+			return []ModelColumn{{
+				Field:  typed.Name,
+				Column: prefix + dbTag,
+				Type:   fieldType,
+			}}, nil
+		}
+
+		if dbTag != "" {
+			prefix = prefix + dbTag + "."
+		}
+
+		return r.structColumns(typed.Name, prefix, visiting)
+
+	case *ast.SelectorExpr:
+		var buf bytes.Buffer
+		_ = printer.Fprint(&buf, r.fset, embeddedType)
+		return nil, errors.Errorf("Embedded struct %s in %q is declared in another package; tag it with `db:\"-\"` to exclude it", buf.String(), name)
+	}
+
+	return nil, errors.Errorf("Unsupported embedded type %T in struct %q", embeddedType, name)
+}
+
+func (r columnResolver) addImports(imports map[string]string) {
+	for importName, importPath := range imports {
+		if _, ok := r.generator.Imports[importName]; !ok {
+			r.generator.Imports[importName] = importPath
+		}
+	}
+}
+
+// fieldDbTag returns the column name from the db tag of the field, if any
+func fieldDbTag(field *ast.Field) (string, error) {
+	if field.Tag == nil {
+		return "", nil
+	}
+
+	tagValue, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return "", err
+	}
+
+	tags, err := structtag.Parse(tagValue)
+	if err != nil {
+		return "", err
+	}
+
+	dbTag, err := tags.Get("db")
+	if err != nil {
+		return "", nil
+	}
+
+	return dbTag.Name, nil
+}
+
+func columnTypeCode(fset *token.FileSet, expr ast.Expr, imports map[string]string) (jen.Code, error) {
+	switch typed := expr.(type) {
+	case *ast.Ident:
+		return jen.Id(typed.Name), nil
+
+	case *ast.SelectorExpr:
+		pkg, ok := typed.X.(*ast.Ident)
+		if !ok {
+			break
+		}
+		importPath, ok := imports[pkg.Name]
+		if !ok {
+			return nil, errors.Errorf("Unknown package %q", pkg.Name)
+		}
+		return jen.Qual(importPath, typed.Sel.Name), nil
+
+	case *ast.StarExpr:
+		elem, err := columnTypeCode(fset, typed.X, imports)
+		if err != nil {
+			return nil, err
+		}
+		return jen.Op("*").Add(elem), nil
+
+	case *ast.ArrayType:
+		elem, err := columnTypeCode(fset, typed.Elt, imports)
+		if err != nil {
+			return nil, err
+		}
+		if typed.Len == nil {
+			return jen.Index().Add(elem), nil
+		}
+		var buf bytes.Buffer
+		if err = printer.Fprint(&buf, fset, typed.Len); err != nil {
+			return nil, err
+		}
+		return jen.Index(jen.Op(buf.String())).Add(elem), nil
+
+	case *ast.MapType:
+		key, err := columnTypeCode(fset, typed.Key, imports)
+		if err != nil {
+			return nil, err
+		}
+		value, err := columnTypeCode(fset, typed.Value, imports)
+		if err != nil {
+			return nil, err
+		}
+		return jen.Map(key).Add(value), nil
+
+	case *ast.InterfaceType:
+		if typed.Methods == nil || len(typed.Methods.List) == 0 {
+			return jen.Interface(), nil
+		}
+	}
+
+	return nil, errors.Errorf("Unsupported type expression %T", expr)
+}
+
+var importVersionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+// fileImports maps each package name in the file to its import path
+func fileImports(file *ast.File) map[string]string {
+	results := make(map[string]string)
+	for _, importSpec := range file.Imports {
+		importPath, _ := strconv.Unquote(importSpec.Path.Value)
+
+		var name string
+		if importSpec.Name != nil {
+			name = importSpec.Name.Name
+		} else {
+			name = defaultImportName(importPath)
+		}
+
+		if name == "_" || name == "." {
+			continue
+		}
+
+		results[name] = importPath
+	}
+	return results
+}
+
+func defaultImportName(importPath string) string {
+	parts := strings.Split(importPath, "/")
+	name := parts[len(parts)-1]
+	if importVersionSuffix.MatchString(name) && len(parts) > 1 {
+		name = parts[len(parts)-2]
+	}
+
+	name = path.Base(name)
+	if idx := strings.Index(name, ".v"); idx > 0 {
+		name = name[:idx]
+	}
+	name = strings.TrimPrefix(name, "go-")
+
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return -1
+	}, name)
+}
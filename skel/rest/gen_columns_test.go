@@ -0,0 +1,73 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package rest
+
+import (
+	"flag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var generateGolden bool
+
+func init() {
+	flag.BoolVar(&generateGolden, "skel.generate-golden", false,
+		"Create new golden expectation files")
+}
+
+func TestGenerateColumns(t *testing.T) {
+	fixtures := filepath.Join("testdata", "columns")
+
+	tests := []struct {
+		Name        string
+		StructNames []string
+		WantErr     string
+	}{
+		{
+			Name: "plain",
+		},
+		{
+			Name:        "embedded",
+			StructNames: []string{"Person"},
+		},
+		{
+			Name:    "external",
+			WantErr: `Embedded struct sync.Mutex in "Person" is declared in another package`,
+		},
+		{
+			Name:    "cycle",
+			WantErr: `Struct "Person" embeds itself via Person.Parent`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			sourceFile := filepath.Join(fixtures, "before", tt.Name, "model.go")
+			goldenFile := filepath.Join(fixtures, "golden", tt.Name+"-test.golden")
+
+			generator, err := NewColumnsGenerator(sourceFile, tt.StructNames)
+			if tt.WantErr != "" {
+				assert.ErrorContains(t, err, tt.WantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			actual, err := generator.Render()
+			require.NoError(t, err)
+
+			if generateGolden {
+				require.NoError(t, os.WriteFile(goldenFile, []byte(actual), 0644))
+				return
+			}
+
+			expected, err := os.ReadFile(goldenFile)
+			require.NoError(t, err, "Failed to locate golden result: %s", tt.Name)
+			assert.Equal(t, string(expected), actual)
+		})
+	}
+}
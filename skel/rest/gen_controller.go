@@ -372,6 +372,57 @@ func (g DomainControllerGenerator) createEndpointActionUpdateSnippet(operation O
 		})
 }
 
+func (g DomainControllerGenerator) createEndpointActionPatchSnippet(operation Operation) error {
+	portStructVariables, err := g.generatePortStructVariables(operation)
+	if err != nil {
+		return err
+	}
+
+	renderOptions := skel.NewEmptyRenderOptions()
+	renderOptions.AddVariables(portStructVariables)
+
+	template, err := skel.Template{
+		SourceData: []byte(`
+			// patchUpperCamelSingular creates an endpoint updating an existing UpperCamelSingular instance
+			// using a JSON Patch or JSON Merge Patch document.
+			func (c *lowerCamelSingularController) patchUpperCamelSingular() restops.EndpointBuilder {
+				${snippet.inputs}
+				${snippet.outputs}
+
+				return ${domain.style}.
+					NewPatchEndpointBuilder(pathSuffixUpperCamelSingularId).
+					WithId("${domain.style}.patchUpperCamelSingular").
+					WithDoc(new(openapi3.Operation).
+						WithSummary("Patch the specified Title Singular")).
+					WithPermissions(permissionManageUpperCamelPlural).
+					WithPatch(
+						func(ctx context.Context, inp interface{}) (interface{}, error) {
+							return c.lowerCamelSingularService.GetUpperCamelSingular(ctx, inp.(*inputs).UpperCamelSingularId)
+						}).
+					WithHandler(
+						func(ctx context.Context, inp *inputs) (out outputs, err error) {
+							out.Body, err = c.lowerCamelSingularService.UpdateUpperCamelSingular(ctx, inp.UpperCamelSingularId, inp.Body)
+							return
+						})
+			}
+			`),
+	}.RenderContents(renderOptions)
+	if err != nil {
+		return err
+	}
+
+	return g.AddNewText(
+		"Endpoints/Patch",
+		"patch",
+		template,
+		[]codegen.Import{
+			text.ImportRestOps,
+			g.importStyle(),
+			text.ImportOpenApi3,
+			text.ImportContext,
+		})
+}
+
 func (g DomainControllerGenerator) createEndpointActionDeleteSnippet(operation Operation) error {
 	portStructVariables, err := g.generatePortStructVariables(operation)
 	if err != nil {
@@ -780,6 +831,11 @@ func (g DomainControllerGenerator) Generate() error {
 			err = g.createEndpointActionCreateSnippet(operation)
 		case ActionUpdate:
 			err = g.createEndpointActionUpdateSnippet(operation)
+			if err == nil && g.Actions.Contains(ActionRetrieve) {
+				// Patch applies to the retrieved representation
+				operationMethods = append(operationMethods, "patchUpperCamelSingular")
+				err = g.createEndpointActionPatchSnippet(operation)
+			}
 		case ActionDelete:
 			err = g.createEndpointActionDeleteSnippet(operation)
 		}
@@ -841,6 +897,7 @@ func NewDomainControllerGenerator(spec Spec) ComponentGenerator {
 							"Retrieve",
 							"Create",
 							"Update",
+							"Patch",
 							"Delete",
 						),
 					},
@@ -443,6 +443,90 @@ func (g DomainControllerUnitTestGenerator) createEndpointActionUpdateTestSnippet
 		})
 }
 
+func (g DomainControllerUnitTestGenerator) createEndpointActionPatchTestSnippet(operation Operation) error {
+	return g.AddNewText(
+		"Tests/Patch",
+		"tests",
+		`
+			func Test_lowerCamelSingularController_patchUpperCamelSingular(t *testing.T) {
+				test := newUpperCamelSingularControllerTest().
+					WithSetup(func(p *testhelpers.FixtureCase[*controllertest.ControllerTest, lowerCamelSingularControllerTestFixture]) {
+						// Always point to the patch endpoint
+						p.Testable.
+							WithRequestMethod(http.MethodPatch).
+							WithRequestPath("/api/${domain.style}/lowerCamelPlural/{lowerCamelSingularId}", map[string]string{
+								"lowerCamelSingularId": p.Fixture.ApiData.UpperCamelSingularId.String(),
+							}).
+							WithRequestBodyJson(p.Fixture.ApiData.UpperCamelSingularUpdateRequest).
+							WithRequestHeader(restops.HeaderContentType, restops.MediaTypeMergePatch)
+					}).
+					WithNamedSetup("service.GetUpperCamelSingular", func(p *testhelpers.FixtureCase[*controllertest.ControllerTest, lowerCamelSingularControllerTestFixture]) {
+						p.Fixture.UpperCamelSingularService.EXPECT().
+							GetUpperCamelSingular(mock.MatchedBy(testhelpers.AnyContext), p.Fixture.ApiData.UpperCamelSingularId).
+							Return(p.Fixture.ApiData.UpperCamelSingularResponse, nil)
+					}).
+					WithNamedSetup("service.UpdateUpperCamelSingular", func(p *testhelpers.FixtureCase[*controllertest.ControllerTest, lowerCamelSingularControllerTestFixture]) {
+						p.Fixture.UpperCamelSingularService.EXPECT().
+							UpdateUpperCamelSingular(mock.MatchedBy(testhelpers.AnyContext), p.Fixture.ApiData.UpperCamelSingularId, p.Fixture.ApiData.UpperCamelSingularUpdateRequest).
+							Return(p.Fixture.ApiData.UpperCamelSingularResponse, nil)
+					}).
+					WithNamedSetup("response", func(p *testhelpers.FixtureCase[*controllertest.ControllerTest, lowerCamelSingularControllerTestFixture]) {
+						p.Testable.
+							WithResponsePredicate(webservicetest.ResponseHasStatus(http.StatusOK))
+					})
+			
+				tests := []struct {
+					name string
+					test testhelpers.Testable
+				}{
+					{
+						name: "Success",
+						test: test.Clone(),
+					},
+					{
+						name: "NotFound",
+						test: test.Clone().
+							WithNamedSetup("service.GetUpperCamelSingular", func(p *testhelpers.FixtureCase[*controllertest.ControllerTest, lowerCamelSingularControllerTestFixture]) {
+								p.Fixture.UpperCamelSingularService.EXPECT().
+									GetUpperCamelSingular(mock.MatchedBy(testhelpers.AnyContext), p.Fixture.ApiData.UpperCamelSingularId).
+									Return(UpperCamelSingularResponse{}, errors.Wrapf(repository.ErrNotFound, "UpperCamelSingular not found"))
+							}).
+							WithoutNamedSetup("service.UpdateUpperCamelSingular").
+							WithNamedSetup("response", func(p *testhelpers.FixtureCase[*controllertest.ControllerTest, lowerCamelSingularControllerTestFixture]) {
+								p.Testable.
+									WithResponsePredicate(webservicetest.ResponseHasStatus(http.StatusNotFound))
+							}),
+					},
+					{
+						name: "MissingPermission",
+						test: test.Clone().
+							WithoutNamedSetup("permissions").
+							WithoutNamedSetup("service.GetUpperCamelSingular").
+							WithoutNamedSetup("service.UpdateUpperCamelSingular").
+							WithNamedSetup("response", func(p *testhelpers.FixtureCase[*controllertest.ControllerTest, lowerCamelSingularControllerTestFixture]) {
+								p.Testable.
+									WithResponsePredicate(webservicetest.ResponseHasStatus(http.StatusForbidden))
+							}),
+					},
+				}
+			
+				for _, tt := range tests {
+					t.Run(tt.name, tt.test.Test)
+				}
+			}
+		`,
+		[]codegen.Import{
+			text.ImportTestHelpers,
+			text.ImportControllerTest,
+			text.ImportWebServiceTest,
+			text.ImportHttp,
+			text.ImportTestifyMock,
+			text.ImportTesting,
+			text.ImportRepository,
+			text.ImportErrors,
+		})
+}
+
 func (g DomainControllerUnitTestGenerator) createEndpointActionDeleteTestSnippet(operation Operation) error {
 	if g.Style == StyleV2 {
 		g.Variables["delete.success.code"] = "http.StatusOK"
@@ -556,6 +640,9 @@ func (g DomainControllerUnitTestGenerator) Generate() error {
 			err = g.createEndpointActionCreateTestSnippet(operation)
 		case ActionUpdate:
 			err = g.createEndpointActionUpdateTestSnippet(operation)
+			if err == nil && g.Actions.Contains(ActionRetrieve) {
+				err = g.createEndpointActionPatchTestSnippet(operation)
+			}
 		case ActionDelete:
 			err = g.createEndpointActionDeleteTestSnippet(operation)
 		}
@@ -622,6 +709,7 @@ func NewDomainControllerUnitTestGenerator(spec Spec) ComponentGenerator {
 							"Retrieve",
 							"Create",
 							"Update",
+							"Patch",
 							"Delete",
 						),
 					},
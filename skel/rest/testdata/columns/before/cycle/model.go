@@ -0,0 +1,11 @@
+package persons
+
+type Person struct {
+	*Parent
+	Name string `db:"name"`
+}
+
+type Parent struct {
+	*Person
+	Relation string `db:"relation"`
+}
@@ -0,0 +1,23 @@
+package persons
+
+import (
+	"github.com/gocql/gocql"
+)
+
+type Address struct {
+	Street string `db:"street"`
+	City   string `db:"city"`
+}
+
+type Person struct {
+	Audited
+	*Named
+	Address `db:"work"`
+	Hidden  `db:"-"`
+	Id      gocql.UUID `db:"id"`
+	Version int        `db:"person_version"`
+}
+
+type Hidden struct {
+	Secret string `db:"secret"`
+}
@@ -0,0 +1,17 @@
+package persons
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+type Audited struct {
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+	Version   int       `db:"version"`
+}
+
+type Named struct {
+	Name    string          `db:"name"`
+	Balance decimal.Decimal `db:"balance"`
+}
@@ -0,0 +1,11 @@
+package persons
+
+import (
+	"github.com/gocql/gocql"
+	"sync"
+)
+
+type Person struct {
+	sync.Mutex
+	Id gocql.UUID `db:"id"`
+}
@@ -0,0 +1,16 @@
+package persons
+
+import (
+	"github.com/gocql/gocql"
+	"time"
+)
+
+type Person struct {
+	Id       gocql.UUID `db:"id"`
+	Name     string     `db:"name"`
+	Birthday *time.Time `db:"birthday"`
+	Tags     []string   `db:"tags"`
+	internal string     `db:"internal"`
+	Ignored  string     `db:"-"`
+	Untagged string
+}
@@ -0,0 +1,333 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package sqldb
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"fmt"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+type ChangeOperation string
+
+const (
+	ChangeOperationInsert ChangeOperation = "insert"
+	ChangeOperationUpdate ChangeOperation = "update"
+	ChangeOperationUpsert ChangeOperation = "upsert"
+	ChangeOperationDelete ChangeOperation = "delete"
+)
+
+var ErrChangePublisherNotRegistered = errors.New("Change publisher not registered")
+
+// ChangeEvent describes a single row-level change performed by a repository.
+type ChangeEvent struct {
+	Table     string
+	Operation ChangeOperation
+	// Keys contains the key column values identifying the affected row(s).
+	Keys map[string]any
+	// Columns lists the columns written by an insert, update or upsert.
+	Columns   []string
+	Tenant    types.UUID
+	Timestamp time.Time
+}
+
+type ChangePublisher interface {
+	PublishChanges(ctx context.Context, events []ChangeEvent) error
+}
+
+var changePublisher ChangePublisher
+var changePublisherMtx sync.Mutex
+
+// RegisterChangePublisher sets the publisher used to emit change events
+// from repositories with change capture enabled.
+func RegisterChangePublisher(publisher ChangePublisher) {
+	changePublisherMtx.Lock()
+	defer changePublisherMtx.Unlock()
+	changePublisher = publisher
+}
+
+func ContextChangePublisher() types.ContextKeyAccessor[ChangePublisher] {
+	return types.NewContextKeyAccessor[ChangePublisher](contextKeyChangePublisher)
+}
+
+func changePublisherFromContext(ctx context.Context) ChangePublisher {
+	if publisher := ContextChangePublisher().Get(ctx); publisher != nil {
+		return publisher
+	}
+
+	changePublisherMtx.Lock()
+	defer changePublisherMtx.Unlock()
+	return changePublisher
+}
+
+// ValidateChangePublisher ensures a change publisher is available to repositories
+// with change capture enabled.
+func ValidateChangePublisher(ctx context.Context) error {
+	if changePublisherFromContext(ctx) == nil {
+		return errors.Wrap(ErrChangePublisherNotRegistered, "Change capture requires a change publisher (enable sqldb.change-stream)")
+	}
+	return nil
+}
+
+func publishChanges(ctx context.Context, events []ChangeEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	publisher := changePublisherFromContext(ctx)
+	if publisher == nil {
+		return ErrChangePublisherNotRegistered
+	}
+
+	return publisher.PublishChanges(ctx, events)
+}
+
+// captureChanges records the events for publication.  Within a transaction, the events
+// are written to the change outbox as part of the transaction, and published once it
+// commits; events which fail to publish are retained in the outbox for RelayChanges.
+// Outside of a transaction, the write has already been committed, so the events are
+// published immediately, and publishing failures are logged rather than reported.
+func captureChanges(ctx context.Context, events []ChangeEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	if !InTransaction(ctx) {
+		if err := publishChanges(ctx, events); err != nil {
+			logger.
+				WithContext(ctx).
+				WithError(err).
+				WithField("table", events[0].Table).
+				Errorf("Failed to publish %d committed change event(s)", len(events))
+		}
+		return nil
+	}
+
+	ids, err := writeChangeOutbox(ctx, events)
+	if err != nil {
+		return err
+	}
+
+	return OnAfterCommit(ctx, func(ctx context.Context) error {
+		relayCommittedChanges(ctx, ids, events)
+		return nil
+	})
+}
+
+type ChangeCaptureOptions struct {
+	// KeyColumns identify the columns copied into the event keys for inserts and upserts.
+	KeyColumns []string
+	// TenantColumn identifies the column copied into the event tenant.
+	TenantColumn string
+}
+
+// ChangeCapturingGoquRepository decorates a GoquRepositoryApi to emit a ChangeEvent
+// for each successful insert, update, upsert and delete.  Within a transaction
+// started by TransactionDecorator, events are recorded in the change outbox and
+// published after commit, and discarded on rollback.
+type ChangeCapturingGoquRepository struct {
+	GoquRepositoryApi
+	options ChangeCaptureOptions
+}
+
+func (c *ChangeCapturingGoquRepository) ExecuteInsert(ctx context.Context, ds *goqu.InsertDataset) error {
+	if err := c.GoquRepositoryApi.ExecuteInsert(ctx, ds); err != nil {
+		return err
+	}
+
+	return c.captureInsert(ctx, ChangeOperationInsert, ds)
+}
+
+func (c *ChangeCapturingGoquRepository) ExecuteUpsert(ctx context.Context, ds *goqu.InsertDataset) error {
+	if err := c.GoquRepositoryApi.ExecuteUpsert(ctx, ds); err != nil {
+		return err
+	}
+
+	return c.captureInsert(ctx, ChangeOperationUpsert, ds)
+}
+
+func (c *ChangeCapturingGoquRepository) ExecuteUpdate(ctx context.Context, ds *goqu.UpdateDataset) error {
+	if err := c.GoquRepositoryApi.ExecuteUpdate(ctx, ds); err != nil {
+		return err
+	}
+
+	clauses := ds.GetClauses()
+
+	updates, err := exp.NewUpdateExpressions(clauses.SetValues())
+	if err != nil {
+		return err
+	}
+
+	keys := whereKeys(clauses.Where())
+	event := c.newEvent(expressionTable(clauses.Table()), ChangeOperationUpdate, keys)
+	for _, update := range updates {
+		column := fmt.Sprint(update.Col().GetCol())
+		event.Columns = append(event.Columns, column)
+		if column == c.options.TenantColumn {
+			event.Tenant = changeTenant(update.Val())
+		}
+	}
+
+	return captureChanges(ctx, []ChangeEvent{event})
+}
+
+func (c *ChangeCapturingGoquRepository) ExecuteDelete(ctx context.Context, ds *goqu.DeleteDataset) error {
+	if err := c.GoquRepositoryApi.ExecuteDelete(ctx, ds); err != nil {
+		return err
+	}
+
+	clauses := ds.GetClauses()
+	keys := whereKeys(clauses.Where())
+	event := c.newEvent(expressionTable(clauses.From()), ChangeOperationDelete, keys)
+	return captureChanges(ctx, []ChangeEvent{event})
+}
+
+func (c *ChangeCapturingGoquRepository) captureInsert(ctx context.Context, operation ChangeOperation, ds *goqu.InsertDataset) error {
+	clauses := ds.GetClauses()
+	table := expressionTable(clauses.Into())
+
+	cols, vals := clauses.Cols(), clauses.Vals()
+	if clauses.Rows() != nil {
+		insert, err := exp.NewInsertExpression(clauses.Rows()...)
+		if err != nil {
+			return err
+		}
+		cols, vals = insert.Cols(), insert.Vals()
+	}
+
+	var columns []string
+	if cols != nil {
+		for _, col := range cols.Columns() {
+			columns = append(columns, fmt.Sprint(expressionColumn(col)))
+		}
+	}
+
+	var events []ChangeEvent
+	for _, row := range vals {
+		event := c.newEvent(table, operation, make(map[string]any))
+		event.Columns = columns
+		for n, column := range columns {
+			if n >= len(row) {
+				break
+			}
+			if types.ComparableSlice[string](c.options.KeyColumns).Contains(column) {
+				event.Keys[column] = row[n]
+			}
+			if column == c.options.TenantColumn {
+				event.Tenant = changeTenant(row[n])
+			}
+		}
+		events = append(events, event)
+	}
+
+	return captureChanges(ctx, events)
+}
+
+func (c *ChangeCapturingGoquRepository) newEvent(table string, operation ChangeOperation, keys map[string]any) ChangeEvent {
+	event := ChangeEvent{
+		Table:     table,
+		Operation: operation,
+		Keys:      keys,
+		Timestamp: time.Now().UTC(),
+	}
+
+	if c.options.TenantColumn != "" {
+		if tenant, ok := keys[c.options.TenantColumn]; ok {
+			event.Tenant = changeTenant(tenant)
+		}
+	}
+
+	return event
+}
+
+// CaptureChanges decorates the supplied repository to emit change events.
+// Callers are expected to verify a publisher is available using
+// ValidateChangePublisher when the repository is created.
+func CaptureChanges(api GoquRepositoryApi, options ChangeCaptureOptions) GoquRepositoryApi {
+	return &ChangeCapturingGoquRepository{
+		GoquRepositoryApi: api,
+		options:           options,
+	}
+}
+
+func expressionTable(expression exp.Expression) string {
+	switch e := expression.(type) {
+	case exp.IdentifierExpression:
+		if table := e.GetTable(); table != "" {
+			return table
+		}
+		// Unqualified table names are parsed as a bare column identifier
+		if col, ok := e.GetCol().(string); ok {
+			return col
+		}
+	case exp.AliasedExpression:
+		return expressionTable(e.Aliased())
+	}
+	return ""
+}
+
+func expressionColumn(expression exp.Expression) any {
+	if ident, ok := expression.(exp.IdentifierExpression); ok {
+		return ident.GetCol()
+	}
+	return expression
+}
+
+// whereKeys extracts the equality predicates from a conjunctive where clause.
+func whereKeys(where exp.ExpressionList) map[string]any {
+	keys := make(map[string]any)
+	if where != nil {
+		collectWhereKeys(where, keys)
+	}
+	return keys
+}
+
+func collectWhereKeys(expression exp.Expression, keys map[string]any) {
+	switch e := expression.(type) {
+	case exp.ExpressionList:
+		if e.Type() != exp.AndType && len(e.Expressions()) > 1 {
+			return
+		}
+		for _, child := range e.Expressions() {
+			collectWhereKeys(child, keys)
+		}
+
+	case exp.Ex:
+		for column, value := range e {
+			switch value.(type) {
+			case exp.Op, exp.Expression:
+				continue
+			}
+			keys[column] = value
+		}
+
+	case exp.BooleanExpression:
+		if e.Op() != exp.EqOp {
+			return
+		}
+		if ident, ok := e.LHS().(exp.IdentifierExpression); ok {
+			keys[fmt.Sprint(ident.GetCol())] = e.RHS()
+		}
+	}
+}
+
+func changeTenant(value any) types.UUID {
+	switch tenantId := value.(type) {
+	case types.UUID:
+		return tenantId
+	case string:
+		result, _ := types.ParseUUID(tenantId)
+		return result
+	case fmt.Stringer:
+		result, _ := types.ParseUUID(tenantId.String())
+		return result
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package sqldb
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"encoding/json"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// ChangeOutboxTable stores change events captured within a transaction until they
+// have been published.
+const ChangeOutboxTable = "sqldb_change_outbox"
+
+const changeOutboxRelayLimit = 100
+
+const (
+	columnChangeOutboxId      = "id"
+	columnChangeOutboxCreated = "created_at"
+)
+
+var changeOutboxDdl = []string{
+	`CREATE TABLE IF NOT EXISTS ` + ChangeOutboxTable + ` (
+		id VARCHAR(36) NOT NULL PRIMARY KEY,
+		event TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS ` + ChangeOutboxTable + `_created_idx
+		ON ` + ChangeOutboxTable + ` (created_at)`,
+}
+
+type changeOutboxEntry struct {
+	Id      string    `db:"id"`
+	Event   string    `db:"event"`
+	Created time.Time `db:"created_at"`
+}
+
+// changeOutboxEvent is the serialized form of a ChangeEvent within the change outbox.
+type changeOutboxEvent struct {
+	Table     string          `json:"table"`
+	Operation ChangeOperation `json:"operation"`
+	Keys      map[string]any  `json:"keys,omitempty"`
+	Columns   []string        `json:"columns,omitempty"`
+	Tenant    string          `json:"tenant,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+func newChangeOutboxEvent(event ChangeEvent) changeOutboxEvent {
+	result := changeOutboxEvent{
+		Table:     event.Table,
+		Operation: event.Operation,
+		Keys:      event.Keys,
+		Columns:   event.Columns,
+		Timestamp: event.Timestamp,
+	}
+
+	if event.Tenant != nil {
+		result.Tenant = event.Tenant.String()
+	}
+
+	return result
+}
+
+func (e changeOutboxEvent) ChangeEvent() (ChangeEvent, error) {
+	result := ChangeEvent{
+		Table:     e.Table,
+		Operation: e.Operation,
+		Keys:      e.Keys,
+		Columns:   e.Columns,
+		Timestamp: e.Timestamp,
+	}
+
+	if e.Tenant != "" {
+		tenant, err := types.ParseUUID(e.Tenant)
+		if err != nil {
+			return result, err
+		}
+		result.Tenant = tenant
+	}
+
+	return result, nil
+}
+
+// CreateChangeOutbox creates the change outbox table if it does not already exist.
+func CreateChangeOutbox(ctx context.Context) error {
+	return WithSqlExecutor(ctx, func(ctx context.Context, sqlExecutor SqlExecutor) error {
+		for _, statement := range changeOutboxDdl {
+			if _, err := sqlExecutor.ExecContext(ctx, statement); err != nil {
+				return errors.Wrap(err, "Failed to create change outbox")
+			}
+		}
+		return nil
+	})
+}
+
+// writeChangeOutbox records the events in the change outbox using the executor
+// of the context, and returns the identifiers of the new outbox entries.
+func writeChangeOutbox(ctx context.Context, events []ChangeEvent) ([]string, error) {
+	repo, err := NewGoquRepository(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	var rows []any
+	now := time.Now().UTC()
+	for _, event := range events {
+		data, err := json.Marshal(newChangeOutboxEvent(event))
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to serialize change event")
+		}
+
+		id := types.MustNewUUID().String()
+		ids = append(ids, id)
+		rows = append(rows, changeOutboxEntry{
+			Id:      id,
+			Event:   string(data),
+			Created: now,
+		})
+	}
+
+	if err = repo.ExecuteInsert(ctx, repo.Insert(ChangeOutboxTable).Rows(rows...)); err != nil {
+		return nil, errors.Wrap(err, "Failed to write change outbox")
+	}
+
+	return ids, nil
+}
+
+// deleteChangeOutbox removes published entries from the change outbox.
+func deleteChangeOutbox(ctx context.Context, ids []string) error {
+	repo, err := NewGoquRepository(ctx)
+	if err != nil {
+		return err
+	}
+
+	ds := repo.Delete(ChangeOutboxTable).Where(goqu.C(columnChangeOutboxId).In(ids))
+	return repo.ExecuteDelete(ctx, ds)
+}
+
+// relayCommittedChanges publishes the events captured by a committed transaction,
+// and removes them from the change outbox.  Events which fail to publish remain
+// in the outbox to be published by RelayChanges.
+func relayCommittedChanges(ctx context.Context, ids []string, events []ChangeEvent) {
+	if err := publishChanges(ctx, events); err != nil {
+		logger.
+			WithContext(ctx).
+			WithError(err).
+			WithField("table", events[0].Table).
+			Warnf("Failed to publish %d committed change event(s): retaining in outbox", len(events))
+		return
+	}
+
+	if err := deleteChangeOutbox(ctx, ids); err != nil {
+		logger.
+			WithContext(ctx).
+			WithError(err).
+			Errorf("Failed to remove %d published change event(s) from outbox", len(ids))
+	}
+}
+
+// RelayChanges publishes the change events remaining in the outbox which were
+// recorded at least minAge ago, removing each entry once it has been published.
+// Returns the number of events published.
+func RelayChanges(ctx context.Context, minAge time.Duration) (count int, err error) {
+	if changePublisherFromContext(ctx) == nil {
+		return 0, ErrChangePublisherNotRegistered
+	}
+
+	repo, err := NewGoquRepository(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var entries []changeOutboxEntry
+	ds := repo.Select(ChangeOutboxTable).
+		Where(goqu.C(columnChangeOutboxCreated).Lte(time.Now().UTC().Add(-minAge))).
+		Order(goqu.C(columnChangeOutboxCreated).Asc()).
+		Limit(changeOutboxRelayLimit)
+	if err = repo.ExecuteSelect(ctx, ds, &entries); err != nil {
+		return 0, errors.Wrap(err, "Failed to read change outbox")
+	}
+
+	for _, entry := range entries {
+		var outboxEvent changeOutboxEvent
+		if err = json.Unmarshal([]byte(entry.Event), &outboxEvent); err != nil {
+			return count, errors.Wrapf(err, "Failed to parse change outbox entry %q", entry.Id)
+		}
+
+		var event ChangeEvent
+		if event, err = outboxEvent.ChangeEvent(); err != nil {
+			return count, errors.Wrapf(err, "Failed to parse change outbox entry %q", entry.Id)
+		}
+
+		if err = publishChanges(ctx, []ChangeEvent{event}); err != nil {
+			return count, err
+		}
+
+		if err = deleteChangeOutbox(ctx, []string{entry.Id}); err != nil {
+			return count, errors.Wrap(err, "Failed to remove published change from outbox")
+		}
+
+		count++
+	}
+
+	return count, nil
+}
@@ -0,0 +1,287 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package sqldb
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"database/sql"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+type recordingChangePublisher struct {
+	events []ChangeEvent
+	err    error
+}
+
+func (p *recordingChangePublisher) PublishChanges(_ context.Context, events []ChangeEvent) error {
+	p.events = append(p.events, events...)
+	return p.err
+}
+
+func TestChangeCapturingGoquRepository(t *testing.T) {
+	id := uuid.MustParse(mockId)
+
+	tests := []struct {
+		name      string
+		execErr   error
+		call      func(ctx context.Context, repo TypedRepositoryApi[Person]) error
+		wantEvent *ChangeEvent
+		wantErr   bool
+	}{
+		{
+			name: "Insert",
+			call: func(ctx context.Context, repo TypedRepositoryApi[Person]) error {
+				return repo.Insert(ctx, Person{Id: id, Name: mockName})
+			},
+			wantEvent: &ChangeEvent{
+				Table:     tableNamePerson,
+				Operation: ChangeOperationInsert,
+				Keys:      map[string]any{columnId: id},
+				Columns:   []string{columnId, "name"},
+			},
+		},
+		{
+			name: "Upsert",
+			call: func(ctx context.Context, repo TypedRepositoryApi[Person]) error {
+				return repo.Upsert(ctx, Person{Id: id, Name: mockName})
+			},
+			wantEvent: &ChangeEvent{
+				Table:     tableNamePerson,
+				Operation: ChangeOperationUpsert,
+				Keys:      map[string]any{columnId: id},
+				Columns:   []string{columnId, "name"},
+			},
+		},
+		{
+			name: "Update",
+			call: func(ctx context.Context, repo TypedRepositoryApi[Person]) error {
+				return repo.Update(ctx, And(map[string]any{columnId: id}), Person{Id: id, Name: mockName})
+			},
+			wantEvent: &ChangeEvent{
+				Table:     tableNamePerson,
+				Operation: ChangeOperationUpdate,
+				Keys:      map[string]any{columnId: id},
+				Columns:   []string{columnId, "name"},
+			},
+		},
+		{
+			name: "DeleteOne",
+			call: func(ctx context.Context, repo TypedRepositoryApi[Person]) error {
+				return repo.DeleteOne(ctx, KeysOption{columnId: id})
+			},
+			wantEvent: &ChangeEvent{
+				Table:     tableNamePerson,
+				Operation: ChangeOperationDelete,
+				Keys:      map[string]any{columnId: id},
+			},
+		},
+		{
+			name:    "Failed",
+			execErr: errors.New("some error"),
+			call: func(ctx context.Context, repo TypedRepositoryApi[Person]) error {
+				return repo.DeleteOne(ctx, KeysOption{columnId: id})
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, _, _ := newSqlMockDependencies("sqlite3")
+
+			mockSqlRepositoryApi := NewMockSqlRepositoryApi(t)
+			ctx = ContextSqlRepository().Set(ctx, mockSqlRepositoryApi)
+
+			mockSqlRepositoryApi.EXPECT().
+				SqlExecute(mock.Anything, mock.Anything, mock.Anything).
+				Return(test.execErr)
+
+			publisher := new(recordingChangePublisher)
+			ctx = ContextChangePublisher().Set(ctx, publisher)
+
+			repo, err := NewTypedRepository[Person](ctx, tableNamePerson, WithChangeCapture(columnId))
+			assert.NoError(t, err)
+
+			err = test.call(ctx, repo)
+			if test.wantErr {
+				assert.Error(t, err)
+				assert.Empty(t, publisher.events)
+				return
+			}
+
+			assert.NoError(t, err)
+			if assert.Len(t, publisher.events, 1) {
+				event := publisher.events[0]
+				assert.False(t, event.Timestamp.IsZero())
+				event.Timestamp = test.wantEvent.Timestamp
+				assert.Equal(t, *test.wantEvent, event)
+			}
+		})
+	}
+}
+
+func TestChangeCapture_Tenant(t *testing.T) {
+	id := uuid.MustParse(mockId)
+	tenantId := uuid.MustParse("c5ca1f36-8b19-4b05-a8b1-2ff3f3d8f5c8")
+
+	ctx, _, _ := newSqlMockDependencies("sqlite3")
+
+	mockSqlRepositoryApi := NewMockSqlRepositoryApi(t)
+	ctx = ContextSqlRepository().Set(ctx, mockSqlRepositoryApi)
+
+	mockSqlRepositoryApi.EXPECT().
+		SqlExecute(mock.Anything, mock.Anything, mock.Anything).
+		Return(nil)
+
+	publisher := new(recordingChangePublisher)
+	ctx = ContextChangePublisher().Set(ctx, publisher)
+
+	repo, err := NewTypedRepository[TenantPerson](ctx, tableNameTenantPerson,
+		WithTenantColumn(columnTenantId),
+		WithChangeCapture(columnId))
+	assert.NoError(t, err)
+
+	err = WithTenantBypass(ctx, "test", func(ctx context.Context) error {
+		return repo.Insert(ctx, TenantPerson{Id: id, Name: mockName, TenantId: tenantId})
+	})
+	assert.NoError(t, err)
+
+	if assert.Len(t, publisher.events, 1) {
+		assert.Equal(t, tenantId.String(), publisher.events[0].Tenant.String())
+	}
+}
+
+func newChangeOutboxTestContext(t *testing.T, publisher ChangePublisher) (context.Context, *sql.DB) {
+	ctx, db := newTransactionTestContext(t, "persons_changes")
+	ctx = ContextChangePublisher().Set(ctx, publisher)
+
+	_, err := db.Exec("DROP TABLE IF EXISTS " + ChangeOutboxTable)
+	assert.NoError(t, err)
+	assert.NoError(t, CreateChangeOutbox(ctx))
+
+	t.Cleanup(func() {
+		_, _ = db.Exec("DROP TABLE " + ChangeOutboxTable)
+	})
+
+	return ctx, db
+}
+
+func TestCaptureChanges_Transaction(t *testing.T) {
+	publisher := new(recordingChangePublisher)
+	ctx, db := newChangeOutboxTestContext(t, publisher)
+
+	event := ChangeEvent{Table: tableNamePerson, Operation: ChangeOperationDelete}
+	err := TransactionDecorator(func(ctx context.Context) error {
+		if err := captureChanges(ctx, []ChangeEvent{event}); err != nil {
+			return err
+		}
+		assert.Empty(t, publisher.events)
+		return nil
+	})(ctx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []ChangeEvent{event}, publisher.events)
+	assert.Equal(t, 0, countPersons(t, db, ChangeOutboxTable))
+}
+
+func TestCaptureChanges_Rollback(t *testing.T) {
+	publisher := new(recordingChangePublisher)
+	ctx, db := newChangeOutboxTestContext(t, publisher)
+
+	event := ChangeEvent{Table: tableNamePerson, Operation: ChangeOperationDelete}
+	err := TransactionDecorator(func(ctx context.Context) error {
+		if err := captureChanges(ctx, []ChangeEvent{event}); err != nil {
+			return err
+		}
+		return errors.New("write failed")
+	})(ctx)
+	assert.EqualError(t, err, "write failed")
+
+	assert.Empty(t, publisher.events)
+	assert.Equal(t, 0, countPersons(t, db, ChangeOutboxTable))
+}
+
+func TestCaptureChanges_PublishFailed(t *testing.T) {
+	publisher := &recordingChangePublisher{err: errors.New("broker unavailable")}
+	ctx, db := newChangeOutboxTestContext(t, publisher)
+
+	event := ChangeEvent{
+		Table:     tableNamePerson,
+		Operation: ChangeOperationDelete,
+		Keys:      map[string]any{columnId: mockId},
+		Tenant:    types.MustParseUUID("c5ca1f36-8b19-4b05-a8b1-2ff3f3d8f5c8"),
+		Timestamp: time.Now().UTC(),
+	}
+
+	// The changes are already committed, so the failure is not reported to the writer
+	err := TransactionDecorator(func(ctx context.Context) error {
+		return captureChanges(ctx, []ChangeEvent{event})
+	})(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, publisher.events, 1)
+
+	// The event is retained until relayed
+	assert.Equal(t, 1, countPersons(t, db, ChangeOutboxTable))
+
+	count, err := RelayChanges(ctx, 0)
+	assert.Error(t, err)
+	assert.Equal(t, 0, count)
+	assert.Equal(t, 1, countPersons(t, db, ChangeOutboxTable))
+
+	publisher.err = nil
+	publisher.events = nil
+	count, err = RelayChanges(ctx, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, 0, countPersons(t, db, ChangeOutboxTable))
+	if assert.Len(t, publisher.events, 1) {
+		assert.Equal(t, event.Keys, publisher.events[0].Keys)
+		assert.Equal(t, event.Tenant, publisher.events[0].Tenant)
+		assert.True(t, event.Timestamp.Equal(publisher.events[0].Timestamp))
+	}
+}
+
+func TestCaptureChanges_NoTransactionPublishFailed(t *testing.T) {
+	publisher := &recordingChangePublisher{err: errors.New("broker unavailable")}
+	ctx := ContextChangePublisher().Set(context.Background(), publisher)
+
+	event := ChangeEvent{Table: tableNamePerson, Operation: ChangeOperationDelete}
+	err := captureChanges(ctx, []ChangeEvent{event})
+	assert.NoError(t, err)
+	assert.Equal(t, []ChangeEvent{event}, publisher.events)
+}
+
+func TestRelayChanges_NotRegistered(t *testing.T) {
+	ctx, _ := newTransactionTestContext(t, "persons_changes")
+
+	_, err := RelayChanges(ctx, 0)
+	assert.ErrorIs(t, err, ErrChangePublisherNotRegistered)
+}
+
+func TestPublishChanges_NotRegistered(t *testing.T) {
+	ctx, _, _ := newSqlMockDependencies("sqlite3")
+
+	_, err := NewTypedRepository[Person](ctx, tableNamePerson, WithChangeCapture(columnId))
+	assert.ErrorIs(t, err, ErrChangePublisherNotRegistered)
+
+	err = ValidateChangePublisher(ctx)
+	assert.ErrorIs(t, err, ErrChangePublisherNotRegistered)
+
+	err = publishChanges(ctx, []ChangeEvent{{Table: tableNamePerson}})
+	assert.ErrorIs(t, err, ErrChangePublisherNotRegistered)
+
+	err = publishChanges(ctx, nil)
+	assert.NoError(t, err)
+
+	// Writes are not failed after the fact
+	err = captureChanges(ctx, []ChangeEvent{{Table: tableNamePerson}})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,10 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+// Package changestream publishes sqldb change events to a stream channel.
+package changestream
+
+import "cto-github.cisco.com/NFV-BU/go-msx/log"
+
+var logger = log.NewPackageLogger()
@@ -0,0 +1,156 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package changestream
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/streamops"
+	"cto-github.cisco.com/NFV-BU/go-msx/schema/asyncapi"
+	"cto-github.cisco.com/NFV-BU/go-msx/sqldb"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"time"
+)
+
+const (
+	configRootChangeStream = "sqldb.change-stream"
+
+	messageNameChange = "SqlChange"
+	eventTypeChange   = "sqlChange"
+)
+
+type ChangeStreamConfig struct {
+	Enabled       bool          `config:"default=false"`
+	Channel       string        `config:"default=SQLDB_CHANGES"`
+	RelayInterval time.Duration `config:"default=30s"`
+	RelayDelay    time.Duration `config:"default=1m"`
+}
+
+func init() {
+	config.RegisterSchema[ChangeStreamConfig](configRootChangeStream)
+}
+
+func NewChangeStreamConfig(ctx context.Context) (*ChangeStreamConfig, error) {
+	var cfg ChangeStreamConfig
+	if err := config.FromContext(ctx).Populate(&cfg, configRootChangeStream); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// ChangeMessage is the published payload describing a single row-level change.
+type ChangeMessage struct {
+	Table     string         `json:"table" description:"Name of the changed table"`
+	Operation string         `json:"operation" enum:"insert,update,upsert,delete" description:"Write operation performed"`
+	Keys      map[string]any `json:"keys,omitempty" description:"Key column values identifying the changed row(s)"`
+	Columns   []string       `json:"columns,omitempty" description:"Columns written by the operation"`
+	Tenant    *types.UUID    `json:"tenant,omitempty" description:"Tenant owning the changed row(s)"`
+	Timestamp types.Time     `json:"timestamp" description:"Time of the change"`
+}
+
+func NewChangeMessage(event sqldb.ChangeEvent) ChangeMessage {
+	result := ChangeMessage{
+		Table:     event.Table,
+		Operation: string(event.Operation),
+		Keys:      event.Keys,
+		Columns:   event.Columns,
+		Timestamp: types.NewTime(event.Timestamp),
+	}
+
+	if event.Tenant != nil {
+		result.Tenant = &event.Tenant
+	}
+
+	return result
+}
+
+type changeOutput struct {
+	EventType string        `out:"header=eventType" const:"sqlChange"`
+	Table     string        `out:"header=table"`
+	Payload   ChangeMessage `out:"body"`
+}
+
+// ChangePublisher publishes sqldb change events to the configured stream channel.
+type ChangePublisher struct {
+	messagePublisher *streamops.MessagePublisher
+}
+
+func (p *ChangePublisher) PublishChanges(ctx context.Context, events []sqldb.ChangeEvent) error {
+	for _, event := range events {
+		err := p.messagePublisher.Publish(ctx, changeOutput{
+			EventType: eventTypeChange,
+			Table:     event.Table,
+			Payload:   NewChangeMessage(event),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func NewChangePublisher(ctx context.Context, channelName string) (*ChangePublisher, error) {
+	ch, err := streamops.NewChannel(ctx, channelName)
+	if err != nil {
+		return nil, err
+	}
+
+	ch.WithDocumentor(new(asyncapi.ChannelDocumentor).
+		WithChannelItem(new(asyncapi.ChannelItem).
+			WithDescription("Row-level changes committed by sqldb repositories with change capture enabled.")))
+
+	cp, err := streamops.NewChannelPublisher(ctx, ch, "publishSqlChanges")
+	if err != nil {
+		return nil, err
+	}
+
+	cp.AddDocumentor(new(asyncapi.ChannelPublisherDocumentor).
+		WithOperation(new(asyncapi.Operation).
+			WithID("publishSqlChanges").
+			WithSummary("Publishes committed sqldb row changes.")))
+
+	mpb, err := streamops.NewMessagePublisherBuilder(ctx, cp, messageNameChange, changeOutput{})
+	if err != nil {
+		return nil, err
+	}
+
+	mp, err := mpb.
+		WithDocumentor(new(asyncapi.MessagePublisherDocumentor).
+			WithMessage(new(asyncapi.Message).
+				WithTitle("SQL Change").
+				WithSummary("Notifies subscribers of a committed row-level change.").
+				WithTags(*asyncapi.NewTag(eventTypeChange)))).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChangePublisher{
+		messagePublisher: mp,
+	}, nil
+}
+
+// Register creates the change publisher and registers it with sqldb when
+// the change stream is enabled.
+func Register(ctx context.Context) error {
+	cfg, err := NewChangeStreamConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !cfg.Enabled {
+		logger.WithContext(ctx).Debug("Change stream disabled")
+		return nil
+	}
+
+	publisher, err := NewChangePublisher(ctx, cfg.Channel)
+	if err != nil {
+		return err
+	}
+
+	sqldb.RegisterChangePublisher(publisher)
+	logger.WithContext(ctx).Infof("Publishing sqldb changes to channel %q", cfg.Channel)
+	return nil
+}
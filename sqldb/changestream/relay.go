@@ -0,0 +1,106 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package changestream
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/sqldb"
+	"cto-github.cisco.com/NFV-BU/go-msx/trace"
+	"time"
+)
+
+// Relay periodically publishes change events retained in the sqldb change outbox,
+// such as those which failed to publish after their transaction committed.
+type Relay struct {
+	ctx      context.Context
+	interval time.Duration
+	delay    time.Duration
+	done     chan struct{}
+}
+
+func (r *Relay) Start() {
+	go r.run()
+}
+
+func (r *Relay) run() {
+	ctx := trace.UntracedContextFromContext(r.ctx)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if ctx.Err() != nil {
+				return
+			}
+
+			r.relay(ctx)
+
+		case <-r.done:
+			return
+		}
+	}
+}
+
+func (r *Relay) relay(ctx context.Context) {
+	count, err := sqldb.RelayChanges(ctx, r.delay)
+	if err != nil {
+		logger.WithContext(ctx).WithError(err).Error("Failed to relay change outbox")
+	}
+	if count > 0 {
+		logger.WithContext(ctx).Infof("Relayed %d change event(s) from outbox", count)
+	}
+}
+
+func (r *Relay) Stop() {
+	close(r.done)
+}
+
+var relay *Relay
+
+// StartRelay starts relaying the change outbox when the change stream is enabled.
+func StartRelay(ctx context.Context) error {
+	cfg, err := NewChangeStreamConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !cfg.Enabled {
+		return nil
+	}
+
+	relay = &Relay{
+		ctx:      ctx,
+		interval: cfg.RelayInterval,
+		delay:    cfg.RelayDelay,
+		done:     make(chan struct{}),
+	}
+	relay.Start()
+	return nil
+}
+
+// StopRelay stops relaying the change outbox.
+func StopRelay(context.Context) error {
+	if relay != nil {
+		relay.Stop()
+		relay = nil
+	}
+	return nil
+}
+
+// Migrate creates the change outbox when the change stream is enabled.
+func Migrate(ctx context.Context) error {
+	cfg, err := NewChangeStreamConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !cfg.Enabled {
+		return nil
+	}
+
+	logger.WithContext(ctx).Infof("Creating change outbox %q", sqldb.ChangeOutboxTable)
+	return sqldb.CreateChangeOutbox(ctx)
+}
@@ -0,0 +1,105 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package sqldb
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/paging"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// Column is a typed column descriptor.  Predicates built from a Column only accept
+// values of the column's type, so mismatches are caught at compile time.
+// Column descriptors are normally generated from model struct tags using
+// the `generate-columns` skel target.
+type Column[T any] struct {
+	name string
+}
+
+func (c Column[T]) Name() string {
+	return c.name
+}
+
+func (c Column[T]) ident() exp.IdentifierExpression {
+	return goqu.I(c.name)
+}
+
+func (c Column[T]) Eq(value T) WhereOption {
+	return c.ident().Eq(value)
+}
+
+func (c Column[T]) Neq(value T) WhereOption {
+	return c.ident().Neq(value)
+}
+
+func (c Column[T]) Gt(value T) WhereOption {
+	return c.ident().Gt(value)
+}
+
+func (c Column[T]) Gte(value T) WhereOption {
+	return c.ident().Gte(value)
+}
+
+func (c Column[T]) Lt(value T) WhereOption {
+	return c.ident().Lt(value)
+}
+
+func (c Column[T]) Lte(value T) WhereOption {
+	return c.ident().Lte(value)
+}
+
+func (c Column[T]) In(values ...T) WhereOption {
+	return c.ident().In(types.Slice[T](values).AnySlice())
+}
+
+func (c Column[T]) NotIn(values ...T) WhereOption {
+	return c.ident().NotIn(types.Slice[T](values).AnySlice())
+}
+
+func (c Column[T]) Between(low, high T) WhereOption {
+	return c.ident().Between(exp.NewRangeVal(low, high))
+}
+
+func (c Column[T]) Like(pattern string) WhereOption {
+	return c.ident().Like(pattern)
+}
+
+func (c Column[T]) ILike(pattern string) WhereOption {
+	return c.ident().ILike(pattern)
+}
+
+func (c Column[T]) IsNull() WhereOption {
+	return c.ident().IsNull()
+}
+
+func (c Column[T]) IsNotNull() WhereOption {
+	return c.ident().IsNotNull()
+}
+
+// Key returns a key option for use with DeleteOne and Keys.
+func (c Column[T]) Key(value T) KeysOption {
+	return KeysOption{c.name: value}
+}
+
+// Asc returns an ascending sort order for use with Sort.
+func (c Column[T]) Asc() paging.SortOrder {
+	return paging.SortOrder{
+		Property:  c.name,
+		Direction: paging.SortDirectionAsc,
+	}
+}
+
+// Desc returns a descending sort order for use with Sort.
+func (c Column[T]) Desc() paging.SortOrder {
+	return paging.SortOrder{
+		Property:  c.name,
+		Direction: paging.SortDirectionDesc,
+	}
+}
+
+func NewColumn[T any](name string) Column[T] {
+	return Column[T]{name: name}
+}
@@ -0,0 +1,123 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package sqldb
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/paging"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+var personColumns = struct {
+	Id   Column[uuid.UUID]
+	Name Column[string]
+}{
+	Id:   NewColumn[uuid.UUID]("id"),
+	Name: NewColumn[string]("name"),
+}
+
+func TestColumn_Predicates(t *testing.T) {
+	id := uuid.MustParse(mockId)
+
+	tests := []struct {
+		name     string
+		where    WhereOption
+		wantStmt string
+		wantArgs []any
+	}{
+		{
+			name:     "Eq",
+			where:    personColumns.Name.Eq(mockName),
+			wantStmt: "SELECT * FROM `person` WHERE (`name` = ?)",
+			wantArgs: []any{mockName},
+		},
+		{
+			name:     "Neq",
+			where:    personColumns.Name.Neq(mockName),
+			wantStmt: "SELECT * FROM `person` WHERE (`name` != ?)",
+			wantArgs: []any{mockName},
+		},
+		{
+			name:     "In",
+			where:    personColumns.Id.In(id),
+			wantStmt: "SELECT * FROM `person` WHERE (`id` IN (?))",
+			wantArgs: []any{mockId},
+		},
+		{
+			name:     "Like",
+			where:    personColumns.Name.Like("Jo%"),
+			wantStmt: "SELECT * FROM `person` WHERE (`name` LIKE ?)",
+			wantArgs: []any{"Jo%"},
+		},
+		{
+			name:     "IsNull",
+			where:    personColumns.Name.IsNull(),
+			wantStmt: "SELECT * FROM `person` WHERE (`name` IS ?)",
+			wantArgs: []any{nil},
+		},
+		{
+			name: "All",
+			where: All(
+				personColumns.Id.Eq(id),
+				personColumns.Name.Gte(mockName)),
+			wantStmt: "SELECT * FROM `person` WHERE ((`id` = ?) AND (`name` >= ?))",
+			wantArgs: []any{mockId, mockName},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, _, _ := newSqlMockDependencies("sqlite3")
+
+			mockSqlRepositoryApi := NewMockSqlRepositoryApi(t)
+			ctx = ContextSqlRepository().Set(ctx, mockSqlRepositoryApi)
+
+			mockSqlRepositoryApi.EXPECT().
+				SqlGet(mock.Anything, test.wantStmt, test.wantArgs, mock.Anything).
+				Return(nil)
+
+			repo, err := NewTypedRepository[Person](ctx, tableNamePerson)
+			assert.NoError(t, err)
+
+			var person Person
+			err = repo.FindOne(ctx, &person, test.where)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestColumn_FindAll(t *testing.T) {
+	ctx, _, _ := newSqlMockDependencies("sqlite3")
+
+	mockSqlRepositoryApi := NewMockSqlRepositoryApi(t)
+	ctx = ContextSqlRepository().Set(ctx, mockSqlRepositoryApi)
+
+	mockSqlRepositoryApi.EXPECT().
+		SqlSelect(
+			mock.Anything,
+			"SELECT * FROM `person` WHERE (`name` = ?) ORDER BY `name` DESC, `id` ASC",
+			[]any{mockName},
+			mock.Anything).
+		Return(nil)
+
+	repo, err := NewTypedRepository[Person](ctx, tableNamePerson)
+	assert.NoError(t, err)
+
+	var persons []Person
+	_, err = repo.FindAll(ctx, &persons,
+		Where(personColumns.Name.Eq(mockName)),
+		Sort([]paging.SortOrder{
+			personColumns.Name.Desc(),
+			personColumns.Id.Asc(),
+		}))
+	assert.NoError(t, err)
+}
+
+func TestColumn_Key(t *testing.T) {
+	key := personColumns.Id.Key(uuid.MustParse(mockId))
+	assert.Equal(t, KeysOption{"id": uuid.MustParse(mockId)}, key)
+}
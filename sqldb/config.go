@@ -18,6 +18,10 @@ type Config struct {
 	Disconnected   bool   `config:"default=${cli.flag.disconnected:false}"`
 }
 
+func init() {
+	config.RegisterSchema[Config](configRootSpringDatasourceConfig)
+}
+
 func NewSqlConfigFromConfig(cfg *config.Config) (*Config, error) {
 	var sqlConfig Config
 	if err := cfg.Populate(&sqlConfig, configRootSpringDatasourceConfig); err != nil {
@@ -21,6 +21,9 @@ const (
 	contextKeyTransactionManager    = contextKey("TransactionManager")
 	contextKeyRepositorySql         = contextKey("RepositorySql")
 	contextKeyRepositoryGoqu        = contextKey("RepositoryGoqu")
+	contextKeyTenantBypass          = contextKey("TenantBypass")
+	contextKeyChangePublisher       = contextKey("ChangePublisher")
+	contextKeyTransactionScope      = contextKey("TransactionScope")
 	contextKeyRepositoryTypedPrefix = "RepositoryTyped"
 )
 
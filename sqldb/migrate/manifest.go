@@ -210,6 +210,10 @@ func script(filename string) string {
 	return path.Base(filename)
 }
 
+func init() {
+	config.RegisterSchema[ManifestConfig](configRootManifest)
+}
+
 func NewManifestConfig(cfg *config.Config) (*ManifestConfig, error) {
 	var manifestConfig ManifestConfig
 	if err := cfg.Populate(&manifestConfig, configRootManifest); err != nil {
@@ -12,7 +12,7 @@ import (
 
 type MockTransactionManager struct{}
 
-func (m *MockTransactionManager) WithTransaction(ctx context.Context, action types.ActionFunc) error {
+func (m *MockTransactionManager) WithTransaction(ctx context.Context, action types.ActionFunc, _ ...TransactionOption) error {
 	return action(ctx)
 }
 
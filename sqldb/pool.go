@@ -115,7 +115,7 @@ func observerDriverName(driverName string) (string, error) {
 	if ok {
 		return observerDriverName, nil
 	}
-	observerDriver = sqldbobserver.NewObserverDriver(baseDriver)
+	observerDriver = sqldbobserver.NewObserverDriver(baseDriver, sqldbobserver.WithDriverName(driverName))
 	drivers[observerDriverName] = observerDriver
 	sql.Register(observerDriverName, observerDriver)
 	return observerDriverName, nil
@@ -200,6 +200,10 @@ func ConfigurePool(ctx context.Context) error {
 		return ErrDisabled
 	}
 
+	if err = sqldbobserver.Configure(ctx); err != nil {
+		return err
+	}
+
 	pool = &ConnectionPool{
 		cfg: sqlConfig,
 	}
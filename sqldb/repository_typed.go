@@ -8,9 +8,9 @@ import (
 	"context"
 	"cto-github.cisco.com/NFV-BU/go-msx/paging"
 	"cto-github.cisco.com/NFV-BU/go-msx/types"
-	"errors"
 	"github.com/doug-martin/goqu/v9"
 	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/pkg/errors"
 )
 
 type WhereOption interface {
@@ -68,12 +68,41 @@ type TypedRepositoryApi[I any] interface {
 	Truncate(ctx context.Context) error
 }
 
+type TypedRepositoryOptions struct {
+	// TenantColumn binds the repository to a tenant id column.  When set, every
+	// query is filtered by the caller's tenant access, and every written row
+	// is validated against it.
+	TenantColumn string
+	// ChangeCapture enables emitting change events for each write.
+	ChangeCapture *ChangeCaptureOptions
+}
+
+type TypedRepositoryOption func(options *TypedRepositoryOptions)
+
+func WithTenantColumn(column string) TypedRepositoryOption {
+	return func(options *TypedRepositoryOptions) {
+		options.TenantColumn = column
+	}
+}
+
+// WithChangeCapture enables publishing a ChangeEvent for each insert, update,
+// upsert and delete.  The values of the key columns are included in each event.
+// Creating the repository fails when no change publisher has been registered.
+func WithChangeCapture(keyColumns ...string) TypedRepositoryOption {
+	return func(options *TypedRepositoryOptions) {
+		options.ChangeCapture = &ChangeCaptureOptions{
+			KeyColumns: keyColumns,
+		}
+	}
+}
+
 type TypedRepository[I any] struct {
-	table string
-	goqu  GoquRepositoryApi
+	table        string
+	tenantColumn string
+	goqu         GoquRepositoryApi
 }
 
-func NewTypedRepository[I any](ctx context.Context, table string) (TypedRepositoryApi[I], error) {
+func NewTypedRepository[I any](ctx context.Context, table string, opts ...TypedRepositoryOption) (TypedRepositoryApi[I], error) {
 	api := ContextTypedRepository[I](table).Get(ctx)
 	if api == nil {
 		goquRepository, err := NewGoquRepository(ctx)
@@ -81,9 +110,27 @@ func NewTypedRepository[I any](ctx context.Context, table string) (TypedReposito
 			return nil, err
 		}
 
+		var options TypedRepositoryOptions
+		for _, opt := range opts {
+			opt(&options)
+		}
+
+		if options.ChangeCapture != nil {
+			if err = ValidateChangePublisher(ctx); err != nil {
+				return nil, err
+			}
+
+			changeCaptureOptions := *options.ChangeCapture
+			if changeCaptureOptions.TenantColumn == "" {
+				changeCaptureOptions.TenantColumn = options.TenantColumn
+			}
+			goquRepository = CaptureChanges(goquRepository, changeCaptureOptions)
+		}
+
 		api = &TypedRepository[I]{
-			table: table,
-			goqu:  goquRepository,
+			table:        table,
+			tenantColumn: options.TenantColumn,
+			goqu:         goquRepository,
 		}
 	}
 	return api, nil
@@ -96,6 +143,13 @@ func (c *TypedRepository[I]) CountAll(ctx context.Context, dest *int64, where Wh
 		ds = ds.Where(where.Expression())
 	}
 
+	tenantFilter, err := c.tenantFilter(ctx)
+	if err != nil {
+		return err
+	} else if tenantFilter != nil {
+		ds = ds.Where(tenantFilter)
+	}
+
 	return c.goqu.ExecuteGet(ctx, ds.Select(goqu.COUNT("*")), dest)
 }
 
@@ -104,6 +158,13 @@ func (c *TypedRepository[I]) FindAll(ctx context.Context, dest *[]I, options ...
 	rowsQuery := c.goqu.Select(c.table)
 	pgReq := paging.Request{}
 
+	tenantFilter, err := c.tenantFilter(ctx)
+	if err != nil {
+		return
+	} else if tenantFilter != nil {
+		rowsQuery = rowsQuery.Where(tenantFilter)
+	}
+
 	// Apply each option to the query and paging request
 	for _, option := range options {
 		rowsQuery, pgReq = option(rowsQuery, pgReq)
@@ -162,32 +223,70 @@ func (c *TypedRepository[I]) FindOne(ctx context.Context, dest *I, where WhereOp
 		ds = ds.Where(where.Expression())
 	}
 
+	tenantFilter, err := c.tenantFilter(ctx)
+	if err != nil {
+		return err
+	} else if tenantFilter != nil {
+		ds = ds.Where(tenantFilter)
+	}
+
 	return c.goqu.ExecuteGet(ctx, ds, dest)
 }
 
 func (c *TypedRepository[I]) Insert(ctx context.Context, value ...I) error {
+	if err := c.validateTenantAccess(ctx, value...); err != nil {
+		return err
+	}
+
 	ds := c.goqu.Insert(c.table)
 	return c.goqu.ExecuteInsert(ctx, ds.Rows(types.Slice[I](value).AnySlice()...))
 }
 
 func (c *TypedRepository[I]) Update(ctx context.Context, where WhereOption, value I) error {
+	if err := c.validateTenantAccess(ctx, value); err != nil {
+		return err
+	}
+
 	ds := c.goqu.Update(c.table)
 
 	if where != nil {
 		ds = ds.Where(where.Expression())
 	}
 
+	tenantFilter, err := c.tenantFilter(ctx)
+	if err != nil {
+		return err
+	} else if tenantFilter != nil {
+		ds = ds.Where(tenantFilter)
+	}
+
 	return c.goqu.ExecuteUpdate(ctx, ds.Set(value))
 }
 
 func (c *TypedRepository[I]) Upsert(ctx context.Context, value ...I) error {
+	if err := c.validateUpsertAccess(ctx); err != nil {
+		return err
+	}
+
+	if err := c.validateTenantAccess(ctx, value...); err != nil {
+		return err
+	}
+
 	ds := c.goqu.Upsert(c.table)
 	return c.goqu.ExecuteUpsert(ctx, ds.Rows(types.Slice[I](value).AnySlice()...))
 }
 
 func (c *TypedRepository[I]) DeleteOne(ctx context.Context, keys KeysOption) error {
-	ds := c.goqu.Delete(c.table)
-	return c.goqu.ExecuteDelete(ctx, ds.Where(keys))
+	ds := c.goqu.Delete(c.table).Where(keys)
+
+	tenantFilter, err := c.tenantFilter(ctx)
+	if err != nil {
+		return err
+	} else if tenantFilter != nil {
+		ds = ds.Where(tenantFilter)
+	}
+
+	return c.goqu.ExecuteDelete(ctx, ds)
 }
 
 func (c *TypedRepository[I]) DeleteAll(ctx context.Context, where WhereOption) error {
@@ -197,10 +296,22 @@ func (c *TypedRepository[I]) DeleteAll(ctx context.Context, where WhereOption) e
 		ds = ds.Where(where.Expression())
 	}
 
+	tenantFilter, err := c.tenantFilter(ctx)
+	if err != nil {
+		return err
+	} else if tenantFilter != nil {
+		ds = ds.Where(tenantFilter)
+	}
+
 	return c.goqu.ExecuteDelete(ctx, ds)
 }
 
 func (c *TypedRepository[I]) Truncate(ctx context.Context) error {
+	// Truncation would cross tenant boundaries, so it requires an explicit bypass
+	if c.tenantColumn != "" && !c.tenantBypassed(ctx, "truncate") {
+		return errors.Wrap(ErrTenantAccessRequired, "Truncate not permitted on tenant-bound repository")
+	}
+
 	ds := c.goqu.Truncate(c.table)
 	return c.goqu.ExecuteTruncate(ctx, ds)
 }
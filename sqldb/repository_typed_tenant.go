@@ -0,0 +1,147 @@
+// Copyright © 2022, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package sqldb
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/audit/auditlog"
+	"cto-github.cisco.com/NFV-BU/go-msx/rbac"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"fmt"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/pkg/errors"
+)
+
+const auditActionTenantBypass = "tenantBypass"
+
+var ErrTenantAccessRequired = errors.New("Tenant access required")
+var ErrTenantColumnMissing = errors.New("Tenant column not found in row")
+
+type tenantBypass struct {
+	Reason string
+}
+
+func contextTenantBypass() types.ContextKeyAccessor[*tenantBypass] {
+	return types.NewContextKeyAccessor[*tenantBypass](contextKeyTenantBypass)
+}
+
+// ContextWithTenantBypass returns a system context in which tenant-bound
+// repositories skip tenant filtering and validation.  Every repository
+// operation performed under the bypass is recorded in the audit log along
+// with the supplied reason.
+func ContextWithTenantBypass(ctx context.Context, reason string) context.Context {
+	return contextTenantBypass().Set(ctx, &tenantBypass{Reason: reason})
+}
+
+// WithTenantBypass executes the action with tenant filtering bypassed.
+func WithTenantBypass(ctx context.Context, reason string, action types.ActionFunc) error {
+	return action(ContextWithTenantBypass(ctx, reason))
+}
+
+func (c *TypedRepository[I]) tenantBypassed(ctx context.Context, operation string) bool {
+	bypass := contextTenantBypass().Get(ctx)
+	if bypass == nil {
+		return false
+	}
+
+	auditlog.Action(logger, ctx, c.table, auditActionTenantBypass).
+		WithField("operation", operation).
+		WithField("reason", bypass.Reason).
+		Info("Tenant access filter bypassed")
+
+	return true
+}
+
+func (c *TypedRepository[I]) tenantAccess(ctx context.Context, operation string) (access rbac.TenantAccess, bypassed bool, err error) {
+	if c.tenantColumn == "" {
+		return access, true, nil
+	}
+
+	if c.tenantBypassed(ctx, operation) {
+		return access, true, nil
+	}
+
+	access, err = rbac.NewTenantAccess(ctx)
+	return access, false, err
+}
+
+// tenantFilter returns the where clause restricting rows to the tenants
+// accessible from the context, or nil if no restriction applies.
+func (c *TypedRepository[I]) tenantFilter(ctx context.Context) (goqu.Expression, error) {
+	access, bypassed, err := c.tenantAccess(ctx, "query")
+	if err != nil || bypassed {
+		return nil, err
+	}
+
+	if !access.Unfiltered && len(access.TenantIds) == 0 {
+		// User has no tenants, and can therefore see no rows
+		return goqu.L("1 = 0"), nil
+	}
+
+	return NewTenantAccessFilter(access, c.tenantColumn).Filter(), nil
+}
+
+// validateTenantAccess ensures each row being written belongs to a tenant
+// accessible from the context.
+func (c *TypedRepository[I]) validateTenantAccess(ctx context.Context, values ...I) error {
+	access, bypassed, err := c.tenantAccess(ctx, "write")
+	if err != nil || bypassed {
+		return err
+	}
+
+	for _, value := range values {
+		tenantId, err := c.rowTenantId(value)
+		if err != nil {
+			return err
+		}
+
+		if err = access.ValidateTenantAccess(tenantId); err != nil {
+			return errors.Wrapf(rbac.ErrUserDoesNotHaveTenantAccess, "Tenant %q", tenantId.String())
+		}
+	}
+
+	return nil
+}
+
+// validateUpsertAccess ensures an upsert cannot replace a row owned by another tenant.
+// Upserts replace existing rows by primary key without consulting the tenant column,
+// so they are only permitted when the caller can access every tenant, or when the
+// tenant filter has been explicitly bypassed.
+func (c *TypedRepository[I]) validateUpsertAccess(ctx context.Context) error {
+	access, bypassed, err := c.tenantAccess(ctx, "upsert")
+	if err != nil || bypassed {
+		return err
+	}
+
+	if !access.Unfiltered {
+		return errors.Wrap(ErrTenantAccessRequired, "Upsert not permitted on tenant-bound repository")
+	}
+
+	return nil
+}
+
+func (c *TypedRepository[I]) rowTenantId(value I) (types.UUID, error) {
+	record, err := exp.NewRecordFromStruct(value, true, false)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantIdValue, ok := record[c.tenantColumn]
+	if !ok {
+		return nil, errors.Wrapf(ErrTenantColumnMissing, "Column %q", c.tenantColumn)
+	}
+
+	switch tenantId := tenantIdValue.(type) {
+	case types.UUID:
+		return tenantId, nil
+	case string:
+		return types.ParseUUID(tenantId)
+	case fmt.Stringer:
+		return types.ParseUUID(tenantId.String())
+	default:
+		return nil, errors.Wrapf(ErrDataInvalid, "Unsupported tenant id type %T", tenantIdValue)
+	}
+}
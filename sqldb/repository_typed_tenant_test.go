@@ -0,0 +1,228 @@
+// Copyright © 2022, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package sqldb
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/rbac"
+	"cto-github.cisco.com/NFV-BU/go-msx/testhelpers/securitytest"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+type TenantPerson struct {
+	Id       uuid.UUID `db:"id"`
+	Name     string    `db:"name"`
+	TenantId uuid.UUID `db:"tenant_id"`
+}
+
+const (
+	columnTenantId        = "tenant_id"
+	tableNameTenantPerson = "tenant_person"
+)
+
+func TestTypedRepository_TenantColumn(t *testing.T) {
+	tenantId := securitytest.DefaultTenantId
+	modelTenantId := uuid.MustParse(tenantId.String())
+	otherTenantId := uuid.MustParse("c5ca1f36-8b19-4b05-a8b1-2ff3f3d8f5c8")
+
+	tests := []struct {
+		name    string
+		inject  types.ContextInjector
+		setup   func(mock *MockSqlRepositoryApi)
+		call    func(ctx context.Context, repo TypedRepositoryApi[TenantPerson]) error
+		wantErr error
+	}{
+		{
+			name:   "FindOneFiltered",
+			inject: securitytest.TenantAssignmentInjector(tenantId),
+			setup: func(mockApi *MockSqlRepositoryApi) {
+				mockApi.EXPECT().
+					SqlGet(
+						mock.Anything,
+						"SELECT * FROM `tenant_person` WHERE ((`id` = ?) AND (`tenant_id` IN (?)))",
+						[]any{mockId, modelTenantId.String()},
+						mock.Anything).
+					Return(nil)
+			},
+			call: func(ctx context.Context, repo TypedRepositoryApi[TenantPerson]) error {
+				var person TenantPerson
+				return repo.FindOne(ctx, &person, And(map[string]any{columnId: mockId}))
+			},
+		},
+		{
+			name:   "CountAllNoTenants",
+			inject: securitytest.TenantAssignmentInjector(),
+			setup: func(mockApi *MockSqlRepositoryApi) {
+				mockApi.EXPECT().
+					SqlGet(
+						mock.Anything,
+						"SELECT COUNT(*) FROM `tenant_person` WHERE 1 = 0",
+						[]any{},
+						mock.Anything).
+					Return(nil)
+			},
+			call: func(ctx context.Context, repo TypedRepositoryApi[TenantPerson]) error {
+				var count int64
+				return repo.CountAll(ctx, &count, nil)
+			},
+		},
+		{
+			name:   "FindAllUnfiltered",
+			inject: securitytest.PermissionInjector(rbac.PermissionAccessAllTenants),
+			setup: func(mockApi *MockSqlRepositoryApi) {
+				mockApi.EXPECT().
+					SqlSelect(
+						mock.Anything,
+						"SELECT * FROM `tenant_person` WHERE (`name` = ?)",
+						[]any{mockName},
+						mock.Anything).
+					Return(nil)
+			},
+			call: func(ctx context.Context, repo TypedRepositoryApi[TenantPerson]) error {
+				var persons []TenantPerson
+				_, err := repo.FindAll(ctx, &persons, Where(And(map[string]any{columnName: mockName})))
+				return err
+			},
+		},
+		{
+			name:   "DeleteOneFiltered",
+			inject: securitytest.TenantAssignmentInjector(tenantId),
+			setup: func(mockApi *MockSqlRepositoryApi) {
+				mockApi.EXPECT().
+					SqlExecute(
+						mock.Anything,
+						"DELETE FROM `tenant_person` WHERE ((`id` = ?) AND (`tenant_id` IN (?)))",
+						[]any{mockId, modelTenantId.String()}).
+					Return(nil)
+			},
+			call: func(ctx context.Context, repo TypedRepositoryApi[TenantPerson]) error {
+				return repo.DeleteOne(ctx, map[string]any{columnId: mockId})
+			},
+		},
+		{
+			name:   "InsertAllowed",
+			inject: securitytest.TenantAssignmentInjector(tenantId),
+			setup: func(mockApi *MockSqlRepositoryApi) {
+				mockApi.EXPECT().
+					SqlExecute(
+						mock.Anything,
+						"INSERT INTO `tenant_person` (`id`, `name`, `tenant_id`) VALUES (?, ?, ?)",
+						[]any{mockId, mockName, modelTenantId.String()}).
+					Return(nil)
+			},
+			call: func(ctx context.Context, repo TypedRepositoryApi[TenantPerson]) error {
+				return repo.Insert(ctx, TenantPerson{
+					Id:       uuid.MustParse(mockId),
+					Name:     mockName,
+					TenantId: modelTenantId,
+				})
+			},
+		},
+		{
+			name:   "InsertDenied",
+			inject: securitytest.TenantAssignmentInjector(tenantId),
+			call: func(ctx context.Context, repo TypedRepositoryApi[TenantPerson]) error {
+				return repo.Insert(ctx, TenantPerson{
+					Id:       uuid.MustParse(mockId),
+					Name:     mockName,
+					TenantId: otherTenantId,
+				})
+			},
+			wantErr: rbac.ErrUserDoesNotHaveTenantAccess,
+		},
+		{
+			name:   "UpsertBypassed",
+			inject: securitytest.TenantAssignmentInjector(tenantId),
+			setup: func(mockApi *MockSqlRepositoryApi) {
+				mockApi.EXPECT().
+					SqlExecute(
+						mock.Anything,
+						"REPLACE INTO `tenant_person` (`id`, `name`, `tenant_id`) VALUES (?, ?, ?)",
+						[]any{mockId, mockName, otherTenantId.String()}).
+					Return(nil)
+			},
+			call: func(ctx context.Context, repo TypedRepositoryApi[TenantPerson]) error {
+				return WithTenantBypass(ctx, "migration", func(ctx context.Context) error {
+					return repo.Upsert(ctx, TenantPerson{
+						Id:       uuid.MustParse(mockId),
+						Name:     mockName,
+						TenantId: otherTenantId,
+					})
+				})
+			},
+		},
+		{
+			name:   "UpsertCrossTenantDenied",
+			inject: securitytest.TenantAssignmentInjector(tenantId),
+			call: func(ctx context.Context, repo TypedRepositoryApi[TenantPerson]) error {
+				// The row with this primary key may belong to another tenant
+				return repo.Upsert(ctx, TenantPerson{
+					Id:       uuid.MustParse(mockId),
+					Name:     mockName,
+					TenantId: modelTenantId,
+				})
+			},
+			wantErr: ErrTenantAccessRequired,
+		},
+		{
+			name:   "UpsertUnfiltered",
+			inject: securitytest.PermissionInjector(rbac.PermissionAccessAllTenants),
+			setup: func(mockApi *MockSqlRepositoryApi) {
+				mockApi.EXPECT().
+					SqlExecute(
+						mock.Anything,
+						"REPLACE INTO `tenant_person` (`id`, `name`, `tenant_id`) VALUES (?, ?, ?)",
+						[]any{mockId, mockName, otherTenantId.String()}).
+					Return(nil)
+			},
+			call: func(ctx context.Context, repo TypedRepositoryApi[TenantPerson]) error {
+				return repo.Upsert(ctx, TenantPerson{
+					Id:       uuid.MustParse(mockId),
+					Name:     mockName,
+					TenantId: otherTenantId,
+				})
+			},
+		},
+		{
+			name:   "TruncateDenied",
+			inject: securitytest.PermissionInjector(rbac.PermissionAccessAllTenants),
+			call: func(ctx context.Context, repo TypedRepositoryApi[TenantPerson]) error {
+				return repo.Truncate(ctx)
+			},
+			wantErr: ErrTenantAccessRequired,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// given
+			ctx, _, _ := newSqlMockDependencies("sqlite3")
+			ctx = test.inject(ctx)
+
+			mockSqlRepositoryApi := NewMockSqlRepositoryApi(t)
+			ctx = ContextSqlRepository().Set(ctx, mockSqlRepositoryApi)
+
+			repo, err := NewTypedRepository[TenantPerson](ctx, tableNameTenantPerson, WithTenantColumn(columnTenantId))
+			assert.NoError(t, err)
+
+			if test.setup != nil {
+				test.setup(mockSqlRepositoryApi)
+			}
+
+			// when
+			err = test.call(ctx, repo)
+
+			// then
+			if test.wantErr != nil {
+				assert.ErrorIs(t, err, test.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package sqldbobserver
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
+	"sync"
+	"time"
+)
+
+const configRootObserver = "sqldb.observer"
+
+type ExplainConfig struct {
+	// Enabled captures the query plan of slow statements (postgres only).
+	Enabled bool `config:"default=false"`
+	// Analyze uses EXPLAIN (ANALYZE) for SELECT statements, re-executing the query.
+	Analyze bool          `config:"default=false"`
+	Timeout time.Duration `config:"default=5s"`
+}
+
+type SlowQueryConfig struct {
+	Enabled   bool          `config:"default=true"`
+	Threshold time.Duration `config:"default=1s"`
+	Explain   ExplainConfig `config:"explain"`
+}
+
+type Config struct {
+	// StatementTimeout is applied to statements executed without a context deadline.
+	StatementTimeout time.Duration   `config:"default=0s"`
+	SlowQuery        SlowQueryConfig `config:"slow-query"`
+}
+
+func init() {
+	config.RegisterSchema[Config](configRootObserver)
+}
+
+func NewConfig(ctx context.Context) (*Config, error) {
+	var cfg Config
+	if err := config.FromContext(ctx).Populate(&cfg, configRootObserver); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+var observerConfig = new(Config)
+var observerConfigMtx sync.Mutex
+
+func currentConfig() *Config {
+	observerConfigMtx.Lock()
+	defer observerConfigMtx.Unlock()
+	return observerConfig
+}
+
+// SetConfig replaces the active observer configuration.
+func SetConfig(cfg *Config) {
+	observerConfigMtx.Lock()
+	defer observerConfigMtx.Unlock()
+	observerConfig = cfg
+}
+
+// Configure loads the observer configuration from the context.
+func Configure(ctx context.Context) error {
+	cfg, err := NewConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	SetConfig(cfg)
+	return nil
+}
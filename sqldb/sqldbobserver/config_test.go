@@ -0,0 +1,62 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package sqldbobserver
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/testhelpers/configtest"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNewConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]string
+		want   *Config
+	}{
+		{
+			name: "Defaults",
+			want: &Config{
+				SlowQuery: SlowQueryConfig{
+					Enabled:   true,
+					Threshold: time.Second,
+					Explain: ExplainConfig{
+						Enabled: false,
+						Analyze: false,
+						Timeout: 5 * time.Second,
+					},
+				},
+			},
+		},
+		{
+			name: "Analyze",
+			values: map[string]string{
+				"sqldb.observer.slow-query.explain.enabled": "true",
+				"sqldb.observer.slow-query.explain.analyze": "true",
+			},
+			want: &Config{
+				SlowQuery: SlowQueryConfig{
+					Enabled:   true,
+					Threshold: time.Second,
+					Explain: ExplainConfig{
+						Enabled: true,
+						Analyze: true,
+						Timeout: 5 * time.Second,
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := configtest.ContextWithNewInMemoryConfig(context.Background(), tt.values)
+			got, err := NewConfig(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
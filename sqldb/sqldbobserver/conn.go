@@ -13,7 +13,15 @@ import (
 
 // conn defines a tracing wrapper for driver.conn.
 type conn struct {
-	conn driver.Conn
+	conn   driver.Conn
+	driver *observerDriver
+}
+
+func (c *conn) driverName() string {
+	if c.driver == nil {
+		return ""
+	}
+	return c.driver.name
 }
 
 // Prepare implements driver.Conn Prepare.
@@ -22,7 +30,7 @@ func (c *conn) Prepare(query string) (driver.Stmt, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &stmt{stmt: s}, nil
+	return &stmt{stmt: s, conn: c, query: query}, nil
 }
 
 // Close implements driver.Conn Close.
@@ -60,7 +68,7 @@ func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, e
 		if err != nil {
 			return nil, err
 		}
-		return &stmt{stmt: s}, nil
+		return &stmt{stmt: s, conn: c, query: query}, nil
 	}
 	return c.conn.Prepare(query)
 }
@@ -85,6 +93,9 @@ func (c *conn) ExecContext(ctx context.Context, query string, args []driver.Name
 	defer s.Finish()
 
 	if execerContext, ok := c.conn.(driver.ExecerContext); ok {
+		ctx, observation := c.observeQuery(ctx, query, args)
+		defer func() { observation.finish(err) }()
+
 		err = observeStats(query, func() error {
 			result, err = execerContext.ExecContext(ctx, query, args)
 			return err
@@ -132,11 +143,16 @@ func (c *conn) QueryContext(ctx context.Context, query string, args []driver.Nam
 	defer s.Finish()
 
 	if queryerContext, ok := c.conn.(driver.QueryerContext); ok {
+		ctx, observation := c.observeQuery(ctx, query, args)
 		err = observeStats(query, func() error {
 			rows, err = queryerContext.QueryContext(ctx, query, args)
 			return err
 		})
-		return
+		if err != nil {
+			observation.finish(err)
+			return nil, err
+		}
+		return observation.rows(rows), nil
 	}
 
 	values, err := namedValueToValue(args)
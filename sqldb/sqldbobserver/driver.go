@@ -11,6 +11,15 @@ import (
 // conn defines a tracing wrapper for driver.Driver.
 type observerDriver struct {
 	driver driver.Driver
+	name   string
+}
+
+// WithDriverName sets the name of the underlying driver, enabling driver-specific
+// features such as EXPLAIN capture.
+func WithDriverName(name string) func(*observerDriver) {
+	return func(d *observerDriver) {
+		d.name = name
+	}
 }
 
 // Open implements driver.Driver Open.
@@ -19,7 +28,7 @@ func (d *observerDriver) Open(name string) (driver.Conn, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &conn{conn: c}, nil
+	return &conn{conn: c, driver: d}, nil
 }
 
 // TracingDriver creates and returns a new SQL driver with tracing capabilities.
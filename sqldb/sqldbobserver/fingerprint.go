@@ -0,0 +1,40 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package sqldbobserver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+var (
+	reStringLiteral   = regexp.MustCompile(`'(?:[^']|'')*'`)
+	rePlaceholder     = regexp.MustCompile(`\$\d+`)
+	reNumericLiteral  = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+	rePlaceholderList = regexp.MustCompile(`\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+	reTupleList       = regexp.MustCompile(`\(\?\)(?:\s*,\s*\(\?\))+`)
+	reWhitespace      = regexp.MustCompile(`\s+`)
+)
+
+// NormalizeQuery replaces literals and placeholders in the query with `?`,
+// collapses value lists and whitespace, so that statements differing only
+// in their parameters share the same normalized form.
+func NormalizeQuery(query string) string {
+	query = reStringLiteral.ReplaceAllString(query, "?")
+	query = rePlaceholder.ReplaceAllString(query, "?")
+	query = reNumericLiteral.ReplaceAllString(query, "?")
+	query = reWhitespace.ReplaceAllString(query, " ")
+	query = rePlaceholderList.ReplaceAllString(query, "(?)")
+	query = reTupleList.ReplaceAllString(query, "(?)")
+	return strings.TrimSpace(query)
+}
+
+// Fingerprint returns a short stable identifier for the normalized query.
+func Fingerprint(query string) string {
+	sum := sha256.Sum256([]byte(NormalizeQuery(query)))
+	return hex.EncodeToString(sum[:8])
+}
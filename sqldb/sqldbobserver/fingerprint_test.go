@@ -0,0 +1,60 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package sqldbobserver
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNormalizeQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "Placeholders",
+			query: `SELECT * FROM "person" WHERE ("id" = $1)`,
+			want:  `SELECT * FROM "person" WHERE ("id" = ?)`,
+		},
+		{
+			name:  "Literals",
+			query: "SELECT * FROM person WHERE name = 'O''Brien' AND age > 42 LIMIT 10",
+			want:  "SELECT * FROM person WHERE name = ? AND age > ? LIMIT ?",
+		},
+		{
+			name:  "InList",
+			query: "SELECT * FROM person WHERE id IN (?, ?, ?)",
+			want:  "SELECT * FROM person WHERE id IN (?)",
+		},
+		{
+			name:  "MultiRowInsert",
+			query: "INSERT INTO person (id, name) VALUES ($1, $2), ($3, $4)",
+			want:  "INSERT INTO person (id, name) VALUES (?)",
+		},
+		{
+			name:  "Whitespace",
+			query: "SELECT *\n\tFROM   person1",
+			want:  "SELECT * FROM person1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeQuery(tt.query))
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a := Fingerprint("SELECT * FROM person WHERE id IN ($1, $2)")
+	b := Fingerprint("SELECT * FROM person WHERE id IN ($1, $2, $3)")
+	c := Fingerprint("SELECT * FROM device WHERE id IN ($1, $2)")
+
+	assert.Len(t, a, 16)
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+}
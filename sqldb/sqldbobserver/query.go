@@ -0,0 +1,300 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package sqldbobserver
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/log"
+	"cto-github.cisco.com/NFV-BU/go-msx/sanitize"
+	"cto-github.cisco.com/NFV-BU/go-msx/trace"
+	"database/sql/driver"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+)
+
+var logger = log.NewLogger("msx.sql.observer")
+
+const (
+	driverPostgres = "postgres"
+	maxCallerDepth = 32
+)
+
+// callerFramePrefixes identify stack frames skipped when locating the caller of a slow query.
+var callerFramePrefixes = []string{
+	"runtime.",
+	"database/sql.",
+	"github.com/jmoiron/sqlx.",
+	"github.com/doug-martin/goqu/",
+	"cto-github.cisco.com/NFV-BU/go-msx/sqldb",
+}
+
+// queryObservation tracks a single statement execution for timeouts, slow query
+// logging and per-fingerprint metrics.
+type queryObservation struct {
+	ctx     context.Context
+	cfg     *Config
+	conn    *conn
+	query   string
+	args    []driver.NamedValue
+	start   time.Time
+	callers []uintptr
+	cancel  context.CancelFunc
+}
+
+// observeQuery starts observing a statement, returning the context to execute it with.
+func (c *conn) observeQuery(ctx context.Context, query string, args []driver.NamedValue) (context.Context, *queryObservation) {
+	cfg := currentConfig()
+
+	o := &queryObservation{
+		cfg:   cfg,
+		conn:  c,
+		query: query,
+		args:  args,
+	}
+
+	if _, ok := ctx.Deadline(); !ok && cfg.StatementTimeout > 0 {
+		ctx, o.cancel = context.WithTimeout(ctx, cfg.StatementTimeout)
+	}
+
+	if cfg.SlowQuery.Enabled {
+		pcs := make([]uintptr, maxCallerDepth)
+		n := runtime.Callers(3, pcs)
+		o.callers = pcs[:n]
+	}
+
+	o.ctx = ctx
+	o.start = time.Now()
+	return ctx, o
+}
+
+// finish records the statement completion.
+func (o *queryObservation) finish(err error) {
+	if o.cancel != nil {
+		defer o.cancel()
+	}
+
+	duration := time.Since(o.start)
+	label := queryLabel(o.query)
+	fingerprint := Fingerprint(o.query)
+
+	histVecSqlQueryFingerprintTime.
+		WithLabelValues(label, fingerprint).
+		Observe(float64(duration) / float64(time.Millisecond))
+
+	if !o.cfg.SlowQuery.Enabled || duration < o.cfg.SlowQuery.Threshold {
+		return
+	}
+
+	countVecSqlSlowQueries.WithLabelValues(label).Inc()
+
+	fields := logrus.Fields{
+		"duration":    duration.String(),
+		"fingerprint": fingerprint,
+		"statement":   sanitize.String(o.query, sanitize.Options{Secret: true}),
+		"caller":      o.caller(),
+	}
+
+	if span := trace.SpanFromContext(o.ctx); span != nil {
+		fields[log.FieldTraceId] = span.Context().TraceId().String()
+	}
+
+	if err == nil && o.cfg.SlowQuery.Explain.Enabled {
+		plan, explainErr := o.explain()
+		if explainErr != nil {
+			logger.WithContext(o.ctx).WithError(explainErr).Debug("Failed to explain slow query")
+		} else if plan != "" {
+			fields["plan"] = sanitize.String(plan, sanitize.Options{Secret: true})
+		}
+	}
+
+	logger.WithContext(o.ctx).WithFields(fields).Warn("Slow query")
+}
+
+// caller returns the first stack frame outside of the database layers.
+func (o *queryObservation) caller() string {
+	if len(o.callers) == 0 {
+		return ""
+	}
+
+	frames := runtime.CallersFrames(o.callers)
+	for {
+		frame, more := frames.Next()
+		if !isInternalFrame(frame.Function) {
+			return fmt.Sprintf("%s (%s:%d)", frame.Function, frame.File, frame.Line)
+		}
+		if !more {
+			break
+		}
+	}
+
+	return ""
+}
+
+func isInternalFrame(function string) bool {
+	for _, prefix := range callerFramePrefixes {
+		if strings.HasPrefix(function, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// explainStatement returns the EXPLAIN statement for the query, or an empty string
+// if the query cannot be explained.
+func (o *queryObservation) explainStatement() string {
+	if o.conn.driverName() != driverPostgres {
+		return ""
+	}
+
+	switch strings.ToUpper(queryLabel(o.query)) {
+	case "SELECT":
+		if o.cfg.SlowQuery.Explain.Analyze {
+			return "EXPLAIN (ANALYZE) " + o.query
+		}
+		return "EXPLAIN " + o.query
+	case "INSERT", "UPDATE", "DELETE", "WITH":
+		// Never analyze writes, since analyze would execute them again
+		return "EXPLAIN " + o.query
+	}
+
+	return ""
+}
+
+// explain captures the query plan of the statement on the same connection.
+func (o *queryObservation) explain() (string, error) {
+	statement := o.explainStatement()
+	if statement == "" {
+		return "", nil
+	}
+
+	queryer, ok := o.conn.conn.(driver.QueryerContext)
+	if !ok {
+		return "", ErrUnsupported
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), o.cfg.SlowQuery.Explain.Timeout)
+	defer cancel()
+
+	rows, err := queryer.QueryContext(ctx, statement, o.args)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var lines []string
+	values := make([]driver.Value, len(rows.Columns()))
+	for {
+		if err = rows.Next(values); err == io.EOF {
+			break
+		} else if err != nil {
+			return "", err
+		}
+
+		for _, value := range values {
+			switch line := value.(type) {
+			case string:
+				lines = append(lines, line)
+			case []byte:
+				lines = append(lines, string(line))
+			}
+		}
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// rows wraps the query results to complete the observation when they are closed.
+func (o *queryObservation) rows(rows driver.Rows) driver.Rows {
+	return &observedRows{
+		rows:        rows,
+		observation: o,
+	}
+}
+
+// observedRows completes the query observation when the rows are closed, so that
+// the statement timeout and duration cover fetching the results.
+type observedRows struct {
+	rows        driver.Rows
+	observation *queryObservation
+	closed      bool
+}
+
+func (r *observedRows) Columns() []string {
+	return r.rows.Columns()
+}
+
+func (r *observedRows) Close() error {
+	err := r.rows.Close()
+	if !r.closed {
+		r.closed = true
+		r.observation.finish(err)
+	}
+	return err
+}
+
+func (r *observedRows) Next(dest []driver.Value) error {
+	return r.rows.Next(dest)
+}
+
+// HasNextResultSet implements driver.RowsNextResultSet HasNextResultSet.
+func (r *observedRows) HasNextResultSet() bool {
+	if rows, ok := r.rows.(driver.RowsNextResultSet); ok {
+		return rows.HasNextResultSet()
+	}
+	return false
+}
+
+// NextResultSet implements driver.RowsNextResultSet NextResultSet.
+func (r *observedRows) NextResultSet() error {
+	if rows, ok := r.rows.(driver.RowsNextResultSet); ok {
+		return rows.NextResultSet()
+	}
+	return io.EOF
+}
+
+// ColumnTypeScanType implements driver.RowsColumnTypeScanType ColumnTypeScanType.
+func (r *observedRows) ColumnTypeScanType(index int) reflect.Type {
+	if rows, ok := r.rows.(driver.RowsColumnTypeScanType); ok {
+		return rows.ColumnTypeScanType(index)
+	}
+	return reflect.TypeOf(new(interface{})).Elem()
+}
+
+// ColumnTypeDatabaseTypeName implements driver.RowsColumnTypeDatabaseTypeName ColumnTypeDatabaseTypeName.
+func (r *observedRows) ColumnTypeDatabaseTypeName(index int) string {
+	if rows, ok := r.rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return rows.ColumnTypeDatabaseTypeName(index)
+	}
+	return ""
+}
+
+// ColumnTypeLength implements driver.RowsColumnTypeLength ColumnTypeLength.
+func (r *observedRows) ColumnTypeLength(index int) (length int64, ok bool) {
+	if rows, ok := r.rows.(driver.RowsColumnTypeLength); ok {
+		return rows.ColumnTypeLength(index)
+	}
+	return 0, false
+}
+
+// ColumnTypeNullable implements driver.RowsColumnTypeNullable ColumnTypeNullable.
+func (r *observedRows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	if rows, ok := r.rows.(driver.RowsColumnTypeNullable); ok {
+		return rows.ColumnTypeNullable(index)
+	}
+	return false, false
+}
+
+// ColumnTypePrecisionScale implements driver.RowsColumnTypePrecisionScale ColumnTypePrecisionScale.
+func (r *observedRows) ColumnTypePrecisionScale(index int) (precision, scale int64, ok bool) {
+	if rows, ok := r.rows.(driver.RowsColumnTypePrecisionScale); ok {
+		return rows.ColumnTypePrecisionScale(index)
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,174 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package sqldbobserver
+
+import (
+	"context"
+	"database/sql/driver"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+	"time"
+)
+
+type stubConn struct {
+	driver.Conn
+	deadline    time.Time
+	hasDeadline bool
+	ctx         context.Context
+	queries     []string
+}
+
+func (c *stubConn) ExecContext(ctx context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.deadline, c.hasDeadline = ctx.Deadline()
+	c.queries = append(c.queries, query)
+	return driver.RowsAffected(1), nil
+}
+
+func (c *stubConn) QueryContext(ctx context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.deadline, c.hasDeadline = ctx.Deadline()
+	c.ctx = ctx
+	c.queries = append(c.queries, query)
+	return &stubRows{lines: []string{"Seq Scan on person"}}, nil
+}
+
+type stubRows struct {
+	lines []string
+}
+
+func (r *stubRows) Columns() []string {
+	return []string{"QUERY PLAN"}
+}
+
+func (r *stubRows) Close() error {
+	return nil
+}
+
+func (r *stubRows) Next(dest []driver.Value) error {
+	if len(r.lines) == 0 {
+		return io.EOF
+	}
+	dest[0], r.lines = r.lines[0], r.lines[1:]
+	return nil
+}
+
+func withObserverConfig(t *testing.T, cfg *Config) {
+	previous := currentConfig()
+	SetConfig(cfg)
+	t.Cleanup(func() {
+		SetConfig(previous)
+	})
+}
+
+func TestConn_StatementTimeout(t *testing.T) {
+	withObserverConfig(t, &Config{StatementTimeout: time.Minute})
+
+	stub := new(stubConn)
+	c := &conn{conn: stub}
+
+	_, err := c.ExecContext(context.Background(), "DELETE FROM person", nil)
+	assert.NoError(t, err)
+	assert.True(t, stub.hasDeadline)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	expected, _ := ctx.Deadline()
+
+	_, err = c.ExecContext(ctx, "DELETE FROM person", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, stub.deadline)
+}
+
+func TestConn_QueryTimeoutUntilClose(t *testing.T) {
+	withObserverConfig(t, &Config{StatementTimeout: time.Minute})
+
+	stub := new(stubConn)
+	c := &conn{conn: stub}
+
+	rows, err := c.QueryContext(context.Background(), "SELECT * FROM person", nil)
+	assert.NoError(t, err)
+	assert.True(t, stub.hasDeadline)
+	assert.NoError(t, stub.ctx.Err())
+
+	assert.NoError(t, rows.Close())
+	assert.Error(t, stub.ctx.Err())
+}
+
+func TestQueryObservation_Explain(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   string
+		analyze  bool
+		query    string
+		wantStmt string
+	}{
+		{
+			name:     "SelectAnalyze",
+			driver:   driverPostgres,
+			analyze:  true,
+			query:    "SELECT * FROM person",
+			wantStmt: "EXPLAIN (ANALYZE) SELECT * FROM person",
+		},
+		{
+			name:     "Select",
+			driver:   driverPostgres,
+			query:    "SELECT * FROM person",
+			wantStmt: "EXPLAIN SELECT * FROM person",
+		},
+		{
+			name:     "UpdateNeverAnalyzed",
+			driver:   driverPostgres,
+			analyze:  true,
+			query:    "UPDATE person SET name = $1",
+			wantStmt: "EXPLAIN UPDATE person SET name = $1",
+		},
+		{
+			name:   "Unsupported",
+			driver: driverPostgres,
+			query:  "BEGIN",
+		},
+		{
+			name:   "OtherDriver",
+			driver: "sqlite3",
+			query:  "SELECT * FROM person",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := new(stubConn)
+			o := &queryObservation{
+				cfg: &Config{
+					SlowQuery: SlowQueryConfig{
+						Explain: ExplainConfig{
+							Enabled: true,
+							Analyze: tt.analyze,
+							Timeout: time.Second,
+						},
+					},
+				},
+				conn:  &conn{conn: stub, driver: &observerDriver{name: tt.driver}},
+				query: tt.query,
+			}
+
+			plan, err := o.explain()
+			assert.NoError(t, err)
+			if tt.wantStmt == "" {
+				assert.Empty(t, stub.queries)
+				assert.Empty(t, plan)
+			} else {
+				assert.Equal(t, []string{tt.wantStmt}, stub.queries)
+				assert.Equal(t, "Seq Scan on person", plan)
+			}
+		})
+	}
+}
+
+func TestIsInternalFrame(t *testing.T) {
+	assert.True(t, isInternalFrame("database/sql.(*DB).QueryContext"))
+	assert.True(t, isInternalFrame("github.com/jmoiron/sqlx.(*DB).SelectContext"))
+	assert.True(t, isInternalFrame("cto-github.cisco.com/NFV-BU/go-msx/sqldb.(*SqlRepository).SqlSelect"))
+	assert.False(t, isInternalFrame("cto-github.cisco.com/NFV-BU/myservice/internal/persons.(*Repository).FindAll"))
+}
@@ -17,6 +17,8 @@ const (
 	statsCounterConnections    = "connections"
 	statsCounterSqlQueryErrors = "query_errors"
 	statsTimerSqlQueryTime     = "query_time"
+	statsTimerSqlFingerprint   = "query_fingerprint_time"
+	statsCounterSqlSlowQueries = "slow_queries"
 )
 
 var (
@@ -25,6 +27,9 @@ var (
 	countConnections         = stats.NewGauge(statsSubsystemSql, statsCounterConnections)
 	countSqlQueryErrors      = stats.NewCounter(statsSubsystemSql, statsCounterSqlQueryErrors)
 	histVecSqlQueryTime      = stats.NewHistogramVec(statsSubsystemSql, statsTimerSqlQueryTime, nil, "action")
+
+	histVecSqlQueryFingerprintTime = stats.NewHistogramVec(statsSubsystemSql, statsTimerSqlFingerprint, nil, "action", "fingerprint")
+	countVecSqlSlowQueries         = stats.NewCounterVec(statsSubsystemSql, statsCounterSqlSlowQueries, "action")
 )
 
 type errorFunc func() error
@@ -8,12 +8,13 @@ import (
 	"context"
 	"cto-github.cisco.com/NFV-BU/go-msx/trace"
 	"database/sql/driver"
-	"strings"
 )
 
 // conn defines a tracing wrapper for driver.Stmt.
 type stmt struct {
-	stmt driver.Stmt
+	stmt  driver.Stmt
+	conn  *conn
+	query string
 }
 
 // Close implements driver.Stmt Close.
@@ -36,15 +37,22 @@ func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
 	return s.stmt.Query(args)
 }
 
-// ExecContext implements driver.ExecerContext ExecContext.
-func (s *stmt) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+// ExecContext implements driver.StmtExecContext ExecContext.
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (result driver.Result, err error) {
 	ctx, span := trace.NewSpan(ctx, "sql.Stmt.ExecContext",
 		trace.StartWithTag(trace.FieldSpanType, "db"))
-	span.SetTag(TagQuery, strings.Fields(query)[0])
+	span.SetTag(TagQuery, queryLabel(s.query))
 	defer span.Finish()
 
-	if execerContext, ok := s.stmt.(driver.ExecerContext); ok {
-		return execerContext.ExecContext(ctx, query, args)
+	if execerContext, ok := s.stmt.(driver.StmtExecContext); ok {
+		ctx, observation := s.conn.observeQuery(ctx, s.query, args)
+		defer func() { observation.finish(err) }()
+
+		err = observeStats(s.query, func() error {
+			result, err = execerContext.ExecContext(ctx, args)
+			return err
+		})
+		return
 	}
 	values, err := namedValueToValue(args)
 	if err != nil {
@@ -53,16 +61,24 @@ func (s *stmt) ExecContext(ctx context.Context, query string, args []driver.Name
 	return s.Exec(values)
 }
 
-// QueryContext implements driver.QueryerContext QueryContext.
-func (s *stmt) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (rows driver.Rows, err error) {
+// QueryContext implements driver.StmtQueryContext QueryContext.
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (rows driver.Rows, err error) {
 	ctx, span := trace.NewSpan(ctx, "sql.Stmt.QueryContext",
 		trace.StartWithTag(trace.FieldSpanType, "db"))
-	span.SetTag(TagQuery, strings.Fields(query)[0])
+	span.SetTag(TagQuery, queryLabel(s.query))
 	defer span.Finish()
 
-	if queryerContext, ok := s.stmt.(driver.QueryerContext); ok {
-		rows, err := queryerContext.QueryContext(ctx, query, args)
-		return rows, err
+	if queryerContext, ok := s.stmt.(driver.StmtQueryContext); ok {
+		ctx, observation := s.conn.observeQuery(ctx, s.query, args)
+		err = observeStats(s.query, func() error {
+			rows, err = queryerContext.QueryContext(ctx, args)
+			return err
+		})
+		if err != nil {
+			observation.finish(err)
+			return nil, err
+		}
+		return observation.rows(rows), nil
 	}
 	values, err := namedValueToValue(args)
 	if err != nil {
@@ -18,20 +18,30 @@ const (
 	TransactionManagerMaxRetriesDefault = 5
 )
 
-func TransactionDecorator(action types.ActionFunc) types.ActionFunc {
+// TransactionDecorator executes the action within a transaction.  When the context already
+// has an active transaction, the action is executed within a savepoint instead.
+func TransactionDecorator(action types.ActionFunc, opts ...TransactionOption) types.ActionFunc {
 	return func(ctx context.Context) error {
+		options := newTransactionOptions(opts)
+
+		if scope := contextTransactionScope().Get(ctx); scope != nil {
+			return scope.nested(ctx, options, action)
+		}
+
 		pool, err := PoolFromContext(ctx)
 		if err != nil {
 			return err
 		}
 
-		err = pool.WithSqlxConnection(ctx, func(ctx context.Context, conn *sqlx.DB) error {
-			tx, err := conn.Beginx()
+		err = pool.WithSqlxConnection(ctx, func(hookCtx context.Context, conn *sqlx.DB) error {
+			tx, err := conn.BeginTxx(hookCtx, options.TxOptions())
 			if err != nil {
 				return err
 			}
 
-			ctx = ContextSqlExecutor().Set(ctx, tx)
+			scope := newTransactionScope(tx, options)
+			ctx := ContextSqlExecutor().Set(hookCtx, tx)
+			ctx = contextTransactionScope().Set(ctx, scope)
 
 			cfg := config.MustFromContext(ctx)
 			driver, err := cfg.String("spring.datasource.driver")
@@ -71,6 +81,7 @@ func TransactionDecorator(action types.ActionFunc) types.ActionFunc {
 
 					err = tx.Rollback()
 					if err != nil {
+						scope.runAfterRollback(hookCtx)
 						return err
 					}
 				}
@@ -79,21 +90,31 @@ func TransactionDecorator(action types.ActionFunc) types.ActionFunc {
 					err = actionErr
 				}
 
+				if err != nil {
+					scope.runAfterRollback(hookCtx)
+				}
+
 				if doRetry {
 					retryCounter++
 					logger.WithContext(ctx).Error(err)
 					logger.WithContext(ctx).Infof("retrying transaction: %d", retryCounter)
 
 					// create a new transaction and re inject in the ctx
-					tx, err = conn.Beginx()
+					tx, err = conn.BeginTxx(hookCtx, options.TxOptions())
 					if err != nil {
 						return err
 					}
 
-					ctx = ContextSqlExecutor().Set(ctx, tx)
+					scope = newTransactionScope(tx, options)
+					ctx = ContextSqlExecutor().Set(hookCtx, tx)
+					ctx = contextTransactionScope().Set(ctx, scope)
 				}
 			}
 
+			if err == nil {
+				scope.runAfterCommit(hookCtx)
+			}
+
 			return err
 		})
 
@@ -102,7 +123,7 @@ func TransactionDecorator(action types.ActionFunc) types.ActionFunc {
 }
 
 type TransactionManager interface {
-	WithTransaction(ctx context.Context, action types.ActionFunc) error
+	WithTransaction(ctx context.Context, action types.ActionFunc, opts ...TransactionOption) error
 }
 
 func ContextTransactionManager() types.ContextKeyAccessor[TransactionManager] {
@@ -119,8 +140,8 @@ func NewTransactionManager(ctx context.Context) (TransactionManager, error) {
 
 type SqlTransactionManager struct{}
 
-func (t SqlTransactionManager) WithTransaction(ctx context.Context, action types.ActionFunc) error {
-	wrappedAction := TransactionDecorator(action)
+func (t SqlTransactionManager) WithTransaction(ctx context.Context, action types.ActionFunc, opts ...TransactionOption) error {
+	wrappedAction := TransactionDecorator(action, opts...)
 	return wrappedAction(ctx)
 }
 
@@ -6,8 +6,10 @@ package sqldb
 
 import (
 	"context"
+	"database/sql"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"modernc.org/sqlite"
 	"testing"
 )
 
@@ -43,3 +45,167 @@ func Test_TransactionManager_Rollback(t *testing.T) {
 	})
 	assert.Error(t, err)
 }
+
+func newTransactionTestContext(t *testing.T, table string) (context.Context, *sql.DB) {
+	ctx := sqlite3DBContext()
+
+	err := ConfigurePool(ctx)
+	assert.NoError(t, err)
+	ctx = ContextWithPool(ctx)
+
+	drivers["sqlite3"] = &sqlite.Driver{}
+
+	myPool, err := PoolFromContext(ctx)
+	assert.NoError(t, err)
+
+	// Keep the shared in-memory database open for the duration of the test
+	db, err := myPool.NewSqlConnection()
+	assert.NoError(t, err)
+
+	_, err = db.Exec("DROP TABLE IF EXISTS " + table)
+	assert.NoError(t, err)
+	_, err = db.Exec("CREATE TABLE " + table + " (id VARCHAR(36) NOT NULL PRIMARY KEY, name TEXT)")
+	assert.NoError(t, err)
+
+	t.Cleanup(func() {
+		_, _ = db.Exec("DROP TABLE " + table)
+		_ = db.Close()
+	})
+
+	return ctx, db
+}
+
+func insertPerson(ctx context.Context, table string, id string) error {
+	rsql, err := NewSqlRepository(ctx)
+	if err != nil {
+		return err
+	}
+	return rsql.SqlExecute(ctx, "INSERT INTO "+table+" VALUES ($1, $2)", []any{id, mockName})
+}
+
+func countPersons(t *testing.T, db *sql.DB, table string) (count int) {
+	err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&count)
+	assert.NoError(t, err)
+	return
+}
+
+func Test_TransactionManager_Nested(t *testing.T) {
+	const table = "persons_nested"
+	ctx, db := newTransactionTestContext(t, table)
+
+	var hooks []string
+	hook := func(name string) func(context.Context) error {
+		return func(context.Context) error {
+			hooks = append(hooks, name)
+			return nil
+		}
+	}
+
+	tm, err := NewTransactionManager(ctx)
+	assert.NoError(t, err)
+
+	err = tm.WithTransaction(ctx, func(ctx context.Context) error {
+		assert.True(t, InTransaction(ctx))
+		assert.NoError(t, OnAfterCommit(ctx, hook("outer-commit")))
+
+		if err := insertPerson(ctx, table, "1"); err != nil {
+			return err
+		}
+
+		innerErr := tm.WithTransaction(ctx, func(ctx context.Context) error {
+			assert.NoError(t, OnAfterCommit(ctx, hook("failed-commit")))
+			OnAfterRollback(ctx, hook("failed-rollback"))
+
+			if err := insertPerson(ctx, table, "2"); err != nil {
+				return err
+			}
+			return errors.New("inner failure")
+		})
+		assert.EqualError(t, innerErr, "inner failure")
+
+		return tm.WithTransaction(ctx, func(ctx context.Context) error {
+			assert.NoError(t, OnAfterCommit(ctx, hook("released-commit")))
+			return insertPerson(ctx, table, "3")
+		})
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, countPersons(t, db, table))
+	assert.Equal(t, []string{"failed-rollback", "outer-commit", "released-commit"}, hooks)
+}
+
+func Test_TransactionManager_RollbackHooks(t *testing.T) {
+	const table = "persons_rollback"
+	ctx, db := newTransactionTestContext(t, table)
+
+	var committed, rolledBack bool
+
+	err := TransactionDecorator(func(ctx context.Context) error {
+		assert.NoError(t, OnAfterCommit(ctx, func(context.Context) error {
+			committed = true
+			return nil
+		}))
+		OnAfterRollback(ctx, func(context.Context) error {
+			rolledBack = true
+			return nil
+		})
+
+		if err := insertPerson(ctx, table, "1"); err != nil {
+			return err
+		}
+		return errors.New("outer failure")
+	})(ctx)
+	assert.EqualError(t, err, "outer failure")
+
+	assert.Equal(t, 0, countPersons(t, db, table))
+	assert.False(t, committed)
+	assert.True(t, rolledBack)
+}
+
+func Test_TransactionManager_CommitHookFailed(t *testing.T) {
+	const table = "persons_commit_hook"
+	ctx, db := newTransactionTestContext(t, table)
+
+	var committed bool
+
+	err := TransactionDecorator(func(ctx context.Context) error {
+		assert.NoError(t, OnAfterCommit(ctx, func(context.Context) error {
+			return errors.New("hook failure")
+		}))
+		assert.NoError(t, OnAfterCommit(ctx, func(context.Context) error {
+			committed = true
+			return nil
+		}))
+
+		return insertPerson(ctx, table, "1")
+	})(ctx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, countPersons(t, db, table))
+	assert.True(t, committed)
+}
+
+func Test_TransactionManager_NestedOptions(t *testing.T) {
+	const table = "persons_options"
+	ctx, _ := newTransactionTestContext(t, table)
+
+	err := TransactionDecorator(func(ctx context.Context) error {
+		return TransactionDecorator(func(ctx context.Context) error {
+			return nil
+		}, WithIsolationLevel(sql.LevelSerializable))(ctx)
+	})(ctx)
+	assert.ErrorIs(t, err, ErrIncompatibleTransactionOptions)
+}
+
+func Test_OnAfterCommit_NoTransaction(t *testing.T) {
+	ctx := context.Background()
+	assert.False(t, InTransaction(ctx))
+
+	called := false
+	err := OnAfterCommit(ctx, func(context.Context) error {
+		called = true
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
@@ -0,0 +1,187 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package sqldb
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"database/sql"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"sync"
+)
+
+var ErrIncompatibleTransactionOptions = errors.New("Nested transaction options differ from the enclosing transaction")
+
+type TransactionOptions struct {
+	Isolation sql.IsolationLevel
+	ReadOnly  bool
+}
+
+func (o TransactionOptions) TxOptions() *sql.TxOptions {
+	return &sql.TxOptions{
+		Isolation: o.Isolation,
+		ReadOnly:  o.ReadOnly,
+	}
+}
+
+type TransactionOption func(options *TransactionOptions)
+
+// WithIsolationLevel sets the isolation level of a new transaction.
+func WithIsolationLevel(level sql.IsolationLevel) TransactionOption {
+	return func(options *TransactionOptions) {
+		options.Isolation = level
+	}
+}
+
+// WithReadOnly starts a read-only transaction.
+func WithReadOnly() TransactionOption {
+	return func(options *TransactionOptions) {
+		options.ReadOnly = true
+	}
+}
+
+func newTransactionOptions(opts []TransactionOption) TransactionOptions {
+	var options TransactionOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// transactionScope tracks a transaction, or a savepoint within a transaction,
+// along with the hooks registered while it is active.
+type transactionScope struct {
+	mtx           sync.Mutex
+	root          *transactionScope
+	tx            *sqlx.Tx
+	options       TransactionOptions
+	savepoints    int
+	afterCommit   []types.ActionFunc
+	afterRollback []types.ActionFunc
+}
+
+func contextTransactionScope() types.ContextKeyAccessor[*transactionScope] {
+	return types.NewContextKeyAccessor[*transactionScope](contextKeyTransactionScope)
+}
+
+func newTransactionScope(tx *sqlx.Tx, options TransactionOptions) *transactionScope {
+	scope := &transactionScope{
+		tx:      tx,
+		options: options,
+	}
+	scope.root = scope
+	return scope
+}
+
+func (s *transactionScope) onAfterCommit(hook types.ActionFunc) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.afterCommit = append(s.afterCommit, hook)
+}
+
+func (s *transactionScope) onAfterRollback(hook types.ActionFunc) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.afterRollback = append(s.afterRollback, hook)
+}
+
+func (s *transactionScope) nextSavepoint() string {
+	s.root.mtx.Lock()
+	defer s.root.mtx.Unlock()
+	s.root.savepoints++
+	return fmt.Sprintf("sqldb_savepoint_%d", s.root.savepoints)
+}
+
+// merge moves the hooks of a released savepoint into its enclosing scope.
+func (s *transactionScope) merge(child *transactionScope) {
+	child.mtx.Lock()
+	defer child.mtx.Unlock()
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.afterCommit = append(s.afterCommit, child.afterCommit...)
+	s.afterRollback = append(s.afterRollback, child.afterRollback...)
+}
+
+// nested executes the action within a savepoint of the current transaction.  If
+// the action fails, only the changes made since the savepoint are rolled back.
+func (s *transactionScope) nested(ctx context.Context, options TransactionOptions, action types.ActionFunc) error {
+	if options != (TransactionOptions{}) && options != s.options {
+		return ErrIncompatibleTransactionOptions
+	}
+
+	savepoint := s.nextSavepoint()
+	if _, err := s.tx.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return errors.Wrap(err, "Failed to create savepoint")
+	}
+
+	child := &transactionScope{
+		root:    s.root,
+		tx:      s.tx,
+		options: s.options,
+	}
+
+	actionErr := types.RecoverErrorDecorator(action)(contextTransactionScope().Set(ctx, child))
+	if actionErr != nil {
+		if _, err := s.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint); err != nil {
+			return errors.Wrap(err, "Failed to roll back to savepoint")
+		}
+		child.runAfterRollback(ctx)
+		return actionErr
+	}
+
+	if _, err := s.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint); err != nil {
+		return errors.Wrap(err, "Failed to release savepoint")
+	}
+
+	s.merge(child)
+	return nil
+}
+
+// runAfterCommit executes the after-commit hooks.  The transaction has already been
+// committed, so hook failures are logged rather than failing the operation.
+func (s *transactionScope) runAfterCommit(ctx context.Context) {
+	for _, hook := range s.afterCommit {
+		if err := hook(ctx); err != nil {
+			logger.WithContext(ctx).WithError(err).Error("After-commit hook failed")
+		}
+	}
+}
+
+func (s *transactionScope) runAfterRollback(ctx context.Context) {
+	for _, hook := range s.afterRollback {
+		if err := hook(ctx); err != nil {
+			logger.WithContext(ctx).WithError(err).Error("After-rollback hook failed")
+		}
+	}
+}
+
+// InTransaction returns true if the context has an active transaction started
+// by TransactionDecorator.
+func InTransaction(ctx context.Context) bool {
+	return contextTransactionScope().Get(ctx) != nil
+}
+
+// OnAfterCommit registers a hook to be executed after the current transaction commits.
+// Hooks registered within a savepoint are discarded if the savepoint is rolled back.
+// Outside of a transaction, the hook is executed immediately.
+func OnAfterCommit(ctx context.Context, hook types.ActionFunc) error {
+	scope := contextTransactionScope().Get(ctx)
+	if scope == nil {
+		return hook(ctx)
+	}
+
+	scope.onAfterCommit(hook)
+	return nil
+}
+
+// OnAfterRollback registers a hook to be executed after the current transaction,
+// or the current savepoint, is rolled back.  Outside of a transaction, the hook is ignored.
+func OnAfterRollback(ctx context.Context, hook types.ActionFunc) {
+	if scope := contextTransactionScope().Get(ctx); scope != nil {
+		scope.onAfterRollback(hook)
+	}
+}
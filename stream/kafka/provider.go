@@ -85,6 +85,15 @@ func (p *Provider) NewSubscriber(ctx context.Context, name string, streamBinding
 	}
 	saramaConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
 
+	if streamBinding.Consumer.Concurrency > 1 {
+		// Allow concurrent processing of messages from each partition
+		return NewWindowSubscriber(
+			connectionConfig.BrokerAddresses(),
+			streamBinding.Group,
+			saramaConfig,
+			streamBinding.Consumer.Concurrency), nil
+	}
+
 	subscriber, err := kafka.NewSubscriber(
 		kafka.SubscriberConfig{
 			Brokers:               connectionConfig.BrokerAddresses(),
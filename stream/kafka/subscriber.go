@@ -0,0 +1,237 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package kafka
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/stream"
+	"github.com/Shopify/sarama"
+	"github.com/ThreeDotsLabs/watermill-kafka/v2/pkg/kafka"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+const (
+	reconnectRetrySleep = time.Second
+	nackResendSleep     = 100 * time.Millisecond
+)
+
+// WindowSubscriber consumes a topic as a member of a consumer group, delivering
+// up to window unacknowledged messages from each claimed partition.  Offsets are
+// marked in order of delivery, as each message is acknowledged.
+type WindowSubscriber struct {
+	brokers      []string
+	group        string
+	saramaConfig *sarama.Config
+	unmarshaler  kafka.Unmarshaler
+	window       int
+
+	closing   chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+func (s *WindowSubscriber) Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error) {
+	select {
+	case <-s.closing:
+		return nil, stream.ErrSubscriberClosed
+	default:
+	}
+
+	group, err := sarama.NewConsumerGroup(s.brokers, s.group, s.saramaConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create consumer group")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	output := make(chan *message.Message)
+	handler := &windowHandler{
+		ctx:         ctx,
+		output:      output,
+		unmarshaler: s.unmarshaler,
+		window:      s.window,
+	}
+
+	go func() {
+		select {
+		case <-s.closing:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if s.saramaConfig.Consumer.Return.Errors {
+		go func() {
+			for err := range group.Errors() {
+				loggerWatermillKafka.WithError(err).Error("Consumer group error")
+			}
+		}()
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer close(output)
+		defer func() {
+			if err := group.Close(); err != nil {
+				loggerWatermillKafka.WithError(err).Error("Failed to close consumer group")
+			}
+		}()
+		defer cancel()
+
+		for ctx.Err() == nil {
+			// Consume returns on each rebalance, and must be called again to rejoin the group
+			err := group.Consume(ctx, []string{topic}, handler)
+			if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+				return
+			} else if err != nil {
+				loggerWatermillKafka.WithError(err).Errorf("Failed to consume topic %q", topic)
+				select {
+				case <-time.After(reconnectRetrySleep):
+				case <-ctx.Done():
+				}
+			}
+		}
+	}()
+
+	return output, nil
+}
+
+func (s *WindowSubscriber) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.closing)
+	})
+	s.wg.Wait()
+	return nil
+}
+
+func NewWindowSubscriber(brokers []string, group string, saramaConfig *sarama.Config, window int) *WindowSubscriber {
+	if window < 1 {
+		window = 1
+	}
+
+	return &WindowSubscriber{
+		brokers:      brokers,
+		group:        group,
+		saramaConfig: saramaConfig,
+		unmarshaler:  kafka.DefaultMarshaler{},
+		window:       window,
+		closing:      make(chan struct{}),
+	}
+}
+
+type windowHandler struct {
+	ctx         context.Context
+	output      chan<- *message.Message
+	unmarshaler kafka.Unmarshaler
+	window      int
+}
+
+func (h *windowHandler) Setup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h *windowHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+type windowMessage struct {
+	source *sarama.ConsumerMessage
+	msg    *message.Message
+}
+
+func (h *windowHandler) ConsumeClaim(sess sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	slots := make(chan struct{}, h.window)
+	delivered := make(chan windowMessage, h.window)
+	acknowledged := make(chan struct{})
+
+	// Mark offsets in order of delivery
+	go func() {
+		defer close(acknowledged)
+		for m := range delivered {
+			if !h.awaitAck(m) {
+				return
+			}
+			sess.MarkMessage(m.source, "")
+			<-slots
+		}
+	}()
+
+	defer func() {
+		close(delivered)
+		<-acknowledged
+	}()
+
+	for {
+		select {
+		case slots <- struct{}{}:
+		case <-sess.Context().Done():
+			return nil
+		case <-h.ctx.Done():
+			return nil
+		}
+
+		var source *sarama.ConsumerMessage
+		var ok bool
+		select {
+		case source, ok = <-claim.Messages():
+			if !ok {
+				return nil
+			}
+		case <-sess.Context().Done():
+			return nil
+		case <-h.ctx.Done():
+			return nil
+		}
+
+		msg, err := h.unmarshaler.Unmarshal(source)
+		if err != nil {
+			return errors.Wrap(err, "Failed to unmarshal message")
+		}
+		msg.SetContext(h.ctx)
+
+		select {
+		case h.output <- msg:
+		case <-h.ctx.Done():
+			return nil
+		}
+
+		delivered <- windowMessage{
+			source: source,
+			msg:    msg,
+		}
+	}
+}
+
+// awaitAck waits for the message to be acknowledged, redelivering it when rejected.
+func (h *windowHandler) awaitAck(m windowMessage) bool {
+	msg := m.msg
+	for {
+		select {
+		case <-msg.Acked():
+			return true
+		case <-msg.Nacked():
+		case <-h.ctx.Done():
+			return false
+		}
+
+		select {
+		case <-time.After(nackResendSleep):
+		case <-h.ctx.Done():
+			return false
+		}
+
+		msg = msg.Copy()
+		msg.SetContext(h.ctx)
+
+		select {
+		case h.output <- msg:
+		case <-h.ctx.Done():
+			return false
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package kafka
+
+import (
+	"context"
+	"github.com/Shopify/sarama"
+	"github.com/ThreeDotsLabs/watermill-kafka/v2/pkg/kafka"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testConsumerGroupSession struct {
+	sarama.ConsumerGroupSession
+	ctx    context.Context
+	mtx    sync.Mutex
+	marked []int64
+}
+
+func (s *testConsumerGroupSession) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testConsumerGroupSession) MarkMessage(msg *sarama.ConsumerMessage, _ string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.marked = append(s.marked, msg.Offset)
+}
+
+func (s *testConsumerGroupSession) Marked() []int64 {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return append([]int64{}, s.marked...)
+}
+
+type testConsumerGroupClaim struct {
+	sarama.ConsumerGroupClaim
+	messages chan *sarama.ConsumerMessage
+}
+
+func (c *testConsumerGroupClaim) Messages() <-chan *sarama.ConsumerMessage {
+	return c.messages
+}
+
+func receiveMessage(t *testing.T, messages <-chan *message.Message) *message.Message {
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(time.Second):
+		assert.FailNow(t, "Message not received")
+		return nil
+	}
+}
+
+func TestWindowHandler_ConsumeClaim(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	output := make(chan *message.Message)
+	handler := &windowHandler{
+		ctx:         ctx,
+		output:      output,
+		unmarshaler: kafka.DefaultMarshaler{},
+		window:      2,
+	}
+
+	sess := &testConsumerGroupSession{ctx: ctx}
+	claim := &testConsumerGroupClaim{messages: make(chan *sarama.ConsumerMessage, 3)}
+	for offset := int64(0); offset < 3; offset++ {
+		claim.messages <- &sarama.ConsumerMessage{
+			Headers: []*sarama.RecordHeader{{
+				Key:   []byte(kafka.UUIDHeaderKey),
+				Value: []byte{byte('a' + offset)},
+			}},
+			Offset: offset,
+		}
+	}
+	close(claim.messages)
+
+	done := make(chan error)
+	go func() {
+		done <- handler.ConsumeClaim(sess, claim)
+	}()
+
+	first := receiveMessage(t, output)
+	second := receiveMessage(t, output)
+	assert.Equal(t, "a", first.UUID)
+	assert.Equal(t, "b", second.UUID)
+
+	// Window is full
+	select {
+	case msg := <-output:
+		assert.Failf(t, "Unexpected message received", "%s", msg.UUID)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Offsets are marked in order of delivery
+	second.Ack()
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, sess.Marked())
+
+	first.Nack()
+	redelivered := receiveMessage(t, output)
+	assert.Equal(t, "a", redelivered.UUID)
+	redelivered.Ack()
+
+	third := receiveMessage(t, output)
+	assert.Equal(t, "c", third.UUID)
+	third.Ack()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		assert.Fail(t, "ConsumeClaim did not return")
+	}
+
+	assert.Equal(t, []int64{0, 1, 2}, sess.Marked())
+}
@@ -0,0 +1,266 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package stream
+
+import (
+	"context"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+const nackResendSleep = 100 * time.Millisecond
+
+var ErrSubscriberClosed = errors.New("Subscriber closed")
+
+// PartitionKeyFunc returns the partition key of a message.  Messages sharing a
+// partition key are processed in the order they were received.  An empty key
+// does not constrain the processing order of the message.
+type PartitionKeyFunc func(msg *message.Message) (string, error)
+
+// PartitionKeyer is implemented by message listeners which define the partition
+// key of their messages.
+type PartitionKeyer interface {
+	PartitionKey(msg *message.Message) (string, error)
+}
+
+// MetadataPartitionKey returns a PartitionKeyFunc reading the partition key from
+// the specified metadata header.
+func MetadataPartitionKey(headerName string) PartitionKeyFunc {
+	return func(msg *message.Message) (string, error) {
+		return msg.Metadata.Get(headerName), nil
+	}
+}
+
+// OrderedSubscriber delivers messages from the underlying subscriber for
+// concurrent processing.  Messages sharing a partition key are delivered one at
+// a time, in order of receipt.  At most concurrency messages are outstanding;
+// further messages are not received until the oldest outstanding message has
+// been processed.  Underlying messages are acknowledged in order of receipt, so
+// offsets only advance past processed messages.
+type OrderedSubscriber struct {
+	subscriber   message.Subscriber
+	concurrency  int
+	partitionKey PartitionKeyFunc
+
+	closing   chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+func (s *OrderedSubscriber) Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error) {
+	select {
+	case <-s.closing:
+		return nil, ErrSubscriberClosed
+	default:
+	}
+
+	messages, err := s.subscriber.Subscribe(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make(chan *message.Message)
+	delivery := &orderedDelivery{
+		ctx:          ctx,
+		closing:      s.closing,
+		output:       output,
+		partitionKey: s.partitionKey,
+		slots:        make(chan struct{}, s.concurrency),
+		blocked:      make(map[string][]*orderedMessage),
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		delivery.run(messages)
+	}()
+
+	return output, nil
+}
+
+func (s *OrderedSubscriber) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.closing)
+	})
+	err := s.subscriber.Close()
+	s.wg.Wait()
+	return err
+}
+
+func NewOrderedSubscriber(subscriber message.Subscriber, concurrency int, partitionKey PartitionKeyFunc) *OrderedSubscriber {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	return &OrderedSubscriber{
+		subscriber:   subscriber,
+		concurrency:  concurrency,
+		partitionKey: partitionKey,
+		closing:      make(chan struct{}),
+	}
+}
+
+type orderedMessage struct {
+	msg  *message.Message
+	key  string
+	done bool
+}
+
+// orderedDelivery tracks the outstanding messages of a single subscription.
+type orderedDelivery struct {
+	ctx          context.Context
+	closing      <-chan struct{}
+	output       chan<- *message.Message
+	partitionKey PartitionKeyFunc
+	slots        chan struct{}
+	wg           sync.WaitGroup
+
+	mtx      sync.Mutex
+	received []*orderedMessage            // Outstanding messages, in order of receipt
+	blocked  map[string][]*orderedMessage // Messages awaiting completion of an earlier message with the same key
+}
+
+func (d *orderedDelivery) run(messages <-chan *message.Message) {
+	defer func() {
+		d.wg.Wait()
+		close(d.output)
+	}()
+
+	for {
+		select {
+		case d.slots <- struct{}{}:
+		case <-d.closing:
+			return
+		case <-d.ctx.Done():
+			return
+		}
+
+		var msg *message.Message
+		var ok bool
+		select {
+		case msg, ok = <-messages:
+			if !ok {
+				return
+			}
+		case <-d.closing:
+			return
+		case <-d.ctx.Done():
+			return
+		}
+
+		m := &orderedMessage{
+			msg: msg,
+			key: d.key(msg),
+		}
+
+		d.mtx.Lock()
+		d.received = append(d.received, m)
+		deliver := true
+		if m.key != "" {
+			if waiting, inFlight := d.blocked[m.key]; inFlight {
+				d.blocked[m.key] = append(waiting, m)
+				deliver = false
+			} else {
+				d.blocked[m.key] = nil
+			}
+		}
+		d.mtx.Unlock()
+
+		if deliver {
+			d.deliver(m)
+		}
+	}
+}
+
+func (d *orderedDelivery) key(msg *message.Message) string {
+	if d.partitionKey == nil {
+		return ""
+	}
+
+	key, err := d.partitionKey(msg)
+	if err != nil {
+		logger.
+			WithContext(msg.Context()).
+			WithError(err).
+			WithField("messageId", msg.UUID).
+			Warn("Failed to retrieve partition key, message will not be ordered")
+		return ""
+	}
+
+	return key
+}
+
+func (d *orderedDelivery) deliver(m *orderedMessage) {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+
+		for {
+			out := m.msg.Copy()
+			out.SetContext(m.msg.Context())
+
+			select {
+			case d.output <- out:
+			case <-d.closing:
+				return
+			case <-d.ctx.Done():
+				return
+			}
+
+			select {
+			case <-out.Acked():
+				d.complete(m)
+				return
+			case <-out.Nacked():
+				// Redeliver after a short delay, retaining the partition key
+				select {
+				case <-time.After(nackResendSleep):
+				case <-d.closing:
+					return
+				case <-d.ctx.Done():
+					return
+				}
+			case <-d.closing:
+				return
+			case <-d.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (d *orderedDelivery) complete(m *orderedMessage) {
+	d.mtx.Lock()
+	m.done = true
+
+	var next *orderedMessage
+	if m.key != "" {
+		if waiting := d.blocked[m.key]; len(waiting) > 0 {
+			next = waiting[0]
+			d.blocked[m.key] = waiting[1:]
+		} else {
+			delete(d.blocked, m.key)
+		}
+	}
+
+	// Acknowledge the processed prefix of the outstanding messages
+	acked := 0
+	for acked < len(d.received) && d.received[acked].done {
+		d.received[acked].msg.Ack()
+		acked++
+	}
+	d.received = d.received[acked:]
+	d.mtx.Unlock()
+
+	for ; acked > 0; acked-- {
+		<-d.slots
+	}
+
+	if next != nil {
+		d.deliver(next)
+	}
+}
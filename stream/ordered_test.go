@@ -0,0 +1,172 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package stream
+
+import (
+	"context"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// windowTestSubscriber delivers its messages without waiting for acknowledgement.
+type windowTestSubscriber struct {
+	messages chan *message.Message
+}
+
+func (s *windowTestSubscriber) Subscribe(context.Context, string) (<-chan *message.Message, error) {
+	return s.messages, nil
+}
+
+func (s *windowTestSubscriber) Close() error {
+	return nil
+}
+
+func newWindowTestSubscriber(keys ...string) (*windowTestSubscriber, []*message.Message) {
+	s := &windowTestSubscriber{
+		messages: make(chan *message.Message, len(keys)),
+	}
+
+	var sent []*message.Message
+	for i, key := range keys {
+		msg := message.NewMessage(string(rune('a'+i)), message.Payload{})
+		msg.Metadata.Set("key", key)
+		s.messages <- msg
+		sent = append(sent, msg)
+	}
+
+	return s, sent
+}
+
+func receiveMessage(t *testing.T, messages <-chan *message.Message) *message.Message {
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(time.Second):
+		assert.Fail(t, "Message not received")
+		return nil
+	}
+}
+
+// receiveMessages returns the next count messages, indexed by UUID, since
+// messages with distinct keys are delivered concurrently.
+func receiveMessages(t *testing.T, messages <-chan *message.Message, count int) map[string]*message.Message {
+	results := make(map[string]*message.Message)
+	for i := 0; i < count; i++ {
+		if msg := receiveMessage(t, messages); msg != nil {
+			results[msg.UUID] = msg
+		}
+	}
+	return results
+}
+
+func assertNoMessage(t *testing.T, messages <-chan *message.Message) {
+	select {
+	case msg := <-messages:
+		assert.Failf(t, "Unexpected message received", "%s", msg.UUID)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func assertAcked(t *testing.T, msg *message.Message, want bool) {
+	select {
+	case <-msg.Acked():
+		assert.True(t, want, "Message %s acknowledged", msg.UUID)
+	case <-time.After(50 * time.Millisecond):
+		assert.False(t, want, "Message %s not acknowledged", msg.UUID)
+	}
+}
+
+func TestOrderedSubscriber_PartitionKey(t *testing.T) {
+	upstream, sent := newWindowTestSubscriber("1", "2", "1")
+	subscriber := NewOrderedSubscriber(upstream, 4, MetadataPartitionKey("key"))
+	defer subscriber.Close()
+
+	messages, err := subscriber.Subscribe(context.Background(), "topic")
+	assert.NoError(t, err)
+
+	received := receiveMessages(t, messages, 2)
+	first, second := received[sent[0].UUID], received[sent[1].UUID]
+	assert.NotNil(t, first)
+	assert.NotNil(t, second)
+
+	// Third message shares a key with the first
+	assertNoMessage(t, messages)
+
+	second.Ack()
+	assertAcked(t, sent[1], false)
+
+	first.Ack()
+	third := receiveMessage(t, messages)
+	assert.Equal(t, sent[2].UUID, third.UUID)
+	assertAcked(t, sent[0], true)
+	assertAcked(t, sent[1], true)
+	assertAcked(t, sent[2], false)
+
+	third.Ack()
+	assertAcked(t, sent[2], true)
+}
+
+func TestOrderedSubscriber_Backpressure(t *testing.T) {
+	upstream, sent := newWindowTestSubscriber("1", "2", "3")
+	subscriber := NewOrderedSubscriber(upstream, 2, MetadataPartitionKey("key"))
+	defer subscriber.Close()
+
+	messages, err := subscriber.Subscribe(context.Background(), "topic")
+	assert.NoError(t, err)
+
+	received := receiveMessages(t, messages, 2)
+	first, second := received[sent[0].UUID], received[sent[1].UUID]
+	assert.NotNil(t, first)
+	assert.NotNil(t, second)
+	assertNoMessage(t, messages)
+
+	// Completing a later message does not free the window
+	second.Ack()
+	assertNoMessage(t, messages)
+
+	first.Ack()
+	third := receiveMessage(t, messages)
+	assert.Equal(t, sent[2].UUID, third.UUID)
+}
+
+func TestOrderedSubscriber_Nack(t *testing.T) {
+	upstream, sent := newWindowTestSubscriber("1", "1")
+	subscriber := NewOrderedSubscriber(upstream, 2, MetadataPartitionKey("key"))
+	defer subscriber.Close()
+
+	messages, err := subscriber.Subscribe(context.Background(), "topic")
+	assert.NoError(t, err)
+
+	first := receiveMessage(t, messages)
+	first.Nack()
+
+	redelivered := receiveMessage(t, messages)
+	assert.Equal(t, sent[0].UUID, redelivered.UUID)
+	assertAcked(t, sent[0], false)
+
+	redelivered.Ack()
+	second := receiveMessage(t, messages)
+	assert.Equal(t, sent[1].UUID, second.UUID)
+	assertAcked(t, sent[0], true)
+}
+
+func TestOrderedSubscriber_Close(t *testing.T) {
+	upstream, _ := newWindowTestSubscriber("1")
+	subscriber := NewOrderedSubscriber(upstream, 1, nil)
+
+	messages, err := subscriber.Subscribe(context.Background(), "topic")
+	assert.NoError(t, err)
+	receiveMessage(t, messages)
+
+	assert.NoError(t, subscriber.Close())
+
+	_, ok := <-messages
+	assert.False(t, ok)
+
+	_, err = subscriber.Subscribe(context.Background(), "topic")
+	assert.ErrorIs(t, err, ErrSubscriberClosed)
+}
@@ -30,10 +30,17 @@ type MessageListener interface {
 	OnMessage(msg *message.Message) error
 }
 
+// topicListener is a registered listener, along with the partition key
+// ordering its concurrent processing of messages (if any).
+type topicListener struct {
+	action       ListenerAction
+	partitionKey PartitionKeyFunc
+}
+
 var (
 	logger                = log.NewLogger("msx.stream")
 	listenerMux           sync.Mutex
-	listeners             = make(map[string][]ListenerAction)
+	listeners             = make(map[string][]topicListener)
 	router                *message.Router
 	routerLogger          = log.NewLogger("watermill.router")
 	routerWatermillLogger = NewWatermillLoggerAdapter(routerLogger)
@@ -65,7 +72,7 @@ func StartRouter(ctx context.Context) error {
 	registered := 0
 	for topic, topicListeners := range listeners {
 		for _, topicListener := range topicListeners {
-			err = addListener(ctx, topic, topicListener)
+			err = addListener(ctx, topic, topicListener.action, topicListener.partitionKey)
 			switch err {
 			case ErrBinderNotEnabled, ErrConsumerNotEnabled, ErrDisconnected:
 				// Ignore
@@ -124,7 +131,7 @@ func StopRouter(context.Context) error {
 	return nil
 }
 
-func addListener(ctx context.Context, topic string, action ListenerAction) error {
+func addListener(ctx context.Context, topic string, action ListenerAction, partitionKey PartitionKeyFunc) error {
 	subscriber, err := NewSubscriber(ctx, topic)
 	if err != nil {
 		return err
@@ -135,6 +142,10 @@ func addListener(ctx context.Context, topic string, action ListenerAction) error
 		return err
 	}
 
+	if bindingConfig.Consumer.Concurrency > 1 {
+		subscriber = NewOrderedSubscriber(subscriber, bindingConfig.Consumer.Concurrency, partitionKey)
+	}
+
 	index := handlerCounter.Inc()
 	handlerName := fmt.Sprintf("%s-%d", topic, index)
 	router.AddNoPublisherHandler(handlerName, topic, subscriber, listenerHandler(topic, action, bindingConfig))
@@ -142,13 +153,29 @@ func addListener(ctx context.Context, topic string, action ListenerAction) error
 }
 
 func AddListener(topic string, action ListenerAction) error {
+	return addTopicListener(topic, topicListener{action: action})
+}
+
+// AddMessageListener registers the listener to receive messages from the topic.
+// If the listener implements PartitionKeyer, its partition keys order the
+// concurrent processing of the messages it receives.
+func AddMessageListener(topic string, listener MessageListener) error {
+	entry := topicListener{action: listener.OnMessage}
+	if keyer, ok := listener.(PartitionKeyer); ok {
+		entry.partitionKey = keyer.PartitionKey
+	}
+
+	return addTopicListener(topic, entry)
+}
+
+func addTopicListener(topic string, entry topicListener) error {
 	listenerMux.Lock()
 	defer listenerMux.Unlock()
 
 	var err error
 	if listeners == nil {
 		err = ErrRouterRunning
-	} else if action == nil {
+	} else if entry.action == nil {
 		return ErrActionNotSpecified
 	} else if topic == "" {
 		return ErrTopicNotSpecified
@@ -158,18 +185,14 @@ func AddListener(topic string, action ListenerAction) error {
 	}
 
 	if _, ok := listeners[topic]; !ok {
-		listeners[topic] = []ListenerAction{}
+		listeners[topic] = []topicListener{}
 	}
 
-	listeners[topic] = append(listeners[topic], action)
+	listeners[topic] = append(listeners[topic], entry)
 
 	return nil
 }
 
-func AddMessageListener(topic string, listener MessageListener) error {
-	return AddListener(topic, listener.OnMessage)
-}
-
 func listenerHandler(topic string, action ListenerAction, cfg *BindingConfiguration) message.NoPublishHandlerFunc {
 	traceAction := TraceActionInterceptor(cfg, StatsActionInterceptor(cfg, func(msg *message.Message) error {
 		entry := logger.
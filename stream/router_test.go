@@ -20,7 +20,7 @@ func TestAddListener(t *testing.T) {
 	tests := []struct {
 		name      string
 		args      args
-		listeners map[string][]ListenerAction
+		listeners map[string][]topicListener
 		wantErr   bool
 	}{
 		{
@@ -29,7 +29,7 @@ func TestAddListener(t *testing.T) {
 				topic:  "mock",
 				action: dummyAction,
 			},
-			listeners: make(map[string][]ListenerAction),
+			listeners: make(map[string][]topicListener),
 			wantErr:   false,
 		},
 		{
@@ -38,7 +38,7 @@ func TestAddListener(t *testing.T) {
 				topic:  "",
 				action: dummyAction,
 			},
-			listeners: make(map[string][]ListenerAction),
+			listeners: make(map[string][]topicListener),
 			wantErr:   true,
 		},
 		{
@@ -47,7 +47,7 @@ func TestAddListener(t *testing.T) {
 				topic:  "mock",
 				action: nil,
 			},
-			listeners: make(map[string][]ListenerAction),
+			listeners: make(map[string][]topicListener),
 			wantErr:   true,
 		},
 		{
@@ -72,3 +72,40 @@ func TestAddListener(t *testing.T) {
 		})
 	}
 }
+
+type testPartitionKeyListener struct{}
+
+func (l testPartitionKeyListener) OnMessage(*message.Message) error {
+	return nil
+}
+
+func (l testPartitionKeyListener) PartitionKey(msg *message.Message) (string, error) {
+	return msg.Metadata.Get("key"), nil
+}
+
+func TestAddMessageListener(t *testing.T) {
+	listeners = make(map[string][]topicListener)
+	defer func() {
+		listeners = make(map[string][]topicListener)
+	}()
+
+	err := AddMessageListener("mock", ListenerAction(func(msg *message.Message) error { return nil }))
+	assert.NoError(t, err)
+
+	err = AddMessageListener("mock", testPartitionKeyListener{})
+	assert.NoError(t, err)
+
+	err = AddListener("mock", func(msg *message.Message) error { return nil })
+	assert.NoError(t, err)
+
+	// Partition keys are retained per listener
+	if assert.Len(t, listeners["mock"], 3) {
+		assert.Nil(t, listeners["mock"][0].partitionKey)
+		assert.NotNil(t, listeners["mock"][1].partitionKey)
+		assert.Nil(t, listeners["mock"][2].partitionKey)
+	}
+
+	listeners = nil
+	err = AddMessageListener("mock", testPartitionKeyListener{})
+	assert.ErrorIs(t, err, ErrRouterRunning)
+}
@@ -0,0 +1,25 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package tokenrevocation
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// Event announces the revocation of a token, identified by its jti claim.
+type Event struct {
+	Jti       string      `json:"jti"`
+	Expires   *types.Time `json:"expires,omitempty"`
+	UserName  string      `json:"username,omitempty"`
+	Reason    string      `json:"reason,omitempty"`
+	Timestamp types.Time  `json:"timestamp"`
+}
+
+func (e Event) Validate() error {
+	return types.ErrorMap{
+		"jti": validation.Validate(&e.Jti, validation.Required),
+	}.Filter()
+}
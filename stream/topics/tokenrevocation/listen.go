@@ -0,0 +1,90 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package tokenrevocation
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
+	"cto-github.cisco.com/NFV-BU/go-msx/log"
+	"cto-github.cisco.com/NFV-BU/go-msx/security"
+	"cto-github.cisco.com/NFV-BU/go-msx/stream"
+	"encoding/json"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/pkg/errors"
+	"time"
+)
+
+const (
+	TopicTokenRevocation = "TOKEN_REVOCATION_TOPIC"
+
+	configRootTokenRevocation = "security.token.revocation"
+)
+
+var logger = log.NewLogger("msx.stream.topics.tokenrevocation")
+
+var ErrDisabled = errors.New("Token revocation listener disabled")
+
+type RevocationConfig struct {
+	Enabled bool `config:"default=false"`
+}
+
+type EventHandler func(ctx context.Context, event Event) error
+
+func NewEventListener(fn EventHandler) stream.ListenerAction {
+	return func(msg *message.Message) error {
+		var event Event
+		err := json.Unmarshal(msg.Payload, &event)
+		if err != nil {
+			return errors.Wrap(err, "Failed to unmarshal message payload to Event")
+		}
+
+		err = event.Validate()
+		if err != nil {
+			return errors.Wrap(err, "Failed to validate message payload to Event")
+		}
+
+		return fn(msg.Context(), event)
+	}
+}
+
+// AddEventListener adds a listener for token revocation events.  Revocation
+// events must reach every instance, so the binding defaults to a per-instance
+// consumer group (see defaults-tokenrevocation.properties) instead of the
+// shared application consumer group.
+func AddEventListener(fn EventHandler) error {
+	return stream.AddListener(TopicTokenRevocation, NewEventListener(fn))
+}
+
+// RevokeToken adds the token identified by the event to the security revocation list.
+func RevokeToken(ctx context.Context, event Event) error {
+	var expires time.Time
+	if event.Expires != nil {
+		expires = event.Expires.ToTimeTime()
+	}
+
+	logger.WithContext(ctx).Infof("Revoking token %q: %s", event.Jti, event.Reason)
+	security.RevokeToken(event.Jti, expires)
+	return nil
+}
+
+func init() {
+	config.RegisterSchema[RevocationConfig](configRootTokenRevocation)
+}
+
+// RegisterRevocationListener applies revocation events to the security revocation list when enabled.
+// Each instance joins its own consumer group and therefore applies every revocation event.
+func RegisterRevocationListener(ctx context.Context) error {
+	var revocationConfig RevocationConfig
+	if err := config.MustFromContext(ctx).Populate(&revocationConfig, configRootTokenRevocation); err != nil {
+		return err
+	}
+
+	if !revocationConfig.Enabled {
+		return ErrDisabled
+	}
+
+	logger.WithContext(ctx).Info("Registering token revocation listener")
+	return AddEventListener(RevokeToken)
+}
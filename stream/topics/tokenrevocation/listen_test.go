@@ -0,0 +1,83 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package tokenrevocation
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
+	"cto-github.cisco.com/NFV-BU/go-msx/security"
+	"cto-github.cisco.com/NFV-BU/go-msx/stream"
+	"cto-github.cisco.com/NFV-BU/go-msx/testhelpers/streamtest"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewEventListener(t *testing.T) {
+	tests := []struct {
+		name string
+		test *streamtest.TopicReceiveTest
+	}{
+		{
+			name: "Success",
+			test: streamtest.NewTopicReceiveTest().
+				WithPayload([]byte(`{"jti":"abc","timestamp":"2023-01-01T00:00:00Z"}`)).
+				WithWantReceive(true),
+		},
+		{
+			name: "MissingJti",
+			test: streamtest.NewTopicReceiveTest().
+				WithPayload([]byte(`{"timestamp":"2023-01-01T00:00:00Z"}`)).
+				WithWantReceive(false).
+				WithWantError(true),
+		},
+		{
+			name: "PayloadError",
+			test: streamtest.NewTopicReceiveTest().
+				WithPayload([]byte("[")).
+				WithWantReceive(false).
+				WithWantError(true),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, tt.test.
+			WithTopic(TopicTokenRevocation).
+			WithAction(NewEventListener(func(ctx context.Context, event Event) error {
+				streamtest.TopicReceiveTestFromContext(ctx).Received()
+				return nil
+			})).
+			Test)
+	}
+}
+
+func TestRevokeToken(t *testing.T) {
+	assert.NoError(t, RevokeToken(context.Background(), Event{Jti: "revoke-token-test"}))
+	assert.ErrorIs(t,
+		security.CheckTokenRevoked(&security.UserContext{Jti: "revoke-token-test"}),
+		security.ErrTokenRevoked)
+}
+
+func TestTokenRevocationBinding_ConsumerGroup(t *testing.T) {
+	newBindingConfiguration := func(instance string) *stream.BindingConfiguration {
+		providers := append([]config.Provider{}, config.EmbeddedDefaultsProviders...)
+		providers = append(providers, config.NewInMemoryProvider("testdata", map[string]string{
+			"spring.application.name":     "testservice",
+			"spring.application.instance": instance,
+		}))
+
+		cfg := config.NewConfig(providers...)
+		assert.NoError(t, cfg.Load(context.Background()))
+
+		bindingConfig, err := stream.NewBindingConfigurationFromConfig(cfg, TopicTokenRevocation)
+		assert.NoError(t, err)
+		return bindingConfig
+	}
+
+	first := newBindingConfiguration("ABCDE")
+	second := newBindingConfiguration("FGHIJ")
+
+	assert.Equal(t, TopicTokenRevocation+"-testservice-ABCDE", first.Group)
+	assert.NotEqual(t, first.Group, second.Group)
+}
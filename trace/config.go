@@ -47,6 +47,10 @@ func (c TracingReporterConfig) Address() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
 
+func init() {
+	config.RegisterSchema[TracingConfig](configRootTracing)
+}
+
 func NewTracingConfig(cfg *config.Config) (*TracingConfig, error) {
 	var tracingConfig TracingConfig
 	if err := cfg.Populate(&tracingConfig, configRootTracing); err != nil {
@@ -17,6 +17,10 @@ type Config struct {
 	ServiceEnv     string `config:"default="`
 }
 
+func init() {
+	config.RegisterSchema[Config](configRootDatadog)
+}
+
 func NewConfig(ctx context.Context) (*Config, error) {
 	var cfg Config
 	if err := config.FromContext(ctx).Populate(&cfg, configRootDatadog); err != nil {
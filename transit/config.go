@@ -15,6 +15,10 @@ type Config struct {
 	Enabled bool `config:"default=false"`
 }
 
+func init() {
+	config.RegisterSchema[Config](configRootEncryptionConfig)
+}
+
 func NewConfig(ctx context.Context) (*Config, error) {
 	var encryptionConfig Config
 	if err := config.FromContext(ctx).Populate(&encryptionConfig, configRootEncryptionConfig); err != nil {
@@ -0,0 +1,33 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package vaultprovider
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/vault"
+)
+
+// ConfigCipher encrypts configuration values using a named vault transit key.
+type ConfigCipher struct {
+	ctx     context.Context
+	conn    vault.ConnectionApi
+	keyName string
+}
+
+func (c ConfigCipher) Encrypt(plaintext string) (string, error) {
+	return c.conn.TransitEncrypt(c.ctx, c.keyName, plaintext)
+}
+
+func (c ConfigCipher) Decrypt(ciphertext string) (string, error) {
+	return c.conn.TransitDecrypt(c.ctx, c.keyName, ciphertext)
+}
+
+func NewConfigCipher(ctx context.Context, conn vault.ConnectionApi, keyName string) ConfigCipher {
+	return ConfigCipher{
+		ctx:     ctx,
+		conn:    conn,
+		keyName: keyName,
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package vaultprovider
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/vault"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestConfigCipher(t *testing.T) {
+	ctx := context.Background()
+
+	mockConnection := new(vault.MockConnection)
+	mockConnection.On("TransitEncrypt", ctx, "msx-config", "secret").Return("vault:v1:abc", nil)
+	mockConnection.On("TransitDecrypt", ctx, "msx-config", "vault:v1:abc").Return("secret", nil)
+
+	configCipher := NewConfigCipher(ctx, mockConnection, "msx-config")
+
+	ciphertext, err := configCipher.Encrypt("secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "vault:v1:abc", ciphertext)
+
+	plaintext, err := configCipher.Decrypt(ciphertext)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", plaintext)
+
+	mockConnection.AssertExpectations(t)
+}
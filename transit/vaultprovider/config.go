@@ -22,6 +22,10 @@ type Config struct {
 	KeyProperties    KeyPropertiesConfig
 }
 
+func init() {
+	config.RegisterSchema[Config](configRootEncryptionConfig)
+}
+
 func NewEncryptionConfig(cfg *config.Config) (*Config, error) {
 	var encryptionConfig Config
 	if err := cfg.Populate(&encryptionConfig, configRootEncryptionConfig); err != nil {
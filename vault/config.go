@@ -82,6 +82,10 @@ func (c ConnectionConfig) ClientConfig() (*api.Config, error) {
 	return clientConfig, nil
 }
 
+func init() {
+	config.RegisterSchema[ConnectionConfig](configRootVaultConnection)
+}
+
 func newConnectionConfig(cfg *config.Config) (*ConnectionConfig, error) {
 	connectionConfig := &ConnectionConfig{}
 	if err := cfg.Populate(connectionConfig, configRootVaultConnection); err != nil {
@@ -31,6 +31,10 @@ func (c *AppRoleSource) Renewable() bool {
 	return true
 }
 
+func init() {
+	config.RegisterSchema[AppRoleConfig](configRootTokenSourceAppRole)
+}
+
 func NewAppRoleConfig(cfg *config.Config) (*AppRoleConfig, error) {
 	var appRoleConfig AppRoleConfig
 	if err := cfg.Populate(&appRoleConfig, configRootTokenSourceAppRole); err != nil {
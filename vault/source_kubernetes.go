@@ -40,6 +40,10 @@ func (k *KubernetesSource) Renewable() bool {
 	return true
 }
 
+func init() {
+	config.RegisterSchema[KubernetesConfig](configRootTokenSourceKubernetes)
+}
+
 func NewKubernetesConfig(cfg *config.Config) (*KubernetesConfig, error) {
 	kubernetesConfig := KubernetesConfig{}
 	if err := cfg.Populate(&kubernetesConfig, configRootTokenSourceKubernetes); err != nil {
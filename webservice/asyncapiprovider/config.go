@@ -43,6 +43,10 @@ type DocumentationConfig struct {
 	Server    DocumentationServerConfig
 }
 
+func init() {
+	config.RegisterSchema[DocumentationConfig](configRootDocumentation)
+}
+
 func NewDocumentationConfig(ctx context.Context) (*DocumentationConfig, error) {
 	var documentationConfig DocumentationConfig
 	if err := config.FromContext(ctx).Populate(&documentationConfig, configRootDocumentation); err != nil {
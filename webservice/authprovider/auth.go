@@ -37,6 +37,10 @@ type ResourcePatternAuthenticationConfig struct {
 	Whitelist []string `config:"default=/admin/health;/admin/info;/admin/alive"`
 }
 
+func init() {
+	config.RegisterSchema[ResourcePatternAuthenticationConfig](configRootAuthenticationProvider)
+}
+
 func NewResourcePatternAuthenticationConfig(cfg *config.Config) (*ResourcePatternAuthenticationConfig, error) {
 	providerConfig := new(ResourcePatternAuthenticationConfig)
 	if err := cfg.Populate(providerConfig, configRootAuthenticationProvider); err != nil {
@@ -89,7 +93,13 @@ func (f *ResourcePatternAuthenticationProvider) Authenticate(req *restful.Reques
 		return err
 	}
 
-	userContext := security.UserContextFromContext(req.Request.Context())
+	return AuthenticateUser(req.Request.Context())
+}
+
+// AuthenticateUser verifies the user of the context is a client holding the read and
+// write scopes, and that their token has not expired or been revoked.
+func AuthenticateUser(ctx context.Context) error {
+	userContext := security.UserContextFromContext(ctx)
 	if !types.StringStack(userContext.Authorities).Contains(oAuthRoleClient) ||
 		!types.StringStack(userContext.Scopes).Contains(oAuthScopeRead) ||
 		!types.StringStack(userContext.Scopes).Contains(oAuthScopeWrite) {
@@ -97,7 +107,7 @@ func (f *ResourcePatternAuthenticationProvider) Authenticate(req *restful.Reques
 	}
 
 	if userContext.Token != "" {
-		active, err := security.IsTokenActive(req.Request.Context())
+		active, err := security.IsTokenActive(ctx)
 		if err != nil {
 			return err
 		}
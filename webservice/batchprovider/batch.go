@@ -0,0 +1,97 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package batchprovider
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/pkg/errors"
+	"net/http"
+	"strings"
+)
+
+var itemMethods = []interface{}{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+}
+
+// BatchRequest contains the sub-requests to be dispatched.
+type BatchRequest struct {
+	Requests []BatchItemRequest `json:"requests" required:"true" minItems:"1"`
+}
+
+func (r BatchRequest) Validate() error {
+	return types.ErrorMap{
+		"requests": validation.Validate(r.Requests,
+			validation.Required,
+			validation.By(r.validateDependencies)),
+	}
+}
+
+// validateDependencies ensures request ids are unique, and that requests
+// only depend on requests appearing earlier in the batch.
+func (r BatchRequest) validateDependencies(interface{}) error {
+	ids := types.StringSet{}
+	for _, item := range r.Requests {
+		for _, dependency := range item.DependsOn {
+			if !ids.Contains(dependency) {
+				return errors.Wrapf(ErrInvalidDependency,
+					"Request %q depends on unknown or later request %q", item.Id, dependency)
+			}
+		}
+
+		if item.Id == "" {
+			continue
+		}
+
+		if ids.Contains(item.Id) {
+			return errors.Wrapf(ErrInvalidDependency, "Duplicate request id %q", item.Id)
+		}
+
+		ids.Add(item.Id)
+	}
+
+	return nil
+}
+
+// BatchItemRequest describes a single sub-request.
+type BatchItemRequest struct {
+	Id        string            `json:"id,omitempty" description:"Identifies the request within the batch"`
+	Method    string            `json:"method" required:"true" enum:"GET,POST,PUT,PATCH,DELETE"`
+	Path      string            `json:"path" required:"true" description:"Request path and query, relative to the server context path" example:"/api/v1/devices?page=0&pageSize=10"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Body      interface{}       `json:"body,omitempty"`
+	DependsOn []string          `json:"dependsOn,omitempty" description:"Ids of earlier requests which must succeed before this request is dispatched"`
+}
+
+func (r BatchItemRequest) Validate() error {
+	return types.ErrorMap{
+		"method": validation.Validate(r.Method, validation.Required, validation.In(itemMethods...)),
+		"path":   validation.Validate(r.Path, validation.Required, validation.By(validatePath)),
+	}
+}
+
+func validatePath(value interface{}) error {
+	if p, _ := value.(string); !strings.HasPrefix(p, "/") {
+		return errors.New("must be an absolute path")
+	}
+	return nil
+}
+
+// BatchResponse contains the responses to each sub-request, in request order.
+type BatchResponse struct {
+	Responses []BatchItemResponse `json:"responses"`
+}
+
+// BatchItemResponse describes the response to a single sub-request.
+type BatchItemResponse struct {
+	Id      string            `json:"id,omitempty"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    interface{}       `json:"body,omitempty"`
+}
@@ -0,0 +1,80 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package batchprovider
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/validate"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestBatchRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request BatchRequest
+		wantErr bool
+	}{
+		{
+			name: "Valid",
+			request: BatchRequest{Requests: []BatchItemRequest{
+				{Id: "a", Method: http.MethodPost, Path: "/api/v1/widgets"},
+				{Method: http.MethodGet, Path: "/api/v1/widgets", DependsOn: []string{"a"}},
+			}},
+		},
+		{
+			name:    "Empty",
+			request: BatchRequest{},
+			wantErr: true,
+		},
+		{
+			name: "InvalidMethod",
+			request: BatchRequest{Requests: []BatchItemRequest{
+				{Method: http.MethodOptions, Path: "/api/v1/widgets"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "RelativePath",
+			request: BatchRequest{Requests: []BatchItemRequest{
+				{Method: http.MethodGet, Path: "api/v1/widgets"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "DuplicateId",
+			request: BatchRequest{Requests: []BatchItemRequest{
+				{Id: "a", Method: http.MethodGet, Path: "/api/v1/widgets"},
+				{Id: "a", Method: http.MethodGet, Path: "/api/v1/widgets"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "LaterDependency",
+			request: BatchRequest{Requests: []BatchItemRequest{
+				{Id: "a", Method: http.MethodGet, Path: "/api/v1/widgets", DependsOn: []string{"b"}},
+				{Id: "b", Method: http.MethodGet, Path: "/api/v1/widgets"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "UnknownDependency",
+			request: BatchRequest{Requests: []BatchItemRequest{
+				{Id: "a", Method: http.MethodGet, Path: "/api/v1/widgets", DependsOn: []string{"z"}},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validate.Validate(tt.request)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
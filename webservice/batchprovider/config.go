@@ -0,0 +1,31 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package batchprovider
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
+)
+
+const configRootBatch = "server.batch"
+
+type BatchConfig struct {
+	Enabled     bool   `config:"default=false"`
+	Path        string `config:"default=/v1/batch"`
+	MaxRequests int    `config:"default=50"`
+	Concurrency int    `config:"default=8"`
+}
+
+func init() {
+	config.RegisterSchema[BatchConfig](configRootBatch)
+}
+
+func NewBatchConfig(cfg *config.Config) (*BatchConfig, error) {
+	var batchConfig BatchConfig
+	if err := cfg.Populate(&batchConfig, configRootBatch); err != nil {
+		return nil, err
+	}
+
+	return &batchConfig, nil
+}
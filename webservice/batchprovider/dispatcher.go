@@ -0,0 +1,247 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package batchprovider
+
+import (
+	"bytes"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops"
+	"cto-github.cisco.com/NFV-BU/go-msx/trace"
+	"cto-github.cisco.com/NFV-BU/go-msx/webservice"
+	"encoding/json"
+	"github.com/emicklei/go-restful"
+	"github.com/pkg/errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// inheritedHeaders are copied from the batch request to each sub-request, so that
+// sub-requests are authenticated and audited as the caller.
+var inheritedHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"X-Forwarded-For",
+	"X-Ssl-Cert",
+}
+
+// Dispatcher executes batched sub-requests against the restops endpoints of a restful.Container.
+type Dispatcher struct {
+	container   *restful.Container
+	router      restful.RouteSelector
+	contextPath string
+	concurrency int
+	batch       *restops.Endpoint
+}
+
+// Dispatch executes the sub-requests on behalf of the parent request.  Sub-requests
+// are executed concurrently up to the configured limit, after any requests they
+// depend on have completed.
+func (d Dispatcher) Dispatch(parent *http.Request, batch BatchRequest) BatchResponse {
+	items := batch.Requests
+	responses := make([]BatchItemResponse, len(items))
+	done := make([]chan struct{}, len(items))
+	indices := make(map[string]int)
+	for i, item := range items {
+		done[i] = make(chan struct{})
+		if item.Id != "" {
+			indices[item.Id] = i
+		}
+	}
+
+	concurrency := d.concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	semaphore := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i := range items {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer close(done[i])
+
+			item := items[i]
+			for _, dependency := range item.DependsOn {
+				j := indices[dependency]
+				<-done[j]
+				if responses[j].Status >= http.StatusBadRequest {
+					responses[i] = d.errorResponse(item, http.StatusFailedDependency,
+						errors.Wrapf(ErrFailedDependency, "Request %q failed", dependency))
+					return
+				}
+			}
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			responses[i] = d.execute(parent, item)
+		}(i)
+	}
+
+	wg.Wait()
+
+	return BatchResponse{
+		Responses: responses,
+	}
+}
+
+func (d Dispatcher) execute(parent *http.Request, item BatchItemRequest) BatchItemResponse {
+	req, err := d.newRequest(parent, item)
+	if err != nil {
+		return d.errorResponse(item, http.StatusBadRequest, err)
+	}
+
+	_, route, err := d.router.SelectRoute(d.container.RegisteredWebServices(), req)
+	if err != nil {
+		status := http.StatusNotFound
+		var serviceError restful.ServiceError
+		if errors.As(err, &serviceError) {
+			status = serviceError.Code
+		}
+		return d.errorResponse(item, status, err)
+	}
+
+	endpoint, ok := route.Metadata[restops.MetadataKeyEndpoint].(*restops.Endpoint)
+	if !ok {
+		return d.errorResponse(item, http.StatusNotFound, ErrNotBatchable)
+	} else if endpoint == d.batch {
+		return d.errorResponse(item, http.StatusBadRequest, ErrNestedBatch)
+	}
+
+	recorder := newResponseRecorder()
+	d.container.ServeHTTP(recorder, req)
+	return recorder.itemResponse(item.Id)
+}
+
+func (d Dispatcher) newRequest(parent *http.Request, item BatchItemRequest) (*http.Request, error) {
+	var body io.Reader = http.NoBody
+	if item.Body != nil {
+		bodyBytes, err := json.Marshal(item.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to encode request body")
+		}
+		body = bytes.NewReader(bodyBytes)
+	}
+
+	target := item.Path
+	if !strings.HasPrefix(target, d.contextPath+"/") {
+		target = d.contextPath + target
+	}
+
+	req, err := http.NewRequestWithContext(parent.Context(), item.Method, target, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set(restops.HeaderAccept, restops.MediaTypeJson)
+	if item.Body != nil {
+		req.Header.Set(restops.HeaderContentType, restops.MediaTypeJson)
+	}
+
+	for name, value := range item.Headers {
+		req.Header.Set(name, value)
+	}
+
+	for _, name := range inheritedHeaders {
+		req.Header.Del(name)
+		for _, value := range parent.Header.Values(name) {
+			req.Header.Add(name, value)
+		}
+	}
+
+	if span := trace.SpanFromContext(parent.Context()); span != nil {
+		if err = trace.HttpHeadersCarrier(req.Header).Inject(span.Context()); err != nil {
+			logger.WithContext(parent.Context()).WithError(err).Error("Failed to inject tracing into request")
+		}
+	}
+
+	req.RemoteAddr = parent.RemoteAddr
+	req.Proto = parent.Proto
+	req.TLS = parent.TLS
+
+	return req, nil
+}
+
+func (d Dispatcher) errorResponse(item BatchItemRequest, status int, err error) BatchItemResponse {
+	body := new(webservice.ErrorV8)
+	body.ApplyError(err)
+
+	return BatchItemResponse{
+		Id:     item.Id,
+		Status: status,
+		Body:   body,
+	}
+}
+
+// responseRecorder captures the response to a sub-request.
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+	return r.body.Write(data)
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *responseRecorder) itemResponse(id string) BatchItemResponse {
+	response := BatchItemResponse{
+		Id:     id,
+		Status: r.status,
+	}
+
+	if response.Status == 0 {
+		response.Status = http.StatusOK
+	}
+
+	for name := range r.header {
+		if name == restops.HeaderContentLength {
+			continue
+		}
+		if response.Headers == nil {
+			response.Headers = make(map[string]string)
+		}
+		response.Headers[name] = strings.Join(r.header.Values(name), ", ")
+	}
+
+	if r.body.Len() == 0 {
+		return response
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.header.Get(restops.HeaderContentType))
+	if isJsonMediaType(mediaType) && json.Valid(r.body.Bytes()) {
+		response.Body = json.RawMessage(r.body.Bytes())
+	} else {
+		response.Body = r.body.String()
+	}
+
+	return response
+}
+
+func isJsonMediaType(mediaType string) bool {
+	return mediaType == restops.MediaTypeJson || strings.HasSuffix(mediaType, "+json")
+}
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{
+		header: make(http.Header),
+	}
+}
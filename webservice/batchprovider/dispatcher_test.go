@@ -0,0 +1,283 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package batchprovider
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops"
+	"encoding/json"
+	"github.com/emicklei/go-restful"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type widget struct {
+	Id            string `json:"id"`
+	Authorization string `json:"authorization,omitempty" optional:"true"`
+}
+
+type testServer struct {
+	container *restful.Container
+	batch     *restops.Endpoint
+	active    int32
+	peak      int32
+	mtx       sync.Mutex
+	order     []string
+}
+
+func (s *testServer) track(id string) func() {
+	active := atomic.AddInt32(&s.active, 1)
+	for {
+		peak := atomic.LoadInt32(&s.peak)
+		if active <= peak || atomic.CompareAndSwapInt32(&s.peak, peak, active) {
+			break
+		}
+	}
+
+	s.mtx.Lock()
+	s.order = append(s.order, id)
+	s.mtx.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+	return func() {
+		atomic.AddInt32(&s.active, -1)
+	}
+}
+
+func (s *testServer) dispatcher(concurrency int) Dispatcher {
+	return Dispatcher{
+		container:   s.container,
+		router:      restful.CurlyRouter{},
+		contextPath: "/app",
+		concurrency: concurrency,
+		batch:       s.batch,
+	}
+}
+
+func newTestServer(t *testing.T) *testServer {
+	type getInputs struct {
+		WidgetId      string `req:"path"`
+		Authorization string `req:"header" optional:"true"`
+	}
+
+	type createInputs struct {
+		Body widget `req:"body"`
+	}
+
+	type outputs struct {
+		Body widget `resp:"body"`
+	}
+
+	type batchInputs struct {
+		Body BatchRequest `req:"body"`
+	}
+
+	s := &testServer{
+		container: restful.NewContainer(),
+	}
+	s.container.Router(restful.CurlyRouter{})
+
+	ws := new(restful.WebService)
+	ws.Path("/app/api")
+
+	endpoints := restops.Endpoints{
+		restops.NewEndpoint(http.MethodGet, "/v1/widgets", "{widgetId}").
+			WithResponseCodes(restops.GetResponseCodes).
+			WithOutputs(outputs{}).
+			WithHandler(func(inp *getInputs) (out outputs, err error) {
+				defer s.track(inp.WidgetId)()
+				if inp.WidgetId == "missing" {
+					return out, restops.NewStatusCodeError(errors.New("Widget not found"), http.StatusNotFound)
+				}
+				out.Body = widget{Id: inp.WidgetId, Authorization: inp.Authorization}
+				return
+			}),
+		restops.NewEndpoint(http.MethodPost, "/v1/widgets").
+			WithResponseCodes(restops.CreateResponseCodes).
+			WithOutputs(outputs{}).
+			WithHandler(func(inp *createInputs) (out outputs, err error) {
+				defer s.track(inp.Body.Id)()
+				out.Body = inp.Body
+				return
+			}),
+		restops.NewEndpoint(http.MethodPost, "/v1/batch").
+			WithResponseCodes(restops.GetResponseCodes).
+			WithHandler(func(inp *batchInputs) {}),
+	}
+
+	for _, endpoint := range endpoints {
+		e, err := endpoint.Build()
+		assert.NoError(t, err)
+		if e.Path == "/v1/batch" {
+			s.batch = e
+		}
+		ws.Route(restops.RouteBuilderFromEndpoint(ws, e))
+	}
+
+	ws.Route(ws.GET("/v1/raw").Produces(restful.MIME_JSON).To(func(req *restful.Request, resp *restful.Response) {
+		resp.WriteHeader(http.StatusOK)
+	}))
+
+	s.container.Add(ws)
+	return s
+}
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		batch   BatchRequest
+		want    []int
+		wantIds []string
+	}{
+		{
+			name: "Success",
+			batch: BatchRequest{Requests: []BatchItemRequest{
+				{Id: "a", Method: http.MethodGet, Path: "/api/v1/widgets/a"},
+				{Id: "b", Method: http.MethodPost, Path: "/api/v1/widgets", Body: widget{Id: "b"}},
+			}},
+			want:    []int{http.StatusOK, http.StatusCreated},
+			wantIds: []string{"a", "b"},
+		},
+		{
+			name: "ContextPath",
+			batch: BatchRequest{Requests: []BatchItemRequest{
+				{Method: http.MethodGet, Path: "/app/api/v1/widgets/a"},
+			}},
+			want:    []int{http.StatusOK},
+			wantIds: []string{""},
+		},
+		{
+			name: "HandlerError",
+			batch: BatchRequest{Requests: []BatchItemRequest{
+				{Id: "a", Method: http.MethodGet, Path: "/api/v1/widgets/missing"},
+			}},
+			want:    []int{http.StatusNotFound},
+			wantIds: []string{"a"},
+		},
+		{
+			name: "FailedDependency",
+			batch: BatchRequest{Requests: []BatchItemRequest{
+				{Id: "a", Method: http.MethodGet, Path: "/api/v1/widgets/missing"},
+				{Id: "b", Method: http.MethodGet, Path: "/api/v1/widgets/b", DependsOn: []string{"a"}},
+				{Id: "c", Method: http.MethodGet, Path: "/api/v1/widgets/c", DependsOn: []string{"b"}},
+			}},
+			want:    []int{http.StatusNotFound, http.StatusFailedDependency, http.StatusFailedDependency},
+			wantIds: []string{"a", "b", "c"},
+		},
+		{
+			name: "UnknownRoute",
+			batch: BatchRequest{Requests: []BatchItemRequest{
+				{Method: http.MethodGet, Path: "/api/v1/gadgets"},
+			}},
+			want:    []int{http.StatusNotFound},
+			wantIds: []string{""},
+		},
+		{
+			name: "MethodNotAllowed",
+			batch: BatchRequest{Requests: []BatchItemRequest{
+				{Method: http.MethodDelete, Path: "/api/v1/widgets/a"},
+			}},
+			want:    []int{http.StatusMethodNotAllowed},
+			wantIds: []string{""},
+		},
+		{
+			name: "NotBatchable",
+			batch: BatchRequest{Requests: []BatchItemRequest{
+				{Method: http.MethodGet, Path: "/api/v1/raw"},
+			}},
+			want:    []int{http.StatusNotFound},
+			wantIds: []string{""},
+		},
+		{
+			name: "Nested",
+			batch: BatchRequest{Requests: []BatchItemRequest{
+				{Method: http.MethodPost, Path: "/api/v1/batch", Body: BatchRequest{}},
+			}},
+			want:    []int{http.StatusBadRequest},
+			wantIds: []string{""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			parent := httptest.NewRequest(http.MethodPost, "/app/api/v1/batch", nil)
+
+			got := s.dispatcher(4).Dispatch(parent, tt.batch)
+
+			var gotStatus []int
+			var gotIds []string
+			for _, response := range got.Responses {
+				gotStatus = append(gotStatus, response.Status)
+				gotIds = append(gotIds, response.Id)
+			}
+			assert.Equal(t, tt.want, gotStatus)
+			assert.Equal(t, tt.wantIds, gotIds)
+		})
+	}
+}
+
+func TestDispatcher_Dispatch_Body(t *testing.T) {
+	s := newTestServer(t)
+	parent := httptest.NewRequest(http.MethodPost, "/app/api/v1/batch", nil)
+	parent.Header.Set("Authorization", "Bearer caller")
+
+	got := s.dispatcher(1).Dispatch(parent, BatchRequest{Requests: []BatchItemRequest{
+		{
+			Method:  http.MethodGet,
+			Path:    "/api/v1/widgets/a",
+			Headers: map[string]string{"Authorization": "Bearer impostor"},
+		},
+	}})
+
+	assert.Len(t, got.Responses, 1)
+	response := got.Responses[0]
+	assert.Equal(t, http.StatusOK, response.Status)
+	assert.Contains(t, response.Headers[restops.HeaderContentType], restops.MediaTypeJson)
+
+	body, ok := response.Body.(json.RawMessage)
+	assert.True(t, ok)
+	assert.JSONEq(t, `{"id":"a","authorization":"Bearer caller"}`, string(body))
+}
+
+func TestDispatcher_Dispatch_Concurrency(t *testing.T) {
+	var items []BatchItemRequest
+	for _, id := range []string{"a", "b", "c", "d", "e", "f"} {
+		items = append(items, BatchItemRequest{Id: id, Method: http.MethodGet, Path: "/api/v1/widgets/" + id})
+	}
+
+	s := newTestServer(t)
+	parent := httptest.NewRequest(http.MethodPost, "/app/api/v1/batch", nil)
+
+	got := s.dispatcher(2).Dispatch(parent, BatchRequest{Requests: items})
+
+	assert.Len(t, got.Responses, len(items))
+	for i, response := range got.Responses {
+		assert.Equal(t, items[i].Id, response.Id)
+		assert.Equal(t, http.StatusOK, response.Status)
+	}
+	assert.LessOrEqual(t, s.peak, int32(2))
+	assert.Greater(t, s.peak, int32(1))
+}
+
+func TestDispatcher_Dispatch_Dependencies(t *testing.T) {
+	s := newTestServer(t)
+	parent := httptest.NewRequest(http.MethodPost, "/app/api/v1/batch", nil)
+
+	got := s.dispatcher(4).Dispatch(parent, BatchRequest{Requests: []BatchItemRequest{
+		{Id: "a", Method: http.MethodPost, Path: "/api/v1/widgets", Body: widget{Id: "a"}},
+		{Id: "b", Method: http.MethodGet, Path: "/api/v1/widgets/b", DependsOn: []string{"a"}},
+		{Id: "c", Method: http.MethodGet, Path: "/api/v1/widgets/c", DependsOn: []string{"b"}},
+	}})
+
+	for _, response := range got.Responses {
+		assert.Less(t, response.Status, http.StatusBadRequest)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, s.order)
+}
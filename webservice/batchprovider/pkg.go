@@ -0,0 +1,22 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+// Package batchprovider serves an endpoint dispatching batches of REST API requests in-process.
+package batchprovider
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/log"
+	"github.com/pkg/errors"
+)
+
+var logger = log.NewPackageLogger()
+
+var (
+	ErrDisabled          = errors.New("Batch endpoint disabled")
+	ErrTooManyRequests   = errors.New("Batch contains too many requests")
+	ErrNotBatchable      = errors.New("Request target is not a batchable endpoint")
+	ErrNestedBatch       = errors.New("Batch requests cannot be nested")
+	ErrFailedDependency  = errors.New("Dependent request failed")
+	ErrInvalidDependency = errors.New("Invalid request dependency")
+)
@@ -0,0 +1,107 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package batchprovider
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops/v8"
+	"cto-github.cisco.com/NFV-BU/go-msx/schema/openapi"
+	"cto-github.cisco.com/NFV-BU/go-msx/webservice"
+	"cto-github.cisco.com/NFV-BU/go-msx/webservice/restfulcontext"
+	"github.com/pkg/errors"
+	"github.com/swaggest/openapi-go/openapi3"
+	"net/http"
+)
+
+const tagName = "Batch"
+
+// Provider serves the batch endpoint.
+type Provider struct {
+	cfg         *BatchConfig
+	contextPath string
+	endpoint    *restops.Endpoint
+}
+
+func (p *Provider) EndpointTransformers() restops.EndpointTransformers {
+	openapi.AddTag(tagName, "Batch Requests")
+
+	return restops.EndpointTransformers{
+		restops.AddEndpointTag(tagName),
+	}
+}
+
+func (p *Provider) Endpoints() (restops.Endpoints, error) {
+	endpoint, err := p.batch().Build()
+	if err != nil {
+		return nil, err
+	}
+
+	p.endpoint = endpoint
+	return restops.Endpoints{endpoint}, nil
+}
+
+func (p *Provider) batch() restops.EndpointBuilder {
+	type inputs struct {
+		Body BatchRequest `req:"body"`
+	}
+
+	type outputs struct {
+		Body BatchResponse `resp:"body"`
+	}
+
+	return v8.
+		NewCommandEndpointBuilder(p.cfg.Path).
+		WithId("batch").
+		WithInputs(inputs{}).
+		WithOutputs(outputs{}).
+		WithDoc(new(openapi3.Operation).
+			WithSummary("Execute a batch of requests").
+			WithDescription("Dispatches each request to the matching endpoint using the credentials of the caller.")).
+		WithHandler(func(req *http.Request, inp *inputs) (out outputs, err error) {
+			if len(inp.Body.Requests) > p.cfg.MaxRequests {
+				err = restops.NewStatusCodeError(
+					errors.Wrapf(ErrTooManyRequests, "Maximum %d requests", p.cfg.MaxRequests),
+					http.StatusBadRequest)
+				return
+			}
+
+			out.Body = p.dispatcher(req.Context()).Dispatch(req, inp.Body)
+			return
+		})
+}
+
+func (p *Provider) dispatcher(ctx context.Context) Dispatcher {
+	return Dispatcher{
+		container:   restfulcontext.ContextContainer().Get(ctx),
+		router:      restfulcontext.ContextRouteSelector().Get(ctx),
+		contextPath: p.contextPath,
+		concurrency: p.cfg.Concurrency,
+		batch:       p.endpoint,
+	}
+}
+
+// RegisterProvider registers the batch endpoint with the web server, if enabled.
+func RegisterProvider(ctx context.Context) error {
+	server := webservice.WebServerFromContext(ctx)
+	if server == nil {
+		return nil
+	}
+
+	cfg, err := NewBatchConfig(config.FromContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	if !cfg.Enabled {
+		return ErrDisabled
+	}
+
+	return restops.ContextEndpointRegisterer(ctx).RegisterEndpoints(&Provider{
+		cfg:         cfg,
+		contextPath: server.ContextPath(),
+	})
+}
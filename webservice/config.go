@@ -45,6 +45,10 @@ func (c WebServerConfig) Url() string {
 	return "http://" + c.Address() + c.ContextPath
 }
 
+func init() {
+	config.RegisterSchema[WebServerConfig](configRootWebServer)
+}
+
 func NewWebServerConfig(cfg *config.Config) (*WebServerConfig, error) {
 	var webServerConfig WebServerConfig
 	if err := cfg.Populate(&webServerConfig, configRootWebServer); err != nil {
@@ -8,14 +8,22 @@ import (
 	"context"
 	"cto-github.cisco.com/NFV-BU/go-msx/config"
 	"cto-github.cisco.com/NFV-BU/go-msx/log"
+	"cto-github.cisco.com/NFV-BU/go-msx/sanitize"
 	"cto-github.cisco.com/NFV-BU/go-msx/webservice"
 	"cto-github.cisco.com/NFV-BU/go-msx/webservice/adminprovider"
 	"github.com/emicklei/go-restful"
 	"strings"
+	"time"
 )
 
 const (
 	endpointName = "env"
+
+	maskedValue = "*****"
+
+	changeOperationAdded   = "added"
+	changeOperationUpdated = "updated"
+	changeOperationRemoved = "removed"
 )
 
 var logger = log.NewLogger("msx.webservice.envprovider")
@@ -38,6 +46,33 @@ type Report struct {
 	PropertySources []PropertySource `json:"propertySources"`
 }
 
+// PropertyOrigin describes a value defined by a single provider.
+type PropertyOrigin struct {
+	Source string `json:"source"`
+	Value  string `json:"value"`
+}
+
+// ResolvedProperty describes a resolved value and where it came from.
+type ResolvedProperty struct {
+	Name       string           `json:"name"`
+	Value      string           `json:"value"`
+	Source     string           `json:"source"`
+	Overridden []PropertyOrigin `json:"overridden,omitempty"`
+	References []string         `json:"references,omitempty"`
+}
+
+type PropertyChange struct {
+	Name      string          `json:"name"`
+	Operation string          `json:"operation"`
+	Previous  *PropertyOrigin `json:"previous,omitempty"`
+	Current   *PropertyOrigin `json:"current,omitempty"`
+}
+
+type HistoryEntry struct {
+	Timestamp time.Time        `json:"timestamp"`
+	Changes   []PropertyChange `json:"changes"`
+}
+
 type Provider struct{}
 
 func (h Provider) EndpointName() string {
@@ -56,6 +91,16 @@ func (h Provider) Actuate(webService *restful.WebService) error {
 		To(adminprovider.RawAdminController(h.report)).
 		Do(webservice.Returns200))
 
+	webService.Route(webService.GET("/properties").
+		Operation("admin.env.properties").
+		To(adminprovider.RawAdminController(h.properties)).
+		Do(webservice.Returns200))
+
+	webService.Route(webService.GET("/history").
+		Operation("admin.env.history").
+		To(adminprovider.RawAdminController(h.history)).
+		Do(webservice.Returns200))
+
 	return nil
 }
 
@@ -87,11 +132,7 @@ func (h Provider) report(req *restful.Request) (body interface{}, err error) {
 		}
 
 		for _, entry := range entries {
-			if h.isSecret(entry.Name) {
-				propertySource.Properties[entry.Name] = Property{"*****"}
-			} else {
-				propertySource.Properties[entry.Name] = Property{entry.Value}
-			}
+			propertySource.Properties[entry.Name] = Property{h.mask(entry.Name, entry.Value)}
 		}
 
 		report.PropertySources = append([]PropertySource{propertySource}, report.PropertySources...)
@@ -100,6 +141,113 @@ func (h Provider) report(req *restful.Request) (body interface{}, err error) {
 	return report, nil
 }
 
+// properties reports each resolved value along with the provider it was loaded
+// from and the lower-precedence values it overrides.
+func (h Provider) properties(req *restful.Request) (body interface{}, err error) {
+	entries := config.FromContext(req.Request.Context()).LatestValues().Entries()
+
+	var results = make([]ResolvedProperty, 0, len(entries))
+	for _, entry := range entries {
+		property := ResolvedProperty{
+			Name:       entry.Name,
+			Value:      h.maskResolved(entry),
+			Source:     h.sourceDescription(entry.ProviderEntry),
+			References: entry.Provenance.References,
+		}
+
+		for _, overridden := range entry.Provenance.Overridden {
+			property.Overridden = append(property.Overridden, PropertyOrigin{
+				Source: h.sourceDescription(overridden),
+				Value:  h.mask(overridden.Name, overridden.Value),
+			})
+		}
+
+		results = append(results, property)
+	}
+
+	return results, nil
+}
+
+// history reports the changes applied by each configuration refresh, most recent first.
+func (h Provider) history(req *restful.Request) (body interface{}, err error) {
+	snapshots := config.FromContext(req.Request.Context()).History()
+
+	var results = make([]HistoryEntry, 0, len(snapshots))
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		entry := HistoryEntry{
+			Timestamp: snapshots[i].Timestamp,
+			Changes:   []PropertyChange{},
+		}
+
+		for _, delta := range snapshots[i].Delta {
+			change := PropertyChange{
+				Name: delta.NewEntry.Name,
+			}
+
+			if delta.OldEntry.Source != nil {
+				change.Previous = h.origin(delta.OldEntry)
+			}
+
+			switch {
+			case delta.IsUnset():
+				change.Name = delta.OldEntry.Name
+				change.Operation = changeOperationRemoved
+			case change.Previous == nil:
+				change.Operation = changeOperationAdded
+				change.Current = h.origin(delta.NewEntry)
+			default:
+				change.Operation = changeOperationUpdated
+				change.Current = h.origin(delta.NewEntry)
+			}
+
+			entry.Changes = append(entry.Changes, change)
+		}
+
+		results = append(results, entry)
+	}
+
+	return results, nil
+}
+
+func (h Provider) origin(entry config.ResolvedEntry) *PropertyOrigin {
+	return &PropertyOrigin{
+		Source: h.sourceDescription(entry.ProviderEntry),
+		Value:  h.maskResolved(entry),
+	}
+}
+
+func (h Provider) sourceDescription(entry config.ProviderEntry) string {
+	if entry.Source == nil {
+		return ""
+	}
+	return entry.Source.Description()
+}
+
+// mask hides the values of secret keys, including values derived from secret keys,
+// and removes any secrets embedded within other values.
+func (h Provider) mask(key, value string, references ...string) string {
+	if h.isSecret(key) {
+		return maskedValue
+	}
+
+	for _, reference := range references {
+		if h.isSecret(reference) {
+			return maskedValue
+		}
+	}
+
+	return sanitize.String(value, sanitize.Options{Secret: true})
+}
+
+// maskResolved hides resolved values which were encrypted at rest or read from files or the
+// environment by placeholder functions, in addition to secrets.
+func (h Provider) maskResolved(entry config.ResolvedEntry) string {
+	if entry.Provenance.Encrypted || entry.Provenance.Sensitive {
+		return maskedValue
+	}
+	return h.mask(entry.Name, entry.ResolvedValue.String(), entry.Provenance.References...)
+}
+
 func (h Provider) isSecret(key string) bool {
 	key = strings.ToLower(key)
 	return strings.Contains(key, "secret") ||
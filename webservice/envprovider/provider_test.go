@@ -0,0 +1,128 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package envprovider
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func newTestRequest(t *testing.T) *restful.Request {
+	defaults := config.NewInMemoryProvider("defaults", map[string]string{
+		"db.password": "default-secret",
+		"db.host":     "localhost",
+	})
+	env := config.NewInMemoryProvider("env", map[string]string{
+		"db.password": "env-secret",
+		"db.url":      "postgres://${db.host}:${db.password}",
+	})
+
+	cfg := config.NewConfig(defaults, env)
+	assert.NoError(t, cfg.Load(context.Background()))
+
+	ctx := config.ContextWithConfig(context.Background(), cfg)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/admin/env/properties", nil)
+	assert.NoError(t, err)
+	return restful.NewRequest(req)
+}
+
+func TestProvider_properties(t *testing.T) {
+	body, err := Provider{}.properties(newTestRequest(t))
+	assert.NoError(t, err)
+
+	assert.Equal(t,
+		[]ResolvedProperty{
+			{
+				Name:   "db.host",
+				Value:  "localhost",
+				Source: "InMemory: defaults",
+			},
+			{
+				Name:   "db.password",
+				Value:  maskedValue,
+				Source: "InMemory: env",
+				Overridden: []PropertyOrigin{
+					{
+						Source: "InMemory: defaults",
+						Value:  maskedValue,
+					},
+				},
+			},
+			{
+				Name:       "db.url",
+				Value:      maskedValue,
+				Source:     "InMemory: env",
+				References: []string{"db.host", "db.password"},
+			},
+		},
+		body)
+}
+
+func TestProvider_history(t *testing.T) {
+	body, err := Provider{}.history(newTestRequest(t))
+	assert.NoError(t, err)
+	assert.Equal(t, []HistoryEntry{}, body)
+}
+
+func TestProvider_mask(t *testing.T) {
+	tests := []struct {
+		name       string
+		key        string
+		value      string
+		references []string
+		want       string
+	}{
+		{
+			name:  "Plain",
+			key:   "server.port",
+			value: "8080",
+			want:  "8080",
+		},
+		{
+			name:  "SecretKey",
+			key:   "spring.security.client-secret",
+			value: "abc",
+			want:  maskedValue,
+		},
+		{
+			name:       "SecretReference",
+			key:        "db.url",
+			value:      "postgres://user:abc@db",
+			references: []string{"db.password"},
+			want:       maskedValue,
+		},
+		{
+			name:  "EmbeddedSecret",
+			key:   "db.options",
+			value: "user=admin,password=abc",
+			want:  "user=admin,password=" + maskedValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Provider{}.mask(tt.key, tt.value, tt.references...))
+		})
+	}
+}
+
+func TestProvider_maskResolved(t *testing.T) {
+	entry := config.ResolvedEntry{
+		ProviderEntry: config.NewEntry(nil, "db.user", "{cipher}abc"),
+		ResolvedValue: "admin",
+	}
+	assert.Equal(t, "admin", Provider{}.maskResolved(entry))
+
+	entry.Provenance.Encrypted = true
+	assert.Equal(t, maskedValue, Provider{}.maskResolved(entry))
+
+	entry.Provenance.Encrypted = false
+	entry.Provenance.Sensitive = true
+	assert.Equal(t, maskedValue, Provider{}.maskResolved(entry))
+}
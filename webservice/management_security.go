@@ -67,6 +67,10 @@ type EndpointSecurityConfig struct {
 	Silenced string `config:"default="`
 }
 
+func init() {
+	config.RegisterSchema[ManagementSecurityConfig](configRootManagementSecurity)
+}
+
 func NewManagementSecurityConfig(ctx context.Context) (*ManagementSecurityConfig, error) {
 	var cfg ManagementSecurityConfig
 	if err := config.FromContext(ctx).Populate(&cfg, configRootManagementSecurity); err != nil {
@@ -0,0 +1,43 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package operationprovider
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
+	"time"
+)
+
+const configRootOperations = "server.operations"
+
+const (
+	StoreMemory = "memory"
+	StoreRedis  = "redis"
+	StoreSql    = "sql"
+)
+
+type OperationsConfig struct {
+	Enabled   bool          `config:"default=false"`
+	Path      string        `config:"default=/v1/operations"`
+	Workers   int           `config:"default=4"`
+	QueueSize int           `config:"default=100"`
+	Store     string        `config:"default=memory"`
+	Retention time.Duration `config:"default=24h"`
+	Prefix    string        `config:"default=op:"`
+	Table     string        `config:"default=async_operation"`
+	Topic     string        `config:"default="`
+}
+
+func init() {
+	config.RegisterSchema[OperationsConfig](configRootOperations)
+}
+
+func NewOperationsConfig(cfg *config.Config) (*OperationsConfig, error) {
+	var operationsConfig OperationsConfig
+	if err := cfg.Populate(&operationsConfig, configRootOperations); err != nil {
+		return nil, err
+	}
+
+	return &operationsConfig, nil
+}
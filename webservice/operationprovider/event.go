@@ -0,0 +1,36 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package operationprovider
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// CompletionEvent announces the completion of an operation.
+type CompletionEvent struct {
+	OperationId types.UUID      `json:"operationId"`
+	Name        string          `json:"name"`
+	Status      OperationStatus `json:"status"`
+	Error       *OperationError `json:"error,omitempty"`
+	Timestamp   types.Time      `json:"timestamp"`
+}
+
+func (e CompletionEvent) Validate() error {
+	return types.ErrorMap{
+		"operationId": validation.Validate(&e.OperationId, validation.Required),
+		"status":      validation.Validate(&e.Status, validation.Required),
+	}.Filter()
+}
+
+func NewCompletionEvent(operation Operation) CompletionEvent {
+	return CompletionEvent{
+		OperationId: operation.Id,
+		Name:        operation.Name,
+		Status:      operation.Status,
+		Error:       operation.Error,
+		Timestamp:   operation.Updated,
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package operationprovider
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops"
+	"github.com/pkg/errors"
+	"net/http"
+)
+
+// acceptedOutputs describes the response to an accepted asynchronous request.
+type acceptedOutputs struct {
+	Location string    `resp:"header" description:"Path of the operation resource to poll"`
+	Body     Operation `resp:"body"`
+}
+
+// Executor executes asynchronous restops endpoints as operations of the context Manager.
+type Executor struct{}
+
+func (e Executor) Outputs() interface{} {
+	return acceptedOutputs{}
+}
+
+func (e Executor) Submit(ctx context.Context, endpoint *restops.Endpoint, call restops.AsyncCall) (interface{}, error) {
+	m := ManagerFromContext(ctx)
+	if m == nil {
+		return nil, restops.NewStatusCodeError(ErrDisabled, http.StatusServiceUnavailable)
+	}
+
+	operation, err := m.Submit(ctx, endpoint.OperationID, endpoint.Permissions, call)
+	if errors.Is(err, ErrQueueFull) {
+		return nil, restops.NewStatusCodeError(err, http.StatusServiceUnavailable)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return acceptedOutputs{
+		Location: m.Location(operation.Id),
+		Body:     operation,
+	}, nil
+}
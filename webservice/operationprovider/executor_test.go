@@ -0,0 +1,97 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package operationprovider
+
+import (
+	"bytes"
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops/v8"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"encoding/json"
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+)
+
+func TestExecutor_Submit(t *testing.T) {
+	type widget struct {
+		Name string `json:"name"`
+	}
+
+	type inputs struct {
+		Body widget `req:"body"`
+	}
+
+	type outputs struct {
+		Body widget `resp:"body"`
+	}
+
+	m := newTestManager(t)
+
+	e, err := v8.NewAsyncCommandEndpointBuilder("/v1/widgets").
+		WithId("createWidget").
+		WithAsync(Executor{}).
+		WithHandler(func(ctx context.Context, inp *inputs) (out outputs, err error) {
+			out.Body = inp.Body
+			return
+		}).
+		Build()
+	assert.NoError(t, err)
+	e.WithInjector(func(ctx context.Context) context.Context {
+		return ContextWithManager(ctx, m)
+	})
+
+	container := restful.NewContainer()
+	ws := new(restful.WebService)
+	ws.Path("/api")
+	ws.Route(restops.RouteBuilderFromEndpoint(ws, e))
+	container.Add(ws)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/widgets", bytes.NewBufferString(`{"name":"a"}`))
+	req.Header.Set(restops.HeaderContentType, restops.MediaTypeJson)
+	req.Header.Set(restops.HeaderAccept, restops.MediaTypeJson)
+	rec := httptest.NewRecorder()
+	container.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+
+	var operation Operation
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &operation))
+	assert.Equal(t, "createWidget", operation.Name)
+	assert.Equal(t, OperationStatusPending, operation.Status)
+	assert.Equal(t, m.Location(operation.Id), rec.Header().Get("Location"))
+
+	id, err := types.ParseUUID(path.Base(rec.Header().Get("Location")))
+	assert.NoError(t, err)
+
+	got := waitForCompletion(t, m, id)
+	assert.Equal(t, OperationStatusSucceeded, got.Status)
+	assert.JSONEq(t, `{"name":"a"}`, string(got.Result))
+}
+
+func TestExecutor_Submit_Disabled(t *testing.T) {
+	_, err := Executor{}.Submit(context.Background(), restops.NewEndpoint(http.MethodPost, "/v1/widgets"), nil)
+	assert.ErrorIs(t, err, ErrDisabled)
+}
+
+func TestExecutor_Submit_QueueFull(t *testing.T) {
+	m := newTestManager(t)
+	for i := 0; i < m.cfg.QueueSize; i++ {
+		m.queue <- struct{}{}
+	}
+
+	ctx := ContextWithManager(context.Background(), m)
+	_, err := Executor{}.Submit(ctx, restops.NewEndpoint(http.MethodPost, "/v1/widgets"), nil)
+	assert.ErrorIs(t, err, ErrQueueFull)
+
+	var statusCoder restops.StatusCodeProvider
+	if assert.ErrorAs(t, err, &statusCoder) {
+		assert.Equal(t, http.StatusServiceUnavailable, statusCoder.StatusCode())
+	}
+}
@@ -0,0 +1,333 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package operationprovider
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops"
+	"cto-github.cisco.com/NFV-BU/go-msx/rbac"
+	"cto-github.cisco.com/NFV-BU/go-msx/security"
+	"cto-github.cisco.com/NFV-BU/go-msx/stream"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"cto-github.cisco.com/NFV-BU/go-msx/webservice"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+)
+
+var manager *Manager
+var managerMtx sync.Mutex
+
+// Manager executes operations on a worker pool, recording their progress in the store.
+type Manager struct {
+	ctx      context.Context
+	cfg      *OperationsConfig
+	pool     *types.WorkerPool
+	queue    chan struct{}
+	store    Store
+	basePath string
+
+	mtx     sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+// Location returns the absolute path of the operation resource.
+func (m *Manager) Location(id types.UUID) string {
+	return path.Join(m.basePath, id.String())
+}
+
+// Submit records a new pending operation owned by the requesting user, and schedules
+// the call on the worker pool.  Callers retrieving the operation must also hold one of
+// the permissions, if any are specified.  Returns ErrQueueFull when the configured
+// number of operations are already pending or running on this instance.
+func (m *Manager) Submit(ctx context.Context, name string, permissions []string, call restops.AsyncCall) (Operation, error) {
+	select {
+	case m.queue <- struct{}{}:
+	default:
+		return Operation{}, ErrQueueFull
+	}
+
+	operation, err := m.submit(ctx, name, permissions, call)
+	if err != nil {
+		<-m.queue
+	}
+	return operation, err
+}
+
+func (m *Manager) submit(ctx context.Context, name string, permissions []string, call restops.AsyncCall) (Operation, error) {
+	id, err := types.NewUUID()
+	if err != nil {
+		return Operation{}, err
+	}
+
+	userContext := security.UserContextFromContext(ctx)
+
+	now := types.NewTime(time.Now())
+	operation := Operation{
+		Id:          id,
+		Name:        name,
+		Status:      OperationStatusPending,
+		Created:     now,
+		Updated:     now,
+		UserName:    userContext.UserName,
+		TenantId:    userContext.TenantId,
+		Permissions: permissions,
+	}
+
+	if err = m.store.Save(ctx, operation); err != nil {
+		return Operation{}, errors.Wrap(err, "Failed to save operation")
+	}
+
+	// Retain the request context values, but not its cancellation
+	jobCtx, cancel := context.WithCancel(detachedContext{
+		Context: m.ctx,
+		values:  ctx,
+	})
+
+	m.mtx.Lock()
+	m.cancels[id.String()] = cancel
+	m.mtx.Unlock()
+
+	go func() {
+		defer func() { <-m.queue }()
+		err := m.pool.Run(func(ctx context.Context) error {
+			return m.execute(ctx, operation, call)
+		}, types.JobContext(jobCtx))
+		if err != nil {
+			logger.WithContext(jobCtx).WithError(err).Errorf("Failed to execute operation %q", operation.Id)
+		}
+	}()
+
+	return operation, nil
+}
+
+func (m *Manager) execute(ctx context.Context, operation Operation, call restops.AsyncCall) error {
+	defer m.release(operation.Id)
+
+	// Skip operations cancelled by another instance while pending
+	if current, err := m.store.Load(ctx, operation.Id); err == nil && current.Status.Completed() {
+		return nil
+	}
+
+	if ctx.Err() == nil {
+		operation.Status = OperationStatusRunning
+		operation.Updated = types.NewTime(time.Now())
+		if err := m.store.Save(ctx, operation); err != nil {
+			return errors.Wrap(err, "Failed to save operation")
+		}
+
+		result, err := call(ctx)
+		if err == nil && result != nil {
+			operation.Result, err = json.Marshal(result)
+		}
+
+		switch {
+		case ctx.Err() != nil:
+			operation.Status = OperationStatusCancelled
+		case err != nil:
+			operation.Status = OperationStatusFailed
+			operation.Error = newOperationError(err)
+		default:
+			operation.Status = OperationStatusSucceeded
+		}
+	} else {
+		operation.Status = OperationStatusCancelled
+	}
+
+	// Preserve cancellation requested by another instance
+	if current, err := m.store.Load(ctx, operation.Id); err == nil && current.Status.Completed() {
+		return nil
+	}
+
+	operation.Updated = types.NewTime(time.Now())
+	if err := m.store.Save(ctx, operation); err != nil {
+		return errors.Wrap(err, "Failed to save operation")
+	}
+
+	m.publish(ctx, operation)
+	return nil
+}
+
+func (m *Manager) release(id types.UUID) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if cancel, ok := m.cancels[id.String()]; ok {
+		cancel()
+		delete(m.cancels, id.String())
+	}
+}
+
+func (m *Manager) publish(ctx context.Context, operation Operation) {
+	if m.cfg.Topic == "" {
+		return
+	}
+
+	err := stream.PublishObject(ctx, m.cfg.Topic, NewCompletionEvent(operation), nil)
+	if err != nil {
+		logger.WithContext(ctx).WithError(err).Errorf("Failed to publish completion of operation %q", operation.Id)
+	}
+}
+
+// Load retrieves the current state of the operation.  Operations not accessible to
+// the requesting user are reported as not found.
+func (m *Manager) Load(ctx context.Context, id types.UUID) (Operation, error) {
+	operation, err := m.store.Load(ctx, id)
+	if err != nil {
+		return Operation{}, err
+	}
+
+	if err = m.authorize(ctx, operation); err != nil {
+		return Operation{}, err
+	}
+
+	return operation, nil
+}
+
+// authorize ensures the requesting user submitted the operation, and holds the
+// permissions of its endpoint.
+func (m *Manager) authorize(ctx context.Context, operation Operation) error {
+	userContext := security.UserContextFromContext(ctx)
+	if userContext.UserName != operation.UserName || !userContext.TenantId.Equals(operation.TenantId) {
+		return ErrOperationNotFound
+	}
+
+	if len(operation.Permissions) > 0 {
+		if err := rbac.HasPermission(ctx, operation.Permissions); err != nil {
+			logger.WithContext(ctx).WithError(err).Warnf("Access to operation %q denied", operation.Id)
+			return ErrOperationNotFound
+		}
+	}
+
+	return nil
+}
+
+// Cancel requests cancellation of an incomplete operation.  Operations executing on
+// this instance have their context cancelled; others are marked as cancelled.
+func (m *Manager) Cancel(ctx context.Context, id types.UUID) (Operation, error) {
+	operation, err := m.Load(ctx, id)
+	if err != nil {
+		return Operation{}, err
+	}
+
+	if operation.Status.Completed() {
+		return operation, ErrOperationCompleted
+	}
+
+	m.mtx.Lock()
+	cancel, ok := m.cancels[id.String()]
+	m.mtx.Unlock()
+
+	if ok {
+		cancel()
+		return operation, nil
+	}
+
+	operation.Status = OperationStatusCancelled
+	operation.Updated = types.NewTime(time.Now())
+	if err = m.store.Save(ctx, operation); err != nil {
+		return Operation{}, errors.Wrap(err, "Failed to save operation")
+	}
+
+	m.publish(ctx, operation)
+	return operation, nil
+}
+
+func newOperationError(err error) *OperationError {
+	status := http.StatusInternalServerError
+	var statusCoder webservice.StatusCodeProvider
+	if errors.As(err, &statusCoder) {
+		status = statusCoder.StatusCode()
+	}
+
+	return &OperationError{
+		Status:  status,
+		Message: err.Error(),
+	}
+}
+
+// detachedContext supplies the values of a request context with the lifetime of the application.
+type detachedContext struct {
+	context.Context
+	values context.Context
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	if v := c.values.Value(key); v != nil {
+		return v
+	}
+	return c.Context.Value(key)
+}
+
+func NewManager(ctx context.Context, cfg *OperationsConfig, store Store, basePath string) (*Manager, error) {
+	if cfg.QueueSize < cfg.Workers {
+		return nil, errors.Errorf("Operation queue size %d must be at least the number of workers %d", cfg.QueueSize, cfg.Workers)
+	}
+
+	pool, err := types.NewWorkerPool(ctx, cfg.Workers)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Manager{
+		ctx:      ctx,
+		cfg:      cfg,
+		pool:     pool,
+		queue:    make(chan struct{}, cfg.QueueSize),
+		store:    store,
+		basePath: basePath,
+		cancels:  make(map[string]context.CancelFunc),
+	}, nil
+}
+
+// ConfigureManager creates the operation manager if enabled.
+func ConfigureManager(ctx context.Context, basePath string) error {
+	managerMtx.Lock()
+	defer managerMtx.Unlock()
+
+	if manager != nil {
+		return nil
+	}
+
+	cfg, err := NewOperationsConfig(config.FromContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	if !cfg.Enabled {
+		return ErrDisabled
+	}
+
+	store, err := NewStore(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	manager, err = NewManager(ctx, cfg, store, basePath)
+	return err
+}
+
+type operationsContextKey int
+
+const contextKeyManager operationsContextKey = iota
+
+func ContextWithManager(ctx context.Context, m *Manager) context.Context {
+	return context.WithValue(ctx, contextKeyManager, m)
+}
+
+// ManagerFromContext returns the context operation manager, or the configured manager.
+func ManagerFromContext(ctx context.Context) *Manager {
+	if m, ok := ctx.Value(contextKeyManager).(*Manager); ok {
+		return m
+	}
+
+	managerMtx.Lock()
+	defer managerMtx.Unlock()
+	return manager
+}
@@ -0,0 +1,338 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package operationprovider
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops"
+	"cto-github.cisco.com/NFV-BU/go-msx/security"
+	"cto-github.cisco.com/NFV-BU/go-msx/testhelpers/streamtest"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newTestManager(t *testing.T) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	cfg := &OperationsConfig{Workers: 2, QueueSize: 2, Retention: time.Hour}
+	m, err := NewManager(ctx, cfg, NewMemoryStore(cfg.Retention), "/app/api/v1/operations")
+	assert.NoError(t, err)
+	return m
+}
+
+// newTestRequestContext returns a request context for a user holding the permissions.
+func newTestRequestContext(permissions ...string) context.Context {
+	tokenDetailsProvider := new(security.MockTokenDetailsProvider)
+	tokenDetailsProvider.
+		On("TokenDetails", mock.Anything).
+		Return(&security.UserContextDetails{Permissions: permissions}, nil)
+
+	return security.ContextWithTokenDetailsProvider(context.Background(), tokenDetailsProvider)
+}
+
+func waitForCompletion(t *testing.T, m *Manager, id types.UUID) (operation Operation) {
+	assert.Eventually(t, func() bool {
+		var err error
+		operation, err = m.Load(context.Background(), id)
+		return err == nil && operation.Status.Completed()
+	}, time.Second, time.Millisecond)
+	return
+}
+
+func TestManager_Submit(t *testing.T) {
+	tests := []struct {
+		name       string
+		call       restops.AsyncCall
+		wantStatus OperationStatus
+		wantResult string
+		wantError  *OperationError
+	}{
+		{
+			name: "Succeeded",
+			call: func(ctx context.Context) (interface{}, error) {
+				return map[string]string{"id": "a"}, nil
+			},
+			wantStatus: OperationStatusSucceeded,
+			wantResult: `{"id":"a"}`,
+		},
+		{
+			name: "NoResult",
+			call: func(ctx context.Context) (interface{}, error) {
+				return nil, nil
+			},
+			wantStatus: OperationStatusSucceeded,
+		},
+		{
+			name: "Failed",
+			call: func(ctx context.Context) (interface{}, error) {
+				return nil, restops.NewStatusCodeError(errors.New("Widget not found"), http.StatusNotFound)
+			},
+			wantStatus: OperationStatusFailed,
+			wantError:  &OperationError{Status: http.StatusNotFound, Message: "Widget not found"},
+		},
+		{
+			name: "InternalError",
+			call: func(ctx context.Context) (interface{}, error) {
+				return nil, errors.New("Widget service unavailable")
+			},
+			wantStatus: OperationStatusFailed,
+			wantError:  &OperationError{Status: http.StatusInternalServerError, Message: "Widget service unavailable"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(t)
+
+			operation, err := m.Submit(context.Background(), "createWidget", nil, tt.call)
+			assert.NoError(t, err)
+			assert.Equal(t, "createWidget", operation.Name)
+			assert.Equal(t, "/app/api/v1/operations/"+operation.Id.String(), m.Location(operation.Id))
+
+			got := waitForCompletion(t, m, operation.Id)
+			assert.Equal(t, tt.wantStatus, got.Status)
+			assert.Equal(t, tt.wantError, got.Error)
+			if tt.wantResult != "" {
+				assert.JSONEq(t, tt.wantResult, string(got.Result))
+			} else {
+				assert.Nil(t, got.Result)
+			}
+		})
+	}
+}
+
+func TestManager_Submit_Context(t *testing.T) {
+	type contextKey struct{}
+
+	m := newTestManager(t)
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), contextKey{}, "value"))
+	operation, err := m.Submit(ctx, "createWidget", nil, func(ctx context.Context) (interface{}, error) {
+		time.Sleep(10 * time.Millisecond)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return ctx.Value(contextKey{}), nil
+	})
+	assert.NoError(t, err)
+
+	// Completing the request does not cancel the operation
+	cancel()
+
+	got := waitForCompletion(t, m, operation.Id)
+	assert.Equal(t, OperationStatusSucceeded, got.Status)
+	assert.Equal(t, `"value"`, string(got.Result))
+}
+
+func TestManager_Submit_QueueFull(t *testing.T) {
+	m := newTestManager(t)
+	release := make(chan struct{})
+	call := func(ctx context.Context) (interface{}, error) {
+		<-release
+		return nil, nil
+	}
+
+	var operations []Operation
+	for i := 0; i < m.cfg.QueueSize; i++ {
+		operation, err := m.Submit(context.Background(), "createWidget", nil, call)
+		assert.NoError(t, err)
+		operations = append(operations, operation)
+	}
+
+	_, err := m.Submit(context.Background(), "createWidget", nil, call)
+	assert.ErrorIs(t, err, ErrQueueFull)
+
+	close(release)
+	for _, operation := range operations {
+		waitForCompletion(t, m, operation.Id)
+	}
+
+	assert.Eventually(t, func() bool {
+		_, err = m.Submit(context.Background(), "createWidget", nil, call)
+		return err == nil
+	}, time.Second, time.Millisecond)
+}
+
+func TestNewManager_QueueSize(t *testing.T) {
+	cfg := &OperationsConfig{Workers: 4, QueueSize: 2}
+	_, err := NewManager(context.Background(), cfg, NewMemoryStore(time.Hour), "/app/api/v1/operations")
+	assert.Error(t, err)
+}
+
+func TestManager_Cancel(t *testing.T) {
+	ctx := newTestRequestContext("MANAGE_WIDGETS")
+
+	t.Run("Running", func(t *testing.T) {
+		m := newTestManager(t)
+		started := make(chan struct{})
+
+		operation, err := m.Submit(ctx, "createWidget", nil, func(ctx context.Context) (interface{}, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		assert.NoError(t, err)
+		<-started
+
+		_, err = m.Cancel(ctx, operation.Id)
+		assert.NoError(t, err)
+
+		got := waitForCompletion(t, m, operation.Id)
+		assert.Equal(t, OperationStatusCancelled, got.Status)
+		assert.Nil(t, got.Error)
+	})
+
+	t.Run("OtherInstance", func(t *testing.T) {
+		m := newTestManager(t)
+		operation := testOperation(OperationStatusRunning, time.Now())
+		assert.NoError(t, m.store.Save(ctx, operation))
+
+		got, err := m.Cancel(ctx, operation.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, OperationStatusCancelled, got.Status)
+
+		got, err = m.Load(ctx, operation.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, OperationStatusCancelled, got.Status)
+	})
+
+	t.Run("OtherInstancePending", func(t *testing.T) {
+		m := newTestManager(t)
+		operation := testOperation(OperationStatusPending, time.Now())
+		assert.NoError(t, m.store.Save(ctx, operation))
+
+		_, err := m.Cancel(ctx, operation.Id)
+		assert.NoError(t, err)
+
+		called := false
+		err = m.execute(context.Background(), operation, func(ctx context.Context) (interface{}, error) {
+			called = true
+			return nil, nil
+		})
+		assert.NoError(t, err)
+		assert.False(t, called)
+
+		got, err := m.Load(ctx, operation.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, OperationStatusCancelled, got.Status)
+	})
+
+	t.Run("Completed", func(t *testing.T) {
+		m := newTestManager(t)
+		operation := testOperation(OperationStatusSucceeded, time.Now())
+		assert.NoError(t, m.store.Save(ctx, operation))
+
+		_, err := m.Cancel(ctx, operation.Id)
+		assert.ErrorIs(t, err, ErrOperationCompleted)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		m := newTestManager(t)
+
+		_, err := m.Cancel(ctx, types.MustNewUUID())
+		assert.ErrorIs(t, err, ErrOperationNotFound)
+	})
+
+	t.Run("OtherUser", func(t *testing.T) {
+		m := newTestManager(t)
+		operation := testOperation(OperationStatusRunning, time.Now())
+		assert.NoError(t, m.store.Save(ctx, operation))
+
+		userCtx := security.ContextWithUserContext(ctx, &security.UserContext{UserName: "mallory"})
+		_, err := m.Cancel(userCtx, operation.Id)
+		assert.ErrorIs(t, err, ErrOperationNotFound)
+
+		got, err := m.store.Load(ctx, operation.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, OperationStatusRunning, got.Status)
+	})
+}
+
+func TestManager_Load(t *testing.T) {
+	tenantId := types.MustNewUUID()
+
+	tests := []struct {
+		name        string
+		userContext *security.UserContext
+		permissions []string
+		wantErr     error
+	}{
+		{
+			name:        "Owner",
+			userContext: &security.UserContext{UserName: "alice", TenantId: tenantId},
+			permissions: []string{"VIEW_WIDGETS"},
+		},
+		{
+			name:        "OtherUser",
+			userContext: &security.UserContext{UserName: "mallory", TenantId: tenantId},
+			permissions: []string{"VIEW_WIDGETS"},
+			wantErr:     ErrOperationNotFound,
+		},
+		{
+			name:        "OtherTenant",
+			userContext: &security.UserContext{UserName: "alice", TenantId: types.MustNewUUID()},
+			permissions: []string{"VIEW_WIDGETS"},
+			wantErr:     ErrOperationNotFound,
+		},
+		{
+			name:        "Anonymous",
+			permissions: []string{"VIEW_WIDGETS"},
+			wantErr:     ErrOperationNotFound,
+		},
+		{
+			name:        "PermissionRevoked",
+			userContext: &security.UserContext{UserName: "alice", TenantId: tenantId},
+			wantErr:     ErrOperationNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(t)
+
+			submitCtx := security.ContextWithUserContext(context.Background(),
+				&security.UserContext{UserName: "alice", TenantId: tenantId})
+			operation, err := m.Submit(submitCtx, "createWidget", []string{"VIEW_WIDGETS"},
+				func(ctx context.Context) (interface{}, error) {
+					return nil, nil
+				})
+			assert.NoError(t, err)
+			assert.Equal(t, "alice", operation.UserName)
+			assert.Equal(t, tenantId, operation.TenantId)
+
+			ctx := newTestRequestContext(tt.permissions...)
+			if tt.userContext != nil {
+				ctx = security.ContextWithUserContext(ctx, tt.userContext)
+			}
+
+			_, err = m.Load(ctx, operation.Id)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestManager_publish(t *testing.T) {
+	const topic = "OPERATION_TOPIC"
+
+	m := newTestManager(t)
+	m.cfg.Topic = topic
+
+	test := streamtest.NewTopicPublishTest().
+		WithTopic(topic).
+		WithCall(func(t *testing.T, ctx context.Context) error {
+			m.publish(ctx, testOperation(OperationStatusSucceeded, time.Now()))
+			return nil
+		})
+
+	t.Run("Success", test.Test)
+}
@@ -0,0 +1,52 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package operationprovider
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"encoding/json"
+)
+
+type OperationStatus string
+
+const (
+	OperationStatusPending   OperationStatus = "pending"
+	OperationStatusRunning   OperationStatus = "running"
+	OperationStatusSucceeded OperationStatus = "succeeded"
+	OperationStatusFailed    OperationStatus = "failed"
+	OperationStatusCancelled OperationStatus = "cancelled"
+)
+
+// Completed returns true once the operation will no longer change status.
+func (s OperationStatus) Completed() bool {
+	switch s {
+	case OperationStatusSucceeded, OperationStatusFailed, OperationStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// Operation describes the state of an asynchronous endpoint execution.
+type Operation struct {
+	Id      types.UUID      `json:"id"`
+	Name    string          `json:"name" description:"Operation ID of the endpoint being executed"`
+	Status  OperationStatus `json:"status" enum:"pending,running,succeeded,failed,cancelled"`
+	Created types.Time      `json:"created"`
+	Updated types.Time      `json:"updated"`
+	Error   *OperationError `json:"error,omitempty" optional:"true"`
+	Result  json.RawMessage `json:"-"`
+
+	// Access control, recorded from the submitting request
+	UserName    string     `json:"-"`
+	TenantId    types.UUID `json:"-"`
+	Permissions []string   `json:"-"`
+}
+
+// OperationError describes the failure of an operation.
+type OperationError struct {
+	Status  int    `json:"status" description:"HTTP status code of the failure"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,24 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+// Package operationprovider executes asynchronous restops endpoints as long-running
+// operations, and serves endpoints to poll, cancel, and retrieve the results of operations.
+package operationprovider
+
+import (
+	"cto-github.cisco.com/NFV-BU/go-msx/log"
+	"github.com/pkg/errors"
+)
+
+var logger = log.NewPackageLogger()
+
+var (
+	ErrDisabled            = errors.New("Operation endpoints disabled")
+	ErrUnknownStore        = errors.New("Unknown operation store")
+	ErrOperationNotFound   = errors.New("Operation not found")
+	ErrOperationIncomplete = errors.New("Operation has not completed")
+	ErrOperationCancelled  = errors.New("Operation was cancelled")
+	ErrOperationCompleted  = errors.New("Operation has already completed")
+	ErrQueueFull           = errors.New("Operation queue is full")
+)
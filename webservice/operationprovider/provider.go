@@ -0,0 +1,159 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package operationprovider
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/config"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops/v8"
+	"cto-github.cisco.com/NFV-BU/go-msx/schema/openapi"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"cto-github.cisco.com/NFV-BU/go-msx/webservice"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"github.com/swaggest/openapi-go/openapi3"
+	"net/http"
+	"path"
+)
+
+const (
+	tagName = "Operations"
+
+	pathSuffixOperationId = "{operationId}"
+)
+
+// Provider serves the operation endpoints.
+type Provider struct {
+	path    string
+	manager *Manager
+}
+
+type operationInputs struct {
+	OperationId types.UUID `req:"path"`
+}
+
+func (p *Provider) EndpointTransformers() restops.EndpointTransformers {
+	openapi.AddTag(tagName, "Asynchronous Operations")
+
+	return restops.EndpointTransformers{
+		restops.AddEndpointTag(tagName),
+	}
+}
+
+func (p *Provider) Endpoints() (restops.Endpoints, error) {
+	return restops.EndpointBuilders{
+		p.retrieve(),
+		p.result(),
+		p.cancel(),
+	}.Endpoints()
+}
+
+func (p *Provider) retrieve() restops.EndpointBuilder {
+	type outputs struct {
+		Body Operation `resp:"body"`
+	}
+
+	return v8.
+		NewRetrieveEndpointBuilder(p.path, pathSuffixOperationId).
+		WithId("getOperation").
+		WithDoc(new(openapi3.Operation).
+			WithSummary("Retrieve the status of an operation")).
+		WithHandler(func(ctx context.Context, inp *operationInputs) (out outputs, err error) {
+			out.Body, err = p.manager.Load(ctx, inp.OperationId)
+			err = operationError(err)
+			return
+		})
+}
+
+func (p *Provider) result() restops.EndpointBuilder {
+	type outputs struct {
+		Body interface{} `resp:"body"`
+	}
+
+	return v8.
+		NewRetrieveEndpointBuilder(p.path, pathSuffixOperationId, "result").
+		WithId("getOperationResult").
+		WithDoc(new(openapi3.Operation).
+			WithSummary("Retrieve the result of a completed operation").
+			WithDescription("Responds with the body returned by the endpoint, or the error that caused the operation to fail.")).
+		WithHandler(func(ctx context.Context, inp *operationInputs) (out outputs, err error) {
+			operation, err := p.manager.Load(ctx, inp.OperationId)
+			if err != nil {
+				err = operationError(err)
+				return
+			}
+
+			switch operation.Status {
+			case OperationStatusSucceeded:
+				if operation.Result != nil {
+					out.Body = json.RawMessage(operation.Result)
+				}
+			case OperationStatusFailed:
+				err = restops.NewStatusCodeError(
+					errors.New(operation.Error.Message),
+					operation.Error.Status)
+			case OperationStatusCancelled:
+				err = restops.NewStatusCodeError(ErrOperationCancelled, http.StatusConflict)
+			default:
+				err = restops.NewStatusCodeError(ErrOperationIncomplete, http.StatusConflict)
+			}
+			return
+		})
+}
+
+func (p *Provider) cancel() restops.EndpointBuilder {
+	type outputs struct {
+		Body Operation `resp:"body"`
+	}
+
+	return v8.
+		NewCommandEndpointBuilder(p.path, pathSuffixOperationId, "cancel").
+		WithId("cancelOperation").
+		WithDoc(new(openapi3.Operation).
+			WithSummary("Cancel an incomplete operation")).
+		WithHandler(func(ctx context.Context, inp *operationInputs) (out outputs, err error) {
+			out.Body, err = p.manager.Cancel(ctx, inp.OperationId)
+			err = operationError(err)
+			return
+		})
+}
+
+func operationError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrOperationNotFound):
+		return restops.NewStatusCodeError(err, http.StatusNotFound)
+	case errors.Is(err, ErrOperationCompleted):
+		return restops.NewStatusCodeError(err, http.StatusConflict)
+	default:
+		return err
+	}
+}
+
+// RegisterProvider configures the operation manager and registers the operation
+// endpoints with the web server, if enabled.
+func RegisterProvider(ctx context.Context) error {
+	server := webservice.WebServerFromContext(ctx)
+	if server == nil {
+		return nil
+	}
+
+	cfg, err := NewOperationsConfig(config.FromContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	basePath := path.Join(server.ContextPath(), restops.PathApiRoot, cfg.Path)
+	if err = ConfigureManager(ctx, basePath); err != nil {
+		return err
+	}
+
+	return restops.ContextEndpointRegisterer(ctx).RegisterEndpoints(&Provider{
+		path:    cfg.Path,
+		manager: ManagerFromContext(ctx),
+	})
+}
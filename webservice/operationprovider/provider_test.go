@@ -0,0 +1,154 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package operationprovider
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/ops/restops"
+	"cto-github.cisco.com/NFV-BU/go-msx/security"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"encoding/json"
+	"github.com/emicklei/go-restful"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestContainer(t *testing.T, m *Manager) *restful.Container {
+	provider := &Provider{path: "/v1/operations", manager: m}
+	endpoints, err := provider.Endpoints()
+	assert.NoError(t, err)
+
+	container := restful.NewContainer()
+	ws := new(restful.WebService)
+	ws.Path("/api")
+	for _, endpoint := range endpoints {
+		ws.Route(restops.RouteBuilderFromEndpoint(ws, endpoint))
+	}
+	container.Add(ws)
+	return container
+}
+
+func TestProvider_Endpoints(t *testing.T) {
+	ctx := context.Background()
+	m := newTestManager(t)
+
+	succeeded := testOperation(OperationStatusSucceeded, time.Now())
+	succeeded.Result = json.RawMessage(`{"id":"a"}`)
+	failed := testOperation(OperationStatusFailed, time.Now())
+	failed.Error = &OperationError{Status: http.StatusNotFound, Message: "Widget not found"}
+	running := testOperation(OperationStatusRunning, time.Now())
+	for _, operation := range []Operation{succeeded, failed, running} {
+		assert.NoError(t, m.store.Save(ctx, operation))
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		userContext *security.UserContext
+		permissions []string
+		wantCode    int
+		wantBody    string
+	}{
+		{
+			name:     "Retrieve",
+			method:   http.MethodGet,
+			path:     "/api/v1/operations/" + running.Id.String(),
+			wantCode: http.StatusOK,
+			wantBody: `"status":"running"`,
+		},
+		{
+			name:     "RetrieveNotFound",
+			method:   http.MethodGet,
+			path:     "/api/v1/operations/" + types.MustNewUUID().String(),
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "Result",
+			method:   http.MethodGet,
+			path:     "/api/v1/operations/" + succeeded.Id.String() + "/result",
+			wantCode: http.StatusOK,
+			wantBody: `{"id":"a"}`,
+		},
+		{
+			name:     "ResultFailed",
+			method:   http.MethodGet,
+			path:     "/api/v1/operations/" + failed.Id.String() + "/result",
+			wantCode: http.StatusNotFound,
+			wantBody: `Widget not found`,
+		},
+		{
+			name:     "ResultIncomplete",
+			method:   http.MethodGet,
+			path:     "/api/v1/operations/" + running.Id.String() + "/result",
+			wantCode: http.StatusConflict,
+		},
+		{
+			name:     "CancelCompleted",
+			method:   http.MethodPost,
+			path:     "/api/v1/operations/" + succeeded.Id.String() + "/cancel",
+			wantCode: http.StatusConflict,
+		},
+		{
+			name:     "Cancel",
+			method:   http.MethodPost,
+			path:     "/api/v1/operations/" + running.Id.String() + "/cancel",
+			wantCode: http.StatusOK,
+			wantBody: `"status":"cancelled"`,
+		},
+		{
+			name:        "RetrieveOtherUser",
+			method:      http.MethodGet,
+			path:        "/api/v1/operations/" + running.Id.String(),
+			userContext: &security.UserContext{UserName: "mallory"},
+			wantCode:    http.StatusNotFound,
+		},
+		{
+			name:        "ResultOtherTenant",
+			method:      http.MethodGet,
+			path:        "/api/v1/operations/" + succeeded.Id.String() + "/result",
+			userContext: &security.UserContext{UserName: "anonymous", TenantId: types.MustNewUUID()},
+			wantCode:    http.StatusNotFound,
+		},
+		{
+			name:        "ResultWithoutPermission",
+			method:      http.MethodGet,
+			path:        "/api/v1/operations/" + succeeded.Id.String() + "/result",
+			permissions: []string{"VIEW_GADGETS"},
+			wantCode:    http.StatusNotFound,
+		},
+		{
+			name:        "CancelOtherUser",
+			method:      http.MethodPost,
+			path:        "/api/v1/operations/" + failed.Id.String() + "/cancel",
+			userContext: &security.UserContext{UserName: "mallory"},
+			wantCode:    http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			permissions := tt.permissions
+			if permissions == nil {
+				permissions = []string{"VIEW_WIDGETS"}
+			}
+			reqCtx := newTestRequestContext(permissions...)
+			if tt.userContext != nil {
+				reqCtx = security.ContextWithUserContext(reqCtx, tt.userContext)
+			}
+
+			req := httptest.NewRequest(tt.method, tt.path, nil).WithContext(reqCtx)
+			req.Header.Set(restops.HeaderAccept, restops.MediaTypeJson)
+			rec := httptest.NewRecorder()
+
+			newTestContainer(t, m).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+		})
+	}
+}
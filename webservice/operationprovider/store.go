@@ -0,0 +1,105 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package operationprovider
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+// Store persists the state of operations.
+type Store interface {
+	Save(ctx context.Context, operation Operation) error
+	Load(ctx context.Context, id types.UUID) (Operation, error)
+}
+
+// NewStore creates the operation store selected by the configuration.
+func NewStore(ctx context.Context, cfg *OperationsConfig) (Store, error) {
+	switch cfg.Store {
+	case StoreMemory:
+		return NewMemoryStore(cfg.Retention), nil
+	case StoreRedis:
+		return NewRedisStore(cfg.Prefix, cfg.Retention), nil
+	case StoreSql:
+		return NewSqlStore(ctx, cfg.Table, cfg.Retention)
+	default:
+		return nil, errors.Wrapf(ErrUnknownStore, "%q", cfg.Store)
+	}
+}
+
+// storedOperation includes the operation result and access control when serialized.
+type storedOperation struct {
+	Operation
+	Result      json.RawMessage `json:"result,omitempty"`
+	UserName    string          `json:"userName"`
+	TenantId    types.UUID      `json:"tenantId,omitempty"`
+	Permissions []string        `json:"permissions,omitempty"`
+}
+
+func newStoredOperation(operation Operation) storedOperation {
+	return storedOperation{
+		Operation:   operation,
+		Result:      operation.Result,
+		UserName:    operation.UserName,
+		TenantId:    operation.TenantId,
+		Permissions: operation.Permissions,
+	}
+}
+
+func (s storedOperation) operation() Operation {
+	result := s.Operation
+	result.Result = s.Result
+	result.UserName = s.UserName
+	result.TenantId = s.TenantId
+	result.Permissions = s.Permissions
+	return result
+}
+
+// MemoryStore retains operations in the memory of the current instance.
+type MemoryStore struct {
+	retention  time.Duration
+	mtx        sync.Mutex
+	operations map[string]Operation
+}
+
+func (s *MemoryStore) Save(_ context.Context, operation Operation) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.operations[operation.Id.String()] = operation
+
+	// Discard expired operations
+	expiry := time.Now().Add(-s.retention)
+	for key, existing := range s.operations {
+		if existing.Status.Completed() && existing.Updated.ToTimeTime().Before(expiry) {
+			delete(s.operations, key)
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) Load(_ context.Context, id types.UUID) (Operation, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	operation, ok := s.operations[id.String()]
+	if !ok {
+		return Operation{}, ErrOperationNotFound
+	}
+
+	return operation, nil
+}
+
+func NewMemoryStore(retention time.Duration) *MemoryStore {
+	return &MemoryStore{
+		retention:  retention,
+		operations: make(map[string]Operation),
+	}
+}
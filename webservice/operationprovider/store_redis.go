@@ -0,0 +1,71 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package operationprovider
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/redis"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"encoding/json"
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// RedisStore shares operations between instances using redis, expiring them after the retention period.
+type RedisStore struct {
+	prefix    string
+	retention time.Duration
+}
+
+func (s RedisStore) client(ctx context.Context) (*goredis.Client, error) {
+	pool := redis.PoolFromContext(ctx)
+	if pool == nil || pool.Connection() == nil {
+		return nil, errors.Wrap(redis.ErrDisabled, "Redis operation store unavailable")
+	}
+	return pool.Connection().Client(ctx), nil
+}
+
+func (s RedisStore) Save(ctx context.Context, operation Operation) error {
+	client, err := s.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(newStoredOperation(operation))
+	if err != nil {
+		return errors.Wrap(err, "Failed to encode operation")
+	}
+
+	return client.Set(ctx, s.prefix+operation.Id.String(), data, s.retention).Err()
+}
+
+func (s RedisStore) Load(ctx context.Context, id types.UUID) (Operation, error) {
+	client, err := s.client(ctx)
+	if err != nil {
+		return Operation{}, err
+	}
+
+	data, err := client.Get(ctx, s.prefix+id.String()).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return Operation{}, ErrOperationNotFound
+	} else if err != nil {
+		return Operation{}, err
+	}
+
+	var stored storedOperation
+	if err = json.Unmarshal(data, &stored); err != nil {
+		return Operation{}, errors.Wrap(err, "Failed to decode operation")
+	}
+
+	return stored.operation(), nil
+}
+
+func NewRedisStore(prefix string, retention time.Duration) RedisStore {
+	return RedisStore{
+		prefix:    prefix,
+		retention: retention,
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package operationprovider
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/sqldb"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"strings"
+	"time"
+)
+
+const (
+	columnId      = "id"
+	columnStatus  = "status"
+	columnUpdated = "updated"
+)
+
+// operationRow is the SQL representation of an Operation.
+type operationRow struct {
+	Id           uuid.UUID  `db:"id"`
+	Name         string     `db:"name"`
+	Status       string     `db:"status"`
+	Created      time.Time  `db:"created"`
+	Updated      time.Time  `db:"updated"`
+	ErrorStatus  *int       `db:"error_status"`
+	ErrorMessage *string    `db:"error_message"`
+	Result       *string    `db:"result"`
+	UserName     string     `db:"user_name"`
+	TenantId     *uuid.UUID `db:"tenant_id"`
+	Permissions  string     `db:"permissions"`
+}
+
+func newOperationRow(operation Operation) operationRow {
+	row := operationRow{
+		Id:      uuid.UUID(operation.Id.ToByteArray()),
+		Name:    operation.Name,
+		Status:  string(operation.Status),
+		Created: operation.Created.ToTimeTime(),
+		Updated: operation.Updated.ToTimeTime(),
+
+		UserName:    operation.UserName,
+		Permissions: strings.Join(operation.Permissions, ","),
+	}
+
+	if !operation.TenantId.IsEmpty() {
+		tenantId := uuid.UUID(operation.TenantId.ToByteArray())
+		row.TenantId = &tenantId
+	}
+
+	if operation.Error != nil {
+		row.ErrorStatus = &operation.Error.Status
+		row.ErrorMessage = &operation.Error.Message
+	}
+
+	if operation.Result != nil {
+		result := string(operation.Result)
+		row.Result = &result
+	}
+
+	return row
+}
+
+func (r operationRow) operation() Operation {
+	operation := Operation{
+		Id:      types.UUID(r.Id[:]),
+		Name:    r.Name,
+		Status:  OperationStatus(r.Status),
+		Created: types.NewTime(r.Created),
+		Updated: types.NewTime(r.Updated),
+
+		UserName: r.UserName,
+	}
+
+	if r.TenantId != nil {
+		operation.TenantId = types.UUID(r.TenantId[:])
+	}
+
+	if r.Permissions != "" {
+		operation.Permissions = strings.Split(r.Permissions, ",")
+	}
+
+	if r.ErrorStatus != nil && r.ErrorMessage != nil {
+		operation.Error = &OperationError{
+			Status:  *r.ErrorStatus,
+			Message: *r.ErrorMessage,
+		}
+	}
+
+	if r.Result != nil {
+		operation.Result = []byte(*r.Result)
+	}
+
+	return operation
+}
+
+// SqlStore shares operations between instances using an SQL table.  Completed operations
+// are removed after the retention period.
+type SqlStore struct {
+	table     string
+	retention time.Duration
+}
+
+func (s SqlStore) repository(ctx context.Context) (sqldb.TypedRepositoryApi[operationRow], error) {
+	return sqldb.NewTypedRepository[operationRow](ctx, s.table)
+}
+
+func (s SqlStore) Save(ctx context.Context, operation Operation) error {
+	repository, err := s.repository(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = repository.Upsert(ctx, newOperationRow(operation)); err != nil {
+		return err
+	}
+
+	if !operation.Status.Completed() {
+		return nil
+	}
+
+	// Discard expired operations
+	return repository.DeleteAll(ctx, sqldb.And(goqu.Ex{
+		columnStatus: []string{
+			string(OperationStatusSucceeded),
+			string(OperationStatusFailed),
+			string(OperationStatusCancelled),
+		},
+		columnUpdated: goqu.Op{"lt": time.Now().Add(-s.retention)},
+	}))
+}
+
+func (s SqlStore) Load(ctx context.Context, id types.UUID) (Operation, error) {
+	repository, err := s.repository(ctx)
+	if err != nil {
+		return Operation{}, err
+	}
+
+	var row operationRow
+	err = repository.FindOne(ctx, &row, sqldb.And(goqu.Ex{
+		columnId: uuid.UUID(id.ToByteArray()),
+	}))
+	if errors.Is(err, sqldb.ErrNotFound) {
+		return Operation{}, ErrOperationNotFound
+	} else if err != nil {
+		return Operation{}, err
+	}
+
+	return row.operation(), nil
+}
+
+func NewSqlStore(_ context.Context, table string, retention time.Duration) (SqlStore, error) {
+	if table == "" {
+		return SqlStore{}, errors.New("Operation store table not specified")
+	}
+
+	return SqlStore{
+		table:     table,
+		retention: retention,
+	}, nil
+}
@@ -0,0 +1,97 @@
+// Copyright © 2023, Cisco Systems Inc.
+// Use of this source code is governed by an MIT-style license that can be
+// found in the LICENSE file or at https://opensource.org/licenses/MIT.
+
+package operationprovider
+
+import (
+	"context"
+	"cto-github.cisco.com/NFV-BU/go-msx/types"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func testOperation(status OperationStatus, updated time.Time) Operation {
+	return Operation{
+		Id:      types.MustNewUUID(),
+		Name:    "createWidget",
+		Status:  status,
+		Created: types.NewTime(updated),
+		Updated: types.NewTime(updated),
+
+		UserName:    "anonymous",
+		Permissions: []string{"VIEW_WIDGETS", "MANAGE_WIDGETS"},
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(time.Hour)
+
+	expired := testOperation(OperationStatusSucceeded, time.Now().Add(-2*time.Hour))
+	running := testOperation(OperationStatusRunning, time.Now().Add(-2*time.Hour))
+	current := testOperation(OperationStatusSucceeded, time.Now())
+	current.Result = json.RawMessage(`{"id":"a"}`)
+
+	for _, operation := range []Operation{expired, running, current} {
+		assert.NoError(t, store.Save(ctx, operation))
+	}
+
+	_, err := store.Load(ctx, expired.Id)
+	assert.ErrorIs(t, err, ErrOperationNotFound)
+
+	got, err := store.Load(ctx, running.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, running, got)
+
+	got, err = store.Load(ctx, current.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, current, got)
+}
+
+func TestStoredOperation(t *testing.T) {
+	operation := testOperation(OperationStatusFailed, time.Now().Truncate(time.Millisecond).UTC())
+	operation.Error = &OperationError{Status: 404, Message: "Widget not found"}
+	operation.Result = json.RawMessage(`{"id":"a"}`)
+
+	operation.TenantId = types.MustNewUUID()
+
+	data, err := json.Marshal(newStoredOperation(operation))
+	assert.NoError(t, err)
+
+	var stored storedOperation
+	assert.NoError(t, json.Unmarshal(data, &stored))
+	assert.Equal(t, operation.Id, stored.operation().Id)
+	assert.Equal(t, operation.Error, stored.operation().Error)
+	assert.JSONEq(t, string(operation.Result), string(stored.operation().Result))
+	assert.Equal(t, operation.UserName, stored.operation().UserName)
+	assert.Equal(t, operation.TenantId, stored.operation().TenantId)
+	assert.Equal(t, operation.Permissions, stored.operation().Permissions)
+}
+
+func TestOperationRow(t *testing.T) {
+	operation := testOperation(OperationStatusFailed, time.Now())
+	operation.Error = &OperationError{Status: 404, Message: "Widget not found"}
+	operation.Result = json.RawMessage(`{"id":"a"}`)
+	operation.TenantId = types.MustNewUUID()
+
+	got := newOperationRow(operation).operation()
+	assert.Equal(t, operation.Id, got.Id)
+	assert.Equal(t, operation.Status, got.Status)
+	assert.Equal(t, operation.Error, got.Error)
+	assert.Equal(t, operation.Result, got.Result)
+	assert.Equal(t, operation.UserName, got.UserName)
+	assert.Equal(t, operation.TenantId, got.TenantId)
+	assert.Equal(t, operation.Permissions, got.Permissions)
+}
+
+func TestNewStore(t *testing.T) {
+	_, err := NewStore(context.Background(), &OperationsConfig{Store: "file"})
+	assert.ErrorIs(t, err, ErrUnknownStore)
+
+	store, err := NewStore(context.Background(), &OperationsConfig{Store: StoreMemory})
+	assert.NoError(t, err)
+	assert.IsType(t, new(MemoryStore), store)
+}
@@ -300,6 +300,11 @@ func tokenUserContextFilter(req *restful.Request, resp *restful.Response, chain
 			return
 		}
 
+		if err = security.CheckTokenRevoked(userContext); err != nil {
+			WriteError(req, resp, http.StatusUnauthorized, err)
+			return
+		}
+
 		ctx := security.ContextWithUserContext(req.Request.Context(), userContext)
 		req.Request = req.Request.WithContext(ctx)
 	}
@@ -309,6 +314,13 @@ func tokenUserContextFilter(req *restful.Request, resp *restful.Response, chain
 
 func certificateUserContextFilter(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
 	cert, err := httprequest.ExtractCertificate(req.Request)
+	if err == httprequest.ErrNotFound &&
+		security.UserContextFromContext(req.Request.Context()).Token == "" &&
+		certdetailsprovider.IdentityMapperRegistered() {
+		// Authenticate directly connected clients without a bearer token using their certificate
+		// only when the identity rules are enabled to map it
+		cert, err = httprequest.ExtractPeerCertificate(req.Request)
+	}
 	if err != nil && err != httprequest.ErrNotFound {
 		WriteError(req, resp, http.StatusUnauthorized, err)
 		return
@@ -322,6 +334,16 @@ func certificateUserContextFilter(req *restful.Request, resp *restful.Response,
 			return
 		}
 
+		// Map the certificate to a service identity
+		userContext, err = certdetailsprovider.MapUserContext(req.Request.Context(), userContext, serverRelativePath(req))
+		if errors.Is(err, certdetailsprovider.ErrPathNotAllowed) {
+			WriteError(req, resp, http.StatusForbidden, err)
+			return
+		} else if err != nil {
+			WriteError(req, resp, http.StatusUnauthorized, err)
+			return
+		}
+
 		// Inject the derived UserContext
 		ctx := security.ContextWithUserContext(req.Request.Context(), userContext)
 
@@ -335,6 +357,15 @@ func certificateUserContextFilter(req *restful.Request, resp *restful.Response,
 	chain.ProcessFilter(req, resp)
 }
 
+// serverRelativePath returns the request path without the server context path.
+func serverRelativePath(req *restful.Request) string {
+	server := WebServerFromContext(req.Request.Context())
+	if server == nil {
+		return req.Request.URL.Path
+	}
+	return strings.TrimPrefix(req.Request.URL.Path, server.ContextPath())
+}
+
 func filterFilter(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
 	filters := FiltersFromContext(req.Request.Context())
 	if filters != nil {
@@ -389,6 +420,63 @@ func PermissionsFilter(anyOf ...string) restful.FilterFunction {
 	}
 }
 
+// ResourceExtractor builds the authorization resource for a request.
+type ResourceExtractor func(req *restful.Request) (rbac.Resource, error)
+
+// TenantResource returns a ResourceExtractor for a resource of the specified type, owned
+// by the tenant identified by the parameter.
+func TenantResource(resourceType string, tenantParameter *restful.Parameter) ResourceExtractor {
+	return func(req *restful.Request) (rbac.Resource, error) {
+		tenantId, err := getParameter(tenantParameter, req)
+		if err != nil {
+			return rbac.Resource{}, err
+		}
+
+		tenantUuid, err := types.ParseUUID(tenantId)
+		if err != nil {
+			return rbac.Resource{}, err
+		}
+
+		return rbac.Resource{
+			Type:     resourceType,
+			TenantId: tenantUuid,
+		}, nil
+	}
+}
+
+// Policy authorizes requests to the route using the policy engine.
+func Policy(action string, resource ResourceExtractor) restfulcontext.RouteBuilderFunc {
+	return func(b *restful.RouteBuilder) {
+		b.Filter(PolicyFilter(action, resource))
+	}
+}
+
+// PolicyFilter authorizes requests using the policy engine, responding with the reasons
+// for the decision when the request is denied.
+func PolicyFilter(action string, resource ResourceExtractor) restful.FilterFunction {
+	return func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+		var target rbac.Resource
+		if resource != nil {
+			var err error
+			if target, err = resource(req); err != nil {
+				WriteError(req, resp, http.StatusBadRequest, err)
+				return
+			}
+		}
+
+		if err := rbac.Authorize(req.Request.Context(), action, target); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, rbac.ErrAccessDenied) {
+				status = http.StatusForbidden
+			}
+			WriteError(req, resp, status, err)
+			return
+		}
+
+		chain.ProcessFilter(req, resp)
+	}
+}
+
 func getParameter(parameter *restful.Parameter, req *restful.Request) (string, error) {
 	switch parameter.Kind() {
 	case restful.PathParameterKind:
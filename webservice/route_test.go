@@ -7,6 +7,7 @@ package webservice
 import (
 	"context"
 	"cto-github.cisco.com/NFV-BU/go-msx/audit/auditlog"
+	"cto-github.cisco.com/NFV-BU/go-msx/rbac"
 	"cto-github.cisco.com/NFV-BU/go-msx/security"
 	"cto-github.cisco.com/NFV-BU/go-msx/testhelpers"
 	"cto-github.cisco.com/NFV-BU/go-msx/testhelpers/contexttest"
@@ -18,6 +19,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"net/http"
 	"testing"
+	"time"
 )
 
 func TestAcceptReturns(t *testing.T) {
@@ -178,6 +180,54 @@ func TestPermissionsFilter(t *testing.T) {
 	}
 }
 
+func TestPolicyFilter(t *testing.T) {
+	rbac.RegisterPolicy("test-policy-filter", rbac.AllOf(
+		rbac.Permission("MANAGE_SERVICES"),
+		rbac.Attribute("kind", "service")))
+
+	resource := func(req *restful.Request) (rbac.Resource, error) {
+		return rbac.Resource{
+			Type:       "service",
+			Attributes: map[string]interface{}{"kind": "service"},
+		}, nil
+	}
+
+	tests := []struct {
+		name string
+		test testhelpers.Testable
+	}{
+		{
+			name: "Forbidden",
+			test: new(webservicetest.RouteBuilderTest).
+				WithContextInjector(securitytest.PermissionInjector("MANAGE_TESTS")).
+				WithRouteFilter(PolicyFilter("test-policy-filter", resource)).
+				WithRouteTargetReturn(http.StatusOK).
+				WithResponsePredicate(webservicetest.ResponseHasStatus(http.StatusForbidden)).
+				WithResponsePredicate(webservicetest.ResponseHasBodySubstring("MANAGE_SERVICES")),
+		},
+		{
+			name: "NoPolicy",
+			test: new(webservicetest.RouteBuilderTest).
+				WithContextInjector(securitytest.PermissionInjector("MANAGE_SERVICES")).
+				WithRouteFilter(PolicyFilter("test-policy-filter-missing", resource)).
+				WithRouteTargetReturn(http.StatusOK).
+				WithResponsePredicate(webservicetest.ResponseHasStatus(http.StatusForbidden)),
+		},
+		{
+			name: "Allowed",
+			test: new(webservicetest.RouteBuilderTest).
+				WithContextInjector(securitytest.PermissionInjector("MANAGE_SERVICES")).
+				WithRouteFilter(PolicyFilter("test-policy-filter", resource)).
+				WithRouteTargetReturn(http.StatusOK).
+				WithResponsePredicate(webservicetest.ResponseHasStatus(http.StatusOK)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, tt.test.Test)
+	}
+}
+
 func TestPopulateParams(t *testing.T) {
 	t.Skipped()
 }
@@ -696,6 +746,26 @@ func Test_securityContextFilter(t *testing.T) {
 				WithResponsePredicate(webservicetest.ResponseHasStatus(http.StatusOK)).
 				WithContextPredicate(contexttest.ContextHasNamedUserContext("tester")),
 		},
+		{
+			name: "RevokedBearerToken",
+			test: new(webservicetest.RouteBuilderTest).
+				WithRouteFilter(tokenUserContextFilter).
+				WithRouteBuilderDo(func(_ *restful.RouteBuilder) {
+					security.RevokeToken("revoked-jti", time.Now().Add(time.Hour))
+					mockTokenProvider := new(security.MockTokenProvider)
+					mockTokenProvider.
+						On("UserContextFromToken", mock.AnythingOfType("*context.valueCtx"), "abc123").
+						Return(&security.UserContext{
+							UserName: "tester",
+							Token:    "abc123",
+							Jti:      "revoked-jti",
+						}, nil)
+					security.SetTokenProvider(mockTokenProvider)
+				}).
+				WithRequestHeader("Authorization", "Bearer abc123").
+				WithRouteTargetReturn(http.StatusOK).
+				WithResponsePredicate(webservicetest.ResponseHasStatus(http.StatusUnauthorized)),
+		},
 		{
 			name: "BadBearerToken",
 			test: new(webservicetest.RouteBuilderTest).
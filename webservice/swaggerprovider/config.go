@@ -66,6 +66,10 @@ type DocumentationConfig struct {
 	Server      DocumentationServerConfig
 }
 
+func init() {
+	config.RegisterSchema[DocumentationConfig](configRootDocumentation)
+}
+
 func DocumentationConfigFromConfig(cfg *config.Config) (*DocumentationConfig, error) {
 	var documentationConfig DocumentationConfig
 	if err := cfg.Populate(&documentationConfig, configRootDocumentation); err != nil {